@@ -5,25 +5,17 @@ package attachments
 
 import (
 	"context"
-	"encoding/base64"
 	"fmt"
 	"io"
-	"mime"
 	"net/http"
 	"net/url"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/openai/openai-go"
-	"github.com/openai/openai-go/packages/param"
+	"github.com/devproje/mininaru/core"
 )
 
-const maxAttachments = 4
-const maxAttachmentBytes = 10 * 1024 * 1024
-const maxTotalBytes = 20 * 1024 * 1024
-
 var client = &http.Client{Timeout: 20 * time.Second}
 
 func allowedURL(raw string) bool {
@@ -40,38 +32,6 @@ func allowedURL(raw string) bool {
 	return host == "cdn.discordapp.com" || host == "media.discordapp.net"
 }
 
-func textType(contentType, filename string) bool {
-	var mediaType string
-	var ext string
-
-	mediaType, _, _ = mime.ParseMediaType(contentType)
-	if strings.HasPrefix(mediaType, "text/") {
-		return true
-	}
-	switch mediaType {
-	case "application/json", "application/xml", "application/yaml", "application/x-yaml", "application/javascript":
-		return true
-	}
-	ext = strings.ToLower(filepath.Ext(filename))
-	switch ext {
-	case ".go", ".py", ".js", ".ts", ".tsx", ".jsx", ".rs", ".java", ".c", ".h", ".cpp", ".hpp",
-		".sh", ".sql", ".md", ".txt", ".json", ".yaml", ".yml", ".toml", ".xml", ".csv", ".log":
-		return true
-	}
-	return false
-}
-
-func imageType(contentType string) bool {
-	var mediaType string
-
-	mediaType, _, _ = mime.ParseMediaType(contentType)
-	switch mediaType {
-	case "image/png", "image/jpeg", "image/gif", "image/webp":
-		return true
-	}
-	return false
-}
-
 func download(ctx context.Context, attachment *discordgo.MessageAttachment) ([]byte, error) {
 	var request *http.Request
 	var response *http.Response
@@ -83,7 +43,7 @@ func download(ctx context.Context, attachment *discordgo.MessageAttachment) ([]b
 	if attachment == nil || !allowedURL(attachment.URL) {
 		return nil, fmt.Errorf("attachment URL is not a Discord CDN URL")
 	}
-	if attachment.Size > maxAttachmentBytes {
+	if attachment.Size > core.MaxAttachmentBytes {
 		return nil, fmt.Errorf("attachment %q exceeds the 10 MiB limit", attachment.Filename)
 	}
 	request, err = http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
@@ -98,70 +58,43 @@ func download(ctx context.Context, attachment *discordgo.MessageAttachment) ([]b
 	if response.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("downloading %q returned %s", attachment.Filename, response.Status)
 	}
-	reader = io.LimitReader(response.Body, maxAttachmentBytes+1)
+	reader = io.LimitReader(response.Body, core.MaxAttachmentBytes+1)
 	buf, err = io.ReadAll(reader)
 	if err != nil {
 		return nil, err
 	}
-	if len(buf) > maxAttachmentBytes {
+	if len(buf) > core.MaxAttachmentBytes {
 		return nil, fmt.Errorf("attachment %q exceeds the 10 MiB limit", attachment.Filename)
 	}
 	return buf, nil
 }
 
-func Build(ctx context.Context, content string, files []*discordgo.MessageAttachment) ([]openai.ChatCompletionContentPartUnionParam, error) {
-	var parts []openai.ChatCompletionContentPartUnionParam
-	var attachment *discordgo.MessageAttachment
+func Build(ctx context.Context, files []*discordgo.MessageAttachment) ([]*core.Attachment, error) {
+	var built []*core.Attachment
+	var file *discordgo.MessageAttachment
 	var buf []byte
 	var total int
-	var mediaType string
-	var detectedType string
-	var encoded string
+	var attachment *core.Attachment
 
 	var err error
 
-	if len(files) > maxAttachments {
-		return nil, fmt.Errorf("at most %d attachments are supported", maxAttachments)
-	}
-	if strings.TrimSpace(content) == "" {
-		content = "Analyze the attached content."
+	if len(files) > core.MaxAttachments {
+		return nil, fmt.Errorf("at most %d attachments are supported", core.MaxAttachments)
 	}
-	parts = append(parts, openai.TextContentPart(content))
-	for _, attachment = range files {
-		buf, err = download(ctx, attachment)
+	for _, file = range files {
+		buf, err = download(ctx, file)
 		if err != nil {
 			return nil, err
 		}
 		total += len(buf)
-		if total > maxTotalBytes {
+		if total > core.MaxAttachmentsTotal {
 			return nil, fmt.Errorf("attachments exceed the 20 MiB total limit")
 		}
-		mediaType, _, _ = mime.ParseMediaType(attachment.ContentType)
-		detectedType, _, _ = mime.ParseMediaType(http.DetectContentType(buf))
-		if imageType(mediaType) {
-			if !imageType(detectedType) {
-				return nil, fmt.Errorf("attachment %q content does not match type %q", attachment.Filename, mediaType)
-			}
-			mediaType = detectedType
-		}
-		if mediaType == "" || mediaType == "application/octet-stream" {
-			mediaType = detectedType
-		}
-		encoded = base64.StdEncoding.EncodeToString(buf)
-		switch {
-		case imageType(mediaType):
-			parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
-				URL: "data:" + mediaType + ";base64," + encoded, Detail: "auto",
-			}))
-		case textType(mediaType, attachment.Filename):
-			parts = append(parts, openai.TextContentPart(fmt.Sprintf("Attachment %q:\n%s", attachment.Filename, string(buf))))
-		case mediaType == "application/pdf":
-			parts = append(parts, openai.FileContentPart(openai.ChatCompletionContentPartFileFileParam{
-				FileData: param.NewOpt(encoded), Filename: param.NewOpt(attachment.Filename),
-			}))
-		default:
-			return nil, fmt.Errorf("attachment %q has unsupported type %q", attachment.Filename, mediaType)
+		attachment, err = core.AttachmentNew(file.Filename, file.ContentType, buf)
+		if err != nil {
+			return nil, err
 		}
+		built = append(built, attachment)
 	}
-	return parts, nil
+	return built, nil
 }
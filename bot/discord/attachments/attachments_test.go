@@ -13,12 +13,3 @@ func TestAllowedURLOnlyAcceptsDiscordCDN(t *testing.T) {
 		t.Fatal("non-Discord or insecure URL was accepted")
 	}
 }
-
-func TestAttachmentTypes(t *testing.T) {
-	if !imageType("image/png") || imageType("image/svg+xml") {
-		t.Fatal("image allowlist is incorrect")
-	}
-	if !textType("application/octet-stream", "main.go") || textType("application/octet-stream", "app.exe") {
-		t.Fatal("text extension allowlist is incorrect")
-	}
-}
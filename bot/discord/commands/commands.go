@@ -30,6 +30,25 @@ var slashCommands = []*discordgo.ApplicationCommand{
 				Type: discordgo.ApplicationCommandOptionString, Name: "name",
 				Description: "Agent to switch to, or leave empty to see the current one", Required: false, Autocomplete: true,
 			},
+			{
+				Type: discordgo.ApplicationCommandOptionBoolean, Name: "keep",
+				Description: "Let the new agent carry on with this conversation instead of starting fresh", Required: false,
+			},
+		},
+	},
+	{
+		Name: "roundtable", Description: "Seat several agents in this channel and let them take turns",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "agents", Description: "Agent names, comma separated", Required: true},
+			{
+				Type: discordgo.ApplicationCommandOptionString, Name: "mode",
+				Description: "Who answers each message, an @name always wins", Required: false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "everyone in turn", Value: "round-robin"},
+					{Name: "a moderator picks", Value: "moderator"},
+					{Name: "only who is addressed", Value: "addressed"},
+				},
+			},
 		},
 	},
 	{
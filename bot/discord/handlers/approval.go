@@ -17,10 +17,11 @@ import (
 )
 
 type approvalPresentation struct {
-	title   string
-	target  string
-	impact  string
-	details string
+	title    string
+	target   string
+	impact   string
+	details  string
+	language string
 }
 
 type discordApproval struct {
@@ -49,7 +50,7 @@ func approvalRequestComponents(id, userId string, view approvalPresentation) []d
 		discordgo.TextDisplay{Content: fmt.Sprintf("Only <@%s> can decide. This request expires in 5 minutes.", userId)},
 	}
 	if view.details != "" {
-		body = append(body, discordgo.TextDisplay{Content: "**Details**\n```json\n" + view.details + "\n```"})
+		body = append(body, discordgo.TextDisplay{Content: "**Details**\n```" + view.language + "\n" + view.details + "\n```"})
 	}
 	body = append(body, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
 		discordgo.Button{Label: "Approve once", Emoji: &discordgo.ComponentEmoji{Name: "✅"}, Style: discordgo.SuccessButton, CustomID: "hil:yes:" + id},
@@ -74,11 +75,15 @@ func approvalArguments(arguments string) (map[string]any, string) {
 	} else {
 		shown = arguments
 	}
+	return payload, approvalBound(shown)
+}
+
+func approvalBound(shown string) string {
 	shown = strings.ReplaceAll(shown, "```", "`\u200b``")
 	if len([]rune(shown)) > approvalDetailsLimit {
 		shown = string([]rune(shown)[:approvalDetailsLimit]) + "…"
 	}
-	return payload, shown
+	return shown
 }
 
 func approvalString(payload map[string]any, key string) string {
@@ -104,8 +109,10 @@ func approvalView(def modules.Def, arguments string) approvalPresentation {
 	var view approvalPresentation
 	var action string
 	var scope string
+	var patch string
 
 	payload, view.details = approvalArguments(arguments)
+	view.language = "json"
 	view.title = strings.Join(strings.Fields(def.Description), " ")
 	view.impact = "This tool can access resources outside the conversation."
 
@@ -118,10 +125,39 @@ func approvalView(def modules.Def, arguments string) approvalPresentation {
 		view.title = "Change a local file"
 		view.target = approvalString(payload, "path")
 		view.impact = "This can create, replace, or append to a file on disk."
+	case "file_patch":
+		patch, _ = payload["patch"].(string)
+		view.title = "Patch local files"
+		view.target = strings.Join(modules.PatchPaths(patch), ", ")
+		view.impact = "This can create, change, or delete several files on disk at once."
+		if patch != "" {
+			view.details = approvalBound(patch)
+			view.language = "diff"
+		}
+	case modules.LSPToolName:
+		view.title = "Ask a language server"
+		view.target = strings.TrimSpace(approvalString(payload, "action") + " " + approvalString(payload, "path"))
+		view.impact = "The language server reads the project and may run its build tools."
+	case modules.GitToolName:
+		view.title = "Inspect the git repository"
+		view.target = strings.Join(strings.Fields(approvalString(payload, "action")+" "+approvalString(payload, "ref")+" "+approvalString(payload, "path")), " ")
+		view.impact = "Repository history and uncommitted changes will be shared with the agent."
+	case modules.GitWriteToolName:
+		view.title = "Change the git repository"
+		view.target = strings.Join(strings.Fields(approvalString(payload, "action")+" "+approvalString(payload, "ref")), " ")
+		view.impact = "This can stage, commit, switch branches, or discard and stash working tree changes."
 	case "bash_exec":
 		view.title = "Run a shell command"
 		view.target = approvalString(payload, "command")
 		view.impact = "The command can read or change files and start other processes."
+	case "bash_start":
+		view.title = "Start a background shell command"
+		view.target = approvalString(payload, "command")
+		view.impact = "The command keeps running after this turn, until it is stopped or mininaru exits."
+	case "bash_send":
+		view.title = "Send input to a background command"
+		view.target = "job " + approvalString(payload, "id")
+		view.impact = "The running command reads this as if it were typed."
 	case modules.MemoryToolName:
 		action = approvalString(payload, "action")
 		view.title = "Manage persistent memory"
@@ -28,6 +28,20 @@ func TestApprovalViewExplainsFileWriteImpact(t *testing.T) {
 	}
 }
 
+func TestApprovalViewShowsFilePatchAsDiff(t *testing.T) {
+	var view approvalPresentation
+
+	view = approvalView(modules.Def{Name: "file_patch"},
+		`{"patch":"--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n--- /dev/null\n+++ b/b.go\n@@ -0,0 +1 @@\n+package b\n"}`)
+
+	if view.title != "Patch local files" || view.target != "a.go, b.go" {
+		t.Fatalf("approval view = %#v", view)
+	}
+	if view.language != "diff" || !strings.Contains(view.details, "-old\n+new") {
+		t.Fatalf("approval details are not the patch: %q", view.details)
+	}
+}
+
 func TestApprovalViewEscapesCodeFenceAndBoundsDetails(t *testing.T) {
 	var arguments string
 	var view approvalPresentation
@@ -12,6 +12,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 	"github.com/devproje/mininaru/bot/discord/commands"
 	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
 	"github.com/devproje/mininaru/util"
 )
 
@@ -30,6 +31,7 @@ type Discord struct {
 	seen      map[string]struct{}
 	seenOrder []string
 	turns     map[string]chan struct{}
+	sessions  map[string]struct{}
 	mu        sync.Mutex
 
 	lifetime context.Context
@@ -58,7 +60,7 @@ func New(cfg Config, registry *core.Registry) (*Discord, error) {
 
 	bot = Discord{
 		cfg: cfg, registry: registry, approvals: make(map[string]*discordApproval),
-		seen: make(map[string]struct{}), turns: make(map[string]chan struct{}),
+		seen: make(map[string]struct{}), turns: make(map[string]chan struct{}), sessions: make(map[string]struct{}),
 	}
 	bot.lifetime, bot.shutdown = context.WithCancel(context.Background())
 
@@ -69,7 +71,9 @@ func New(cfg Config, registry *core.Registry) (*Discord, error) {
 	}
 	bot.gateway.Identify.Intents = discordgo.IntentsGuildMessages |
 		discordgo.IntentsDirectMessages |
-		discordgo.IntentsMessageContent
+		discordgo.IntentsMessageContent |
+		discordgo.IntentsGuildMessageReactions |
+		discordgo.IntentsDirectMessageReactions
 
 	return &bot, nil
 }
@@ -137,6 +141,8 @@ func (d *Discord) Start() error {
 	d.gateway.AddHandler(d.onReady)
 	d.gateway.AddHandler(d.onMessage)
 	d.gateway.AddHandler(d.onInteraction)
+	d.gateway.AddHandler(d.onReactionAdd)
+	d.gateway.AddHandler(d.onReactionRemove)
 	if err = d.gateway.Open(); err != nil {
 		return err
 	}
@@ -158,10 +164,41 @@ func (d *Discord) Start() error {
 	return nil
 }
 
+func (d *Discord) trackSession(sessionId string) {
+	d.mu.Lock()
+	if d.sessions == nil {
+		d.sessions = make(map[string]struct{})
+	}
+	d.sessions[sessionId] = struct{}{}
+	d.mu.Unlock()
+}
+
+func (d *Discord) endSession(sessionId string) {
+	d.mu.Lock()
+	delete(d.sessions, sessionId)
+	d.mu.Unlock()
+
+	modules.BashJobsCloseOwner(sessionId)
+	modules.BashShellsCloseOwner(sessionId)
+}
+
 func (d *Discord) Stop() error {
+	var sessionId string
+	var ended []string
+
 	if d.shutdown != nil {
 		d.shutdown()
 	}
 
+	d.mu.Lock()
+	for sessionId = range d.sessions {
+		ended = append(ended, sessionId)
+	}
+	d.mu.Unlock()
+
+	for _, sessionId = range ended {
+		d.endSession(sessionId)
+	}
+
 	return d.gateway.Close()
 }
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -17,6 +18,7 @@ import (
 	"github.com/devproje/mininaru/bot/discord/commands"
 	"github.com/devproje/mininaru/core"
 	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
 )
 
 func TestInteractionUserFallsBackToGuildMember(t *testing.T) {
@@ -89,8 +91,8 @@ func TestAgentCommandEnablesAutocomplete(t *testing.T) {
 		if command.Name != "agent" {
 			continue
 		}
-		if len(command.Options) != 1 || !command.Options[0].Autocomplete {
-			t.Fatalf("agent command option = %#v, want autocomplete", command.Options)
+		if len(command.Options) != 2 || !command.Options[0].Autocomplete || command.Options[1].Name != "keep" {
+			t.Fatalf("agent command options = %#v, want an autocompleted name and keep", command.Options)
 		}
 		return
 	}
@@ -447,3 +449,175 @@ func TestUsageReportSaysWhenNothingIsRecorded(t *testing.T) {
 		t.Fatalf("empty report should not draw a table: %q", text)
 	}
 }
+
+func TestUsageReportSplitsARoundtableByAgent(t *testing.T) {
+	var totals core.UsageTotals
+	var text string
+
+	totals = core.UsageTotals{
+		Lines: []core.UsageLine{{Kind: "turn", PromptTokens: 40, CompletionTokens: 4, TotalTokens: 44}},
+		Agents: []core.AgentUsage{
+			{AgentId: "a", Agent: "alpha", PromptTokens: 30, CompletionTokens: 3, TotalTokens: 33},
+			{AgentId: "b", Agent: "beta", PromptTokens: 10, CompletionTokens: 1, TotalTokens: 11},
+		},
+		PromptTokens: 40, CompletionTokens: 4, TotalTokens: 44,
+	}
+
+	text = usageReport(&totals)
+
+	if !strings.Contains(text, "AGENT") || !strings.Contains(text, "alpha") || !strings.Contains(text, "beta") {
+		t.Fatalf("report has no per-agent split: %s", text)
+	}
+	if strings.Count(text, "```") != 2 {
+		t.Fatalf("the agent table is outside the code block: %s", text)
+	}
+}
+
+func TestKeepHandoffMovesTheChannelConversation(t *testing.T) {
+	var naru, worker *core.NaruAgent
+	var bound, found *core.Session
+	var reply string
+
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	naru = &core.NaruAgent{Id: "n", Name: "naru"}
+	worker = &core.NaruAgent{Id: "w", Name: "worker"}
+
+	reply, err = keepHandoff("c1", worker)
+	if err != nil || !strings.Contains(reply, "starts fresh") {
+		t.Fatalf("unbound channel reply = %q, %v", reply, err)
+	}
+
+	bound, err = core.SessionAttach(naru, OriginDiscord, "c2", "discord c2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err = keepHandoff("c2", worker)
+	if err != nil || !strings.Contains(reply, "keeping the conversation") {
+		t.Fatalf("handoff reply = %q, %v", reply, err)
+	}
+	found, err = core.SessionByExternal(OriginDiscord, "c2")
+	if err != nil || found.Id != bound.Id || found.AgentId != worker.Id {
+		t.Fatalf("bound session after keep = %+v, %v", found, err)
+	}
+
+	reply, err = keepHandoff("c2", worker)
+	if err != nil || !strings.Contains(reply, "already talks to worker") {
+		t.Fatalf("repeated keep reply = %q, %v", reply, err)
+	}
+}
+
+func TestKeepHandoffWaitsForTheChannelsRunningTurn(t *testing.T) {
+	var bot Discord
+	var naru, worker *core.NaruAgent
+	var bound, found *core.Session
+	var sent []string
+	var release chan struct{}
+	var completed chan struct{}
+
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	naru = &core.NaruAgent{Id: "n", Name: "naru"}
+	worker = &core.NaruAgent{Id: "w", Name: "worker"}
+	bound, err = core.SessionAttach(naru, OriginDiscord, "c1", "discord c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot = Discord{gateway: recordingGateway(t, &sent), lifetime: context.Background()}
+	release = make(chan struct{})
+	completed = make(chan struct{})
+	bot.queueTurn("c1", func() {
+		<-release
+	})
+	bot.queueKeepHandoff(&discordgo.InteractionCreate{Interaction: &discordgo.Interaction{ID: "i", Token: "t"}}, "c1", worker)
+
+	found, err = core.SessionByExternal(OriginDiscord, "c1")
+	if err != nil || found.AgentId != naru.Id {
+		t.Fatalf("the handoff ran during the channel's turn: %+v, %v", found, err)
+	}
+
+	close(release)
+	bot.queueTurn("c1", func() {
+		close(completed)
+	})
+	select {
+	case <-completed:
+	case <-time.After(time.Second):
+		t.Fatal("the queued handoff did not complete")
+	}
+
+	found, err = core.SessionByExternal(OriginDiscord, "c1")
+	if err != nil || found.Id != bound.Id || found.AgentId != worker.Id {
+		t.Fatalf("bound session after the queued keep = %+v, %v", found, err)
+	}
+	if len(sent) != 2 || !strings.Contains(sent[1], "keeping the conversation") {
+		t.Fatalf("interaction replies = %v", sent)
+	}
+}
+
+func TestLatestAnswerOnlyAcceptsTheNewestReply(t *testing.T) {
+	var naru *core.NaruAgent
+	var bound, found *core.Session
+	var older, newest *core.Message
+
+	var err error
+
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	naru = &core.NaruAgent{Id: "n", Name: "naru"}
+	bound, err = core.SessionAttach(naru, OriginDiscord, "c1", "discord c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = core.MessageSave(bound.Id, "user", "first", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	older, err = core.MessageSave(bound.Id, "assistant", "one", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = core.MessageSave(bound.Id, "user", "second", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newest, err = core.MessageSave(bound.Id, "assistant", "two", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err = (&Discord{}).latestAnswer("c1", older.Id)
+	if err != nil || found != nil {
+		t.Fatalf("an older answer resolved to %+v, %v", found, err)
+	}
+	found, err = (&Discord{}).latestAnswer("c1", newest.Id)
+	if err != nil || found == nil || found.Id != bound.Id {
+		t.Fatalf("the newest answer resolved to %+v, %v", found, err)
+	}
+	found, err = (&Discord{}).latestAnswer("c2", newest.Id)
+	if err != nil || found != nil {
+		t.Fatalf("an unbound channel resolved to %+v, %v", found, err)
+	}
+}
@@ -166,8 +166,19 @@ func usageReport(totals *core.UsageTotals) string {
 			totals.Lines[index].TotalTokens)
 	}
 
-	fmt.Fprintf(&builder, "%-10s %8d    %8d    %8d\n```", "total",
+	fmt.Fprintf(&builder, "%-10s %8d    %8d    %8d\n", "total",
 		totals.PromptTokens, totals.CompletionTokens, totals.TotalTokens)
+
+	if len(totals.Agents) > 0 {
+		builder.WriteString("\nAGENT        PROMPT  COMPLETION       TOTAL\n")
+	}
+	for index = range totals.Agents {
+		fmt.Fprintf(&builder, "%-10s %8d    %8d    %8d\n", totals.Agents[index].Agent,
+			totals.Agents[index].PromptTokens, totals.Agents[index].CompletionTokens,
+			totals.Agents[index].TotalTokens)
+	}
+
+	builder.WriteString("```")
 	builder.WriteString("\nTokens, not money — mininaru does not know what your provider charges.")
 
 	return builder.String()
@@ -208,6 +219,8 @@ func (d *Discord) resetInteraction(interaction *discordgo.InteractionCreate) {
 	var parts []string
 	var user *discordgo.User
 	var role string
+	var bound *core.Session
+	var seats []*core.NaruAgent
 	var target *core.Instance
 	var components []discordgo.MessageComponent
 
@@ -234,6 +247,28 @@ func (d *Discord) resetInteraction(interaction *discordgo.InteractionCreate) {
 		d.respond(interaction, "You are not paired with this bot.")
 		return
 	}
+	bound, err = core.SessionByExternal(OriginDiscord, interaction.ChannelID)
+	if err != nil {
+		d.respond(interaction, publicFailure("looking up this channel's conversation", err))
+		return
+	}
+	if bound != nil {
+		d.endSession(bound.Id)
+	}
+	if bound != nil && bound.TurnMode != "" {
+		seats, err = core.RoundtableSeats(bound.Id)
+		if err == nil {
+			_, err = core.RoundtableAttach(seats, bound.TurnMode, OriginDiscord, interaction.ChannelID, "discord "+interaction.ChannelID)
+		}
+		if err != nil {
+			d.respond(interaction, publicFailure("resetting the conversation", err))
+			return
+		}
+		components = v2Container(statusAccent,
+			discordgo.TextDisplay{Content: "✅ **Fresh conversation started**\nThis channel now has a clean context with " + seatList(seats) + "."})
+		d.updateInteraction(interaction, components)
+		return
+	}
 	target, err = d.instance(interaction.ChannelID)
 	if err != nil {
 		d.respond(interaction, publicFailure("looking up the agent", err))
@@ -258,17 +293,127 @@ func (d *Discord) updateInteraction(interaction *discordgo.InteractionCreate, co
 	})
 }
 
+func (d *Discord) regenerateInteraction(interaction *discordgo.InteractionCreate) {
+	var messageId string
+	var user *discordgo.User
+	var role string
+	var session *core.Session
+	var channelId string
+
+	var err error
+
+	messageId = strings.TrimPrefix(interaction.MessageComponentData().CustomID, "regen:")
+	user = interactionUser(interaction)
+	if user == nil {
+		return
+	}
+	role, err = d.role(user.ID)
+	if err != nil || role == "" {
+		d.respond(interaction, "You are not paired with this bot.")
+		return
+	}
+	channelId = interaction.ChannelID
+	session, err = d.latestAnswer(channelId, messageId)
+	if err != nil {
+		d.respond(interaction, publicFailure("looking up the answer", err))
+		return
+	}
+	if session == nil {
+		d.respond(interaction, "Only the latest answer can be regenerated.")
+		return
+	}
+
+	d.gateway.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{Components: []discordgo.MessageComponent{}, AllowedMentions: silentMentions()},
+	})
+	d.queueTurn(channelId, func() {
+		var ctx context.Context
+		var cancel context.CancelFunc
+
+		ctx, cancel = d.turnContext()
+		defer cancel()
+		d.regenerateFor(ctx, channelId, user.ID, role, messageId)
+	})
+}
+
+func keepHandoff(channelId string, target *core.NaruAgent) (string, error) {
+	var bound *core.Session
+
+	var err error
+
+	bound, err = core.SessionByExternal(OriginDiscord, channelId)
+	if err != nil {
+		return "", err
+	}
+
+	if bound == nil {
+		_, err = core.SessionAttach(target, OriginDiscord, channelId, "discord "+channelId)
+		if err != nil {
+			return "", err
+		}
+
+		return "This channel talks to " + target.Name + " now. There was no conversation to keep, so it starts fresh.", nil
+	}
+	if bound.TurnMode != "" {
+		return "This channel is a roundtable, use `/roundtable` to change who sits at it.", nil
+	}
+	if bound.AgentId == target.Id {
+		return "This channel already talks to " + target.Name + ".", nil
+	}
+
+	_, err = core.SessionHandoff(bound, target)
+	if err != nil {
+		return "", err
+	}
+
+	return "This channel talks to " + target.Name + " now, keeping the conversation.", nil
+}
+
+func (d *Discord) queueKeepHandoff(interaction *discordgo.InteractionCreate, channelId string, target *core.NaruAgent) {
+	var err error
+
+	err = d.gateway.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: "Handing the conversation off…", Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if err != nil {
+		return
+	}
+
+	d.queueTurn(channelId, func() {
+		var reply string
+
+		var err error
+
+		reply, err = keepHandoff(channelId, target)
+		if err != nil {
+			reply = publicFailure("handing the conversation off", err)
+		}
+		d.gateway.InteractionResponseEdit(interaction.Interaction, &discordgo.WebhookEdit{Content: &reply})
+	})
+}
+
 func (d *Discord) agentCommand(interaction *discordgo.InteractionCreate) {
 	var channelId string
-	var options []*discordgo.ApplicationCommandInteractionDataOption
+	var option *discordgo.ApplicationCommandInteractionDataOption
 	var target *core.Instance
 	var name string
+	var keep bool
 
 	var err error
 
 	channelId = interaction.ChannelID
-	options = interaction.ApplicationCommandData().Options
-	if len(options) == 0 {
+	for _, option = range interaction.ApplicationCommandData().Options {
+		if option.Name == "name" {
+			name = option.StringValue()
+		}
+		if option.Name == "keep" {
+			keep = option.BoolValue()
+		}
+	}
+
+	if name == "" {
 		target, err = d.instance(channelId)
 		if err != nil {
 			d.respond(interaction, publicFailure("looking up the agent", err))
@@ -278,13 +423,18 @@ func (d *Discord) agentCommand(interaction *discordgo.InteractionCreate) {
 		return
 	}
 
-	name = options[0].StringValue()
 	target, err = d.registry.Get(name)
 	if err != nil {
 		publicFailure("looking up the agent", err)
 		d.respond(interaction, "No agent by that name.")
 		return
 	}
+
+	if keep {
+		d.queueKeepHandoff(interaction, channelId, target.Agent)
+		return
+	}
+
 	_, err = core.SessionAttach(target.Agent, OriginDiscord, channelId, "discord "+channelId)
 	if err != nil {
 		d.respond(interaction, publicFailure("switching the agent", err))
@@ -293,6 +443,62 @@ func (d *Discord) agentCommand(interaction *discordgo.InteractionCreate) {
 	d.respond(interaction, "This channel talks to "+name+" now, starting fresh.")
 }
 
+func seatList(seats []*core.NaruAgent) string {
+	var names []string
+	var seat *core.NaruAgent
+
+	for _, seat = range seats {
+		names = append(names, "**"+seat.Name+"**")
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func (d *Discord) roundtableCommand(interaction *discordgo.InteractionCreate) {
+	var channelId string
+	var option *discordgo.ApplicationCommandInteractionDataOption
+	var names []string
+	var mode string
+	var name string
+	var target *core.Instance
+	var seats []*core.NaruAgent
+
+	var err error
+
+	channelId = interaction.ChannelID
+	mode = core.TurnRoundRobin
+	for _, option = range interaction.ApplicationCommandData().Options {
+		if option.Name == "agents" {
+			names = strings.Split(option.StringValue(), ",")
+		}
+		if option.Name == "mode" {
+			mode = option.StringValue()
+		}
+	}
+
+	for _, name = range names {
+		target, err = d.registry.Get(strings.TrimSpace(name))
+		if err != nil {
+			d.respond(interaction, "No agent named "+strings.TrimSpace(name)+".")
+			return
+		}
+
+		seats = append(seats, target.Agent)
+	}
+
+	err = core.RoundtableCheck(seats, mode)
+	if err != nil {
+		d.respond(interaction, "That table cannot be seated: "+err.Error()+".")
+		return
+	}
+	_, err = core.RoundtableAttach(seats, mode, OriginDiscord, channelId, "discord "+channelId)
+	if err != nil {
+		d.respond(interaction, publicFailure("seating the roundtable", err))
+		return
+	}
+	d.respond(interaction, "This channel seats "+seatList(seats)+" now, starting fresh. Address one with @name.")
+}
+
 func agentChoices(instances []*core.Instance, query string) []*discordgo.ApplicationCommandOptionChoice {
 	var normalized string
 	var instance *core.Instance
@@ -323,6 +529,7 @@ func (d *Discord) agentAutocomplete(interaction *discordgo.InteractionCreate) {
 	var data discordgo.ApplicationCommandInteractionData
 	var user *discordgo.User
 	var role string
+	var option *discordgo.ApplicationCommandInteractionDataOption
 	var query string
 	var choices []*discordgo.ApplicationCommandOptionChoice
 
@@ -333,8 +540,12 @@ func (d *Discord) agentAutocomplete(interaction *discordgo.InteractionCreate) {
 	if user != nil {
 		role, err = d.role(user.ID)
 	}
-	if err == nil && role != "" && data.Name == "agent" && len(data.Options) > 0 {
-		query = data.Options[0].StringValue()
+	if err == nil && role != "" && data.Name == "agent" {
+		for _, option = range data.Options {
+			if option.Name == "name" {
+				query = option.StringValue()
+			}
+		}
 		choices = agentChoices(d.registry.List(), query)
 	}
 	d.gateway.InteractionRespond(interaction.Interaction, &discordgo.InteractionResponse{
@@ -429,6 +640,9 @@ func (d *Discord) onInteraction(gateway *discordgo.Session, interaction *discord
 		if strings.HasPrefix(interaction.MessageComponentData().CustomID, "reset:") {
 			d.resetInteraction(interaction)
 		}
+		if strings.HasPrefix(interaction.MessageComponentData().CustomID, "regen:") {
+			d.regenerateInteraction(interaction)
+		}
 		return
 	}
 	if interaction.Type == discordgo.InteractionApplicationCommandAutocomplete {
@@ -474,6 +688,8 @@ func (d *Discord) onInteraction(gateway *discordgo.Session, interaction *discord
 		d.usageCommand(interaction, role)
 	case "agent":
 		d.agentCommand(interaction)
+	case "roundtable":
+		d.roundtableCommand(interaction)
 	case "mention":
 		d.mentionCommand(interaction, user)
 	case "user":
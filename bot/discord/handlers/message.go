@@ -5,6 +5,7 @@ package handlers
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"sync"
@@ -12,10 +13,10 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/devproje/mininaru/bot/discord/attachments"
+	"github.com/devproje/mininaru/config"
 	"github.com/devproje/mininaru/core"
 	"github.com/devproje/mininaru/modules"
 	"github.com/devproje/mininaru/util"
-	"github.com/openai/openai-go"
 )
 
 type conversationTarget struct {
@@ -64,6 +65,10 @@ func toolFailureReason(text string) string {
 	return reason
 }
 
+func roundtableReply(speaker, content string) string {
+	return "**" + speaker + "**\n" + content
+}
+
 func (d *Discord) addressed(message *discordgo.MessageCreate) (string, bool) {
 	var content string
 	var mention *discordgo.User
@@ -233,20 +238,75 @@ func (d *Discord) queueTurn(channelId string, run func()) {
 	}()
 }
 
+func statusHooks(status *executionStatus) (func(string), core.ToolEventFunc) {
+	var reasoningOnce sync.Once
+
+	return func(text string) {
+			reasoningOnce.Do(func() { status.progress("⚡", "Reasoning") })
+		}, func(event core.ToolEvent) {
+			var label string
+
+			label = core.ToolLabel(event.Name, event.Arguments)
+
+			if event.Phase == core.ToolEventStarted {
+				status.progress("🔧", "Running `"+label+"`")
+				return
+			}
+
+			if event.Phase == core.ToolEventOutput {
+				status.stream(event.Output)
+				return
+			}
+
+			if event.Status == core.MessageCompleted {
+				status.log("✓", "`"+label+"`")
+				return
+			}
+
+			status.log("✗", "`"+label+"` — "+toolFailureReason(event.Error))
+		}
+}
+
+func (d *Discord) turnTools(target *core.Instance, channelId, userId, role string) ([]modules.Def, core.ToolApprovalFunc, error) {
+	var home string
+	var defs []modules.Def
+
+	var err error
+
+	if role != core.DiscordRoleAdmin {
+		return target.Tools, nil, nil
+	}
+
+	home, err = os.UserHomeDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve home directory: %w", err)
+	}
+	defs, err = modules.DefaultToolsAt(home)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return defs, func(ctx context.Context, def modules.Def, arguments string) (bool, error) {
+		return d.approve(ctx, channelId, userId, def, arguments)
+	}, nil
+}
+
 func (d *Discord) answerFor(ctx context.Context, channelId, sourceChannelId, sourceMessageId, userId, role, content string,
 	sourceAttachments []*discordgo.MessageAttachment, note string) {
 	var target *core.Instance
 	var session *core.Session
 	var indicator *typing
 	var status *executionStatus
-	var parts []openai.ChatCompletionContentPartUnionParam
+	var attached []*core.Attachment
 	var onReasoning func(string)
-	var reasoningOnce sync.Once
 	var onTool core.ToolEventFunc
 	var defs []modules.Def
+	var approve core.ToolApprovalFunc
+	var messages []*core.Message
+	var speakers []string
+	var index int
 	var message *core.Message
 	var replyTo string
-	var home string
 
 	var err error
 
@@ -262,10 +322,11 @@ func (d *Discord) answerFor(ctx context.Context, channelId, sourceChannelId, sou
 		d.sendReply(channelId, conversationFailure("setting up the conversation", err))
 		return
 	}
+	d.trackSession(session.Id)
 	indicator = startTyping(d.gateway, channelId)
 	status = newExecutionStatus(d.gateway, channelId, sourceChannelId, sourceMessageId, note)
 	if len(sourceAttachments) > 0 {
-		parts, err = attachments.Build(ctx, content, sourceAttachments)
+		attached, err = attachments.Build(ctx, sourceAttachments)
 		if err != nil {
 			indicator.stop()
 			status.finish("❌", "Failed")
@@ -273,48 +334,33 @@ func (d *Discord) answerFor(ctx context.Context, channelId, sourceChannelId, sou
 			return
 		}
 	}
-	onReasoning = func(text string) {
-		reasoningOnce.Do(func() { status.progress("⚡", "Reasoning") })
-	}
-	onTool = func(event core.ToolEvent) {
-		var label string
-
-		label = core.ToolLabel(event.Name, event.Arguments)
-
-		if event.Phase == core.ToolEventStarted {
-			status.progress("🔧", "Running `"+label+"`")
-			return
-		}
-
-		if event.Status == core.MessageCompleted {
-			status.log("✓", "`"+label+"`")
-			return
-		}
-
-		status.log("✗", "`"+label+"` — "+toolFailureReason(event.Error))
+	onReasoning, onTool = statusHooks(status)
+	defs, approve, err = d.turnTools(target, channelId, userId, role)
+	if err != nil {
+		indicator.stop()
+		status.finish("❌", "Failed")
+		d.sendReplyTo(channelId, replyTo, conversationFailure("opening the tool workspace", err))
+		return
 	}
-	if role == core.DiscordRoleAdmin {
-		home, err = os.UserHomeDir()
-		if err != nil {
-			indicator.stop()
-			status.finish("❌", "Failed")
-			d.sendReplyTo(channelId, replyTo, conversationFailure("resolving the tool workspace", err))
-			return
+	if session.TurnMode != "" {
+		messages, err = d.registry.Roundtable(ctx, session, content, attached, defs, config.Client.Thinking.Level,
+			func(agent *core.NaruAgent) {
+				speakers = append(speakers, agent.Name)
+				status.progress("💬", "**"+agent.Name+"** is answering")
+			}, nil, onReasoning, onTool, approve)
+		indicator.stop()
+		for index = range messages {
+			d.sendReplyTo(channelId, replyTo, roundtableReply(speakers[index], messages[index].Content))
 		}
-		defs, err = modules.DefaultToolsAt(home)
 		if err != nil {
-			indicator.stop()
 			status.finish("❌", "Failed")
-			d.sendReplyTo(channelId, replyTo, conversationFailure("opening the tool workspace", err))
+			d.sendReplyTo(channelId, replyTo, conversationFailure("answering", err))
 			return
 		}
-		message, err = target.ChatInput(ctx, session, content, parts, defs, onReasoning, onTool,
-			func(ctx context.Context, def modules.Def, arguments string) (bool, error) {
-				return d.approve(ctx, channelId, userId, def, arguments)
-			})
-	} else {
-		message, err = target.ChatInput(ctx, session, content, parts, target.Tools, onReasoning, onTool, nil)
+		status.finish("✅", "Answered")
+		return
 	}
+	message, err = target.ChatInput(ctx, session, content, attached, defs, onReasoning, onTool, approve)
 	indicator.stop()
 	if err != nil {
 		status.finish("❌", "Failed")
@@ -322,7 +368,82 @@ func (d *Discord) answerFor(ctx context.Context, channelId, sourceChannelId, sou
 		return
 	}
 	status.finish("✅", "Answered")
-	d.sendReplyTo(channelId, replyTo, message.Content)
+	d.sendAnswer(channelId, replyTo, message)
+}
+
+func (d *Discord) latestAnswer(channelId, messageId string) (*core.Session, error) {
+	var session *core.Session
+	var history []*core.Message
+
+	var err error
+
+	session, err = core.SessionByExternal(OriginDiscord, channelId)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || session.TurnMode != "" {
+		return nil, nil
+	}
+
+	history, err = core.MessageList(session.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 || history[len(history)-1].Role != "assistant" || history[len(history)-1].Id != messageId {
+		return nil, nil
+	}
+
+	return session, nil
+}
+
+func (d *Discord) regenerateFor(ctx context.Context, channelId, userId, role, messageId string) {
+	var target *core.Instance
+	var session *core.Session
+	var indicator *typing
+	var status *executionStatus
+	var onReasoning func(string)
+	var onTool core.ToolEventFunc
+	var defs []modules.Def
+	var approve core.ToolApprovalFunc
+	var message *core.Message
+
+	var err error
+
+	target, err = d.instance(channelId)
+	if err != nil {
+		d.sendReply(channelId, conversationFailure("looking up the agent", err))
+		return
+	}
+	session, err = d.latestAnswer(channelId, messageId)
+	if err != nil {
+		d.sendReply(channelId, conversationFailure("looking up the answer", err))
+		return
+	}
+	if session == nil {
+		d.sendReply(channelId, "Only the latest answer can be regenerated.")
+		return
+	}
+	d.trackSession(session.Id)
+
+	indicator = startTyping(d.gateway, channelId)
+	status = newExecutionStatus(d.gateway, channelId, channelId, "", "")
+	onReasoning, onTool = statusHooks(status)
+	defs, approve, err = d.turnTools(target, channelId, userId, role)
+	if err != nil {
+		indicator.stop()
+		status.finish("❌", "Failed")
+		d.sendReply(channelId, conversationFailure("opening the tool workspace", err))
+		return
+	}
+	message, err = target.ChatRetry(ctx, session, "", defs, config.Client.Thinking.Level, nil, onReasoning, onTool, approve)
+	indicator.stop()
+	if err != nil {
+		status.finish("❌", "Failed")
+		d.sendReply(channelId, conversationFailure("regenerating the answer", err))
+		return
+	}
+	status.finish("✅", "Regenerated")
+	d.sendAnswer(channelId, "", message)
 }
 
 func (d *Discord) onMessage(gateway *discordgo.Session, message *discordgo.MessageCreate) {
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package handlers
+
+import (
+	"github.com/bwmarrin/discordgo"
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/util"
+)
+
+func reactionRating(emoji string) string {
+	switch emoji {
+	case "👍":
+		return core.RatingGood
+	case "👎":
+		return core.RatingBad
+	}
+
+	return ""
+}
+
+func ownReaction(gateway *discordgo.Session, userId string) bool {
+	return gateway != nil && gateway.State != nil && gateway.State.User != nil && gateway.State.User.ID == userId
+}
+
+func (d *Discord) reactionAnswer(userId, discordMessageId string) (string, *core.Rating, error) {
+	var role string
+	var messageId string
+	var current *core.Rating
+
+	var err error
+
+	role, err = d.role(userId)
+	if err != nil || role == "" {
+		return "", nil, err
+	}
+
+	messageId, err = core.DiscordAnswerMessage(discordMessageId)
+	if err != nil || messageId == "" {
+		return "", nil, err
+	}
+
+	current, err = core.MessageRating(messageId)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return messageId, current, nil
+}
+
+func (d *Discord) rateReaction(userId, discordMessageId, rating string, added bool) error {
+	var messageId string
+	var current *core.Rating
+	var note string
+
+	var err error
+
+	messageId, current, err = d.reactionAnswer(userId, discordMessageId)
+	if err != nil || messageId == "" {
+		return err
+	}
+
+	if current != nil {
+		note = current.Note
+	}
+	if !added {
+		if current == nil || current.Rating != rating {
+			return nil
+		}
+		rating = ""
+		note = ""
+	}
+
+	return core.MessageRate(messageId, rating, note)
+}
+
+func (d *Discord) onReactionAdd(gateway *discordgo.Session, reaction *discordgo.MessageReactionAdd) {
+	var rating string
+
+	var err error
+
+	rating = reactionRating(reaction.Emoji.Name)
+	if rating == "" || ownReaction(gateway, reaction.UserID) {
+		return
+	}
+
+	err = d.rateReaction(reaction.UserID, reaction.MessageID, rating, true)
+	if err != nil {
+		util.Log.Error("rating discord answer failed", "message", reaction.MessageID, "error", err)
+	}
+}
+
+func (d *Discord) onReactionRemove(gateway *discordgo.Session, reaction *discordgo.MessageReactionRemove) {
+	var rating string
+
+	var err error
+
+	rating = reactionRating(reaction.Emoji.Name)
+	if rating == "" || ownReaction(gateway, reaction.UserID) {
+		return
+	}
+
+	err = d.rateReaction(reaction.UserID, reaction.MessageID, rating, false)
+	if err != nil {
+		util.Log.Error("clearing discord answer rating failed", "message", reaction.MessageID, "error", err)
+	}
+}
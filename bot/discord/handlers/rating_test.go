@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package handlers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/util"
+)
+
+func TestReactionsRateOnlyForPairedUsers(t *testing.T) {
+	var bot Discord
+	var session *core.Session
+	var answer *core.Message
+	var rating *core.Rating
+
+	var err error
+
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot = Discord{cfg: Config{BotId: "bot"}}
+	err = core.DiscordUserAdd("bot", "paired", core.DiscordRoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err = core.SessionAttach(&core.NaruAgent{Id: "n"}, OriginDiscord, "c1", "discord c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err = core.MessageSave(session.Id, "assistant", "answer", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = core.DiscordAnswerSave("chunk-1", answer.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reactionRating("🎉") != "" || reactionRating("👍") != core.RatingGood || reactionRating("👎") != core.RatingBad {
+		t.Fatal("reaction emoji map is wrong")
+	}
+
+	err = bot.rateReaction("stranger", "chunk-1", core.RatingGood, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating != nil {
+		t.Fatalf("an unpaired reaction rated the answer: %+v, %v", rating, err)
+	}
+
+	err = core.MessageRate(answer.Id, core.RatingGood, "from the tui")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = bot.rateReaction("paired", "chunk-1", core.RatingBad, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating == nil || rating.Rating != core.RatingBad || rating.Note != "from the tui" {
+		t.Fatalf("rating after 👎 = %+v, %v", rating, err)
+	}
+
+	err = bot.rateReaction("paired", "chunk-1", core.RatingGood, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating == nil || rating.Rating != core.RatingBad {
+		t.Fatalf("removing a different reaction changed the rating: %+v, %v", rating, err)
+	}
+
+	err = bot.rateReaction("paired", "chunk-1", core.RatingBad, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating != nil {
+		t.Fatalf("removing 👎 left %+v, %v", rating, err)
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/util"
 )
 
 type typing struct {
@@ -168,12 +170,43 @@ func (d *Discord) sendReplyTo(channelId, replyToId, text string) {
 	}
 
 	chunks = splitReply(text, messageLimit)
-	d.sendChunks(channelId, replyToId, chunks)
+	d.sendChunks(channelId, replyToId, chunks, nil)
 }
 
-func (d *Discord) sendChunks(channelId, replyToId string, chunks []string) {
+func regenerateComponents(messageId string) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Regenerate", Style: discordgo.SecondaryButton, CustomID: "regen:" + messageId},
+		}},
+	}
+}
+
+func (d *Discord) sendAnswer(channelId, replyToId string, message *core.Message) {
+	var text string
+	var sentId string
+
+	var err error
+
+	text = message.Content
+	if strings.TrimSpace(text) == "" {
+		text = emptyReply
+	}
+
+	for _, sentId = range d.sendChunks(channelId, replyToId, splitReply(text, messageLimit), regenerateComponents(message.Id)) {
+		err = core.DiscordAnswerSave(sentId, message.Id)
+		if err != nil {
+			util.Log.Error("recording discord answer failed", "message", message.Id, "error", err)
+		}
+	}
+}
+
+func (d *Discord) sendChunks(channelId, replyToId string, chunks []string, components []discordgo.MessageComponent) []string {
 	var index int
 	var send *discordgo.MessageSend
+	var sent *discordgo.Message
+	var ids []string
+
+	var err error
 
 	for index = range chunks {
 		send = &discordgo.MessageSend{
@@ -183,7 +216,15 @@ func (d *Discord) sendChunks(channelId, replyToId string, chunks []string) {
 		if index == 0 && replyToId != "" {
 			send.Reference = &discordgo.MessageReference{MessageID: replyToId, ChannelID: channelId}
 		}
+		if index == len(chunks)-1 {
+			send.Components = components
+		}
 
-		d.gateway.ChannelMessageSendComplex(channelId, send)
+		sent, err = d.gateway.ChannelMessageSendComplex(channelId, send)
+		if err == nil {
+			ids = append(ids, sent.ID)
+		}
 	}
+
+	return ids
 }
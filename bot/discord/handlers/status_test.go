@@ -7,11 +7,14 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/bwmarrin/discordgo"
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/util"
 )
 
 func recordingGateway(t *testing.T, sent *[]string) *discordgo.Session {
@@ -226,6 +229,31 @@ func TestStatusFinishAddsTheFooterAndLeavesTheReplyAlone(t *testing.T) {
 	}
 }
 
+func TestStatusFlushesThrottledOutputBeforeFinishing(t *testing.T) {
+	var sent []string
+	var status executionStatus
+
+	status = executionStatus{
+		gateway: recordingGateway(t, &sent), channelId: "channel", messageId: "status",
+		stateIcon: "🔧", stateText: "Running `bash_exec`",
+	}
+	status.rendered = status.render()
+
+	status.stream("first line\n")
+	status.stream("last line\n")
+	if strings.Contains(strings.Join(sent, "\n"), "last line") {
+		t.Fatalf("output inside the throttle window was published early: %v", sent)
+	}
+
+	status.finish("✅", "Answered")
+	if len(sent) < 3 || !strings.Contains(sent[len(sent)-2], "last line") {
+		t.Fatalf("the pending tail was never published before finishing: %v", sent)
+	}
+	if strings.Contains(sent[len(sent)-1], "last line") {
+		t.Fatalf("the finished card still shows tool output: %s", sent[len(sent)-1])
+	}
+}
+
 func sentBody(t *testing.T, entry string) map[string]any {
 	var start int
 	var payload map[string]any
@@ -330,6 +358,51 @@ func TestOnlyTheFirstChunkRepliesToTheSource(t *testing.T) {
 	}
 }
 
+func TestOnlyTheLastChunkOfAnAnswerOffersRegenerate(t *testing.T) {
+	var sent []string
+	var bot Discord
+	var first, last map[string]any
+	var session *core.Session
+	var answer *core.Message
+	var messageId string
+
+	var err error
+
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err = core.SessionCreate(&core.NaruAgent{Id: "n"}, "answers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err = core.MessageSave(session.Id, "assistant", strings.Repeat("a", 2500), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bot = Discord{gateway: recordingGateway(t, &sent)}
+	bot.sendAnswer("channel", "source", answer)
+
+	if len(sent) != 2 {
+		t.Fatalf("requests = %d, want 2", len(sent))
+	}
+	first = sentBody(t, sent[0])
+	last = sentBody(t, sent[1])
+	if first["components"] != nil {
+		t.Fatalf("the first chunk carries controls: %v", first["components"])
+	}
+	if !strings.Contains(sent[1], `"custom_id":"regen:`+answer.Id+`"`) {
+		t.Fatalf("the last chunk has no regenerate button: %v", last["components"])
+	}
+
+	messageId, err = core.DiscordAnswerMessage("status")
+	if err != nil || messageId != answer.Id {
+		t.Fatalf("sent chunk maps to %q, %v", messageId, err)
+	}
+}
+
 func TestReplyTargetOnlyWhenTheSourceSharesTheChannel(t *testing.T) {
 	if replyTarget("channel", "channel", "source") != "source" {
 		t.Fatal("a same-channel answer should reply to the message")
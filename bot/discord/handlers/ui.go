@@ -28,6 +28,9 @@ type executionStatus struct {
 	stateText       string
 	steps           []statusStep
 	footer          string
+	output          string
+	outputAt        time.Time
+	outputPending   bool
 	rendered        string
 	started         time.Time
 	currentReaction string
@@ -47,6 +50,9 @@ const userAppDone = "done"
 const userAppFailed = "failed"
 
 const maxStatusSteps = 20
+const maxStatusOutputLines = 8
+const maxStatusOutput = 600
+const statusOutputInterval = 2 * time.Second
 
 const statusThinkingIcon = "⏳"
 const statusThinkingText = "Thinking"
@@ -147,6 +153,10 @@ func (s *executionStatus) render() string {
 		lines = append(lines, step.icon+" "+step.text)
 	}
 
+	if s.output != "" {
+		lines = append(lines, "```\n"+statusOutputTail(s.output)+"\n```")
+	}
+
 	if s.footer != "" {
 		lines = append(lines, "-# "+s.footer)
 	}
@@ -201,12 +211,31 @@ func (s *executionStatus) react(reaction string) {
 	s.currentReaction = reaction
 }
 
+func statusOutputTail(output string) string {
+	var lines []string
+	var runes []rune
+
+	lines = strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > maxStatusOutputLines {
+		lines = lines[len(lines)-maxStatusOutputLines:]
+	}
+	output = strings.ReplaceAll(strings.Join(lines, "\n"), "```", "`\u200b``")
+	runes = []rune(output)
+	if len(runes) > maxStatusOutput {
+		output = "…" + string(runes[len(runes)-maxStatusOutput:])
+	}
+
+	return output
+}
+
 func (s *executionStatus) progress(icon, text string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.flushOutput()
 	s.stateIcon = icon
 	s.stateText = text
+	s.output = ""
 	s.publish()
 	s.react(icon)
 }
@@ -215,9 +244,38 @@ func (s *executionStatus) log(icon, text string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.flushOutput()
 	s.steps = append(s.steps, statusStep{icon: icon, text: text})
 	s.stateIcon = statusThinkingIcon
 	s.stateText = statusThinkingText
+	s.output = ""
+	s.publish()
+}
+
+func (s *executionStatus) stream(chunk string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.output += chunk
+	if len(s.output) > 4*maxStatusOutput {
+		s.output = s.output[len(s.output)-4*maxStatusOutput:]
+	}
+	if time.Since(s.outputAt) < statusOutputInterval {
+		s.outputPending = true
+		return
+	}
+
+	s.outputAt = time.Now()
+	s.outputPending = false
+	s.publish()
+}
+
+func (s *executionStatus) flushOutput() {
+	if !s.outputPending {
+		return
+	}
+
+	s.outputPending = false
 	s.publish()
 }
 
@@ -240,8 +298,10 @@ func (s *executionStatus) finish(icon, text string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.flushOutput()
 	s.stateIcon = icon
 	s.stateText = text
+	s.output = ""
 	s.footer = statusFooter(time.Since(s.started), len(s.steps))
 	s.publish()
 	s.react(icon)
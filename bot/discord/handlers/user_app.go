@@ -122,7 +122,7 @@ func (d *Discord) runContextCommand(ctx context.Context, interaction *discordgo.
 	var view userAppPresentation
 	var prompt string
 	var defs []modules.Def
-	var parts []openai.ChatCompletionContentPartUnionParam
+	var attached []*core.Attachment
 	var components []discordgo.MessageComponent
 	var messages []openai.ChatCompletionMessageParamUnion
 	var result *core.Completion
@@ -133,7 +133,7 @@ func (d *Discord) runContextCommand(ctx context.Context, interaction *discordgo.
 	view = userAppView(title, true)
 	prompt, defs = contextPrompt(title)
 	if len(files) > 0 {
-		parts, err = attachments.Build(ctx, content, files)
+		attached, err = attachments.Build(ctx, files)
 		if err != nil {
 			publicFailure("reading the attachment", err)
 			components = userAppComponents(view, userAppFailed, "I could not read the attachment. Check the file and try again.")
@@ -142,11 +142,11 @@ func (d *Discord) runContextCommand(ctx context.Context, interaction *discordgo.
 			})
 			return
 		}
-		messages = []openai.ChatCompletionMessageParamUnion{openai.SystemMessage(prompt), openai.UserMessage(parts)}
+		messages = []openai.ChatCompletionMessageParamUnion{openai.SystemMessage(prompt), core.UserMessage(content, attached)}
 	} else {
 		messages = []openai.ChatCompletionMessageParamUnion{openai.SystemMessage(prompt), openai.UserMessage(content)}
 	}
-	result, err = core.Complete(ctx, target.Agent, messages, defs, "", nil, nil)
+	result, err = core.CompleteTurn(ctx, target.Agent, messages, defs, "", nil, nil)
 	if err != nil {
 		publicFailure("running that command", err)
 		components = userAppComponents(view, userAppFailed, "I could not finish this one-time request. Please try again.")
@@ -234,7 +234,7 @@ func (d *Discord) chatCommand(interaction *discordgo.InteractionCreate, user *di
 func (d *Discord) runStatelessChat(ctx context.Context, interaction *discordgo.InteractionCreate, target *core.Instance, content string,
 	files []*discordgo.MessageAttachment, private bool) {
 	var view userAppPresentation
-	var parts []openai.ChatCompletionContentPartUnionParam
+	var attached []*core.Attachment
 	var components []discordgo.MessageComponent
 	var messages []openai.ChatCompletionMessageParamUnion
 	var result *core.Completion
@@ -243,7 +243,7 @@ func (d *Discord) runStatelessChat(ctx context.Context, interaction *discordgo.I
 
 	view = userAppView("chat", private)
 	if len(files) > 0 {
-		parts, err = attachments.Build(ctx, content, files)
+		attached, err = attachments.Build(ctx, files)
 		if err != nil {
 			publicFailure("reading the attachment", err)
 			components = userAppComponents(view, userAppFailed, "I could not read the attachment. Check the file and try again.")
@@ -252,11 +252,11 @@ func (d *Discord) runStatelessChat(ctx context.Context, interaction *discordgo.I
 			})
 			return
 		}
-		messages = []openai.ChatCompletionMessageParamUnion{openai.UserMessage(parts)}
+		messages = []openai.ChatCompletionMessageParamUnion{core.UserMessage(content, attached)}
 	} else {
 		messages = []openai.ChatCompletionMessageParamUnion{openai.UserMessage(content)}
 	}
-	result, err = core.Complete(ctx, target.Agent, messages, nil, "", nil, nil)
+	result, err = core.CompleteTurn(ctx, target.Agent, messages, nil, "", nil, nil)
 	if err != nil {
 		publicFailure("answering", err)
 		components = userAppComponents(view, userAppFailed, "I could not finish this one-time request. Please try again.")
@@ -34,3 +34,27 @@ func TestBootstrapSkippedForVersion(t *testing.T) {
 		t.Fatalf("--version created %d entries under the data root, want 0", len(entries))
 	}
 }
+
+func TestBootstrapSkippedForDoctor(t *testing.T) {
+	var dir string
+	var entries []os.DirEntry
+
+	var err error
+
+	dir = t.TempDir()
+
+	t.Setenv("NARU_PATH", dir+"/data")
+
+	err = bootstrapExecute(doctorCmd, nil)
+	if err != nil {
+		t.Fatalf("bootstrapExecute returned %v", err)
+	}
+
+	entries, err = os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("doctor created %d entries under the data root before checking it, want 0", len(entries))
+	}
+}
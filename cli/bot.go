@@ -215,7 +215,7 @@ func botListExecute(cmd *cobra.Command, args []string) error {
 	rows = uiTable("ID", "NAME", "KIND", "TOKEN", "AGENT", "STATE")
 
 	for _, cur = range core.Bots {
-		rows.row(cur.Id, cur.Name, cur.Kind, maskSecret(cur.Token), botAgentLabel(cur.Agent), botState(cur.Enabled))
+		rows.row(cur.Id, cur.Name, cur.Kind, secretLabel(cur.Token, cur.TokenRef()), botAgentLabel(cur.Agent), botState(cur.Enabled))
 	}
 
 	rows.flush()
@@ -312,12 +312,12 @@ func botDisableExecute(cmd *cobra.Command, args []string) error {
 func init() {
 	botAdd.Flags().StringVarP(&botNameRef, "name", "n", "", "bot name")
 	botAdd.Flags().StringVarP(&botKindRef, "kind", "k", core.BotDiscord, "bot kind, currently only discord")
-	botAdd.Flags().StringVarP(&botTokenRef, "token", "t", "", "bot token")
+	botAdd.Flags().StringVarP(&botTokenRef, "token", "t", "", "bot token, or an env:, file:, or cmd: reference")
 	botAdd.Flags().StringVarP(&botAgentRef, "agent", "a", "", "agent new channels talk to, defaults to the global agent")
 	botAdd.Flags().StringVarP(&botGuildRef, "guild", "g", "", "register slash commands to this guild only, which applies them instantly")
 
 	botUpdate.Flags().StringVarP(&botNameRef, "name", "n", "", "bot name")
-	botUpdate.Flags().StringVarP(&botTokenRef, "token", "t", "", "bot token")
+	botUpdate.Flags().StringVarP(&botTokenRef, "token", "t", "", "bot token, or an env:, file:, or cmd: reference")
 	botUpdate.Flags().StringVarP(&botAgentRef, "agent", "a", "", "agent new channels talk to, pass an empty value to fall back to the global agent")
 	botUpdate.Flags().StringVarP(&botGuildRef, "guild", "g", "", "guild id for slash command registration")
 
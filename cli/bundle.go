@@ -0,0 +1,277 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/devproje/mininaru/core"
+	"github.com/spf13/cobra"
+)
+
+var agentExport *cobra.Command = &cobra.Command{
+	Use:   "export <id or name>",
+	Short: "package an agent into a shareable bundle",
+	Long: `Write an agent to a single .tar.gz bundle another mininaru can import.
+
+The bundle holds the role, soul, model, context and sampling settings, plus every
+skill the role or soul mentions by name. Add more skills with --skill and MCP
+server entries with --mcp. API keys never leave this machine, and MCP env and
+header values are written empty.`,
+	Example: `  mininaru agent export reviewer
+  mininaru agent export reviewer -o reviewer.tar.gz --skill pr-review --mcp github`,
+	Args:    usageArgs(cobra.ExactArgs(1)),
+	PreRunE: mcpLoadExecute,
+	RunE:    agentExportExecute,
+}
+
+var agentImport *cobra.Command = &cobra.Command{
+	Use:   "import <file>",
+	Short: "add an agent from a bundle",
+	Long: `Add the agent, skills, and MCP servers packaged by agent export.
+
+The agent needs one of your providers. On a terminal you pick it from a list,
+starting from the one that matches the bundle; otherwise --provider names it or
+the closest match is used. An agent name that is taken is an error unless
+--name picks another or --replace overwrites it. Existing skills and MCP servers
+are kept unless --replace is passed. Imported MCP servers with empty env or
+header values stay disabled until you fill them in.`,
+	Example: `  mininaru agent import reviewer.tar.gz
+  mininaru agent import reviewer.tar.gz --name reviewer-2 --provider openrouter`,
+	Args:    usageArgs(cobra.ExactArgs(1)),
+	PreRunE: mcpLoadExecute,
+	RunE:    agentImportExecute,
+}
+
+var bundleOutputRef string
+var bundleSkillsRef []string
+var bundleServersRef []string
+var bundleNameRef string
+var bundleProviderRef string
+var bundleReplaceRef bool
+var bundleScopeRef string
+
+func agentExportExecute(cmd *cobra.Command, args []string) error {
+	var target *core.NaruAgent
+	var bundle *core.AgentBundle
+	var path string
+	var file *os.File
+	var out io.Writer
+
+	var err error
+
+	target, err = core.AgentByName(args[0])
+	if err != nil {
+		return err
+	}
+
+	bundle, err = core.AgentBundleNew(target, bundleSkillsRef, bundleServersRef)
+	if err != nil {
+		return err
+	}
+
+	path = bundleOutputRef
+	if path == "" {
+		path = target.Name + ".tar.gz"
+	}
+
+	out = os.Stdout
+	if path != "-" {
+		file, err = os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			return usageErrorf("%s already exists, pass -o to write somewhere else", path)
+		}
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		out = file
+	}
+
+	err = core.AgentBundleWrite(out, bundle)
+	if err != nil {
+		return err
+	}
+
+	if path == "-" {
+		return nil
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	uiOk("exported agent %s to %s", target.Name, path)
+	if len(bundle.Skills) > 0 {
+		uiNote("skills: %s", strings.Join(bundle.Skills, ", "))
+	}
+
+	return nil
+}
+
+func bundleProviderMatch(hint core.BundleProvider) *core.Provider {
+	var cur *core.Provider
+
+	for _, cur = range core.Providers {
+		if hint.BaseURL != "" && strings.TrimRight(cur.BaseURL, "/") == strings.TrimRight(hint.BaseURL, "/") {
+			return cur
+		}
+	}
+
+	for _, cur = range core.Providers {
+		if hint.Name != "" && cur.Name == hint.Name {
+			return cur
+		}
+	}
+
+	return core.DefaultProvider
+}
+
+func bundleProvider(bundle *core.AgentBundle) (*core.Provider, error) {
+	var match *core.Provider
+	var fallback string
+	var picked string
+
+	var err error
+
+	if bundleProviderRef != "" {
+		return core.ProviderFind(bundleProviderRef)
+	}
+
+	if len(core.Providers) == 0 {
+		return nil, configErrorf("no provider configured, add one with `mininaru provider add` first")
+	}
+
+	match = bundleProviderMatch(bundle.Provider)
+	if match != nil {
+		fallback = match.Name
+	}
+
+	if !askInteractive() {
+		if match == nil {
+			return nil, usageErrorf("no provider matches the bundle, pass --provider")
+		}
+
+		uiNote("using provider %s", match.Name)
+		return match, nil
+	}
+
+	if bundle.Provider.Name != "" {
+		uiNote("the bundle was made with provider %s at %s", bundle.Provider.Name, bundle.Provider.BaseURL)
+	}
+
+	picked, err = askChoice("provider", providerNames(), fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.ProviderFind(picked)
+}
+
+func bundleName(bundle *core.AgentBundle) (string, error) {
+	var name string
+
+	var err error
+
+	name = bundleNameRef
+	if name == "" {
+		name = bundle.Agent.Name
+	}
+
+	_, err = core.AgentByName(name)
+	if err != nil || bundleReplaceRef {
+		return name, nil
+	}
+
+	if !askInteractive() {
+		return "", usageErrorf("agent %s already exists, pass --name to import it under another name or --replace to overwrite it", name)
+	}
+
+	name, err = askRequired(fmt.Sprintf("agent %s already exists, import as", name))
+	if err != nil {
+		return "", err
+	}
+
+	_, err = core.AgentByName(name)
+	if err == nil {
+		return "", usageErrorf("agent %s already exists too", name)
+	}
+
+	return name, nil
+}
+
+func agentImportExecute(cmd *cobra.Command, args []string) error {
+	var file *os.File
+	var bundle *core.AgentBundle
+	var name string
+	var prov *core.Provider
+	var imported *core.AgentImported
+	var server string
+
+	var err error
+
+	file, err = os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	bundle, err = core.AgentBundleRead(file)
+	if err != nil {
+		return err
+	}
+
+	name, err = bundleName(bundle)
+	if err != nil {
+		return err
+	}
+
+	prov, err = bundleProvider(bundle)
+	if err != nil {
+		return err
+	}
+
+	imported, err = core.AgentBundleImport(bundle, name, prov, bundleReplaceRef, bundleScopeRef)
+	if err != nil {
+		return err
+	}
+
+	if imported.Replaced {
+		uiOk("replaced agent %s using provider %s", imported.Agent.Name, prov.Name)
+	} else {
+		uiOk("imported agent %s using provider %s", imported.Agent.Name, prov.Name)
+	}
+	if len(imported.Skills) > 0 {
+		uiOk("installed skills: %s", strings.Join(imported.Skills, ", "))
+	}
+	if len(imported.Servers) > 0 {
+		uiOk("added mcp servers: %s", strings.Join(imported.Servers, ", "))
+	}
+	if len(imported.Kept) > 0 {
+		uiNote("kept existing %s, pass --replace to overwrite", strings.Join(imported.Kept, ", "))
+	}
+	for _, server = range imported.Disabled {
+		uiNote("mcp server %s needs its env or header values in mcp.json, then `mininaru mcp enable %s`", server, server)
+	}
+
+	return nil
+}
+
+func init() {
+	agentExport.Flags().StringVarP(&bundleOutputRef, "output", "o", "", "bundle path, - for stdout, defaults to <name>.tar.gz")
+	agentExport.Flags().StringArrayVar(&bundleSkillsRef, "skill", nil, "also package this skill, repeatable")
+	agentExport.Flags().StringArrayVar(&bundleServersRef, "mcp", nil, "also package this mcp server entry without secrets, repeatable")
+
+	agentImport.Flags().StringVarP(&bundleNameRef, "name", "n", "", "import the agent under this name")
+	agentImport.Flags().StringVarP(&bundleProviderRef, "provider", "p", "", "provider name or id the agent should use")
+	agentImport.Flags().BoolVar(&bundleReplaceRef, "replace", false, "overwrite an agent, skills, and mcp servers of the same name")
+	agentImport.Flags().StringVar(&bundleScopeRef, "skill-scope", "project", "where to install skills, project or user")
+
+	agent.AddCommand(agentExport, agentImport)
+}
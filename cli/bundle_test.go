@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/util"
+)
+
+func bundleCommandSetup(t *testing.T) {
+	var err error
+
+	t.Helper()
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	core.Providers = nil
+	core.Agents = nil
+	core.ProviderCreate(core.Provider{Name: "work", BaseURL: "https://api.openai.com/v1", ApiKey: "k"})
+	core.ProviderCreate(core.Provider{Name: "local", BaseURL: "http://localhost:11434/v1", ApiKey: "k"})
+	core.DefaultProvider = core.Providers[0]
+	core.Global = core.AgentNew("reviewer", "", "", "m", core.Providers[0])
+
+	bundleNameRef = ""
+	bundleProviderRef = ""
+	bundleReplaceRef = false
+}
+
+func TestBundleProviderPrefersTheSameEndpoint(t *testing.T) {
+	var bundle core.AgentBundle
+	var picked *core.Provider
+
+	var err error
+
+	bundleCommandSetup(t)
+	askInteractive = func() bool { return false }
+	t.Cleanup(func() { askInteractive = terminalSession })
+
+	bundle.Provider = core.BundleProvider{Name: "ollama", BaseURL: "http://localhost:11434/v1/"}
+	picked, err = bundleProvider(&bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if picked.Name != "local" {
+		t.Fatalf("picked %s, want the provider with the same base url", picked.Name)
+	}
+}
+
+func TestBundleProviderAsksOnATerminal(t *testing.T) {
+	var bundle core.AgentBundle
+	var picked *core.Provider
+
+	var err error
+
+	bundleCommandSetup(t)
+	fakeSession(t, "2\n")
+
+	bundle.Provider = core.BundleProvider{Name: "work"}
+	picked, err = bundleProvider(&bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if picked.Name != "local" {
+		t.Fatalf("picked %s, want the second choice", picked.Name)
+	}
+}
+
+func TestBundleNameConflicts(t *testing.T) {
+	var bundle core.AgentBundle
+	var name string
+
+	var err error
+
+	bundleCommandSetup(t)
+	bundle.Agent.Name = "reviewer"
+
+	askInteractive = func() bool { return false }
+	t.Cleanup(func() { askInteractive = terminalSession })
+	_, err = bundleName(&bundle)
+	if err == nil || !strings.Contains(err.Error(), "--name") {
+		t.Fatalf("err = %v, want a hint about --name", err)
+	}
+
+	fakeSession(t, "reviewer-2\n")
+	name, err = bundleName(&bundle)
+	if err != nil || name != "reviewer-2" {
+		t.Fatalf("name = %q, %v", name, err)
+	}
+}
@@ -4,10 +4,15 @@
 package main
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/devproje/mininaru/config"
 	"github.com/spf13/cobra"
 )
 
+var contextKeepRef int
+
 var contextConfig *cobra.Command = &cobra.Command{
 	Use:   "context",
 	Short: "show context management settings",
@@ -17,7 +22,8 @@ The TUI reads actual input-token usage from provider responses. When the
 provider exposes its model context window, mininaru uses that value for the
 status display and automatic compaction threshold.`,
 	Example: `  mininaru context
-	  mininaru context compact off`,
+	  mininaru context compact off
+	  mininaru context strategy elide window summarize`,
 	Args: usageArgs(cobra.NoArgs),
 	RunE: contextExecute,
 }
@@ -27,17 +33,35 @@ var contextCompactCmd *cobra.Command = &cobra.Command{
 	Short: "show or set whether older turns are summarised before they leave",
 	Long: `Show whether compaction is on, or turn it on or off.
 
-With compaction on, completed turns are summarised into a running summary when
-provider-reported input usage reaches 90% of a known model context window. This
-costs one extra model call. With it off mininaru does not compact automatically
-or enforce a separate local history limit. A summary already saved for a
-conversation keeps being used either way.`,
+With compaction on, the strategies picked with context strategy make room when
+the conversation reaches 90% of a known model context window. Only summarize
+costs an extra model call. With it off mininaru does not compact automatically
+or enforce a separate local history limit. A summary or elision already saved
+for a conversation keeps being used either way.`,
 	Example: `  mininaru context compact
   mininaru context compact off`,
 	Args: usageArgs(cobra.MaximumNArgs(1)),
 	RunE: contextCompactExecute,
 }
 
+var contextStrategyCmd *cobra.Command = &cobra.Command{
+	Use:   "strategy [elide|window|summarize]...",
+	Short: "show or set how compaction makes room",
+	Long: `Show the compaction strategies, or choose which ones run.
+
+elide replaces old tool results with a short note carrying the result id, which
+the model can read back with the tool_result tool. window drops turns older
+than the last --keep-turns without a model call. summarize folds everything into
+the running summary with one extra model call. The chosen strategies always run
+cheapest first, and compaction stops as soon as the conversation fits again.
+An agent can override the choice with agent update --context-strategy.`,
+	Example: `  mininaru context strategy
+  mininaru context strategy elide summarize
+  mininaru context strategy window --keep-turns 8`,
+	Args: usageArgs(cobra.ArbitraryArgs),
+	RunE: contextStrategyExecute,
+}
+
 func compactState() string {
 	if config.Client.Context.Compact {
 		return "on"
@@ -47,7 +71,8 @@ func compactState() string {
 }
 
 func contextExecute(cmd *cobra.Command, args []string) error {
-	uiOk("compact %s", compactState())
+	uiOk("compact %s, strategy %s, keep %d turns", compactState(),
+		strings.Join(config.ContextStrategy(), ","), config.ContextKeepTurns())
 
 	return nil
 }
@@ -84,6 +109,49 @@ func contextCompactExecute(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func contextStrategyExecute(cmd *cobra.Command, args []string) error {
+	var rows *uiRows
+	var strategy []string
+
+	var err error
+
+	if len(args) == 0 && !cmd.Flags().Changed("keep-turns") {
+		rows = uiTable("STRATEGY", "KEEP TURNS")
+		rows.row(strings.Join(config.ContextStrategy(), ","), strconv.Itoa(config.ContextKeepTurns()))
+		rows.flush()
+
+		return nil
+	}
+
+	if cmd.Flags().Changed("keep-turns") {
+		if contextKeepRef <= 0 {
+			return usageErrorf("keep-turns must be a positive number of turns")
+		}
+
+		config.Client.Context.KeepTurns = contextKeepRef
+	}
+
+	if len(args) > 0 {
+		strategy, err = config.CompactStrategyOrder(args)
+		if err != nil {
+			return usageErrorf("%v", err)
+		}
+
+		config.Client.Context.Strategy = strategy
+	}
+
+	err = config.ClientSave()
+	if err != nil {
+		return err
+	}
+
+	uiOk("strategy %s, keep %d turns", strings.Join(config.ContextStrategy(), ","), config.ContextKeepTurns())
+
+	return nil
+}
+
 func init() {
-	contextConfig.AddCommand(contextCompactCmd)
+	contextStrategyCmd.Flags().IntVar(&contextKeepRef, "keep-turns", 0, "turns kept verbatim by the elide and window strategies")
+
+	contextConfig.AddCommand(contextCompactCmd, contextStrategyCmd)
 }
@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 
@@ -264,6 +265,10 @@ func daemonActiveState(ctx context.Context) string {
 	return strings.TrimSpace(string(out))
 }
 
+func daemonRunning(ctx context.Context) bool {
+	return runtime.GOOS == "linux" && daemonActiveState(ctx) == "active"
+}
+
 func daemonInstalled() (bool, error) {
 	var unitPath string
 
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"io"
+	"os"
+
+	"github.com/devproje/mininaru/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	datasetRatedRef  string
+	datasetFormatRef string
+	datasetOutputRef string
+)
+
+var datasetConfig *cobra.Command = &cobra.Command{
+	Use:   "dataset",
+	Short: "export rated conversations for fine-tuning and evals",
+}
+
+var datasetExportCmd *cobra.Command = &cobra.Command{
+	Use:   "export",
+	Short: "write rated answers as one conversation per line",
+	Long: `Write every rated answer as the conversation that led up to it, ending
+with the rated reply. Tool calls and their results are replayed the same way
+the model saw them. The system prompt is left out because it is rebuilt for
+every turn.
+
+Answers are rated with ctrl+g or /rate in the TUI, a 👍 or 👎 reaction in
+Discord, or the RateMessage gRPC call. This reads the database on this machine,
+so run it where serve runs.`,
+	Example: `  mininaru dataset export --rated good > good.jsonl
+  mininaru dataset export --rated bad -o bad.jsonl`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: datasetExportExecute,
+}
+
+func datasetExportExecute(cmd *cobra.Command, args []string) error {
+	var filter core.DatasetFilter
+	var out io.Writer
+	var file *os.File
+	var written int
+
+	var err error
+
+	switch datasetRatedRef {
+	case "any":
+	case core.RatingGood, core.RatingBad:
+		filter.Rated = datasetRatedRef
+	default:
+		return usageErrorf("--rated must be good, bad or any, got %q", datasetRatedRef)
+	}
+	if datasetFormatRef != core.DatasetOpenAI {
+		return usageErrorf("--format must be %s, got %q", core.DatasetOpenAI, datasetFormatRef)
+	}
+
+	out = os.Stdout
+	if datasetOutputRef != "" && datasetOutputRef != "-" {
+		file, err = os.Create(datasetOutputRef)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	written, err = core.DatasetExport(out, filter, datasetFormatRef)
+	if err != nil {
+		return err
+	}
+
+	if file != nil {
+		err = file.Close()
+		if err != nil {
+			return err
+		}
+		uiOk("wrote %d conversations to %s", written, datasetOutputRef)
+		return nil
+	}
+	if written == 0 {
+		uiEmpty("no rated answers match")
+	}
+
+	return nil
+}
+
+func init() {
+	datasetExportCmd.Flags().StringVar(&datasetRatedRef, "rated", "any", "which answers to export: good, bad or any")
+	datasetExportCmd.Flags().StringVar(&datasetFormatRef, "format", core.DatasetOpenAI, "output format")
+	datasetExportCmd.Flags().StringVarP(&datasetOutputRef, "output", "o", "", "write to this file instead of stdout")
+
+	datasetConfig.AddCommand(datasetExportCmd)
+}
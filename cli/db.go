@@ -0,0 +1,274 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/core"
+	mininarurpc "github.com/devproje/mininaru/rpc"
+	"github.com/devproje/mininaru/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dbSessionDaysRef int
+	dbPerAgentRef    int
+	dbUsageDaysRef   int
+)
+
+var dbConfig *cobra.Command = &cobra.Command{
+	Use:   "db",
+	Short: "back up, restore, prune and compact mininaru.db",
+	Long: `Maintain the SQLite database that holds sessions, messages and usage.
+
+backup is safe while serve is running. restore replaces every session with the
+contents of a backup, so stop the daemon first. The retention policy is off
+until you set it; serve applies it on startup and every few hours after that.`,
+	Example: `  mininaru db backup ~/mininaru-$(date +%F).db
+  mininaru db retention --session-days 90 --usage-days 365
+  mininaru db vacuum`,
+}
+
+var dbBackupCmd *cobra.Command = &cobra.Command{
+	Use:   "backup <file>",
+	Short: "copy the database to a new file while it stays in use",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  dbBackupExecute,
+}
+
+var dbRestoreCmd *cobra.Command = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "replace the database with a backup",
+	Long: `Replace the contents of mininaru.db with a file made by db backup.
+
+The backup is checked for integrity first and migrated to the current schema
+after it is copied in. Everything written since the backup was taken is lost.`,
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: dbRestoreExecute,
+}
+
+var dbVacuumCmd *cobra.Command = &cobra.Command{
+	Use:   "vacuum",
+	Short: "rebuild the database file to give deleted space back to the disk",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  dbVacuumExecute,
+}
+
+var dbPruneCmd *cobra.Command = &cobra.Command{
+	Use:   "prune",
+	Short: "apply the retention policy now",
+	Args:  usageArgs(cobra.NoArgs),
+	RunE:  dbPruneExecute,
+}
+
+var dbRetentionCmd *cobra.Command = &cobra.Command{
+	Use:   "retention",
+	Short: "show or set how long sessions and usage records are kept",
+	Long: `Show the retention policy, or change it with the flags. 0 keeps forever.
+
+--session-days deletes sessions with no message for that many days.
+--sessions-per-agent keeps only that many of each agent's most recently active
+sessions. Pinned sessions are never deleted. --usage-days drops token usage and skill use records older than that.
+Expired gRPC pairing requests are always removed.`,
+	Example: `  mininaru db retention
+  mininaru db retention --sessions-per-agent 200
+  mininaru db retention --session-days 0`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: dbRetentionExecute,
+}
+
+func dbSize() int64 {
+	var info os.FileInfo
+	var size int64
+	var suffix string
+
+	var err error
+
+	for _, suffix = range []string{"", "-wal"} {
+		info, err = os.Stat(util.Path("mininaru.db" + suffix))
+		if err == nil {
+			size += info.Size()
+		}
+	}
+
+	return size
+}
+
+func dbDaysLabel(days int) string {
+	if days <= 0 {
+		return "forever"
+	}
+
+	return strconv.Itoa(days) + " days"
+}
+
+func dbCountLabel(count int) string {
+	if count <= 0 {
+		return "all"
+	}
+
+	return strconv.Itoa(count)
+}
+
+func retentionRun() (*core.RetentionResult, int64, error) {
+	var result *core.RetentionResult
+	var pairings int64
+
+	var err error
+
+	result, err = core.RetentionApply(config.Client.Retention)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pairings, err = mininarurpc.PairingPrune()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, pairings, nil
+}
+
+func dbBackupExecute(cmd *cobra.Command, args []string) error {
+	var path string
+
+	var err error
+
+	path, err = filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+
+	err = util.DatabaseBackup(util.DB, path)
+	if err != nil {
+		return err
+	}
+
+	uiOk("backed up to %s", path)
+
+	return nil
+}
+
+func dbRestoreExecute(cmd *cobra.Command, args []string) error {
+	var confirmed bool
+
+	var err error
+
+	if daemonRunning(cmd.Context()) {
+		return configErrorf("the daemon is running, stop it with `systemctl --user stop %s` before restoring", daemonUnitName)
+	}
+
+	if askInteractive() {
+		confirmed, err = askConfirm("replace every session with "+args[0], false)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			uiNote("restore cancelled")
+			return nil
+		}
+	}
+
+	err = util.DatabaseRestore(util.DB, args[0])
+	if err != nil {
+		return err
+	}
+
+	uiOk("restored from %s", args[0])
+
+	return nil
+}
+
+func dbVacuumExecute(cmd *cobra.Command, args []string) error {
+	var before int64
+
+	var err error
+
+	before = dbSize()
+
+	err = withProgress(cmd.Context(), "vacuuming mininaru.db", func() error {
+		return util.DatabaseVacuum(util.DB)
+	})
+	if err != nil {
+		return err
+	}
+
+	uiOk("mininaru.db %d -> %d bytes", before, dbSize())
+
+	return nil
+}
+
+func dbPruneExecute(cmd *cobra.Command, args []string) error {
+	var result *core.RetentionResult
+	var pairings int64
+
+	var err error
+
+	if !config.RetentionEnabled() {
+		uiNote("no retention policy set, only expired pairing requests are removed")
+	}
+
+	result, pairings, err = retentionRun()
+	if err != nil {
+		return err
+	}
+
+	uiOk("removed %d sessions, %d usage records, %d skill uses and %d pairing requests",
+		result.Sessions, result.Usage, result.SkillUses, pairings)
+	uiNote("run `mininaru db vacuum` to give the space back to the disk")
+
+	return nil
+}
+
+func dbRetentionExecute(cmd *cobra.Command, args []string) error {
+	var rows *uiRows
+	var policy *config.Retention
+	var changed bool
+
+	var err error
+
+	policy = &config.Client.Retention
+
+	if cmd.Flags().Changed("session-days") {
+		policy.SessionDays = max(dbSessionDaysRef, 0)
+		changed = true
+	}
+	if cmd.Flags().Changed("sessions-per-agent") {
+		policy.SessionsPerAgent = max(dbPerAgentRef, 0)
+		changed = true
+	}
+	if cmd.Flags().Changed("usage-days") {
+		policy.UsageDays = max(dbUsageDaysRef, 0)
+		changed = true
+	}
+
+	if changed {
+		err = config.ClientSave()
+		if err != nil {
+			return err
+		}
+	}
+
+	rows = uiTable("SESSIONS", "PER AGENT", "USAGE")
+	rows.row(dbDaysLabel(policy.SessionDays), dbCountLabel(policy.SessionsPerAgent), dbDaysLabel(policy.UsageDays))
+	rows.flush()
+
+	if changed && daemonRunning(cmd.Context()) {
+		uiNote("run `mininaru daemon reload` so the daemon uses the new policy")
+	}
+
+	return nil
+}
+
+func init() {
+	dbRetentionCmd.Flags().IntVar(&dbSessionDaysRef, "session-days", 0, "delete sessions idle for this many days, 0 keeps them")
+	dbRetentionCmd.Flags().IntVar(&dbPerAgentRef, "sessions-per-agent", 0, "keep only this many recent sessions per agent, 0 keeps all")
+	dbRetentionCmd.Flags().IntVar(&dbUsageDaysRef, "usage-days", 0, "delete token and skill usage older than this many days, 0 keeps it")
+
+	dbConfig.AddCommand(dbBackupCmd, dbRestoreCmd, dbVacuumCmd, dbPruneCmd, dbRetentionCmd)
+}
@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/util"
+)
+
+func TestDbRetentionSavesOnlyTheFlagsGiven(t *testing.T) {
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = config.ClientInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Client.Retention = config.Retention{SessionDays: 90, UsageDays: 30}
+
+	err = dbRetentionCmd.Flags().Set("sessions-per-agent", "50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbRetentionCmd.Flags().Lookup("sessions-per-agent").Changed = false })
+
+	dbRetentionCmd.SetContext(context.Background())
+	err = dbRetentionExecute(dbRetentionCmd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.ClientInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Client.Retention != (config.Retention{SessionDays: 90, SessionsPerAgent: 50, UsageDays: 30}) {
+		t.Fatalf("retention = %+v", config.Client.Retention)
+	}
+	if dbDaysLabel(0) != "forever" || dbCountLabel(0) != "all" {
+		t.Fatal("an unset limit is not shown as keeping everything")
+	}
+}
@@ -0,0 +1,499 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/rpc"
+	"github.com/devproje/mininaru/util"
+	"github.com/spf13/cobra"
+)
+
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Fix    string `json:"fix,omitempty"`
+}
+
+type doctorReport struct {
+	Checks []doctorCheck `json:"checks"`
+	Failed int           `json:"failed"`
+	Warned int           `json:"warned"`
+}
+
+const (
+	doctorOk   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+	doctorSkip = "skip"
+)
+
+const (
+	doctorTimeout    = 10 * time.Second
+	doctorCertWindow = 30 * 24 * time.Hour
+)
+
+var doctorJSONRef bool
+
+var doctorCmd *cobra.Command = &cobra.Command{
+	Use:   "doctor",
+	Short: "check the data directory, database, providers and services for problems",
+	Long: `Check everything mininaru depends on and say how to fix what is broken.
+
+doctor does not stop at the first problem. It checks the data directory, the
+database and its migrations, every config file, each provider's model list, each
+agent's model and context window, each mcp server, each language server command,
+the skills, the certificates under pki/, and the systemd daemon. It exits non-zero when any check fails;
+warnings alone exit cleanly.`,
+	Example: `  mininaru doctor
+  mininaru doctor --json | jq '.checks[] | select(.status != "ok")'`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: doctorExecute,
+}
+
+func (r *doctorReport) add(name, status, detail, fix string) {
+	r.Checks = append(r.Checks, doctorCheck{Name: name, Status: status, Detail: detail, Fix: fix})
+
+	switch status {
+	case doctorFail:
+		r.Failed++
+	case doctorWarn:
+		r.Warned++
+	}
+}
+
+func doctorDataDir(report *doctorReport) bool {
+	var dir string
+	var info os.FileInfo
+	var probe *os.File
+	var name string
+	var loose []string
+
+	var err error
+
+	dir, err = filepath.Abs(dataDirPath())
+	if err != nil {
+		report.add("data directory", doctorFail, err.Error(), "")
+		return false
+	}
+
+	info, err = os.Stat(dir)
+	if os.IsNotExist(err) {
+		report.add("data directory", doctorFail, dir+" does not exist", "run `mininaru setup`, or point NARU_PATH at your data directory")
+		return false
+	}
+	if err != nil {
+		report.add("data directory", doctorFail, err.Error(), "")
+		return false
+	}
+	if !info.IsDir() {
+		report.add("data directory", doctorFail, dir+" is not a directory", "move it aside and run `mininaru setup`")
+		return false
+	}
+
+	probe, err = os.CreateTemp(dir, ".doctor")
+	if err != nil {
+		report.add("data directory", doctorFail, dir+" is not writable: "+err.Error(), "check the owner of "+dir)
+		return false
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	util.RootDir = dir
+
+	for _, name = range []string{config.CLIENT_PATH, core.PROVIDER_PATH, core.AGENT_PATH, core.BOT_PATH, modules.WEB_PATH, modules.MCP_PATH, modules.LSP_PATH} {
+		info, err = os.Stat(util.Path(name))
+		if err == nil && info.Mode().Perm()&0077 != 0 {
+			loose = append(loose, name)
+		}
+	}
+
+	info, err = os.Stat(dir)
+	if err != nil {
+		report.add("data directory", doctorFail, err.Error(), "")
+		return false
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		report.add("data directory", doctorWarn, fmt.Sprintf("%s is mode %04o, expected 0700", dir, info.Mode().Perm()), "chmod 700 "+dir)
+		return true
+	}
+	if len(loose) > 0 {
+		report.add("data directory", doctorWarn, strings.Join(loose, ", ")+" can be read by other users",
+			"chmod 600 "+filepath.Join(dir, "*.json"))
+		return true
+	}
+
+	report.add("data directory", doctorOk, dir, "")
+
+	return true
+}
+
+func doctorDatabase(report *doctorReport) {
+	var path string
+	var pending []string
+
+	var err error
+
+	path = util.Path("mininaru.db")
+
+	_, err = os.Stat(path)
+	if os.IsNotExist(err) {
+		report.add("database", doctorWarn, "mininaru.db has not been created yet", "run any mininaru command once to create it")
+		return
+	}
+
+	pending, err = util.DatabaseCheck(path)
+	if err != nil {
+		report.add("database", doctorFail, err.Error(),
+			"restore mininaru.db from a backup, or salvage it with `sqlite3 mininaru.db .recover`")
+		return
+	}
+	if len(pending) > 0 {
+		report.add("database", doctorWarn, fmt.Sprintf("%d migrations pending: %s", len(pending), strings.Join(pending, ", ")),
+			"they apply the next time mininaru opens the database, stop the daemon first if it runs an older build")
+		return
+	}
+
+	report.add("database", doctorOk, "integrity ok, migrations current", "")
+}
+
+func doctorLoad(report *doctorReport, name, fix string, load func() error) bool {
+	var err error
+
+	err = load()
+	if err != nil {
+		report.add(name, doctorFail, err.Error(), fix)
+		return false
+	}
+
+	report.add(name, doctorOk, "", "")
+
+	return true
+}
+
+func doctorConfig(report *doctorReport) bool {
+	var workingDir string
+	var problems []string
+	var loaded bool
+
+	var err error
+
+	workingDir, err = os.Getwd()
+	if err == nil {
+		err = modules.SetWorkingRoot(workingDir)
+	}
+	if err != nil {
+		report.add("working directory", doctorFail, err.Error(), "")
+	}
+
+	doctorLoad(report, config.CLIENT_PATH, "fix the json by hand or delete the file to start over", config.ClientInit)
+
+	err = modules.WebLoad()
+	if err == nil {
+		err = modules.WebCheck()
+	}
+	if err != nil {
+		report.add(modules.WEB_PATH, doctorFail, err.Error(), "fix web.json by hand or set it again with `mininaru web provider <name>`")
+	} else {
+		report.add(modules.WEB_PATH, doctorOk, modules.WebSearchConfig().Provider, "")
+	}
+
+	modules.SkillInit()
+	problems = modules.SkillProblems()
+	if len(problems) > 0 {
+		report.add("skills", doctorWarn, strings.Join(problems, "; "), "fix the SKILL.md frontmatter, `mininaru skill show <name>` shows what loaded")
+	} else {
+		report.add("skills", doctorOk, fmt.Sprintf("%d loaded", len(modules.SkillAll())), "")
+	}
+
+	loaded = doctorLoad(report, core.PROVIDER_PATH, "fix the entry with `mininaru provider update`", core.ProviderInit)
+	if loaded {
+		loaded = doctorLoad(report, core.AGENT_PATH, "fix the entry with `mininaru agent update`", core.AgentInit)
+	}
+	if loaded {
+		doctorLoad(report, core.BOT_PATH, "fix the entry with `mininaru bot update`", core.BotInit)
+	}
+	doctorLoad(report, modules.MCP_PATH, "fix the json by hand, `mininaru mcp remove` drops a server entirely", modules.MCPLoad)
+	doctorLoad(report, modules.LSP_PATH, "fix the json by hand", modules.LSPLoad)
+
+	return loaded
+}
+
+func doctorProviders(ctx context.Context, report *doctorReport) map[string][]string {
+	var offered map[string][]string
+	var current *core.Provider
+	var probe context.Context
+	var cancel context.CancelFunc
+	var models []string
+
+	var err error
+
+	offered = make(map[string][]string)
+
+	if len(core.Providers) == 0 {
+		report.add("providers", doctorFail, "no providers configured", "add one with `mininaru provider add`")
+		return offered
+	}
+
+	for _, current = range core.Providers {
+		probe, cancel = context.WithTimeout(ctx, doctorTimeout)
+		models, err = core.ProviderModels(probe, current)
+		cancel()
+		if err != nil {
+			report.add("provider "+current.Name, doctorFail, err.Error(),
+				"check the base url and api key with `mininaru provider update "+current.Name+"`, then `mininaru secrets check`")
+			continue
+		}
+
+		offered[current.Id] = models
+		report.add("provider "+current.Name, doctorOk, fmt.Sprintf("%d models", len(models)), "")
+	}
+
+	return offered
+}
+
+func doctorAgents(ctx context.Context, report *doctorReport, offered map[string][]string) {
+	var current *core.NaruAgent
+	var models []string
+	var reached bool
+	var window int64
+
+	var err error
+
+	if len(core.AgentAll()) == 0 {
+		report.add("agents", doctorFail, "no agents configured", "add one with `mininaru agent add`")
+		return
+	}
+
+	for _, current = range core.AgentAll() {
+		_, err = core.ProviderFind(current.ProviderId)
+		if err != nil {
+			report.add("agent "+current.Name, doctorFail, "provider "+current.ProviderId+" does not exist",
+				"`mininaru agent update "+current.Name+" --provider <name>`")
+			continue
+		}
+
+		models, reached = offered[current.ProviderId]
+		if reached && len(models) > 0 && !slices.Contains(models, current.Model) {
+			report.add("agent "+current.Name, doctorFail, "the provider does not offer model "+current.Model,
+				"`mininaru agent update "+current.Name+" --model <model>`, the provider lists "+strings.Join(models[:min(len(models), 5)], ", "))
+			continue
+		}
+
+		window = current.ModelContextWindow(ctx)
+		if window <= 0 {
+			report.add("agent "+current.Name, doctorWarn, current.Model+", context window unknown so automatic compaction stays off",
+				"`mininaru agent update "+current.Name+" --context-window <tokens>`")
+			continue
+		}
+
+		report.add("agent "+current.Name, doctorOk, current.Model+", "+strconv.FormatInt(window, 10)+" token context", "")
+	}
+}
+
+func doctorMCP(ctx context.Context, report *doctorReport) {
+	var all []modules.MCPStatus
+	var entry modules.MCPServer
+	var status modules.MCPStatus
+	var known bool
+
+	var err error
+
+	if len(modules.MCP.Servers) == 0 {
+		return
+	}
+
+	err = modules.MCPInit(ctx)
+	if err != nil {
+		report.add(modules.MCP_PATH, doctorFail, err.Error(), "")
+		return
+	}
+
+	all = modules.MCPStatusAll()
+	for _, entry = range modules.MCP.Servers {
+		status, known = mcpStatusOf(all, entry.Name)
+		switch {
+		case !known:
+			report.add("mcp "+entry.Name, doctorSkip, "disabled", "")
+		case !status.Connected:
+			report.add("mcp "+entry.Name, doctorFail, status.Error,
+				"check its command or url in mcp.json, or turn it off with `mininaru mcp disable "+entry.Name+"`")
+		case status.Tools == 0:
+			report.add("mcp "+entry.Name, doctorWarn, "connected but lists no tools", "")
+		default:
+			report.add("mcp "+entry.Name, doctorOk, fmt.Sprintf("%d tools", status.Tools), "")
+		}
+	}
+}
+
+func doctorLSP(report *doctorReport) {
+	var server modules.LSPServer
+	var path string
+
+	var err error
+
+	for _, server = range modules.LSPServers() {
+		path, err = exec.LookPath(server.Command)
+		if err != nil {
+			report.add("lsp "+server.Name, doctorFail, server.Command+" is not on PATH",
+				"install it or fix its command in "+modules.LSP_PATH)
+			continue
+		}
+
+		report.add("lsp "+server.Name, doctorOk, path+" for "+strings.Join(server.Extensions, " "), "")
+	}
+}
+
+func doctorPKI(report *doctorReport) {
+	var expiry []rpc.CertificateExpiry
+	var current rpc.CertificateExpiry
+	var left time.Duration
+	var fix string
+
+	var err error
+
+	expiry, err = rpc.PKIExpiry()
+	if err != nil {
+		report.add("pki", doctorFail, err.Error(), "delete pki/ and pair your clients again")
+		return
+	}
+
+	for _, current = range expiry {
+		fix = "delete " + current.File + " and restart `mininaru serve` to issue a new one"
+		if filepath.Base(current.File) == "ca.crt" {
+			fix = "delete pki/ and pair your clients again"
+		}
+
+		left = time.Until(current.NotAfter)
+		switch {
+		case left <= 0:
+			report.add(current.File, doctorFail, "expired "+current.NotAfter.Format(time.DateOnly), fix)
+		case left < doctorCertWindow:
+			report.add(current.File, doctorWarn, "expires "+current.NotAfter.Format(time.DateOnly), fix)
+		default:
+			report.add(current.File, doctorOk, "expires "+current.NotAfter.Format(time.DateOnly), "")
+		}
+	}
+}
+
+func doctorDaemon(ctx context.Context, report *doctorReport) {
+	var installed bool
+	var state string
+
+	var err error
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+
+	installed, err = daemonInstalled()
+	if err != nil {
+		report.add("daemon", doctorFail, err.Error(), "")
+		return
+	}
+	if !installed {
+		report.add("daemon", doctorSkip, "not installed", "")
+		return
+	}
+
+	state = daemonActiveState(ctx)
+	if state != "active" {
+		report.add("daemon", doctorFail, daemonUnitName+" is "+state,
+			"read `journalctl --user -u "+daemonUnitName+"`, then `mininaru daemon reload`")
+		return
+	}
+
+	report.add("daemon", doctorOk, daemonUnitName+" is active", "")
+}
+
+func doctorRun(ctx context.Context) *doctorReport {
+	var report doctorReport
+	var offered map[string][]string
+
+	if !doctorDataDir(&report) {
+		return &report
+	}
+
+	doctorDatabase(&report)
+	if doctorConfig(&report) {
+		offered = doctorProviders(ctx, &report)
+		doctorAgents(ctx, &report, offered)
+	}
+	doctorMCP(ctx, &report)
+	doctorLSP(&report)
+	doctorPKI(&report)
+	doctorDaemon(ctx, &report)
+
+	return &report
+}
+
+func doctorPrint(report *doctorReport) {
+	var rows *uiRows
+	var check doctorCheck
+
+	rows = uiTable("CHECK", "STATUS", "DETAIL")
+	for _, check = range report.Checks {
+		rows.row(check.Name, check.Status, check.Detail)
+	}
+	rows.flush()
+
+	for _, check = range report.Checks {
+		if check.Fix == "" {
+			continue
+		}
+
+		uiNote("%s: %s", check.Name, check.Fix)
+	}
+}
+
+func doctorExecute(cmd *cobra.Command, args []string) error {
+	var report *doctorReport
+	var buf []byte
+
+	var err error
+
+	err = withProgress(cmd.Context(), "running checks", func() error {
+		report = doctorRun(cmd.Context())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if doctorJSONRef {
+		buf, err = json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		uiOk("%s", buf)
+	} else {
+		doctorPrint(report)
+	}
+
+	if report.Failed > 0 {
+		return configErrorf("%d checks failed", report.Failed)
+	}
+
+	return nil
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorJSONRef, "json", false, "print the report as json")
+}
@@ -0,0 +1,129 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+func doctorFind(t *testing.T, report *doctorReport, name string) doctorCheck {
+	var check doctorCheck
+
+	t.Helper()
+
+	for _, check = range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+
+	t.Fatalf("no %q check in %+v", name, report.Checks)
+
+	return doctorCheck{}
+}
+
+func doctorSetup(t *testing.T, model string) {
+	var dir string
+	var srv *httptest.Server
+
+	var err error
+
+	t.Helper()
+
+	dir = t.TempDir()
+	t.Setenv("NARU_PATH", dir)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/models":
+			w.Write([]byte(`{"object":"list","data":[{"id":"small","object":"model"}]}`))
+		case "/props":
+			w.Write([]byte(`{"default_generation_settings":{"n_ctx":8192}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	err = util.InitFS(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	core.Providers = nil
+	core.DefaultProvider = nil
+	core.Global = nil
+	core.Agents = nil
+	core.Bots = nil
+	modules.MCP = modules.MCPConfig{}
+
+	core.ProviderCreate(core.Provider{Name: "local", BaseURL: srv.URL + "/v1"})
+	core.DefaultProvider = core.Providers[0]
+	core.Global = core.AgentNew("naru", "", "", model, core.Providers[0])
+
+	err = core.ProviderSave()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = core.AgentSave()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDoctorFlagsAModelTheProviderDoesNotOffer(t *testing.T) {
+	var report *doctorReport
+	var check doctorCheck
+
+	doctorSetup(t, "large")
+
+	report = doctorRun(context.Background())
+	if doctorFind(t, report, "provider local").Status != doctorOk {
+		t.Fatalf("provider check = %+v", doctorFind(t, report, "provider local"))
+	}
+	check = doctorFind(t, report, "agent naru")
+	if check.Status != doctorFail || check.Fix == "" {
+		t.Fatalf("agent check = %+v, want a failure with a fix", check)
+	}
+	if report.Failed == 0 {
+		t.Fatal("the report counted no failures")
+	}
+}
+
+func TestDoctorReportsTheContextWindowAsJSON(t *testing.T) {
+	var report *doctorReport
+	var buf []byte
+	var decoded doctorReport
+
+	var err error
+
+	doctorSetup(t, "small")
+
+	report = doctorRun(context.Background())
+	if doctorFind(t, report, "agent naru").Detail != "small, 8192 token context" {
+		t.Fatalf("agent check = %+v", doctorFind(t, report, "agent naru"))
+	}
+
+	buf, err = json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(buf, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Checks) != len(report.Checks) || decoded.Failed != report.Failed {
+		t.Fatalf("json report = %s", buf)
+	}
+}
@@ -38,7 +38,10 @@ var (
 	sessionIdRef string
 	resumeRef    string
 	chatAgentRef string
+	seatsRef     []string
+	turnsRef     string
 	promptRef    string
+	attachRef    []string
 	serverRef    string
 
 	logLevelRef  string
@@ -57,6 +60,7 @@ compatible APIs.`,
 	Example: `  mininaru
   mininaru --resume
   mininaru -a reviewer -p "summarise the diff on stdin" -
+  mininaru --agents architect,reviewer --turns moderator
   mininaru serve --port 8080`,
 	SilenceUsage:      true,
 	SilenceErrors:     true,
@@ -89,14 +93,25 @@ func resolveDataDir() {
 	util.RootDir = abs
 }
 
+func bootstrapLogging() error {
+	var err error
+
+	err = util.LogInit(util.LogOptions{Level: logLevelRef, Format: logFormatRef})
+	if err != nil {
+		return usageErrorf("init logging: %w", err)
+	}
+
+	return nil
+}
+
 func bootstrap() error {
 	var workingDir string
 
 	var err error
 
-	err = util.LogInit(util.LogOptions{Level: logLevelRef, Format: logFormatRef})
+	err = bootstrapLogging()
 	if err != nil {
-		return usageErrorf("init logging: %w", err)
+		return err
 	}
 
 	workingDir, err = os.Getwd()
@@ -123,12 +138,18 @@ func bootstrap() error {
 	if err != nil {
 		return fmt.Errorf("load client config: %w", err)
 	}
+	modules.SetGitAuthor(config.Client.Tools.Git.AuthorName, config.Client.Tools.Git.AuthorEmail)
 
 	err = modules.WebLoad()
 	if err != nil {
 		return fmt.Errorf("load web search config: %w", err)
 	}
 
+	err = modules.LSPLoad()
+	if err != nil {
+		return fmt.Errorf("load language server config: %w", err)
+	}
+
 	err = modules.SkillInit()
 	if err != nil {
 		return fmt.Errorf("load skills: %w", err)
@@ -150,6 +171,9 @@ func bootstrap() error {
 	}
 
 	core.InstallAgentTool()
+	core.InstallHandoffTool()
+	core.InstallToolResultTool()
+	core.InstallArtifactTool()
 
 	return nil
 }
@@ -162,6 +186,9 @@ func bootstrapExecute(cmd *cobra.Command, args []string) error {
 
 		return nil
 	}
+	if cmd == doctorCmd {
+		return bootstrapLogging()
+	}
 
 	err = bootstrap()
 	if err != nil {
@@ -200,6 +227,39 @@ func resolveAgent() (*core.NaruAgent, error) {
 	return core.Global, nil
 }
 
+func roundtableFlagsCheck() error {
+	if len(seatsRef) > 0 && (chatAgentRef != "" || sessionIdRef != "" || resumeRef != "") {
+		return usageErrorf("--agents starts a new roundtable and cannot be combined with --agent, --session or --resume")
+	}
+
+	return nil
+}
+
+func resolveRoundtable() (*core.Session, error) {
+	var name string
+	var seat *core.NaruAgent
+	var seats []*core.NaruAgent
+	var session *core.Session
+
+	var err error
+
+	for _, name = range seatsRef {
+		seat, err = core.AgentByName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+
+		seats = append(seats, seat)
+	}
+
+	session, err = core.RoundtableCreate(seats, turnsRef, time.Now().Format("2006-01-02 15:04"))
+	if err != nil {
+		return nil, usageErrorf("%v", err)
+	}
+
+	return session, nil
+}
+
 func resolveSession(agent *core.NaruAgent, args []string) (*core.Session, error) {
 	var id string
 	var session *core.Session
@@ -220,7 +280,7 @@ func resolveSession(agent *core.NaruAgent, args []string) (*core.Session, error)
 		if err != nil {
 			return nil, err
 		}
-		if session.AgentId != agent.Id {
+		if session.TurnMode == "" && session.AgentId != agent.Id {
 			return nil, fmt.Errorf("session %s belongs to agent %s, not %s", session.Id, session.AgentId, agent.Name)
 		}
 
@@ -244,6 +304,7 @@ func resolveSession(agent *core.NaruAgent, args []string) (*core.Session, error)
 func execute(cmd *cobra.Command, args []string) error {
 	var agent *core.NaruAgent
 	var content string
+	var attachments []*core.Attachment
 	var session *core.Session
 	var history []*core.Message
 
@@ -263,6 +324,19 @@ func execute(cmd *cobra.Command, args []string) error {
 		if content == "" {
 			return fmt.Errorf("prompt is empty")
 		}
+
+		attachments, err = promptAttachments(attachRef)
+		if err != nil {
+			return err
+		}
+	}
+	if promptRef == "" && len(attachRef) > 0 {
+		return fmt.Errorf("--attach needs --prompt, mention files with @path in the tui instead")
+	}
+
+	err = roundtableFlagsCheck()
+	if err != nil {
+		return err
 	}
 
 	serverRef = activeServerAddress()
@@ -276,21 +350,32 @@ func execute(cmd *cobra.Command, args []string) error {
 		}
 	}
 	if serverRef != "" {
-		return executeRemote(cmd.Context(), args, content)
+		return executeRemote(cmd.Context(), args, content, attachments)
 	}
 
-	agent, err = resolveAgent()
+	if len(seatsRef) > 0 {
+		session, err = resolveRoundtable()
+	} else {
+		agent, err = resolveAgent()
+		if err != nil {
+			return err
+		}
+
+		session, err = resolveSession(agent, args)
+	}
 	if err != nil {
 		return err
 	}
 
-	session, err = resolveSession(agent, args)
-	if err != nil {
-		return err
+	if session.TurnMode != "" {
+		agent, err = core.AgentByName(session.AgentId)
+		if err != nil {
+			return err
+		}
 	}
 
 	if content != "" {
-		return runPrompt(cmd.Context(), os.Stdout, os.Stderr, session, agent, content)
+		return runPrompt(cmd.Context(), os.Stdout, os.Stderr, session, agent, content, attachments)
 	}
 
 	history, err = core.MessageList(session.Id)
@@ -321,7 +406,11 @@ func rootInit() {
 	root.Flags().Lookup("resume").NoOptDefVal = latestSession
 
 	root.Flags().StringVarP(&chatAgentRef, "agent", "a", "", "agent name or id to chat with, defaults to the global agent")
+	root.Flags().StringSliceVar(&seatsRef, "agents", nil, "seat several agents at a new roundtable session, comma separated")
+	root.Flags().StringVar(&turnsRef, "turns", core.TurnRoundRobin,
+		"who answers at a roundtable: "+strings.Join(core.TurnModes(), ", ")+", an @name in the message always wins")
 	root.Flags().StringVarP(&promptRef, "prompt", "p", "", "run one turn without the tui and print the answer, pass - to read it from stdin")
+	root.Flags().StringArrayVar(&attachRef, "attach", nil, "attach a file to the --prompt turn, repeat for several files")
 
 	root.SetFlagErrorFunc(usageFlagError)
 
@@ -348,6 +437,8 @@ func rootInit() {
 	pairCmd.GroupID = groupService
 	daemonConfig.GroupID = groupService
 	updateCmd.GroupID = groupService
+	doctorCmd.GroupID = groupService
+	dbConfig.GroupID = groupService
 
 	root.AddCommand(setup)
 	root.AddCommand(serve)
@@ -361,10 +452,14 @@ func rootInit() {
 	root.AddCommand(skillConfig)
 	root.AddCommand(webConfig)
 	root.AddCommand(botConfig)
+	root.AddCommand(secretsConfig)
 	root.AddCommand(clientConfig)
 	root.AddCommand(pairCmd)
 	root.AddCommand(daemonConfig)
 	root.AddCommand(updateCmd)
+	root.AddCommand(doctorCmd)
+	root.AddCommand(dbConfig)
+	root.AddCommand(datasetConfig)
 }
 
 func main() {
@@ -393,6 +488,9 @@ func main() {
 	err = root.ExecuteContext(ctx)
 
 	modules.MCPClose()
+	modules.LSPClose()
+	modules.BashJobsClose()
+	modules.BashShellsClose()
 
 	if util.DB != nil {
 		util.DB.Close()
@@ -5,9 +5,13 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 
+	"github.com/devproje/mininaru/config"
 	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
 	"github.com/spf13/cobra"
 )
 
@@ -19,17 +23,32 @@ var (
 	providerCacheRef   string
 	providerRespCache  bool
 	providerRespTTL    int
+	providerEmbedRef   string
 
 	agentNameRef     string
 	agentRoleRef     string
 	agentSoulRef     string
 	agentModelRef    string
 	agentProviderRef string
-
-	sessionAgentIdRef string
-	sessionNameRef    string
+	agentContextRef  int64
+	agentStrategyRef []string
+	agentVisionRef   bool
+	agentSandboxRef  string
+
+	sessionAgentIdRef  string
+	sessionNameRef     string
+	sessionTagRef      string
+	sessionArchivedRef bool
+	sessionUntagRef    bool
 )
 
+var sessionMarkDone map[string]string = map[string]string{
+	"pin":       "pinned",
+	"unpin":     "unpinned",
+	"archive":   "archived",
+	"unarchive": "unarchived",
+}
+
 var provider *cobra.Command = &cobra.Command{
 	Use:   "provider",
 	Short: "manage LLM providers",
@@ -129,9 +148,10 @@ var agentUpdate *cobra.Command = &cobra.Command{
 
 Only the fields you pass as flags change. On a terminal, passing no flag at all
 walks through every field with the current value as the default.`,
-	Example: `  mininaru agent update reviewer --model gpt-4o-mini`,
-	Args:    usageArgs(cobra.ExactArgs(1)),
-	RunE:    agentUpdateExecute,
+	Example: `  mininaru agent update reviewer --model gpt-4o-mini
+  mininaru agent update reviewer --temperature 0.2 --max-tokens 2048 --allow-override temperature=0:1,max_tokens=4096`,
+	Args: usageArgs(cobra.ExactArgs(1)),
+	RunE: agentUpdateExecute,
 }
 
 var agentRemove *cobra.Command = &cobra.Command{
@@ -158,15 +178,24 @@ var session *cobra.Command = &cobra.Command{
 Every session belongs to one agent, so these commands act on the global agent
 unless --agent names another one. Resume a session with ` + "`mininaru --session <id>`" + `.`,
 	Example: `  mininaru session list
-  mininaru session rename 3f2a --name "release notes"`,
+  mininaru session list --tag work
+  mininaru session rename 3f2a --name "release notes"
+  mininaru session tag 3f2a work release
+  mininaru session pin 3f2a`,
 }
 
 var sessionList *cobra.Command = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
 	Short:   "list sessions",
-	Args:    usageArgs(cobra.NoArgs),
-	RunE:    sessionListExecute,
+	Long: `List an agent's sessions, pinned ones first and the rest oldest to newest.
+
+Archived sessions are hidden unless --archived is given, which lists only them.
+--tag narrows the list to sessions carrying that tag.`,
+	Example: `  mininaru session list --tag work
+  mininaru session list --archived`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: sessionListExecute,
 }
 
 var sessionUsage *cobra.Command = &cobra.Command{
@@ -205,6 +234,60 @@ On a terminal the new name is prompted for when --name is omitted.`,
 	RunE:    sessionRenameExecute,
 }
 
+var sessionTag *cobra.Command = &cobra.Command{
+	Use:   "tag <id> [tag...]",
+	Short: "show, add or remove a session's tags",
+	Long: `Tag a session so it can be found again with ` + "`session list --tag`" + `.
+
+Tags are lowercase letters, digits, '-', '_' and '.', up to 32 characters, and a
+session holds at most 16. Without tags the current ones are printed.`,
+	Example: `  mininaru session tag 3f2a work release
+  mininaru session tag 3f2a release --remove`,
+	Args: usageArgs(cobra.MinimumNArgs(1)),
+	RunE: sessionTagExecute,
+}
+
+var sessionUndo *cobra.Command = &cobra.Command{
+	Use:   "undo <message-id>",
+	Short: "restore the files an answer changed",
+	Long: `Put back every file that file_write, file_edit and file_patch changed while
+the agent wrote the given answer.
+
+Nothing is restored when any of those files changed again afterwards, whether
+by a later answer or by hand. Changes made through bash_exec are not tracked.`,
+	Example: `  mininaru session undo 7c41e0d2-5b8e-4a57-9d1f-2f0c6a3b8e11`,
+	Args:    usageArgs(cobra.ExactArgs(1)),
+	RunE:    sessionUndoExecute,
+}
+
+var sessionPin *cobra.Command = &cobra.Command{
+	Use:   "pin <id>",
+	Short: "keep a session at the top of the list and out of retention",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  sessionMarkExecute,
+}
+
+var sessionUnpin *cobra.Command = &cobra.Command{
+	Use:   "unpin <id>",
+	Short: "let a pinned session sort and expire like the rest",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  sessionMarkExecute,
+}
+
+var sessionArchive *cobra.Command = &cobra.Command{
+	Use:   "archive <id>",
+	Short: "hide a session from the list and from resuming the latest",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  sessionMarkExecute,
+}
+
+var sessionUnarchive *cobra.Command = &cobra.Command{
+	Use:   "unarchive <id>",
+	Short: "bring an archived session back",
+	Args:  usageArgs(cobra.ExactArgs(1)),
+	RunE:  sessionMarkExecute,
+}
+
 func providerAddAsk() error {
 	var err error
 
@@ -251,6 +334,7 @@ func providerAddExecute(cmd *cobra.Command, args []string) error {
 	payload = core.Provider{
 		Name: providerNameRef, ApiKey: providerApiKeyRef, BaseURL: providerBaseURLRef,
 		Kind: providerKindRef, Cache: providerCacheRef, ResponseCache: providerRespCache, ResponseCacheTTL: providerRespTTL,
+		EmbeddingModel: providerEmbedRef,
 	}
 	err = core.ProviderValidate(payload)
 	if err != nil {
@@ -285,7 +369,7 @@ func providerListExecute(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	rows = uiTable("ID", "NAME", "KIND", "CACHE", "RESPONSE CACHE", "BASE URL", "API KEY", "")
+	rows = uiTable("ID", "NAME", "KIND", "CACHE", "RESPONSE CACHE", "EMBEDDINGS", "BASE URL", "API KEY", "")
 
 	for _, cur = range core.Providers {
 		mark = ""
@@ -293,7 +377,7 @@ func providerListExecute(cmd *cobra.Command, args []string) error {
 			mark = "[default]"
 		}
 
-		rows.row(cur.Id, cur.Name, cur.ProviderKind(), cur.CachePolicy(), strconv.FormatBool(cur.ResponseCache), cur.BaseURL, maskSecret(cur.ApiKey), mark)
+		rows.row(cur.Id, cur.Name, cur.ProviderKind(), cur.CachePolicy(), strconv.FormatBool(cur.ResponseCache), cur.EmbeddingModel, cur.BaseURL, secretLabel(cur.ApiKey, cur.ApiKeyRef()), mark)
 	}
 
 	rows.flush()
@@ -348,6 +432,7 @@ func providerUpdateExecute(cmd *cobra.Command, args []string) error {
 	var name, apiKey, baseURL, kind, cache *string
 	var responseCache *bool
 	var responseTTL *int
+	var embeddingModel *string
 
 	var err error
 
@@ -357,7 +442,8 @@ func providerUpdateExecute(cmd *cobra.Command, args []string) error {
 	}
 
 	touched = cmd.Flags().Changed("name") || cmd.Flags().Changed("api-key") || cmd.Flags().Changed("base-url") ||
-		cmd.Flags().Changed("kind") || cmd.Flags().Changed("cache") || cmd.Flags().Changed("response-cache") || cmd.Flags().Changed("response-cache-ttl")
+		cmd.Flags().Changed("kind") || cmd.Flags().Changed("cache") || cmd.Flags().Changed("response-cache") || cmd.Flags().Changed("response-cache-ttl") ||
+		cmd.Flags().Changed("embedding-model")
 
 	if !touched && askInteractive() {
 		err = providerUpdateAsk(current)
@@ -393,8 +479,11 @@ func providerUpdateExecute(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("response-cache-ttl") {
 		responseTTL = &providerRespTTL
 	}
+	if cmd.Flags().Changed("embedding-model") {
+		embeddingModel = &providerEmbedRef
+	}
 
-	return core.ProviderUpdateConfig(current.Id, name, apiKey, baseURL, kind, cache, responseCache, responseTTL)
+	return core.ProviderUpdateConfig(current.Id, name, apiKey, baseURL, kind, cache, responseCache, responseTTL, embeddingModel)
 }
 
 func providerRemoveExecute(cmd *cobra.Command, args []string) error {
@@ -470,7 +559,45 @@ func agentAddAsk() error {
 	return err
 }
 
+func agentStrategy(names []string) ([]string, error) {
+	var ordered []string
+
+	var err error
+
+	if len(names) == 0 || (len(names) == 1 && names[0] == "default") {
+		return nil, nil
+	}
+
+	ordered, err = config.CompactStrategyOrder(names)
+	if err != nil {
+		return nil, usageErrorf("%v", err)
+	}
+
+	return ordered, nil
+}
+
+func agentSandbox(cmd *cobra.Command) (string, error) {
+	var err error
+
+	if !cmd.Flags().Changed("sandbox") {
+		return "", nil
+	}
+
+	err = modules.SandboxCheck(agentSandboxRef)
+	if err != nil {
+		return "", usageErrorf("%v", err)
+	}
+	if agentSandboxRef == modules.SandboxOff {
+		return "", nil
+	}
+
+	return agentSandboxRef, nil
+}
+
 func agentAddExecute(cmd *cobra.Command, args []string) error {
+	var strategy []string
+	var sampling core.Sampling
+	var bounds core.SamplingBounds
 	var prov *core.Provider
 	var newAgent *core.NaruAgent
 
@@ -490,23 +617,53 @@ func agentAddExecute(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("agent model is required, pass --model")
 	}
 
+	if agentContextRef < 0 {
+		return usageErrorf("context window must be 0 or a positive token count")
+	}
+
+	strategy, err = agentStrategy(agentStrategyRef)
+	if err != nil {
+		return err
+	}
+
+	sampling, err = agentSampling(cmd, core.Sampling{})
+	if err != nil {
+		return err
+	}
+
+	bounds, err = agentBounds(agentOverrideRef)
+	if err != nil {
+		return err
+	}
+
 	prov, err = resolveProvider()
 	if err != nil {
 		return err
 	}
 
-	if core.Global == nil {
-		newAgent = core.AgentNew(agentNameRef, agentRoleRef, agentSoulRef, agentModelRef, prov)
-		if newAgent == nil {
-			return fmt.Errorf("failed to create agent")
-		}
+	newAgent = core.AgentNew(agentNameRef, agentRoleRef, agentSoulRef, agentModelRef, prov)
+	if newAgent == nil {
+		return fmt.Errorf("failed to create agent")
+	}
+	newAgent.ContextWindow = agentContextRef
+	newAgent.ContextStrategy = strategy
+	newAgent.Sampling = sampling
+	newAgent.SamplingBounds = bounds
+	if cmd.Flags().Changed("vision") {
+		newAgent.Vision = &agentVisionRef
+	}
+	newAgent.Sandbox, err = agentSandbox(cmd)
+	if err != nil {
+		return err
+	}
 
+	if core.Global == nil {
 		core.Global = newAgent
-
-		return core.AgentSave()
+	} else {
+		core.Agents = append(core.Agents, newAgent)
 	}
 
-	return core.AgentCreate(agentNameRef, agentRoleRef, agentSoulRef, agentModelRef, prov)
+	return core.AgentSave()
 }
 
 func providerLabel(id string) string {
@@ -522,6 +679,14 @@ func providerLabel(id string) string {
 	return prov.Name
 }
 
+func agentContextLabel(agent *core.NaruAgent) string {
+	if agent.ContextWindow <= 0 {
+		return "auto"
+	}
+
+	return strconv.FormatInt(agent.ContextWindow, 10)
+}
+
 func agentListExecute(cmd *cobra.Command, args []string) error {
 	var rows *uiRows
 	var all []*core.NaruAgent
@@ -539,7 +704,7 @@ func agentListExecute(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	rows = uiTable("ID", "NAME", "MODEL", "PROVIDER", "")
+	rows = uiTable("ID", "NAME", "MODEL", "PROVIDER", "CONTEXT", "")
 
 	for _, cur = range all {
 		mark = ""
@@ -547,7 +712,7 @@ func agentListExecute(cmd *cobra.Command, args []string) error {
 			mark = "[global]"
 		}
 
-		rows.row(cur.Id, cur.Name, cur.Model, providerLabel(cur.ProviderId), mark)
+		rows.row(cur.Id, cur.Name, cur.Model, providerLabel(cur.ProviderId), agentContextLabel(cur), mark)
 	}
 
 	rows.flush()
@@ -589,11 +754,21 @@ func agentUpdateAsk(current *core.NaruAgent) error {
 	return err
 }
 
-func agentApplyUpdate(ref string, name, role, soul, model, providerId *string) error {
+func agentApplyUpdate(ref string, name, role, soul, model, providerId *string, contextWindow *int64, contextStrategy *[]string,
+	sampling *core.Sampling, bounds *core.SamplingBounds, vision *bool, sandbox *string) error {
 	var err error
 
+	if contextWindow != nil && *contextWindow < 0 {
+		return usageErrorf("context window must be 0 or a positive token count")
+	}
+
 	if core.Global == nil || core.Global.Id != ref {
-		return core.AgentUpdateFields(ref, name, role, soul, model, providerId)
+		return core.AgentUpdateFields(ref, name, role, soul, model, providerId, contextWindow, contextStrategy, sampling, bounds, vision, sandbox)
+	}
+
+	err = core.Global.PersonaCheck(name, role, soul, model, providerId)
+	if err != nil {
+		return err
 	}
 
 	if name != nil {
@@ -621,19 +796,56 @@ func agentApplyUpdate(ref string, name, role, soul, model, providerId *string) e
 		}
 	}
 
+	if contextWindow != nil {
+		core.Global.ContextWindow = *contextWindow
+	}
+
+	if contextStrategy != nil {
+		core.Global.ContextStrategy = *contextStrategy
+	}
+
+	if sampling != nil {
+		core.Global.Sampling = sampling.Clone()
+	}
+
+	if bounds != nil {
+		core.Global.SamplingBounds = *bounds
+	}
+
+	if vision != nil {
+		core.Global.Vision = vision
+	}
+
+	if sandbox != nil {
+		core.Global.Sandbox = *sandbox
+	}
+
 	return core.AgentSave()
 }
 
 func agentUpdateTouched(cmd *cobra.Command) bool {
 	return cmd.Flags().Changed("name") || cmd.Flags().Changed("role") ||
-		cmd.Flags().Changed("soul") || cmd.Flags().Changed("model") || cmd.Flags().Changed("provider")
+		cmd.Flags().Changed("soul") || cmd.Flags().Changed("model") || cmd.Flags().Changed("provider") ||
+		cmd.Flags().Changed("context-window") || cmd.Flags().Changed("context-strategy") ||
+		samplingTouched(cmd) || cmd.Flags().Changed("allow-override") || cmd.Flags().Changed("vision") ||
+		cmd.Flags().Changed("sandbox")
 }
 
 func agentUpdateExecute(cmd *cobra.Command, args []string) error {
 	var name, role, soul, model *string
 	var prov *core.Provider
 	var providerId *string
+	var contextWindow *int64
+	var strategy []string
+	var contextStrategy *[]string
 	var current *core.NaruAgent
+	var nextSampling core.Sampling
+	var sampling *core.Sampling
+	var nextBounds core.SamplingBounds
+	var bounds *core.SamplingBounds
+	var vision *bool
+	var mode string
+	var sandbox *string
 
 	var err error
 
@@ -659,7 +871,7 @@ func agentUpdateExecute(cmd *cobra.Command, args []string) error {
 			providerId = &prov.Id
 		}
 
-		return agentApplyUpdate(current.Id, name, role, soul, model, providerId)
+		return agentApplyUpdate(current.Id, name, role, soul, model, providerId, nil, nil, nil, nil, nil, nil)
 	}
 
 	if cmd.Flags().Changed("name") {
@@ -674,6 +886,49 @@ func agentUpdateExecute(cmd *cobra.Command, args []string) error {
 	if cmd.Flags().Changed("model") {
 		model = &agentModelRef
 	}
+	if cmd.Flags().Changed("context-window") {
+		contextWindow = &agentContextRef
+	}
+	if cmd.Flags().Changed("context-strategy") {
+		strategy, err = agentStrategy(agentStrategyRef)
+		if err != nil {
+			return err
+		}
+
+		contextStrategy = &strategy
+	}
+	if samplingTouched(cmd) {
+		current, err = core.AgentByName(args[0])
+		if err != nil {
+			return err
+		}
+
+		nextSampling, err = agentSampling(cmd, current.Sampling)
+		if err != nil {
+			return err
+		}
+
+		sampling = &nextSampling
+	}
+	if cmd.Flags().Changed("allow-override") {
+		nextBounds, err = agentBounds(agentOverrideRef)
+		if err != nil {
+			return err
+		}
+
+		bounds = &nextBounds
+	}
+	if cmd.Flags().Changed("vision") {
+		vision = &agentVisionRef
+	}
+	if cmd.Flags().Changed("sandbox") {
+		mode, err = agentSandbox(cmd)
+		if err != nil {
+			return err
+		}
+
+		sandbox = &mode
+	}
 
 	if agentProviderRef != "" {
 		prov, err = core.ProviderFind(agentProviderRef)
@@ -684,7 +939,7 @@ func agentUpdateExecute(cmd *cobra.Command, args []string) error {
 		providerId = &prov.Id
 	}
 
-	return agentApplyUpdate(args[0], name, role, soul, model, providerId)
+	return agentApplyUpdate(args[0], name, role, soul, model, providerId, contextWindow, contextStrategy, sampling, bounds, vision, sandbox)
 }
 
 func agentRemoveExecute(cmd *cobra.Command, args []string) error {
@@ -768,13 +1023,13 @@ func sessionListExecute(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	sessions, err = core.SessionList(target.Id)
+	sessions, err = core.SessionList(target.Id, core.SessionFilter{Tag: sessionTagRef, Archived: sessionArchivedRef})
 	if err != nil {
-		return err
+		return usageErrorf("%v", err)
 	}
 
 	if len(sessions) == 0 {
-		uiEmpty("no sessions for %s yet, run `mininaru` to start one", target.Name)
+		sessionListEmpty(target.Name)
 
 		return nil
 	}
@@ -784,10 +1039,14 @@ func sessionListExecute(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	rows = uiTable("ID", "TOKENS", "NAME")
+	slices.SortStableFunc(sessions, func(a, b *core.Session) int {
+		return sessionPinOrder(a.Pinned, b.Pinned)
+	})
+
+	rows = uiTable("ID", "TOKENS", "TAGS", "NAME", "")
 
 	for _, cur = range sessions {
-		rows.row(cur.Id, tokenCount(spent[cur.Id]), cur.Name)
+		rows.row(cur.Id, tokenCount(spent[cur.Id]), strings.Join(cur.Tags, ","), cur.Name, sessionPinMark(cur.Pinned))
 	}
 
 	rows.flush()
@@ -795,6 +1054,38 @@ func sessionListExecute(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func sessionListEmpty(owner string) {
+	if sessionTagRef != "" {
+		uiEmpty("no sessions for %s tagged %s", owner, sessionTagRef)
+		return
+	}
+	if sessionArchivedRef {
+		uiEmpty("no archived sessions for %s", owner)
+		return
+	}
+
+	uiEmpty("no sessions for %s yet, run `mininaru` to start one", owner)
+}
+
+func sessionPinOrder(a, b bool) int {
+	if a == b {
+		return 0
+	}
+	if a {
+		return -1
+	}
+
+	return 1
+}
+
+func sessionPinMark(pinned bool) string {
+	if pinned {
+		return "[pinned]"
+	}
+
+	return ""
+}
+
 func sessionUsageTarget(args []string) (*core.Session, error) {
 	var target *core.NaruAgent
 	var found *core.Session
@@ -821,6 +1112,22 @@ func sessionUsageTarget(args []string) (*core.Session, error) {
 	return found, nil
 }
 
+func agentUsageTable(agents []core.AgentUsage) {
+	var agent core.AgentUsage
+	var rows *uiRows
+
+	if len(agents) == 0 {
+		return
+	}
+
+	fmt.Println()
+	rows = uiTable("AGENT", "PROMPT", "COMPLETION", "TOTAL")
+	for _, agent = range agents {
+		rows.row(agent.Agent, tokenCount(agent.PromptTokens), tokenCount(agent.CompletionTokens), tokenCount(agent.TotalTokens))
+	}
+	rows.flush()
+}
+
 func sessionUsageExecute(cmd *cobra.Command, args []string) error {
 	var session *core.Session
 	var totals *core.UsageTotals
@@ -859,6 +1166,7 @@ func sessionUsageExecute(cmd *cobra.Command, args []string) error {
 	rows.row("total", tokenCount(totals.PromptTokens), tokenCount(totals.CachedTokens), tokenCount(totals.CacheWriteTokens), tokenCount(totals.CompletionTokens),
 		tokenCount(totals.TotalTokens))
 	rows.flush()
+	agentUsageTable(totals.Agents)
 
 	return nil
 }
@@ -910,22 +1218,111 @@ func sessionRenameExecute(cmd *cobra.Command, args []string) error {
 	return core.SessionUpdate(args[0], sessionNameRef)
 }
 
+func sessionTagExecute(cmd *cobra.Command, args []string) error {
+	var tags []string
+
+	var err error
+
+	if activeServerAddress() != "" {
+		return remoteSessionTagExecute(cmd.Context(), args[0], args[1:])
+	}
+
+	if len(args) > 1 && sessionUntagRef {
+		err = core.SessionUntag(args[0], args[1:])
+	} else if len(args) > 1 {
+		err = core.SessionTag(args[0], args[1:])
+	}
+	if err != nil {
+		return usageErrorf("%v", err)
+	}
+
+	tags, err = core.SessionTags(args[0])
+	if err != nil {
+		return err
+	}
+
+	sessionTagsPrint(args[0], tags)
+
+	return nil
+}
+
+func sessionTagsPrint(id string, tags []string) {
+	if len(tags) == 0 {
+		uiEmpty("session %s has no tags", id)
+		return
+	}
+
+	uiOk("session %s tagged %s", id, strings.Join(tags, ", "))
+}
+
+func sessionUndoExecute(cmd *cobra.Command, args []string) error {
+	var restored []string
+
+	var err error
+
+	if activeServerAddress() != "" {
+		return remoteSessionUndoExecute(cmd.Context(), args[0])
+	}
+
+	restored, err = core.UndoTurn("", args[0])
+	if err != nil {
+		return err
+	}
+
+	sessionUndoPrint(args[0], restored)
+
+	return nil
+}
+
+func sessionUndoPrint(messageId string, restored []string) {
+	var path string
+
+	uiOk("restored %d files changed by message %s", len(restored), messageId)
+	for _, path = range restored {
+		uiOk("  %s", path)
+	}
+}
+
+func sessionMarkExecute(cmd *cobra.Command, args []string) error {
+	var err error
+
+	if activeServerAddress() != "" {
+		return remoteSessionMarkExecute(cmd.Context(), cmd.Name(), args[0])
+	}
+
+	switch cmd.Name() {
+	case "pin", "unpin":
+		err = core.SessionPin(args[0], cmd.Name() == "pin")
+	default:
+		err = core.SessionArchive(args[0], cmd.Name() == "archive")
+	}
+	if err != nil {
+		return err
+	}
+
+	uiOk("session %s %s", args[0], sessionMarkDone[cmd.Name()])
+
+	return nil
+}
+
 func init() {
 	providerAdd.Flags().StringVarP(&providerNameRef, "name", "n", "", "provider name")
-	providerAdd.Flags().StringVarP(&providerApiKeyRef, "api-key", "k", "", "provider api key")
+	providerAdd.Flags().StringVarP(&providerApiKeyRef, "api-key", "k", "", "provider api key, or an env:, file:, or cmd: reference")
 	providerAdd.Flags().StringVarP(&providerBaseURLRef, "base-url", "b", "", "provider base url")
 	providerAdd.Flags().StringVar(&providerKindRef, "kind", core.ProviderOpenAI, "provider API kind (openai or anthropic)")
 	providerAdd.Flags().StringVar(&providerCacheRef, "cache", core.CacheAuto, "prompt cache policy (auto, off, ephemeral, or ephemeral_1h)")
 	providerAdd.Flags().BoolVar(&providerRespCache, "response-cache", false, "enable OpenRouter whole-response caching")
 	providerAdd.Flags().IntVar(&providerRespTTL, "response-cache-ttl", 0, "OpenRouter response cache TTL in seconds")
+	providerAdd.Flags().StringVar(&providerEmbedRef, "embedding-model", "", "embedding model used to rank memories and knowledge")
 
 	providerUpdate.Flags().StringVarP(&providerNameRef, "name", "n", "", "provider name")
-	providerUpdate.Flags().StringVarP(&providerApiKeyRef, "api-key", "k", "", "provider api key")
+	providerUpdate.Flags().StringVarP(&providerApiKeyRef, "api-key", "k", "", "provider api key, or an env:, file:, or cmd: reference")
 	providerUpdate.Flags().StringVarP(&providerBaseURLRef, "base-url", "b", "", "provider base url")
 	providerUpdate.Flags().StringVar(&providerKindRef, "kind", "", "provider API kind (openai or anthropic)")
 	providerUpdate.Flags().StringVar(&providerCacheRef, "cache", "", "prompt cache policy (auto, off, ephemeral, or ephemeral_1h)")
 	providerUpdate.Flags().BoolVar(&providerRespCache, "response-cache", false, "enable OpenRouter whole-response caching")
 	providerUpdate.Flags().IntVar(&providerRespTTL, "response-cache-ttl", 0, "OpenRouter response cache TTL in seconds")
+	providerUpdate.Flags().StringVar(&providerEmbedRef, "embedding-model", "", "embedding model used to rank memories and knowledge, empty to turn it off")
 
 	provider.AddCommand(providerAdd, providerList, providerUpdate, providerRemove, providerDefault)
 
@@ -934,19 +1331,33 @@ func init() {
 	agentAdd.Flags().StringVarP(&agentSoulRef, "soul", "s", "", "agent soul prompt")
 	agentAdd.Flags().StringVarP(&agentModelRef, "model", "m", "", "agent model")
 	agentAdd.Flags().StringVarP(&agentProviderRef, "provider", "p", "", "provider id or name, defaults to the default provider")
+	agentAdd.Flags().Int64Var(&agentContextRef, "context-window", 0, "context window in tokens, 0 asks the provider")
+	agentAdd.Flags().StringSliceVar(&agentStrategyRef, "context-strategy", nil, "compaction strategies (elide, window, summarize), default follows client.json")
+	agentAdd.Flags().BoolVar(&agentVisionRef, "vision", true, "send attached images to the model, turn off for text-only models")
+	agentAdd.Flags().StringVar(&agentSandboxRef, "sandbox", modules.SandboxOff, "confine bash commands on Linux (off, on, or offline to also cut the network)")
+	samplingFlags(agentAdd)
 
 	agentUpdate.Flags().StringVarP(&agentNameRef, "name", "n", "", "agent name")
 	agentUpdate.Flags().StringVarP(&agentRoleRef, "role", "r", "", "agent role")
 	agentUpdate.Flags().StringVarP(&agentSoulRef, "soul", "s", "", "agent soul prompt")
 	agentUpdate.Flags().StringVarP(&agentModelRef, "model", "m", "", "agent model")
 	agentUpdate.Flags().StringVarP(&agentProviderRef, "provider", "p", "", "provider id or name")
+	agentUpdate.Flags().Int64Var(&agentContextRef, "context-window", 0, "context window in tokens, 0 asks the provider")
+	agentUpdate.Flags().StringSliceVar(&agentStrategyRef, "context-strategy", nil, "compaction strategies (elide, window, summarize), default follows client.json")
+	agentUpdate.Flags().BoolVar(&agentVisionRef, "vision", true, "send attached images to the model, turn off for text-only models")
+	agentUpdate.Flags().StringVar(&agentSandboxRef, "sandbox", modules.SandboxOff, "confine bash commands on Linux (off, on, or offline to also cut the network)")
+	samplingFlags(agentUpdate)
 
 	agent.AddCommand(agentAdd, agentList, agentUpdate, agentRemove, agentDefault)
 
 	sessionList.Flags().StringVarP(&sessionAgentIdRef, "agent", "a", "", "agent name or id, defaults to the global agent")
+	sessionList.Flags().StringVarP(&sessionTagRef, "tag", "t", "", "only sessions carrying this tag")
+	sessionList.Flags().BoolVar(&sessionArchivedRef, "archived", false, "list archived sessions instead of active ones")
 	sessionUsage.Flags().StringVarP(&sessionAgentIdRef, "agent", "a", "", "agent name or id, defaults to the global agent")
 	sessionRemove.Flags().StringVarP(&sessionAgentIdRef, "agent", "a", "", "agent name or id that owns the session, defaults to the global agent")
 	sessionRename.Flags().StringVarP(&sessionNameRef, "name", "n", "", "new session name")
 
-	session.AddCommand(sessionList, sessionUsage, sessionRemove, sessionRename)
+	sessionTag.Flags().BoolVar(&sessionUntagRef, "remove", false, "remove the given tags instead of adding them")
+
+	session.AddCommand(sessionList, sessionUsage, sessionRemove, sessionRename, sessionTag, sessionUndo, sessionPin, sessionUnpin, sessionArchive, sessionUnarchive)
 }
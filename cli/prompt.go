@@ -32,9 +32,37 @@ func promptContent(value string, in io.Reader) (string, error) {
 	return strings.TrimSpace(string(buf)), nil
 }
 
+func promptAttachments(paths []string) ([]*core.Attachment, error) {
+	var attachments []*core.Attachment
+	var path string
+	var attachment *core.Attachment
+
+	var err error
+
+	for _, path = range paths {
+		attachment, err = core.AttachmentRead(path)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	err = core.AttachmentsCheck(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
 func promptToolLog(logs io.Writer, event core.ToolEvent) {
 	var label string
 
+	if event.Phase == core.ToolEventOutput {
+		return
+	}
+
 	label = core.ToolLabel(event.Name, event.Arguments)
 
 	if event.Phase == core.ToolEventStarted {
@@ -50,15 +78,68 @@ func promptToolLog(logs io.Writer, event core.ToolEvent) {
 	fmt.Fprintf(logs, "tool %s completed\n", label)
 }
 
-func runPrompt(ctx context.Context, out, logs io.Writer, session *core.Session, agent *core.NaruAgent, content string) error {
+func printRoundtable(out io.Writer, speakers []string, messages []*core.Message) {
+	var index int
+
+	for index = range messages {
+		if index < len(speakers) {
+			fmt.Fprintf(out, "[%s] ", speakers[index])
+		}
+
+		fmt.Fprintln(out, messages[index].Content)
+	}
+}
+
+func runRoundtablePrompt(ctx context.Context, out, logs io.Writer, session *core.Session, content string,
+	attachments []*core.Attachment) error {
+	var speakers []string
+	var messages []*core.Message
+	var waiting *progress
+
+	var err error
+
+	waiting = progressStart(ctx, "thinking")
+
+	messages, err = core.RoundtableChat(ctx, session, content, attachments,
+		func(agent *core.NaruAgent) {
+			speakers = append(speakers, agent.Name)
+		}, nil,
+		func(delta string) {
+			waiting.stop()
+
+			if !config.Client.Thinking.Show {
+				return
+			}
+
+			io.WriteString(logs, delta)
+		},
+		func(event core.ToolEvent) {
+			waiting.stop()
+
+			promptToolLog(logs, event)
+		}, nil)
+
+	waiting.stop()
+
+	printRoundtable(out, speakers, messages)
+
+	return err
+}
+
+func runPrompt(ctx context.Context, out, logs io.Writer, session *core.Session, agent *core.NaruAgent, content string,
+	attachments []*core.Attachment) error {
 	var message *core.Message
 	var waiting *progress
 
 	var err error
 
+	if session.TurnMode != "" {
+		return runRoundtablePrompt(ctx, out, logs, session, content, attachments)
+	}
+
 	waiting = progressStart(ctx, "thinking")
 
-	message, err = core.ChatWithApproval(ctx, session, agent, content, nil,
+	message, err = core.ChatWithAttachments(ctx, session, agent, content, attachments, nil,
 		func(delta string) {
 			waiting.stop()
 
@@ -101,7 +101,7 @@ func TestRunPromptPrintsAnswerToStdoutAndLogsToStderr(t *testing.T) {
 	config.Client.Tools.Enabled = true
 	config.Client.Thinking.Show = false
 
-	err = runPrompt(context.Background(), &out, &logs, session, agent, "hi")
+	err = runPrompt(context.Background(), &out, &logs, session, agent, "hi", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -140,7 +140,7 @@ func TestRunPromptReportsFailure(t *testing.T) {
 
 	session, agent = promptSetup(t, srv.URL)
 
-	err = runPrompt(context.Background(), &out, &logs, session, agent, "hi")
+	err = runPrompt(context.Background(), &out, &logs, session, agent, "hi", nil)
 	if err == nil {
 		t.Fatal("runPrompt hid an upstream failure")
 	}
@@ -6,9 +6,12 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/devproje/mininaru/cli/tui"
@@ -25,6 +28,8 @@ type remoteBackend struct {
 	toolCalls map[string][]*core.ToolCall
 }
 
+type remoteChatOpen func(context.Context, ...grpc.CallOption) (mininaruv1.MininaruService_ChatClient, error)
+
 func activeServerAddress() string {
 	if serverRef != "" {
 		return serverRef
@@ -135,7 +140,8 @@ func coreMessage(message *mininaruv1.Message) *core.Message {
 	}
 
 	return &core.Message{Id: message.GetId(), SessionId: message.GetSessionId(), Role: message.GetRole(),
-		Content: message.GetContent(), Reasoning: message.GetReasoning(), Status: message.GetStatus(), Error: message.GetError()}
+		Content: message.GetContent(), Reasoning: message.GetReasoning(), Status: message.GetStatus(), Error: message.GetError(),
+		AgentId: message.GetAgentId()}
 }
 
 func coreSession(session *mininaruv1.Session) *core.Session {
@@ -143,7 +149,8 @@ func coreSession(session *mininaruv1.Session) *core.Session {
 		return nil
 	}
 
-	return &core.Session{Id: session.GetId(), AgentId: session.GetAgentId(), Name: session.GetName()}
+	return &core.Session{Id: session.GetId(), AgentId: session.GetAgentId(), Name: session.GetName(), TurnMode: session.GetTurnMode(),
+		Tags: session.GetTags(), Pinned: session.GetPinned(), Archived: session.GetArchived()}
 }
 
 func coreAgent(agent *mininaruv1.Agent) *core.NaruAgent {
@@ -157,6 +164,7 @@ func coreAgent(agent *mininaruv1.Agent) *core.NaruAgent {
 func coreUsage(usage *mininaruv1.Usage) *core.UsageTotals {
 	var totals core.UsageTotals
 	var line *mininaruv1.UsageLine
+	var agent *mininaruv1.AgentUsage
 
 	if usage == nil {
 		return &totals
@@ -170,6 +178,10 @@ func coreUsage(usage *mininaruv1.Usage) *core.UsageTotals {
 			CompletionTokens: line.GetCompletionTokens(), TotalTokens: line.GetTotalTokens(),
 			CachedTokens: line.GetCachedTokens(), CacheWriteTokens: line.GetCacheWriteTokens()})
 	}
+	for _, agent = range usage.GetAgents() {
+		totals.Agents = append(totals.Agents, core.AgentUsage{AgentId: agent.GetAgentId(), Agent: agent.GetAgent(),
+			PromptTokens: agent.GetPromptTokens(), CompletionTokens: agent.GetCompletionTokens(), TotalTokens: agent.GetTotalTokens()})
+	}
 
 	return &totals
 }
@@ -189,7 +201,8 @@ func remoteTool(event *mininaruv1.ToolEvent) core.ToolEvent {
 	}
 
 	return core.ToolEvent{Phase: event.GetPhase(), CallId: event.GetCallId(), Name: event.GetName(),
-		Arguments: event.GetArguments(), Result: event.GetResult(), Status: event.GetStatus(), Error: event.GetError()}
+		Arguments: event.GetArguments(), Result: event.GetResult(), Status: event.GetStatus(), Error: event.GetError(),
+		Output: event.GetOutput()}
 }
 
 func remoteApproval(ctx context.Context, stream mininaruv1.MininaruService_ChatClient,
@@ -234,6 +247,22 @@ func remoteToolDefinitions(defs []modules.Def) ([]*mininaruv1.ToolDefinition, er
 	return result, nil
 }
 
+func localToolContext(ctx context.Context, sessionId string, request *mininaruv1.ToolRequest, onTool core.ToolEventFunc) context.Context {
+	ctx = modules.ToolSessionContext(ctx, sessionId)
+	ctx = modules.ToolSandboxContext(ctx, request.GetSandbox())
+	if request.GetMessageId() != "" {
+		ctx = modules.ToolTurnContext(ctx, request.GetMessageId())
+	}
+	if onTool == nil || request.GetCallId() == "" {
+		return ctx
+	}
+
+	return modules.ToolProgressContext(ctx, func(chunk string) {
+		onTool(core.ToolEvent{Phase: core.ToolEventOutput, CallId: request.GetCallId(), Name: request.GetToolName(),
+			Arguments: request.GetArguments(), Output: chunk})
+	})
+}
+
 func executeLocalTool(ctx context.Context, request *mininaruv1.ToolRequest, defs []modules.Def,
 	approve core.ToolApprovalFunc) (string, error) {
 	var def modules.Def
@@ -241,6 +270,10 @@ func executeLocalTool(ctx context.Context, request *mininaruv1.ToolRequest, defs
 
 	var err error
 
+	err = modules.SandboxCheck(request.GetSandbox())
+	if err != nil {
+		return "", err
+	}
 	for _, def = range defs {
 		if def.Name != request.GetToolName() {
 			continue
@@ -264,9 +297,12 @@ func executeLocalTool(ctx context.Context, request *mininaruv1.ToolRequest, defs
 	return "", fmt.Errorf("unknown local tool %q", request.GetToolName())
 }
 
-func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *core.NaruAgent, content string,
-	onContent, onReasoning func(string), onTool core.ToolEventFunc, approve core.ToolApprovalFunc) (*core.Message, error) {
+func (r *remoteBackend) chat(ctx context.Context, open remoteChatOpen, session *core.Session, content string, attachments []*core.Attachment,
+	onSpeaker core.SpeakerFunc, onContent, onReasoning func(string), onTool core.ToolEventFunc,
+	approve core.ToolApprovalFunc) (*mininaruv1.ChatCompleted, error) {
 	var stream mininaruv1.MininaruService_ChatClient
+	var attachment *core.Attachment
+	var sent []*mininaruv1.Attachment
 	var event *mininaruv1.ChatServerEvent
 	var failed *mininaruv1.ChatFailed
 	var defs []modules.Def
@@ -277,7 +313,7 @@ func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *
 
 	var err error
 
-	stream, err = r.client.Chat(ctx)
+	stream, err = open(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -290,8 +326,12 @@ func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *
 		}
 	}
 
+	for _, attachment = range attachments {
+		sent = append(sent, &mininaruv1.Attachment{Name: attachment.Name, MediaType: attachment.MediaType, Content: attachment.Content})
+	}
+
 	err = stream.Send(&mininaruv1.ChatClientEvent{Event: &mininaruv1.ChatClientEvent_Start{Start: &mininaruv1.ChatStart{
-		SessionId: session.Id, Content: content, Thinking: config.Client.Thinking.Level, Tools: advertised}}})
+		SessionId: session.Id, Content: content, Thinking: config.Client.Thinking.Level, Tools: advertised, Attachments: sent}}})
 	if err != nil {
 		return nil, err
 	}
@@ -304,6 +344,9 @@ func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *
 			}
 			return nil, err
 		}
+		if event.GetSpeaker() != nil && onSpeaker != nil {
+			onSpeaker(&core.NaruAgent{Id: event.GetSpeaker().GetAgentId(), Name: event.GetSpeaker().GetName()})
+		}
 		if event.GetContent() != nil && onContent != nil {
 			onContent(event.GetContent().GetText())
 		}
@@ -322,7 +365,7 @@ func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *
 		request = event.GetToolRequest()
 		if request != nil {
 			result = ""
-			result, err = executeLocalTool(ctx, request, defs, approve)
+			result, err = executeLocalTool(localToolContext(ctx, session.Id, request, onTool), request, defs, approve)
 			resultError = ""
 			if err != nil {
 				resultError = err.Error()
@@ -334,7 +377,7 @@ func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *
 			}
 		}
 		if event.GetCompleted() != nil {
-			return coreMessage(event.GetCompleted().GetMessage()), nil
+			return event.GetCompleted(), nil
 		}
 		failed = event.GetFailed()
 		if failed != nil {
@@ -343,6 +386,175 @@ func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *
 	}
 }
 
+func (r *remoteBackend) Chat(ctx context.Context, session *core.Session, agent *core.NaruAgent, content string, attachments []*core.Attachment,
+	onContent, onReasoning func(string), onTool core.ToolEventFunc, approve core.ToolApprovalFunc) (*core.Message, error) {
+	var completed *mininaruv1.ChatCompleted
+
+	var err error
+
+	completed, err = r.chat(ctx, r.client.Chat, session, content, attachments, nil, onContent, onReasoning, onTool, approve)
+	if err != nil {
+		return nil, err
+	}
+
+	return coreMessage(completed.GetMessage()), nil
+}
+
+func (r *remoteBackend) Retry(ctx context.Context, session *core.Session, agent *core.NaruAgent, content string,
+	onContent, onReasoning func(string), onTool core.ToolEventFunc, approve core.ToolApprovalFunc) (*core.Message, error) {
+	var open remoteChatOpen
+	var completed *mininaruv1.ChatCompleted
+
+	var err error
+
+	open = r.client.RegenerateMessage
+	if content != "" {
+		open = r.client.EditMessage
+	}
+
+	completed, err = r.chat(ctx, open, session, content, nil, nil, onContent, onReasoning, onTool, approve)
+	if err != nil {
+		return nil, err
+	}
+
+	return coreMessage(completed.GetMessage()), nil
+}
+
+func (r *remoteBackend) Roundtable(ctx context.Context, session *core.Session, content string, attachments []*core.Attachment,
+	onSpeaker core.SpeakerFunc, onContent, onReasoning func(string), onTool core.ToolEventFunc, approve core.ToolApprovalFunc) ([]*core.Message, error) {
+	var completed *mininaruv1.ChatCompleted
+	var message *mininaruv1.Message
+	var messages []*core.Message
+
+	var err error
+
+	completed, err = r.chat(ctx, r.client.Chat, session, content, attachments, onSpeaker, onContent, onReasoning, onTool, approve)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, message = range completed.GetMessages() {
+		messages = append(messages, coreMessage(message))
+	}
+
+	return messages, nil
+}
+
+func (r *remoteBackend) Seats(sessionId string) ([]*core.NaruAgent, error) {
+	var detail *mininaruv1.SessionDetail
+	var listed *mininaruv1.ListAgentsResponse
+	var names map[string]string
+	var agent *mininaruv1.Agent
+	var id string
+	var seats []*core.NaruAgent
+
+	var err error
+
+	detail, err = r.client.GetSession(context.Background(), &mininaruv1.GetSessionRequest{SessionId: sessionId})
+	if err != nil {
+		return nil, err
+	}
+	listed, err = r.client.ListAgents(context.Background(), &mininaruv1.ListAgentsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	names = make(map[string]string)
+	for _, agent = range listed.GetAgents() {
+		names[agent.GetId()] = agent.GetName()
+	}
+	for _, id = range detail.GetSession().GetAgentIds() {
+		seats = append(seats, &core.NaruAgent{Id: id, Name: names[id]})
+	}
+
+	return seats, nil
+}
+
+func (r *remoteBackend) Handoff(session *core.Session, name string, copy bool) (*core.Session, *core.NaruAgent, error) {
+	var response *mininaruv1.HandoffSessionResponse
+
+	var err error
+
+	response, err = r.client.HandoffSession(context.Background(),
+		&mininaruv1.HandoffSessionRequest{SessionId: session.Id, Agent: name, Copy: copy})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return coreSession(response.GetSession()), coreAgent(response.GetAgent()), nil
+}
+
+func (r *remoteBackend) Owner(sessionId string) (*core.NaruAgent, error) {
+	var detail *mininaruv1.SessionDetail
+
+	var err error
+
+	detail, err = r.client.GetSession(context.Background(), &mininaruv1.GetSessionRequest{SessionId: sessionId})
+	if err != nil {
+		return nil, err
+	}
+
+	return coreAgent(detail.GetAgent()), nil
+}
+
+func (r *remoteBackend) Tag(sessionId string, add, remove []string) ([]string, error) {
+	var updated *mininaruv1.Session
+
+	var err error
+
+	updated, err = r.client.UpdateSession(context.Background(),
+		&mininaruv1.UpdateSessionRequest{SessionId: sessionId, AddTags: add, RemoveTags: remove})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.GetTags(), nil
+}
+
+func (r *remoteBackend) Rate(messageId, rating, note string) error {
+	var request *mininaruv1.RateMessageRequest
+
+	var err error
+
+	request = &mininaruv1.RateMessageRequest{MessageId: messageId, Note: note}
+	switch rating {
+	case core.RatingGood:
+		request.Rating = mininaruv1.MessageRating_MESSAGE_RATING_GOOD
+	case core.RatingBad:
+		request.Rating = mininaruv1.MessageRating_MESSAGE_RATING_BAD
+	}
+
+	_, err = r.client.RateMessage(context.Background(), request)
+
+	return err
+}
+
+func (r *remoteBackend) Undo(sessionId, messageId string) ([]string, error) {
+	return remoteUndo(context.Background(), r.client, sessionId, messageId)
+}
+
+func remoteUndo(ctx context.Context, client mininaruv1.MininaruServiceClient, sessionId, messageId string) ([]string, error) {
+	var response *mininaruv1.UndoTurnResponse
+	var restored []string
+
+	var err error
+
+	response, err = client.UndoTurn(ctx, &mininaruv1.UndoTurnRequest{SessionId: sessionId, MessageId: messageId})
+	if err != nil {
+		return nil, err
+	}
+
+	restored, err = modules.CheckpointRestore(sessionId, response.GetTurnId())
+	if errors.Is(err, modules.ErrNoCheckpoint) && len(response.GetRestored()) > 0 {
+		err = nil
+	}
+	if err != nil {
+		return response.GetRestored(), err
+	}
+
+	return append(response.GetRestored(), restored...), nil
+}
+
 func (r *remoteBackend) Compact(ctx context.Context, agent *core.NaruAgent, session *core.Session) (bool, error) {
 	var response *mininaruv1.CompactSessionResponse
 
@@ -369,7 +581,7 @@ func (r *remoteBackend) Usage(sessionId string) (*core.UsageTotals, error) {
 	return coreUsage(usage), nil
 }
 
-func (r *remoteBackend) Context(sessionId string) (int64, int64, bool, error) {
+func (r *remoteBackend) Context(agent *core.NaruAgent, sessionId string) (int64, int64, bool, error) {
 	var detail *mininaruv1.SessionDetail
 
 	var err error
@@ -439,7 +651,7 @@ func remoteSession(ctx context.Context, client mininaruv1.MininaruServiceClient,
 
 	if id == "" {
 		created, err = client.CreateSession(ctx, &mininaruv1.CreateSessionRequest{Agent: agent.GetName(),
-			Name: time.Now().Format("2006-01-02 15:04")})
+			Name: time.Now().Format("2006-01-02 15:04"), Agents: seatsRef, TurnMode: turnsRef})
 		if err != nil {
 			return nil, err
 		}
@@ -450,21 +662,56 @@ func remoteSession(ctx context.Context, client mininaruv1.MininaruServiceClient,
 	if err != nil {
 		return nil, err
 	}
-	if detail.GetAgent().GetId() != agent.GetId() {
+	if detail.GetSession().GetTurnMode() == "" && detail.GetAgent().GetId() != agent.GetId() {
 		return nil, fmt.Errorf("session %s belongs to agent %s, not %s", id, detail.GetAgent().GetName(), agent.GetName())
 	}
 
 	return detail, nil
 }
 
-func runRemotePrompt(ctx context.Context, backend *remoteBackend, session *core.Session, agent *core.NaruAgent, content string) error {
+func runRemoteRoundtablePrompt(ctx context.Context, backend *remoteBackend, session *core.Session, content string,
+	attachments []*core.Attachment) error {
+	var speakers []string
+	var messages []*core.Message
+	var waiting *progress
+
+	var err error
+
+	waiting = progressStart(ctx, "thinking")
+	messages, err = backend.Roundtable(ctx, session, content, attachments, func(agent *core.NaruAgent) {
+		speakers = append(speakers, agent.Name)
+	}, nil, func(delta string) {
+		waiting.stop()
+		if config.Client.Thinking.Show {
+			fmt.Fprint(os.Stderr, delta)
+		}
+	}, func(event core.ToolEvent) {
+		waiting.stop()
+		promptToolLog(os.Stderr, event)
+	}, nil)
+	waiting.stop()
+	if err != nil {
+		return err
+	}
+
+	printRoundtable(os.Stdout, speakers, messages)
+
+	return nil
+}
+
+func runRemotePrompt(ctx context.Context, backend *remoteBackend, session *core.Session, agent *core.NaruAgent, content string,
+	attachments []*core.Attachment) error {
 	var message *core.Message
 	var waiting *progress
 
 	var err error
 
+	if session.TurnMode != "" {
+		return runRemoteRoundtablePrompt(ctx, backend, session, content, attachments)
+	}
+
 	waiting = progressStart(ctx, "thinking")
-	message, err = backend.Chat(ctx, session, agent, content, nil, func(delta string) {
+	message, err = backend.Chat(ctx, session, agent, content, attachments, nil, func(delta string) {
 		waiting.stop()
 		if config.Client.Thinking.Show {
 			fmt.Fprint(os.Stderr, delta)
@@ -483,7 +730,7 @@ func runRemotePrompt(ctx context.Context, backend *remoteBackend, session *core.
 	return nil
 }
 
-func executeRemote(cmdCtx context.Context, args []string, content string) error {
+func executeRemote(cmdCtx context.Context, args []string, content string, attachments []*core.Attachment) error {
 	var connection *grpc.ClientConn
 	var client mininaruv1.MininaruServiceClient
 	var listed *mininaruv1.ListAgentsResponse
@@ -530,7 +777,7 @@ func executeRemote(cmdCtx context.Context, args []string, content string) error
 	}
 
 	if content != "" {
-		return runRemotePrompt(cmdCtx, &backend, session, agent, content)
+		return runRemotePrompt(cmdCtx, &backend, session, agent, content, attachments)
 	}
 
 	return tui.RunWithBackend(session, agent, history, updateNotice(), &backend)
@@ -552,22 +799,28 @@ func remoteSessionListExecute(ctx context.Context) error {
 	}
 	defer connection.Close()
 
-	sessions, err = client.ListSessions(ctx, &mininaruv1.ListSessionsRequest{Agent: sessionAgentIdRef})
+	sessions, err = client.ListSessions(ctx, &mininaruv1.ListSessionsRequest{Agent: sessionAgentIdRef, Tag: sessionTagRef,
+		Archived: sessionArchivedRef})
 	if err != nil {
 		return err
 	}
 	if len(sessions.GetSessions()) == 0 {
-		uiEmpty("no sessions on the grpc server yet")
+		sessionListEmpty("the grpc server")
 		return nil
 	}
 
-	rows = uiTable("ID", "TOKENS", "NAME")
+	slices.SortStableFunc(sessions.Sessions, func(a, b *mininaruv1.Session) int {
+		return sessionPinOrder(a.GetPinned(), b.GetPinned())
+	})
+
+	rows = uiTable("ID", "TOKENS", "TAGS", "NAME", "")
 	for _, session = range sessions.GetSessions() {
 		usage, err = client.GetUsage(ctx, &mininaruv1.GetUsageRequest{SessionId: session.GetId()})
 		if err != nil {
 			return err
 		}
-		rows.row(session.GetId(), tokenCount(usage.GetTotalTokens()), session.GetName())
+		rows.row(session.GetId(), tokenCount(usage.GetTotalTokens()), strings.Join(session.GetTags(), ","), session.GetName(),
+			sessionPinMark(session.GetPinned()))
 	}
 	rows.flush()
 
@@ -631,6 +884,7 @@ func remoteSessionUsageExecute(ctx context.Context, args []string) error {
 	rows.row("total", tokenCount(usage.GetPromptTokens()), tokenCount(usage.GetCachedTokens()),
 		tokenCount(usage.GetCacheWriteTokens()), tokenCount(usage.GetCompletionTokens()), tokenCount(usage.GetTotalTokens()))
 	rows.flush()
+	agentUsageTable(coreUsage(usage).Agents)
 
 	return nil
 }
@@ -668,3 +922,85 @@ func remoteSessionRenameExecute(ctx context.Context, sessionId, name string) err
 
 	return err
 }
+
+func remoteSessionTagExecute(ctx context.Context, sessionId string, tags []string) error {
+	var connection *grpc.ClientConn
+	var client mininaruv1.MininaruServiceClient
+	var request *mininaruv1.UpdateSessionRequest
+	var updated *mininaruv1.Session
+
+	var err error
+
+	connection, client, err = remoteConnect(ctx)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	request = &mininaruv1.UpdateSessionRequest{SessionId: sessionId, AddTags: tags}
+	if sessionUntagRef {
+		request = &mininaruv1.UpdateSessionRequest{SessionId: sessionId, RemoveTags: tags}
+	}
+
+	updated, err = client.UpdateSession(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	sessionTagsPrint(sessionId, updated.GetTags())
+
+	return nil
+}
+
+func remoteSessionUndoExecute(ctx context.Context, messageId string) error {
+	var connection *grpc.ClientConn
+	var client mininaruv1.MininaruServiceClient
+	var restored []string
+
+	var err error
+
+	connection, client, err = remoteConnect(ctx)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	restored, err = remoteUndo(ctx, client, "", messageId)
+	if err != nil {
+		return err
+	}
+
+	sessionUndoPrint(messageId, restored)
+
+	return nil
+}
+
+func remoteSessionMarkExecute(ctx context.Context, action, sessionId string) error {
+	var connection *grpc.ClientConn
+	var client mininaruv1.MininaruServiceClient
+	var request *mininaruv1.UpdateSessionRequest
+	var set bool
+
+	var err error
+
+	connection, client, err = remoteConnect(ctx)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+
+	set = action == "pin" || action == "archive"
+	request = &mininaruv1.UpdateSessionRequest{SessionId: sessionId, Archived: &set}
+	if action == "pin" || action == "unpin" {
+		request = &mininaruv1.UpdateSessionRequest{SessionId: sessionId, Pinned: &set}
+	}
+
+	_, err = client.UpdateSession(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	uiOk("session %s %s", sessionId, sessionMarkDone[action])
+
+	return nil
+}
@@ -54,3 +54,34 @@ func TestRemoteToolRefusesDangerousWorkWithoutAnApprovalUI(t *testing.T) {
 		t.Fatalf("err=%v executed=%t", err, executed)
 	}
 }
+
+func TestRemoteToolRunsUnderTheAgentsSandbox(t *testing.T) {
+	var seen string
+	var executed bool
+	var defs []modules.Def
+	var request *mininaruv1.ToolRequest
+
+	var err error
+
+	defs = []modules.Def{{Name: "local", Permission: modules.PermissionSafe,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			executed = true
+			seen = modules.ToolSandboxFrom(ctx)
+			return "", nil
+		}}}
+	request = &mininaruv1.ToolRequest{ToolName: "local", Sandbox: modules.SandboxOffline}
+	_, err = executeLocalTool(localToolContext(context.Background(), "session", request, nil), request, defs, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seen != modules.SandboxOffline {
+		t.Fatalf("sandbox = %q, want %q", seen, modules.SandboxOffline)
+	}
+
+	executed = false
+	request = &mininaruv1.ToolRequest{ToolName: "local", Sandbox: "jail"}
+	_, err = executeLocalTool(localToolContext(context.Background(), "session", request, nil), request, defs, nil)
+	if err == nil || executed {
+		t.Fatalf("unknown sandbox err=%v executed=%t", err, executed)
+	}
+}
@@ -0,0 +1,219 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/devproje/mininaru/core"
+	"github.com/spf13/cobra"
+)
+
+var (
+	agentTemperatureRef float64
+	agentTopPRef        float64
+	agentMaxTokensRef   int64
+	agentStopRef        []string
+	agentSeedRef        int64
+	agentPresenceRef    float64
+	agentFrequencyRef   float64
+	agentExtraRef       []string
+	agentOverrideRef    []string
+	agentResetRef       bool
+)
+
+var samplingFlagNames = []string{
+	"temperature", "top-p", "max-tokens", "stop", "seed",
+	"presence-penalty", "frequency-penalty", "extra", "reset-sampling",
+}
+
+func samplingFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64Var(&agentTemperatureRef, "temperature", 0, "sampling temperature, 0 to 2")
+	cmd.Flags().Float64Var(&agentTopPRef, "top-p", 0, "nucleus sampling probability mass, 0 to 1")
+	cmd.Flags().Int64Var(&agentMaxTokensRef, "max-tokens", 0, "maximum tokens in one reply")
+	cmd.Flags().StringSliceVar(&agentStopRef, "stop", nil, "stop sequences, at most 4")
+	cmd.Flags().Int64Var(&agentSeedRef, "seed", 0, "sampling seed for providers that support it")
+	cmd.Flags().Float64Var(&agentPresenceRef, "presence-penalty", 0, "presence penalty, -2 to 2")
+	cmd.Flags().Float64Var(&agentFrequencyRef, "frequency-penalty", 0, "frequency penalty, -2 to 2")
+	cmd.Flags().StringArrayVar(&agentExtraRef, "extra", nil, "extra request body field as key=value, the value is read as JSON when it parses, an empty value removes the key")
+	cmd.Flags().StringSliceVar(&agentOverrideRef, "allow-override", nil,
+		"fields HTTP callers may override: temperature=MIN:MAX, top_p=MIN:MAX, presence_penalty=MIN:MAX, frequency_penalty=MIN:MAX, max_tokens=CEILING, seed, stop, or none")
+	cmd.Flags().BoolVar(&agentResetRef, "reset-sampling", false, "clear every sampling parameter before applying the other flags")
+}
+
+func samplingTouched(cmd *cobra.Command) bool {
+	var name string
+
+	for _, name = range samplingFlagNames {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func samplingExtra(extra map[string]any, specs []string) (map[string]any, error) {
+	var spec string
+	var key, raw string
+	var found bool
+	var value any
+
+	var err error
+
+	for _, spec = range specs {
+		key, raw, found = strings.Cut(spec, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, usageErrorf("extra field %q must look like key=value", spec)
+		}
+
+		if raw == "" {
+			delete(extra, key)
+			continue
+		}
+
+		err = json.Unmarshal([]byte(raw), &value)
+		if err != nil {
+			value = raw
+		}
+
+		if extra == nil {
+			extra = map[string]any{}
+		}
+		extra[key] = value
+	}
+
+	return extra, nil
+}
+
+func agentSampling(cmd *cobra.Command, base core.Sampling) (core.Sampling, error) {
+	var sampling core.Sampling
+	var value float64
+	var count int64
+
+	var err error
+
+	sampling = base.Clone()
+	if agentResetRef {
+		sampling = core.Sampling{}
+	}
+
+	if cmd.Flags().Changed("temperature") {
+		value = agentTemperatureRef
+		sampling.Temperature = &value
+	}
+	if cmd.Flags().Changed("top-p") {
+		value = agentTopPRef
+		sampling.TopP = &value
+	}
+	if cmd.Flags().Changed("presence-penalty") {
+		value = agentPresenceRef
+		sampling.PresencePenalty = &value
+	}
+	if cmd.Flags().Changed("frequency-penalty") {
+		value = agentFrequencyRef
+		sampling.FrequencyPenalty = &value
+	}
+	if cmd.Flags().Changed("max-tokens") {
+		count = agentMaxTokensRef
+		sampling.MaxTokens = &count
+	}
+	if cmd.Flags().Changed("seed") {
+		count = agentSeedRef
+		sampling.Seed = &count
+	}
+	if cmd.Flags().Changed("stop") {
+		sampling.Stop = slices.Clone(agentStopRef)
+	}
+
+	sampling.Extra, err = samplingExtra(maps.Clone(sampling.Extra), agentExtraRef)
+	if err != nil {
+		return core.Sampling{}, err
+	}
+
+	err = sampling.Validate()
+	if err != nil {
+		return core.Sampling{}, usageErrorf("%v", err)
+	}
+
+	return sampling, nil
+}
+
+func overrideRange(field, raw string) (*core.Bound, error) {
+	var low, high string
+	var found bool
+	var bound core.Bound
+
+	var err error
+
+	low, high, found = strings.Cut(raw, ":")
+	if !found {
+		return nil, usageErrorf("%s override must be a MIN:MAX range", field)
+	}
+
+	bound.Min, err = strconv.ParseFloat(low, 64)
+	if err != nil {
+		return nil, usageErrorf("%s override minimum %q is not a number", field, low)
+	}
+
+	bound.Max, err = strconv.ParseFloat(high, 64)
+	if err != nil {
+		return nil, usageErrorf("%s override maximum %q is not a number", field, high)
+	}
+
+	return &bound, nil
+}
+
+func agentBounds(specs []string) (core.SamplingBounds, error) {
+	var bounds core.SamplingBounds
+	var spec string
+	var field, raw string
+	var found bool
+
+	var err error
+
+	for _, spec = range specs {
+		field, raw, found = strings.Cut(strings.TrimSpace(spec), "=")
+		field = strings.ReplaceAll(field, "-", "_")
+
+		switch {
+		case field == "none" && !found:
+			bounds = core.SamplingBounds{}
+		case field == "seed" && !found:
+			bounds.Seed = true
+		case field == "stop" && !found:
+			bounds.Stop = true
+		case field == "max_tokens" && found:
+			bounds.MaxTokens, err = strconv.ParseInt(raw, 10, 64)
+			if err != nil || bounds.MaxTokens <= 0 {
+				return core.SamplingBounds{}, usageErrorf("max_tokens override must be a positive token ceiling")
+			}
+		case field == "temperature" && found:
+			bounds.Temperature, err = overrideRange(field, raw)
+		case field == "top_p" && found:
+			bounds.TopP, err = overrideRange(field, raw)
+		case field == "presence_penalty" && found:
+			bounds.PresencePenalty, err = overrideRange(field, raw)
+		case field == "frequency_penalty" && found:
+			bounds.FrequencyPenalty, err = overrideRange(field, raw)
+		default:
+			return core.SamplingBounds{}, usageErrorf("unknown override %q", spec)
+		}
+		if err != nil {
+			return core.SamplingBounds{}, err
+		}
+	}
+
+	err = bounds.Validate()
+	if err != nil {
+		return core.SamplingBounds{}, usageErrorf("%v", err)
+	}
+
+	return bounds, nil
+}
@@ -0,0 +1,62 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"testing"
+
+	"github.com/devproje/mininaru/core"
+)
+
+func TestAgentBoundsParsesOverrideSpecs(t *testing.T) {
+	var bounds core.SamplingBounds
+	var bad string
+
+	var err error
+
+	bounds, err = agentBounds([]string{"temperature=0.1:1.2", "max-tokens=4096", "seed", "stop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds.Temperature == nil || bounds.Temperature.Min != 0.1 || bounds.Temperature.Max != 1.2 ||
+		bounds.MaxTokens != 4096 || !bounds.Seed || !bounds.Stop || bounds.TopP != nil {
+		t.Fatalf("bounds = %+v", bounds)
+	}
+
+	bounds, err = agentBounds([]string{"seed", "none"})
+	if err != nil || bounds.Seed {
+		t.Fatalf("none should clear earlier overrides, got %+v, %v", bounds, err)
+	}
+
+	for _, bad = range []string{"temperature=1:0", "top_p=0.5", "max_tokens=0", "seed=1", "tone"} {
+		_, err = agentBounds([]string{bad})
+		if err == nil {
+			t.Errorf("agentBounds accepted %q", bad)
+		}
+	}
+}
+
+func TestSamplingExtraReadsJSONValues(t *testing.T) {
+	var extra map[string]any
+	var found bool
+
+	var err error
+
+	extra, err = samplingExtra(map[string]any{"old": 1}, []string{"top_k=20", "mode=fast", "flags={\"a\":true}", "old="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extra["top_k"] != float64(20) || extra["mode"] != "fast" || extra["flags"].(map[string]any)["a"] != true {
+		t.Fatalf("extra = %v", extra)
+	}
+	_, found = extra["old"]
+	if found {
+		t.Fatal("an empty value did not remove the key")
+	}
+
+	_, err = samplingExtra(nil, []string{"novalue"})
+	if err == nil {
+		t.Fatal("a field without = was accepted")
+	}
+}
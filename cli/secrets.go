@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"github.com/spf13/cobra"
+)
+
+var secretsConfig *cobra.Command = &cobra.Command{
+	Use:   "secrets",
+	Short: "check the secret references in your config files",
+	Long: `Check the API keys and tokens in provider.json, bot.json, web.json, and mcp.json.
+
+Any of those values can be a reference instead of the secret itself:
+
+  env:OPENAI_KEY           read an environment variable
+  file:/run/secrets/key    read a file, without its trailing newline
+  cmd:pass show openai     run a command through sh and use what it prints
+
+References are resolved every time the config is loaded and are written back
+as references, never as the resolved value. check resolves each one again and
+lists the ones that fail, along with any secret still stored in plaintext.`,
+	Example: `  mininaru provider update openai --api-key env:OPENAI_KEY
+  mininaru secrets check`,
+	Args:    usageArgs(cobra.NoArgs),
+	PreRunE: mcpLoadExecute,
+	RunE:    secretsCheckExecute,
+}
+
+var secretsCheckCmd *cobra.Command = &cobra.Command{
+	Use:     "check",
+	Short:   "resolve every secret reference and report the ones that fail",
+	Args:    usageArgs(cobra.NoArgs),
+	PreRunE: mcpLoadExecute,
+	RunE:    secretsCheckExecute,
+}
+
+func secretLabel(value, ref string) string {
+	if ref != "" {
+		return ref
+	}
+
+	return maskSecret(value)
+}
+
+func secretsCheckExecute(cmd *cobra.Command, args []string) error {
+	var fields []util.SecretField
+	var field util.SecretField
+	var rows *uiRows
+	var plain int
+	var failed int
+
+	var err error
+
+	fields = append(fields, core.ProviderSecrets()...)
+	fields = append(fields, core.BotSecrets()...)
+	fields = append(fields, modules.WebSecrets()...)
+	fields = append(fields, modules.MCPSecrets()...)
+
+	if len(fields) == 0 {
+		uiEmpty("no secrets configured")
+
+		return nil
+	}
+
+	rows = uiTable("WHERE", "REFERENCE", "STATUS")
+	for _, field = range fields {
+		if field.Plain {
+			rows.row(field.Owner, "-", "plaintext")
+			plain++
+			continue
+		}
+
+		_, err = util.SecretResolve(field.Ref)
+		if err != nil {
+			rows.row(field.Owner, field.Ref, err.Error())
+			failed++
+			continue
+		}
+
+		rows.row(field.Owner, field.Ref, "ok")
+	}
+	rows.flush()
+
+	if plain > 0 {
+		uiNote("%d secrets are stored in plaintext, replace them with env:, file:, or cmd: references", plain)
+	}
+	if failed > 0 {
+		return configErrorf("%d secret references did not resolve", failed)
+	}
+
+	return nil
+}
+
+func init() {
+	secretsConfig.AddCommand(secretsCheckCmd)
+}
@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+func TestSecretsCheckFailsOnAnUnresolvedReference(t *testing.T) {
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	core.Providers = nil
+	core.DefaultProvider = nil
+	core.Bots = nil
+	modules.MCP = modules.MCPConfig{}
+
+	t.Setenv("NARU_TEST_KEY", "sk-resolved")
+	core.ProviderCreate(core.Provider{Name: "ok", BaseURL: "http://localhost", ApiKey: "env:NARU_TEST_KEY"})
+	err = secretsCheckExecute(secretsCheckCmd, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	core.ProviderCreate(core.Provider{Name: "broken", BaseURL: "http://localhost", ApiKey: "env:NARU_TEST_UNSET"})
+	err = secretsCheckExecute(secretsCheckCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 secret") {
+		t.Fatalf("err = %v, want one unresolved reference", err)
+	}
+	if exitCode(err) == 0 {
+		t.Fatal("an unresolved reference exited cleanly")
+	}
+}
+
+func TestSecretLabelShowsReferences(t *testing.T) {
+	if secretLabel("sk-resolved", "env:NARU_TEST_KEY") != "env:NARU_TEST_KEY" {
+		t.Fatal("a reference was masked")
+	}
+	if secretLabel("sk-resolved", "") != "sk-r****" {
+		t.Fatal("a plaintext key was not masked")
+	}
+}
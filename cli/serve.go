@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/devproje/mininaru/bot"
 	"github.com/devproje/mininaru/config"
@@ -22,6 +23,8 @@ import (
 
 const apiKeyEnv = "MININARU_API_KEY"
 
+const retentionInterval = 6 * time.Hour
+
 var (
 	serveHostRef     string
 	servePortRef     int
@@ -70,6 +73,11 @@ func watchReload(ctx context.Context, registry *core.Registry) {
 				util.Log.Error("skill reload failed", "error", err)
 			}
 
+			err = modules.LSPReload()
+			if err != nil {
+				util.Log.Error("language server reload failed", "error", err)
+			}
+
 			if config.Client.Tools.Enabled {
 				err = modules.MCPReload(ctx)
 				if err != nil {
@@ -91,6 +99,33 @@ func watchReload(ctx context.Context, registry *core.Registry) {
 	}
 }
 
+func watchRetention(ctx context.Context) {
+	var ticker *time.Ticker
+	var result *core.RetentionResult
+	var pairings int64
+
+	var err error
+
+	ticker = time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+
+	for {
+		result, pairings, err = retentionRun()
+		if err != nil {
+			util.Log.Error("applying the retention policy failed", "error", err)
+		} else {
+			util.Log.Info("retention applied", "sessions", result.Sessions, "usage", result.Usage,
+				"skill_uses", result.SkillUses, "pairings", pairings)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func startBot(cfg *core.Bot, registry *core.Registry) (*bot.Discord, error) {
 	var discord *bot.Discord
 
@@ -219,6 +254,7 @@ func serveExecute(cmd *cobra.Command, args []string) error {
 	}
 
 	go watchReload(cmd.Context(), registry)
+	go watchRetention(cmd.Context())
 
 	started, err = startBots(registry)
 	if err != nil {
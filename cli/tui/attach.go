@@ -0,0 +1,158 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devproje/mininaru/core"
+)
+
+const maxMentionCandidates = 8
+
+func mentionPath(token string) string {
+	var home string
+
+	var err error
+
+	if !strings.HasPrefix(token, "~/") {
+		return token
+	}
+
+	home, err = os.UserHomeDir()
+	if err != nil {
+		return token
+	}
+
+	return filepath.Join(home, token[2:])
+}
+
+func mentionPaths(content string) []string {
+	var paths []string
+	var field string
+	var path string
+	var info os.FileInfo
+	var seen map[string]bool
+
+	var err error
+
+	seen = make(map[string]bool)
+	for _, field = range strings.Fields(content) {
+		if !strings.HasPrefix(field, "@") {
+			continue
+		}
+
+		path = mentionPath(strings.TrimRight(field[1:], ".,;:!?)\"'"))
+		if path == "" || seen[path] {
+			continue
+		}
+
+		info, err = os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func mentionAttachments(content string) ([]*core.Attachment, error) {
+	var attachments []*core.Attachment
+	var path string
+	var attachment *core.Attachment
+
+	var err error
+
+	for _, path = range mentionPaths(content) {
+		attachment, err = core.AttachmentRead(path)
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	err = core.AttachmentsCheck(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func mentionWord(value string) (string, bool) {
+	var index int
+	var word string
+
+	if strings.HasPrefix(value, "/") || strings.Contains(value, "\n") {
+		return "", false
+	}
+	if value == "" || strings.HasSuffix(value, " ") {
+		return "", false
+	}
+
+	index = strings.LastIndex(value, " ")
+	word = value[index+1:]
+	if !strings.HasPrefix(word, "@") {
+		return "", false
+	}
+
+	return word, true
+}
+
+func mentionCandidates(word string) []slashCommand {
+	var candidates []slashCommand
+	var typed string
+	var dir string
+	var prefix string
+	var listed string
+	var entries []os.DirEntry
+	var entry os.DirEntry
+	var name string
+	var label string
+	var description string
+
+	var err error
+
+	typed = word[1:]
+	dir, prefix = filepath.Split(typed)
+	listed = "."
+	if dir != "" {
+		listed = mentionPath(dir)
+	}
+
+	entries, err = os.ReadDir(listed)
+	if err != nil {
+		return nil
+	}
+
+	for _, entry = range entries {
+		if !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+		if strings.HasPrefix(entry.Name(), ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+
+		label = entry.Name()
+		description = "attach file"
+		if entry.IsDir() {
+			label += "/"
+			description = "directory"
+		}
+		name = "@" + dir + label
+
+		candidates = append(candidates, slashCommand{name: name, label: label, description: description})
+		if len(candidates) == maxMentionCandidates {
+			break
+		}
+	}
+
+	return candidates
+}
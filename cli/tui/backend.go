@@ -10,18 +10,31 @@ import (
 )
 
 type Backend interface {
-	Chat(context.Context, *core.Session, *core.NaruAgent, string, func(string), func(string), core.ToolEventFunc, core.ToolApprovalFunc) (*core.Message, error)
+	Chat(context.Context, *core.Session, *core.NaruAgent, string, []*core.Attachment, func(string), func(string), core.ToolEventFunc, core.ToolApprovalFunc) (*core.Message, error)
+	Retry(context.Context, *core.Session, *core.NaruAgent, string, func(string), func(string), core.ToolEventFunc, core.ToolApprovalFunc) (*core.Message, error)
 	Compact(context.Context, *core.NaruAgent, *core.Session) (bool, error)
 	Usage(string) (*core.UsageTotals, error)
-	Context(string) (int64, int64, bool, error)
+	Context(*core.NaruAgent, string) (int64, int64, bool, error)
 	ToolCalls(string) ([]*core.ToolCall, error)
+	Roundtable(context.Context, *core.Session, string, []*core.Attachment, core.SpeakerFunc, func(string), func(string), core.ToolEventFunc, core.ToolApprovalFunc) ([]*core.Message, error)
+	Seats(string) ([]*core.NaruAgent, error)
+	Handoff(*core.Session, string, bool) (*core.Session, *core.NaruAgent, error)
+	Owner(string) (*core.NaruAgent, error)
+	Tag(string, []string, []string) ([]string, error)
+	Rate(string, string, string) error
+	Undo(string, string) ([]string, error)
 }
 
 type localBackend struct{}
 
-func (localBackend) Chat(ctx context.Context, session *core.Session, agent *core.NaruAgent, content string,
+func (localBackend) Chat(ctx context.Context, session *core.Session, agent *core.NaruAgent, content string, attachments []*core.Attachment,
 	onContent, onReasoning func(string), onTool core.ToolEventFunc, approve core.ToolApprovalFunc) (*core.Message, error) {
-	return core.ChatWithApproval(ctx, session, agent, content, onContent, onReasoning, onTool, approve)
+	return core.ChatWithAttachments(ctx, session, agent, content, attachments, onContent, onReasoning, onTool, approve)
+}
+
+func (localBackend) Retry(ctx context.Context, session *core.Session, agent *core.NaruAgent, content string,
+	onContent, onReasoning func(string), onTool core.ToolEventFunc, approve core.ToolApprovalFunc) (*core.Message, error) {
+	return core.ChatRetry(ctx, session, agent, content, onContent, onReasoning, onTool, approve)
 }
 
 func (localBackend) Compact(ctx context.Context, agent *core.NaruAgent, session *core.Session) (bool, error) {
@@ -32,10 +45,79 @@ func (localBackend) Usage(sessionId string) (*core.UsageTotals, error) {
 	return core.SessionUsage(sessionId)
 }
 
-func (localBackend) Context(sessionId string) (int64, int64, bool, error) {
-	return core.SessionContextTokens(sessionId)
+func (localBackend) Context(agent *core.NaruAgent, sessionId string) (int64, int64, bool, error) {
+	return core.SessionContext(agent, sessionId)
 }
 
 func (localBackend) ToolCalls(messageId string) ([]*core.ToolCall, error) {
 	return core.ToolCallList(messageId)
 }
+
+func (localBackend) Roundtable(ctx context.Context, session *core.Session, content string, attachments []*core.Attachment,
+	onSpeaker core.SpeakerFunc, onContent, onReasoning func(string), onTool core.ToolEventFunc, approve core.ToolApprovalFunc) ([]*core.Message, error) {
+	return core.RoundtableChat(ctx, session, content, attachments, onSpeaker, onContent, onReasoning, onTool, approve)
+}
+
+func (localBackend) Seats(sessionId string) ([]*core.NaruAgent, error) {
+	return core.RoundtableSeats(sessionId)
+}
+
+func (localBackend) Handoff(session *core.Session, name string, copy bool) (*core.Session, *core.NaruAgent, error) {
+	var target *core.NaruAgent
+	var moved *core.Session
+
+	var err error
+
+	target, err = core.AgentByName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if copy {
+		moved, err = core.SessionHandoffCopy(session, target, "")
+	} else {
+		moved, err = core.SessionHandoff(session, target)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return moved, target, nil
+}
+
+func (localBackend) Owner(sessionId string) (*core.NaruAgent, error) {
+	var session *core.Session
+
+	var err error
+
+	session, err = core.SessionFind(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.AgentByName(session.AgentId)
+}
+
+func (localBackend) Tag(sessionId string, add, remove []string) ([]string, error) {
+	var err error
+
+	err = core.SessionTag(sessionId, add)
+	if err != nil {
+		return nil, err
+	}
+
+	err = core.SessionUntag(sessionId, remove)
+	if err != nil {
+		return nil, err
+	}
+
+	return core.SessionTags(sessionId)
+}
+
+func (localBackend) Rate(messageId, rating, note string) error {
+	return core.MessageRate(messageId, rating, note)
+}
+
+func (localBackend) Undo(sessionId, messageId string) ([]string, error) {
+	return core.UndoTurn(sessionId, messageId)
+}
@@ -8,8 +8,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -33,6 +36,8 @@ type chatThinkMsg string
 
 type toolEventMsg core.ToolEvent
 
+type speakerMsg string
+
 type chatDoneMsg struct {
 	message *core.Message
 	err     error
@@ -53,9 +58,13 @@ type toolApprovalMsg struct {
 
 type transcriptEntry struct {
 	kind    string
+	id      string
 	role    string
+	speaker string
 	content string
+	rating  string
 	tool    core.ToolEvent
+	output  string
 }
 
 type toolDisplayArgs struct {
@@ -64,10 +73,12 @@ type toolDisplayArgs struct {
 	Content   string `json:"content"`
 	OldString string `json:"old_string"`
 	NewString string `json:"new_string"`
+	Patch     string `json:"patch"`
 }
 
 type slashCommand struct {
 	name        string
+	label       string
 	description string
 }
 
@@ -83,17 +94,24 @@ type client struct {
 	hilView viewport.Model
 
 	notice string
+	seats  []*core.NaruAgent
 
+	speaker    string
+	handedOff  bool
 	pending    strings.Builder
 	thinking   strings.Builder
 	transcript []transcriptEntry
 	markdown   map[string]string
 	sending    bool
 	compacting bool
+	editing    bool
+	retryFrom  int
+	retryKept  []transcriptEntry
 	approval   *toolApprovalMsg
 	approvalAt int
 	slashOpen  bool
 	slashAt    int
+	outputOpen bool
 	allowed    map[string]bool
 	allowMu    sync.Mutex
 	cancel     context.CancelFunc
@@ -118,13 +136,23 @@ const (
 
 var slashCommands = []slashCommand{
 	{name: "/thinking", description: "show or change thinking"},
-	{name: "/usage", description: "show session token usage"},
+	{name: "/usage", description: "show session token usage, split by agent at a roundtable"},
 	{name: "/compact", description: "compact conversation context"},
+	{name: "/handoff", description: "hand this conversation to another agent"},
+	{name: "/tag", description: "show or add tags on this session"},
+	{name: "/untag", description: "remove tags from this session"},
+	{name: "/rate", description: "rate the last answer good or bad, with an optional note"},
+	{name: "/undo", description: "restore the files an answer changed, the last one by default"},
+	{name: "/jobs", description: "list or kill background commands started in this session"},
 	{name: "/help", description: "show command help"},
 	{name: "/exit", description: "leave the chat"},
 	{name: "/quit", description: "leave the chat"},
 }
 
+const maxLiveOutput = 65536
+const liveOutputLines = 4
+const liveOutputOpenLines = 24
+
 const (
 	transcriptMessage  = "message"
 	transcriptThinking = "thinking"
@@ -182,6 +210,8 @@ func newClientWithBackend(session *core.Session, agent *core.NaruAgent, history
 	var calls []*core.ToolCall
 	var call *core.ToolCall
 	var event core.ToolEvent
+	var names map[string]string
+	var seat *core.NaruAgent
 
 	var err error
 
@@ -216,11 +246,22 @@ func newClientWithBackend(session *core.Session, agent *core.NaruAgent, history
 		height:   24,
 		stored:   len(history) > 0,
 	}
+	names = make(map[string]string)
+	if session.TurnMode != "" {
+		c.seats, err = backend.Seats(session.Id)
+		if err != nil {
+			c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "roundtable seats error: " + err.Error()})
+		}
+		for _, seat = range c.seats {
+			names[seat.Id] = seat.Name
+		}
+	}
 	for _, cur = range history {
 		if cur.Reasoning != "" && config.Client.Thinking.Show {
 			c.transcript = append(c.transcript, transcriptEntry{kind: transcriptThinking, content: cur.Reasoning})
 		}
-		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, role: cur.Role, content: cur.Content})
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, id: cur.Id, role: cur.Role,
+			speaker: names[cur.AgentId], content: cur.Content})
 		if cur.Role != "user" {
 			continue
 		}
@@ -372,6 +413,12 @@ func shortId(id string) string {
 func (c *client) banner() string {
 	var body strings.Builder
 	var banner string
+	var names []string
+	var seat *core.NaruAgent
+
+	for _, seat = range c.seats {
+		names = append(names, seat.Name)
+	}
 
 	body.WriteString(bannerStyle.Render("✻ mininaru"))
 	if c.width < 56 {
@@ -380,7 +427,11 @@ func (c *client) banner() string {
 		return lipgloss.NewStyle().MaxWidth(max(1, c.width-4)).Render(banner)
 	}
 
-	body.WriteString(metaStyle.Render(fmt.Sprintf("  %s · %s", c.agent.Name, c.agent.Model)))
+	if len(c.seats) > 0 {
+		body.WriteString(metaStyle.Render(fmt.Sprintf("  %s · %s", strings.Join(names, ", "), c.session.TurnMode)))
+	} else {
+		body.WriteString(metaStyle.Render(fmt.Sprintf("  %s · %s", c.agent.Name, c.agent.Model)))
+	}
 	body.WriteString("\n")
 	body.WriteString(metaStyle.Render("  session " + shortId(c.session.Id)))
 
@@ -392,46 +443,79 @@ func (c *client) banner() string {
 	return lipgloss.NewStyle().MaxWidth(max(1, c.width)).Render(body.String())
 }
 
-func (c *client) sendPrompt(ctx context.Context, content string) tea.Cmd {
+func (c *client) approveTool(approvalCtx context.Context, def modules.Def, arguments string) (bool, error) {
+	var request toolApprovalMsg
+	var decision approvalDecision
+
+	if c.sessionAllowed(def.Name) {
+		return true, nil
+	}
+
+	request = toolApprovalMsg{name: def.Name, arguments: arguments,
+		response: make(chan approvalDecision, 1)}
+	c.program.Send(request)
+
+	select {
+	case decision = <-request.response:
+		return c.recordApproval(def.Name, decision), nil
+	case <-approvalCtx.Done():
+		return false, approvalCtx.Err()
+	}
+}
+
+func (c *client) sendPrompt(ctx context.Context, content string, attachments []*core.Attachment) tea.Cmd {
 	return func() tea.Msg {
+		var onContent, onReasoning func(string)
+		var onTool core.ToolEventFunc
+		var messages []*core.Message
 		var message *core.Message
 
 		var err error
 
-		message, err = c.backend.Chat(ctx, c.session, c.agent, content, func(delta string) {
+		onContent = func(delta string) {
 			c.program.Send(chatDeltaMsg(delta))
-		}, func(delta string) {
+		}
+		onReasoning = func(delta string) {
 			c.program.Send(chatThinkMsg(delta))
-		}, func(event core.ToolEvent) {
+		}
+		onTool = func(event core.ToolEvent) {
 			c.program.Send(toolEventMsg(event))
-		}, func(approvalCtx context.Context, def modules.Def, arguments string) (bool, error) {
-			var request toolApprovalMsg
-			var decision approvalDecision
+		}
 
-			if c.sessionAllowed(def.Name) {
-				return true, nil
-			}
+		if c.session.TurnMode == "" {
+			message, err = c.backend.Chat(ctx, c.session, c.agent, content, attachments, onContent, onReasoning, onTool, c.approveTool)
 
-			request = toolApprovalMsg{name: def.Name, arguments: arguments,
-				response: make(chan approvalDecision, 1)}
-			c.program.Send(request)
+			return chatDoneMsg{message: message, err: err}
+		}
 
-			select {
-			case decision = <-request.response:
-				return c.recordApproval(def.Name, decision), nil
-			case <-approvalCtx.Done():
-				return false, approvalCtx.Err()
-			}
-		})
+		messages, err = c.backend.Roundtable(ctx, c.session, content, attachments, func(agent *core.NaruAgent) {
+			c.program.Send(speakerMsg(agent.Name))
+		}, onContent, onReasoning, onTool, c.approveTool)
+		if err == nil && len(messages) > 0 {
+			message = messages[len(messages)-1]
+		}
 
 		return chatDoneMsg{message: message, err: err}
 	}
 }
 
 func (c *client) submit(content string) tea.Cmd {
+	var attachments []*core.Attachment
+	var attachment *core.Attachment
+	var names []string
 	var ctx context.Context
 	var cancel context.CancelFunc
 
+	var err error
+
+	attachments, err = mentionAttachments(content)
+	if err != nil {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "could not attach: " + err.Error()})
+		c.refreshViewport(true)
+
+		return nil
+	}
+
 	ctx, cancel = context.WithCancel(context.Background())
 
 	c.cancel = cancel
@@ -443,19 +527,142 @@ func (c *client) submit(content string) tea.Cmd {
 	c.input.Blur()
 	c.growInput()
 	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, role: "user", content: content})
+	for _, attachment = range attachments {
+		names = append(names, attachment.Name)
+	}
+	if len(names) > 0 {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "attached " + strings.Join(names, ", ")})
+	}
+	c.refreshViewport(true)
+
+	return tea.Batch(
+		c.spinner.Tick,
+		c.sendPrompt(ctx, content, attachments),
+	)
+}
+
+func (c *client) lastTurn() int {
+	var index int
+
+	for index = len(c.transcript) - 1; index >= 0; index-- {
+		if c.transcript[index].kind == transcriptMessage && c.transcript[index].role == "user" {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func (c *client) retryNotice(text string) tea.Cmd {
+	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: text})
+	c.refreshViewport(true)
+
+	return nil
+}
+
+func (c *client) retrySend(ctx context.Context, content string) tea.Cmd {
+	return func() tea.Msg {
+		var message *core.Message
+
+		var err error
+
+		message, err = c.backend.Retry(ctx, c.session, c.agent, content,
+			func(delta string) { c.program.Send(chatDeltaMsg(delta)) },
+			func(delta string) { c.program.Send(chatThinkMsg(delta)) },
+			func(event core.ToolEvent) { c.program.Send(toolEventMsg(event)) },
+			c.approveTool)
+
+		return chatDoneMsg{message: message, err: err}
+	}
+}
+
+func (c *client) retry(content string) tea.Cmd {
+	var from int
+	var shown string
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	if c.session.TurnMode != "" {
+		return c.retryNotice("a roundtable turn cannot be regenerated or edited")
+	}
+
+	from = c.lastTurn()
+	if from < 0 {
+		return c.retryNotice("nothing to regenerate yet")
+	}
+
+	shown = content
+	if shown == "" {
+		shown = c.transcript[from].content
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+
+	c.cancel = cancel
+	c.sending = true
+	c.compacting = false
+	c.editing = false
+	c.err = nil
+	c.retryFrom = from
+	c.retryKept = append([]transcriptEntry(nil), c.transcript[from:]...)
+	c.transcript = append(c.transcript[:from], transcriptEntry{kind: transcriptMessage, role: "user", content: shown})
+	c.input.Reset()
+	c.input.Blur()
+	c.growInput()
 	c.refreshViewport(true)
 
 	return tea.Batch(
 		c.spinner.Tick,
-		c.sendPrompt(ctx, content),
+		c.retrySend(ctx, content),
 	)
 }
 
+func (c *client) editLast() tea.Cmd {
+	var from int
+
+	if c.session.TurnMode != "" {
+		return c.retryNotice("a roundtable turn cannot be regenerated or edited")
+	}
+
+	from = c.lastTurn()
+	if from < 0 {
+		return c.retryNotice("nothing to edit yet")
+	}
+
+	c.editing = true
+	c.slashOpen = false
+	c.input.SetValue(c.transcript[from].content)
+	c.growInput()
+
+	return nil
+}
+
+func (c *client) nextSpeaker(name string) {
+	if c.thinkingVisible() {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptThinking, content: c.thinking.String()})
+	}
+	if c.pending.Len() > 0 {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, role: "assistant",
+			speaker: c.speaker, content: c.pending.String()})
+	}
+
+	c.pending.Reset()
+	c.thinking.Reset()
+	c.speaker = name
+	c.refreshViewport(false)
+}
+
 func (c *client) finish(msg chatDoneMsg) tea.Cmd {
 	var reply string
+	var id string
+	var speaker string
+	var kept []transcriptEntry
 	var cmds []tea.Cmd
 
 	reply = c.pending.String()
+	speaker = c.speaker
+	kept = c.retryKept
+	c.retryKept = nil
 
 	if c.thinkingVisible() {
 		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptThinking, content: c.thinking.String()})
@@ -465,11 +672,17 @@ func (c *client) finish(msg chatDoneMsg) tea.Cmd {
 	c.compacting = false
 	c.approval = nil
 	c.cancel = nil
+	c.speaker = ""
 	c.pending.Reset()
 	c.thinking.Reset()
 	c.input.Focus()
 	c.refreshContextUsage()
 
+	if msg.err != nil && kept != nil {
+		c.transcript = append(c.transcript[:c.retryFrom], kept...)
+		reply = ""
+	}
+
 	if msg.err != nil {
 		if !errors.Is(msg.err, context.Canceled) {
 			c.err = msg.err
@@ -479,7 +692,7 @@ func (c *client) finish(msg chatDoneMsg) tea.Cmd {
 		}
 
 		if reply != "" {
-			c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, role: "assistant", content: reply})
+			c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, role: "assistant", speaker: speaker, content: reply})
 		}
 
 		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "interrupted"})
@@ -491,15 +704,38 @@ func (c *client) finish(msg chatDoneMsg) tea.Cmd {
 
 	if msg.message != nil {
 		reply = msg.message.Content
+		id = msg.message.Id
 	}
 
-	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, role: "assistant", content: reply})
+	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptMessage, id: id, role: "assistant", speaker: speaker, content: reply})
+	if c.handedOff {
+		c.ownerRefresh()
+	}
 	c.refreshViewport(false)
 	cmds = append(cmds, textarea.Blink)
 
 	return tea.Batch(cmds...)
 }
 
+func (c *client) ownerRefresh() {
+	var owner *core.NaruAgent
+
+	var err error
+
+	c.handedOff = false
+
+	owner, err = c.backend.Owner(c.session.Id)
+	if err != nil {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "could not read the new owner: " + err.Error()})
+		return
+	}
+
+	c.session.AgentId = owner.Id
+	c.agent = owner
+	c.allowed = make(map[string]bool)
+	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: owner.Name + " answers from here on"})
+}
+
 func thinkingNext(level string) string {
 	var levels []string
 	var index int
@@ -623,6 +859,7 @@ func (c *client) finishCompact(msg compactDoneMsg) tea.Cmd {
 func (c *client) usageCommand() tea.Cmd {
 	var totals *core.UsageTotals
 	var notice string
+	var agent core.AgentUsage
 
 	var err error
 
@@ -637,6 +874,10 @@ func (c *client) usageCommand() tea.Cmd {
 
 	notice = fmt.Sprintf("%d tokens this session (%d prompt, %d completion, %d cache read, %d cache write)",
 		totals.TotalTokens, totals.PromptTokens, totals.CompletionTokens, totals.CachedTokens, totals.CacheWriteTokens)
+	for _, agent = range totals.Agents {
+		notice += fmt.Sprintf("\n  %s: %d tokens (%d prompt, %d completion)",
+			agent.Agent, agent.TotalTokens, agent.PromptTokens, agent.CompletionTokens)
+	}
 
 	if totals.TotalTokens == 0 {
 		notice = "no token usage recorded for this session yet"
@@ -648,6 +889,237 @@ func (c *client) usageCommand() tea.Cmd {
 	return nil
 }
 
+func (c *client) handoffCommand(args []string) tea.Cmd {
+	var name string
+	var copy bool
+	var arg string
+	var moved *core.Session
+	var target *core.NaruAgent
+	var notice string
+
+	var err error
+
+	for _, arg = range args {
+		if arg == "--copy" {
+			copy = true
+			continue
+		}
+
+		name = arg
+	}
+
+	if name == "" {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "usage: /handoff <agent> [--copy]"})
+		c.refreshViewport(false)
+
+		return nil
+	}
+
+	moved, target, err = c.backend.Handoff(c.session, name, copy)
+	if err != nil {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "could not hand off: " + err.Error()})
+		c.refreshViewport(false)
+
+		return nil
+	}
+
+	notice = "handed off to " + target.Name + ", who answers from the next message"
+	if copy {
+		notice = "copied into session " + shortId(moved.Id) + " for " + target.Name + ", the original stays with " + c.agent.Name
+	}
+
+	c.session = moved
+	c.agent = target
+	c.allowed = make(map[string]bool)
+	c.refreshContextUsage()
+	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: notice})
+	c.refreshViewport(false)
+
+	return nil
+}
+
+func (c *client) tagCommand(add, remove []string) tea.Cmd {
+	var tags []string
+	var notice string
+
+	var err error
+
+	if add == nil && len(remove) == 0 {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "usage: /untag <tag...>"})
+		c.refreshViewport(false)
+
+		return nil
+	}
+
+	tags, err = c.backend.Tag(c.session.Id, add, remove)
+	if err != nil {
+		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: "could not tag: " + err.Error()})
+		c.refreshViewport(false)
+
+		return nil
+	}
+
+	c.session.Tags = tags
+
+	notice = "no tags on this session, add some with /tag <tag...>"
+	if len(tags) > 0 {
+		notice = "tags: " + strings.Join(tags, ", ")
+	}
+
+	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: notice})
+	c.refreshViewport(false)
+
+	return nil
+}
+
+func ratingNext(rating string) string {
+	switch rating {
+	case "":
+		return core.RatingGood
+	case core.RatingGood:
+		return core.RatingBad
+	}
+
+	return ""
+}
+
+func (c *client) lastAnswer() int {
+	var index int
+
+	for index = len(c.transcript) - 1; index >= 0; index-- {
+		if c.transcript[index].kind == transcriptMessage && c.transcript[index].role == "assistant" && c.transcript[index].id != "" {
+			return index
+		}
+	}
+
+	return -1
+}
+
+func (c *client) rate(rating, note string) tea.Cmd {
+	var index int
+	var notice string
+
+	var err error
+
+	index = c.lastAnswer()
+	if index < 0 {
+		return c.retryNotice("no answer to rate yet")
+	}
+
+	err = c.backend.Rate(c.transcript[index].id, rating, note)
+	if err != nil {
+		return c.retryNotice("could not rate: " + err.Error())
+	}
+
+	c.transcript[index].rating = rating
+
+	notice = "cleared the rating on the last answer"
+	if rating != "" {
+		notice = "rated the last answer " + rating
+	}
+	if note != "" {
+		notice = notice + ": " + note
+	}
+
+	return c.retryNotice(notice)
+}
+
+func (c *client) rateCommand(args []string) tea.Cmd {
+	var rating string
+
+	if len(args) == 0 {
+		return c.retryNotice("usage: /rate <good|bad|clear> [note]")
+	}
+
+	rating = strings.ToLower(args[0])
+	if rating == "clear" {
+		rating = ""
+	}
+	if rating != "" && !core.RatingValid(rating) {
+		return c.retryNotice("usage: /rate <good|bad|clear> [note]")
+	}
+
+	return c.rate(rating, strings.Join(args[1:], " "))
+}
+
+func (c *client) undoCommand(args []string) tea.Cmd {
+	var index int
+	var messageId string
+	var restored []string
+
+	var err error
+
+	if len(args) > 1 {
+		return c.retryNotice("usage: /undo [message-id]")
+	}
+	if len(args) == 1 {
+		messageId = args[0]
+	} else {
+		index = c.lastAnswer()
+		if index < 0 {
+			return c.retryNotice("no answer to undo yet")
+		}
+		messageId = c.transcript[index].id
+	}
+
+	restored, err = c.backend.Undo(c.session.Id, messageId)
+	if err != nil {
+		return c.retryNotice("could not undo: " + err.Error())
+	}
+
+	return c.retryNotice(fmt.Sprintf("restored %d files: %s", len(restored), strings.Join(restored, ", ")))
+}
+
+func (c *client) jobsCommand(args []string) tea.Cmd {
+	var jobs []modules.BashJob
+	var job modules.BashJob
+	var body strings.Builder
+	var state string
+
+	var err error
+
+	if len(args) == 2 && strings.ToLower(args[0]) == "kill" {
+		job, err = modules.BashJobKill(c.session.Id, args[1])
+		if err != nil {
+			return c.retryNotice("could not kill: " + err.Error())
+		}
+
+		return c.retryNotice(fmt.Sprintf("killed job %s (%s): %s", job.Id, job.Exit, job.Command))
+	}
+	if len(args) > 0 {
+		return c.retryNotice("usage: /jobs [kill <id>]")
+	}
+
+	jobs = modules.BashJobs(c.session.Id)
+	if len(jobs) == 0 {
+		return c.retryNotice("no background jobs in this session")
+	}
+
+	body.WriteString("background jobs:")
+	for _, job = range jobs {
+		state = "running " + time.Since(job.Started).Round(time.Second).String()
+		if !job.Running {
+			state = job.Exit
+		}
+		fmt.Fprintf(&body, "\n  %s  pid %d  %s  %s", job.Id, job.Pid, state, job.Command)
+	}
+
+	return c.retryNotice(body.String())
+}
+
+func (c *client) runningJobs() int {
+	var job modules.BashJob
+	var running int
+
+	for _, job = range modules.BashJobs(c.session.Id) {
+		if job.Running {
+			running++
+		}
+	}
+
+	return running
+}
+
 func (c *client) exitCommand() tea.Cmd {
 	if c.cancel != nil {
 		c.cancel()
@@ -671,11 +1143,33 @@ func (c *client) helpCommand() tea.Cmd {
 	body.WriteString("\n")
 	body.WriteString(hintStyle.Render("  /compact                  fold this conversation into a summary now"))
 	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  /handoff <agent> [--copy] let another agent carry on with this history"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  /tag [tag...]             show this session's tags or add some"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  /untag <tag...>           remove tags from this session"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  /rate <good|bad|clear> [note]  rate the last answer for dataset export"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  /undo [message-id]        restore the files the last answer changed"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  /jobs [kill <id>]         list background commands, or stop one"))
+	body.WriteString("\n")
 	body.WriteString(hintStyle.Render("  /exit, /quit              leave the chat"))
 	body.WriteString("\n")
 	body.WriteString(hintStyle.Render("  /help                     this list"))
 	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  @path                     attach a file or image to the message, tab completes"))
+	body.WriteString("\n")
 	body.WriteString(hintStyle.Render("  ctrl+t                    cycle thinking level"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  ctrl+o                    show more or less of a running command's output"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  ctrl+r                    regenerate the last answer"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  ctrl+e                    edit your last message and resend it, esc cancels"))
+	body.WriteString("\n")
+	body.WriteString(hintStyle.Render("  ctrl+g                    rate the last answer: good, bad, then cleared"))
 
 	c.transcript = append(c.transcript, transcriptEntry{kind: transcriptNotice, content: body.String()})
 	c.refreshViewport(false)
@@ -711,6 +1205,30 @@ func (c *client) runCommand(input string) tea.Cmd {
 		return c.usageCommand()
 	}
 
+	if name == "handoff" {
+		return c.handoffCommand(fields[1:])
+	}
+
+	if name == "tag" {
+		return c.tagCommand(fields[1:], nil)
+	}
+
+	if name == "untag" {
+		return c.tagCommand(nil, fields[1:])
+	}
+
+	if name == "rate" {
+		return c.rateCommand(fields[1:])
+	}
+
+	if name == "undo" {
+		return c.undoCommand(fields[1:])
+	}
+
+	if name == "jobs" {
+		return c.jobsCommand(fields[1:])
+	}
+
 	if name == "exit" || name == "quit" {
 		return c.exitCommand()
 	}
@@ -734,6 +1252,7 @@ func (c *client) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var content string
 	var deltaMsg chatDeltaMsg
 	var thinkMsg chatThinkMsg
+	var nameMsg speakerMsg
 	var eventMsg toolEventMsg
 	var approvalMsg toolApprovalMsg
 	var doneMsg chatDoneMsg
@@ -863,6 +1382,12 @@ func (c *client) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return c, tea.Quit
 
 		case tea.KeyEsc:
+			if c.editing {
+				c.editing = false
+				c.input.Reset()
+				c.growInput()
+				return c, nil
+			}
 			if !c.sending {
 				return c, nil
 			}
@@ -881,6 +1406,10 @@ func (c *client) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return c, nil
 			}
 
+			if c.editing {
+				return c, c.retry(content)
+			}
+
 			if strings.HasPrefix(content, "/") {
 				return c, c.runCommand(content)
 			}
@@ -895,6 +1424,38 @@ func (c *client) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			config.Client.Thinking.Level = thinkingNext(config.Client.Thinking.Level)
 
 			return c, c.saveThinking()
+
+		case tea.KeyCtrlO:
+			c.outputOpen = !c.outputOpen
+			c.refreshViewport(false)
+
+			return c, nil
+
+		case tea.KeyCtrlR:
+			if c.sending {
+				return c, nil
+			}
+
+			return c, c.retry("")
+
+		case tea.KeyCtrlE:
+			if c.sending {
+				return c, nil
+			}
+
+			return c, c.editLast()
+
+		case tea.KeyCtrlG:
+			if c.sending {
+				return c, nil
+			}
+
+			index = c.lastAnswer()
+			if index < 0 {
+				return c, c.retryNotice("no answer to rate yet")
+			}
+
+			return c, c.rate(ratingNext(c.transcript[index].rating), "")
 		}
 
 	case chatDeltaMsg:
@@ -911,18 +1472,38 @@ func (c *client) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		return c, nil
 
+	case speakerMsg:
+		nameMsg = msg.(speakerMsg)
+		c.nextSpeaker(string(nameMsg))
+
+		return c, nil
+
 	case toolEventMsg:
 		eventMsg = msg.(toolEventMsg)
-		if eventMsg.Phase == core.ToolEventFinished {
+		if eventMsg.Phase == core.ToolEventFinished && eventMsg.Name == core.HandoffToolName && eventMsg.Status == core.MessageCompleted {
+			c.handedOff = true
+		}
+		if eventMsg.Phase == core.ToolEventOutput || eventMsg.Phase == core.ToolEventFinished {
 			for index = len(c.transcript) - 1; index >= 0; index-- {
 				if c.transcript[index].kind != transcriptTool || c.transcript[index].tool.CallId != eventMsg.CallId {
 					continue
 				}
+				if eventMsg.Phase == core.ToolEventOutput {
+					if c.transcript[index].tool.Phase == core.ToolEventStarted {
+						c.transcript[index].output = liveOutputAppend(c.transcript[index].output, eventMsg.Output)
+						c.refreshViewport(false)
+					}
+					return c, nil
+				}
 				c.transcript[index].tool = core.ToolEvent(eventMsg)
+				c.transcript[index].output = ""
 				c.refreshViewport(false)
 				return c, nil
 			}
 		}
+		if eventMsg.Phase == core.ToolEventOutput {
+			return c, nil
+		}
 		c.transcript = append(c.transcript, transcriptEntry{kind: transcriptTool, tool: core.ToolEvent(eventMsg)})
 		c.refreshViewport(false)
 
@@ -1001,11 +1582,18 @@ func (c *client) growInput() {
 }
 
 func (c *client) filteredSlashCommands() []slashCommand {
+	var word string
+	var mention bool
 	var prefix string
 	var command slashCommand
 	var commands []slashCommand
 	var level string
 
+	word, mention = mentionWord(c.input.Value())
+	if mention {
+		return mentionCandidates(word)
+	}
+
 	prefix = strings.ToLower(c.input.Value())
 	if strings.HasPrefix(prefix, "/thinking ") {
 		for _, level = range config.ThinkingLevels() {
@@ -1033,10 +1621,12 @@ func (c *client) filteredSlashCommands() []slashCommand {
 
 func (c *client) updateSlashMenu() {
 	var value string
+	var mention bool
 	var commands []slashCommand
 
 	value = c.input.Value()
-	c.slashOpen = strings.HasPrefix(value, "/") && !strings.Contains(value, "\n")
+	_, mention = mentionWord(value)
+	c.slashOpen = (strings.HasPrefix(value, "/") && !strings.Contains(value, "\n")) || mention
 	commands = c.filteredSlashCommands()
 	if len(commands) == 0 {
 		c.slashOpen = false
@@ -1051,6 +1641,9 @@ func (c *client) updateSlashMenu() {
 func (c *client) selectSlashCommand(run bool) tea.Cmd {
 	var commands []slashCommand
 	var selected string
+	var word string
+	var mention bool
+	var value string
 
 	commands = c.filteredSlashCommands()
 	if len(commands) == 0 {
@@ -1058,6 +1651,21 @@ func (c *client) selectSlashCommand(run bool) tea.Cmd {
 		return nil
 	}
 	selected = commands[c.slashAt].name
+
+	word, mention = mentionWord(c.input.Value())
+	if mention {
+		value = strings.TrimSuffix(c.input.Value(), word) + selected
+		if !strings.HasSuffix(selected, "/") {
+			value += " "
+		}
+
+		c.input.SetValue(value)
+		c.input.CursorEnd()
+		c.slashAt = 0
+		c.updateSlashMenu()
+
+		return nil
+	}
 	c.input.SetValue(selected)
 	c.input.CursorEnd()
 	c.slashOpen = false
@@ -1183,8 +1791,11 @@ func toolDisplay(event core.ToolEvent) (string, string) {
 		payload = toolDisplayArgs{}
 	}
 
-	if event.Name == "bash_exec" {
+	if event.Name == "bash_exec" || event.Name == "bash_start" {
 		title = "Bash"
+		if event.Name == "bash_start" {
+			title = "Bash in background"
+		}
 		if event.Phase == core.ToolEventStarted {
 			detail = "$ " + payload.Command
 		} else if event.Status == core.MessageCompleted {
@@ -1205,6 +1816,16 @@ func toolDisplay(event core.ToolEvent) (string, string) {
 				detail += "\n\n" + event.Error
 			}
 		}
+	} else if event.Name == "file_patch" {
+		title = "Patch " + strings.Join(modules.PatchPaths(payload.Patch), ", ")
+		if event.Phase == core.ToolEventFinished && event.Status == core.MessageCompleted {
+			detail = toolUnifiedDiff(event.Result)
+		} else {
+			detail = toolUnifiedDiff(payload.Patch)
+			if event.Phase == core.ToolEventFinished {
+				detail += "\n\n" + event.Error
+			}
+		}
 	} else if event.Name == "file_write" {
 		title = "Write " + payload.Path
 		if event.Phase == core.ToolEventFinished && event.Status == core.MessageCompleted {
@@ -1239,6 +1860,45 @@ func (c *client) renderToolEvent(event core.ToolEvent) string {
 		toolBodyStyle.Width(max(1, c.contentWidth()-2)).Render(detail)
 }
 
+func liveOutputAppend(output, chunk string) string {
+	output += chunk
+	if len(output) > maxLiveOutput {
+		output = output[len(output)-maxLiveOutput:]
+	}
+	for len(output) > 0 && !utf8.RuneStart(output[0]) {
+		output = output[1:]
+	}
+
+	return output
+}
+
+func (c *client) renderToolOutput(output string) string {
+	var lines []string
+	var index int
+	var limit int
+	var hint string
+
+	lines = strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for index = range lines {
+		lines[index] = strings.TrimSuffix(lines[index], "\r")
+		lines[index] = lines[index][strings.LastIndex(lines[index], "\r")+1:]
+	}
+
+	limit = liveOutputLines
+	hint = "ctrl+o shows more"
+	if c.outputOpen {
+		limit = liveOutputOpenLines
+		hint = "ctrl+o shows less"
+	}
+	if len(lines) > limit {
+		hint = strconv.Itoa(len(lines)-limit) + " earlier lines · " + hint
+		lines = lines[len(lines)-limit:]
+	}
+
+	return toolBodyStyle.Width(max(1, c.contentWidth()-2)).Render(strings.Join(lines, "\n")) + "\n" +
+		hintStyle.Render("    "+hint)
+}
+
 func (c *client) renderToolCall(call *core.ToolCall) string {
 	var event core.ToolEvent
 
@@ -1262,7 +1922,7 @@ func (c *client) streamView() string {
 	}
 
 	if c.pending.Len() > 0 {
-		blocks = append(blocks, c.renderPending(c.pending.String()))
+		blocks = append(blocks, c.speakerLabel(c.speaker, c.renderPending(c.pending.String())))
 	}
 
 	if len(blocks) == 0 {
@@ -1272,13 +1932,24 @@ func (c *client) streamView() string {
 	return strings.Join(blocks, "\n\n")
 }
 
+func (c *client) speakerLabel(speaker, block string) string {
+	if speaker == "" {
+		return block
+	}
+
+	return metaStyle.Render("  "+speaker) + "\n" + block
+}
+
 func (c *client) renderTranscriptEntry(entry transcriptEntry) string {
 	if entry.kind == transcriptMessage {
-		return c.renderMessage(entry.role, entry.content)
+		return c.speakerLabel(entry.speaker, c.renderMessage(entry.role, entry.content))
 	}
 	if entry.kind == transcriptThinking {
 		return c.renderThinking(entry.content)
 	}
+	if entry.kind == transcriptTool && entry.output != "" {
+		return c.renderToolEvent(entry.tool) + "\n" + c.renderToolOutput(entry.output)
+	}
 	if entry.kind == transcriptTool {
 		return c.renderToolEvent(entry.tool)
 	}
@@ -1368,7 +2039,7 @@ func (c *client) resizeHIL() int {
 	height = min(8, max(1, available-headerHeight-choicesHeight-2))
 	c.hilView.Width = max(1, c.width-4)
 	c.hilView.Height = height
-	c.hilView.SetContent(hintStyle.Width(c.contentWidth()).Render("  " + c.approval.arguments))
+	c.hilView.SetContent(hintStyle.Width(c.contentWidth()).Render("  " + approvalDetail(c.approval.name, c.approval.arguments)))
 
 	return headerHeight + height + choicesHeight + 2
 }
@@ -1382,7 +2053,12 @@ func (c *client) hintText() string {
 	level = config.Client.Thinking.Level
 	reserved = lipgloss.Width(c.contextUsage()) + 3
 
+	if c.editing && lipgloss.Width("editing · enter resend · esc cancel")+reserved <= c.contentWidth() {
+		return "editing · enter resend · esc cancel"
+	}
+
 	options = []string{
+		"enter send · ctrl+j newline · ctrl+t thinking:" + level + " · ctrl+r regenerate · ctrl+e edit · /help · ctrl+c quit",
 		"enter send · ctrl+j newline · ctrl+t thinking:" + level + " · /help · ctrl+c quit",
 		"ctrl+j newline · ctrl+t thinking:" + level + " · /help · ctrl+c quit",
 		"ctrl+t thinking:" + level + " · /help · ctrl+c quit",
@@ -1420,6 +2096,9 @@ func (c *client) slashView() string {
 			body.WriteString("\n")
 		}
 		line = "  " + command.name + "  " + command.description
+		if command.label != "" {
+			line = "  " + command.label + "  " + command.description
+		}
 		if index == c.slashAt {
 			body.WriteString(statusStyle.MaxWidth(c.contentWidth()).Render("▸ " + line))
 			continue
@@ -1431,7 +2110,23 @@ func (c *client) slashView() string {
 }
 
 func (c *client) approvalContent() string {
-	return c.approvalHeader() + "\n" + c.approval.arguments + "\n" + c.approvalChoicesView()
+	return c.approvalHeader() + "\n" + approvalDetail(c.approval.name, c.approval.arguments) + "\n" + c.approvalChoicesView()
+}
+
+func approvalDetail(name, arguments string) string {
+	var payload toolDisplayArgs
+
+	var err error
+
+	if name != "file_patch" {
+		return arguments
+	}
+	err = json.Unmarshal([]byte(arguments), &payload)
+	if err != nil || payload.Patch == "" {
+		return arguments
+	}
+
+	return toolUnifiedDiff(payload.Patch)
 }
 
 func (c *client) approvalHeader() string {
@@ -1492,7 +2187,7 @@ func (c *client) refreshContextUsage() {
 
 	var err error
 
-	tokens, window, known, err = c.backend.Context(c.session.Id)
+	tokens, window, known, err = c.backend.Context(c.agent, c.session.Id)
 	if err != nil {
 		return
 	}
@@ -1503,9 +2198,14 @@ func (c *client) refreshContextUsage() {
 
 func (c *client) statusLine(left string) string {
 	var right string
+	var running int
 	var gap int
 
 	right = c.contextUsage()
+	running = c.runningJobs()
+	if running > 0 {
+		right = fmt.Sprintf("%d jobs · %s", running, right)
+	}
 	gap = c.contentWidth() - lipgloss.Width(left) - lipgloss.Width(right)
 	if gap < 1 {
 		return left
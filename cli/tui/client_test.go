@@ -4,6 +4,9 @@
 package tui
 
 import (
+	"context"
+	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -13,9 +16,32 @@ import (
 
 	"github.com/devproje/mininaru/config"
 	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
 	"github.com/devproje/mininaru/util"
 )
 
+type handoffBackend struct {
+	localBackend
+	copied bool
+}
+
+func (b *handoffBackend) Handoff(session *core.Session, name string, copy bool) (*core.Session, *core.NaruAgent, error) {
+	var moved core.Session
+
+	b.copied = copy
+	moved = *session
+	moved.AgentId = "w"
+	if copy {
+		moved.Id = "copy"
+	}
+
+	return &moved, &core.NaruAgent{Id: "w", Name: name, Model: "worker-model"}, nil
+}
+
+func (b *handoffBackend) Owner(sessionId string) (*core.NaruAgent, error) {
+	return &core.NaruAgent{Id: "w", Name: "worker", Model: "worker-model"}, nil
+}
+
 func tuiClient(t *testing.T) *client {
 	var c *client
 
@@ -407,6 +433,17 @@ func TestDetailedToolLogRendering(t *testing.T) {
 	if !strings.Contains(rendered, "Write new.go") || !strings.Contains(rendered, "package main") {
 		t.Fatalf("file write content was not expanded: %q", rendered)
 	}
+
+	rendered = c.renderToolEvent(core.ToolEvent{Phase: core.ToolEventStarted, Name: "file_patch",
+		Arguments: `{"patch":"--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n--- a/b.go\n+++ b/b.go\n@@ -1 +1 @@\n-x\n+y\n"}`,
+		Status:    core.MessagePending})
+	if !strings.Contains(rendered, "Patch a.go, b.go") || !strings.Contains(rendered, toolAddedStyle.Render("+y")) {
+		t.Fatalf("file patch was not rendered as a diff: %q", rendered)
+	}
+	if !strings.Contains(approvalDetail("file_patch", `{"patch":"--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-old\n+new\n"}`),
+		toolCutStyle.Render("-old")) {
+		t.Fatal("file patch approval does not show the diff")
+	}
 }
 
 func TestCompactStatusNamesCompaction(t *testing.T) {
@@ -439,6 +476,38 @@ func TestFinishedToolEventReplacesItsRunningBlock(t *testing.T) {
 	}
 }
 
+func TestToolOutputStreamsIntoACollapsiblePane(t *testing.T) {
+	var c *client
+	var rendered string
+
+	c = tuiClient(t)
+	c.Update(toolEventMsg(core.ToolEvent{Phase: core.ToolEventStarted, CallId: "call-1", Name: "bash_exec",
+		Arguments: `{"command":"go test ./..."}`, Status: core.MessagePending}))
+	c.Update(toolEventMsg(core.ToolEvent{Phase: core.ToolEventOutput, CallId: "call-1", Name: "bash_exec",
+		Output: "one\ntwo\nthree\n"}))
+	c.Update(toolEventMsg(core.ToolEvent{Phase: core.ToolEventOutput, CallId: "call-1", Name: "bash_exec",
+		Output: "four\nfive\nprogress 10%\rprogress 90%\n"}))
+
+	rendered = c.renderTranscriptEntry(c.transcript[0])
+	if strings.Contains(rendered, "two") || !strings.Contains(rendered, "three") || !strings.Contains(rendered, "progress 90%") ||
+		strings.Contains(rendered, "progress 10%") || !strings.Contains(rendered, "2 earlier lines · ctrl+o shows more") {
+		t.Fatalf("collapsed output = %q", rendered)
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlO})
+	rendered = c.renderTranscriptEntry(c.transcript[0])
+	if !strings.Contains(rendered, "one") || !strings.Contains(rendered, "ctrl+o shows less") {
+		t.Fatalf("expanded output = %q", rendered)
+	}
+
+	c.Update(toolEventMsg(core.ToolEvent{Phase: core.ToolEventFinished, CallId: "call-1", Name: "bash_exec",
+		Arguments: `{"command":"go test ./..."}`, Result: "ok", Status: core.MessageCompleted}))
+	c.Update(toolEventMsg(core.ToolEvent{Phase: core.ToolEventOutput, CallId: "call-1", Name: "bash_exec", Output: "late\n"}))
+	if len(c.transcript) != 1 || c.transcript[0].output != "" {
+		t.Fatalf("finished tool kept live output: %+v", c.transcript)
+	}
+}
+
 func TestAssistantMessagesRenderMarkdown(t *testing.T) {
 	var c *client
 	var rendered string
@@ -684,6 +753,153 @@ func TestHelpListsTheNewCommands(t *testing.T) {
 	}
 }
 
+func TestEditAndRegenerateReplaceTheLastTurn(t *testing.T) {
+	var c *client
+
+	c = tuiClient(t)
+	c.transcript = []transcriptEntry{
+		{kind: transcriptMessage, role: "user", content: "question"},
+		{kind: transcriptMessage, role: "assistant", content: "meh"},
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	if !c.editing || c.input.Value() != "question" {
+		t.Fatalf("ctrl+e editing = %v, input = %q", c.editing, c.input.Value())
+	}
+	c.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if c.editing || c.input.Value() != "" {
+		t.Fatal("esc did not cancel the edit")
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlE})
+	typeEnter(c, "better question")
+	if !c.sending || len(c.transcript) != 1 || c.transcript[0].content != "better question" {
+		t.Fatalf("edit resend transcript = %#v", c.transcript)
+	}
+
+	c.finish(chatDoneMsg{err: errors.New("upstream down")})
+	if len(c.transcript) != 2 || c.transcript[0].content != "question" || c.transcript[1].content != "meh" {
+		t.Fatalf("a failed edit did not restore the earlier turn: %#v", c.transcript)
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if !c.sending || len(c.transcript) != 1 || c.transcript[0].content != "question" {
+		t.Fatalf("regenerate transcript = %#v", c.transcript)
+	}
+
+	c.finish(chatDoneMsg{message: &core.Message{Role: "assistant", Content: "better"}})
+	if len(c.transcript) != 2 || c.transcript[1].content != "better" {
+		t.Fatalf("transcript after regenerate = %#v", c.transcript)
+	}
+}
+
+func TestRegenerateRefusesARoundtable(t *testing.T) {
+	var c *client
+
+	c = tuiClient(t)
+	c.session.TurnMode = "sequential"
+	c.transcript = []transcriptEntry{{kind: transcriptMessage, role: "user", content: "question"}}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	if c.sending || !strings.Contains(c.transcript[len(c.transcript)-1].content, "roundtable") {
+		t.Fatalf("roundtable regenerate = %#v", c.transcript)
+	}
+}
+
+func TestCtrlGAndSlashRateRateTheLastAnswer(t *testing.T) {
+	var c *client
+	var session *core.Session
+	var answer *core.Message
+	var rating *core.Rating
+
+	var err error
+
+	c = tuiClient(t)
+	session, err = core.SessionCreate(&core.NaruAgent{Id: "g"}, "rated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err = core.MessageSave(session.Id, "assistant", "answer", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.transcript = []transcriptEntry{{kind: transcriptMessage, id: answer.Id, role: "assistant", content: "answer"}}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating == nil || rating.Rating != core.RatingGood {
+		t.Fatalf("rating after ctrl+g = %+v, %v", rating, err)
+	}
+
+	typeEnter(c, "/rate bad too long")
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating == nil || rating.Rating != core.RatingBad || rating.Note != "too long" {
+		t.Fatalf("rating after /rate = %+v, %v", rating, err)
+	}
+	if c.sending {
+		t.Fatal("/rate was sent to the model")
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyCtrlG})
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating != nil {
+		t.Fatalf("ctrl+g after bad should clear, got %+v, %v", rating, err)
+	}
+}
+
+func TestSlashUndoRestoresTheLastAnswersFiles(t *testing.T) {
+	var c *client
+	var session *core.Session
+	var question *core.Message
+	var answer *core.Message
+	var root string
+	var ctx context.Context
+
+	var err error
+
+	c = tuiClient(t)
+	session, err = core.SessionCreate(&core.NaruAgent{Id: "g"}, "undone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	question, err = core.MessageSave(session.Id, "user", "make a file", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err = core.MessageSave(session.Id, "assistant", "answer", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.session = session
+
+	typeEnter(c, "/undo")
+	if !strings.Contains(c.transcript[len(c.transcript)-1].content, "no answer to undo yet") {
+		t.Fatalf("undo without an answer = %#v", c.transcript)
+	}
+
+	root = t.TempDir()
+	ctx = modules.ToolTurnContext(modules.ToolSessionContext(context.Background(), session.Id), question.Id)
+	_, err = modules.FileWrite(root).Execute(ctx, `{"path":"made.txt","content":"made\n"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.transcript = []transcriptEntry{{kind: transcriptMessage, id: answer.Id, role: "assistant", content: "answer"}}
+
+	typeEnter(c, "/undo")
+	if c.sending || !strings.Contains(c.transcript[len(c.transcript)-1].content, "restored 1 files") {
+		t.Fatalf("undo transcript = %#v", c.transcript)
+	}
+	_, err = os.Stat(filepath.Join(root, "made.txt"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("file after /undo = %v", err)
+	}
+
+	typeEnter(c, "/undo "+answer.Id)
+	if !strings.Contains(c.transcript[len(c.transcript)-1].content, "could not undo") {
+		t.Fatalf("second undo = %#v", c.transcript)
+	}
+}
+
 func TestSlashUsageReportsWithoutSending(t *testing.T) {
 	var c *client
 	var notice string
@@ -700,3 +916,174 @@ func TestSlashUsageReportsWithoutSending(t *testing.T) {
 		t.Fatalf("usage notice = %q", notice)
 	}
 }
+
+func TestSlashTagTagsTheCurrentSession(t *testing.T) {
+	var c *client
+	var session *core.Session
+	var notice string
+
+	var err error
+
+	c = tuiClient(t)
+	session, err = core.SessionCreate(c.agent, "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.session = session
+
+	typeEnter(c, "/tag Release work")
+	if c.sending {
+		t.Fatal("/tag was sent to the model")
+	}
+	notice = c.transcript[len(c.transcript)-1].content
+	if notice != "tags: release, work" || len(c.session.Tags) != 2 {
+		t.Fatalf("tag notice = %q, session tags = %v", notice, c.session.Tags)
+	}
+
+	typeEnter(c, "/untag work")
+	if c.transcript[len(c.transcript)-1].content != "tags: release" {
+		t.Fatalf("untag notice = %q", c.transcript[len(c.transcript)-1].content)
+	}
+
+	typeEnter(c, "/tag bad/tag")
+	if !strings.Contains(c.transcript[len(c.transcript)-1].content, "could not tag") {
+		t.Fatalf("bad tag notice = %q", c.transcript[len(c.transcript)-1].content)
+	}
+
+	typeEnter(c, "/untag")
+	if !strings.Contains(c.transcript[len(c.transcript)-1].content, "usage: /untag") {
+		t.Fatalf("empty untag notice = %q", c.transcript[len(c.transcript)-1].content)
+	}
+}
+
+func TestSpeakerChangeLabelsEachRoundtableReply(t *testing.T) {
+	var c *client
+	var entry transcriptEntry
+	var view string
+
+	c = tuiClient(t)
+	c.sending = true
+
+	c.Update(speakerMsg("alpha"))
+	c.Update(chatDeltaMsg("first answer"))
+	c.Update(speakerMsg("beta"))
+	if c.pending.Len() != 0 || c.speaker != "beta" {
+		t.Fatalf("speaker change kept pending %q for %q", c.pending.String(), c.speaker)
+	}
+
+	entry = c.transcript[len(c.transcript)-1]
+	if entry.speaker != "alpha" || entry.content != "first answer" {
+		t.Fatalf("flushed entry = %+v", entry)
+	}
+
+	c.Update(chatDeltaMsg("second answer"))
+	c.Update(chatDoneMsg{message: &core.Message{Content: "second answer"}})
+
+	entry = c.transcript[len(c.transcript)-1]
+	if entry.speaker != "beta" || c.speaker != "" {
+		t.Fatalf("final entry = %+v, speaker left at %q", entry, c.speaker)
+	}
+
+	view = c.transcriptContent()
+	if !strings.Contains(view, "alpha") || !strings.Contains(view, "beta") {
+		t.Fatalf("speaker labels missing: %q", view)
+	}
+}
+
+func TestSlashHandoffSwitchesTheAgentWithoutSending(t *testing.T) {
+	var c *client
+	var backend *handoffBackend
+
+	c = tuiClient(t)
+	backend = &handoffBackend{}
+	c.backend = backend
+	c.allowed["bash"] = true
+
+	typeEnter(c, "/handoff worker")
+
+	if c.sending {
+		t.Fatal("/handoff was sent to the model")
+	}
+	if c.agent.Name != "worker" || c.session.Id != "s" || c.session.AgentId != "w" || c.allowed["bash"] {
+		t.Fatalf("agent = %+v, session = %+v, allowed = %v", c.agent, c.session, c.allowed)
+	}
+	if !strings.Contains(c.transcript[len(c.transcript)-1].content, "handed off to worker") {
+		t.Fatalf("handoff notice = %q", c.transcript[len(c.transcript)-1].content)
+	}
+
+	typeEnter(c, "/handoff --copy naru")
+	if !backend.copied || c.session.Id != "copy" || !strings.Contains(c.transcript[len(c.transcript)-1].content, "original stays with worker") {
+		t.Fatalf("copy handoff session = %+v, notice = %q", c.session, c.transcript[len(c.transcript)-1].content)
+	}
+
+	typeEnter(c, "/handoff")
+	if !strings.Contains(c.transcript[len(c.transcript)-1].content, "usage: /handoff") {
+		t.Fatalf("missing agent notice = %q", c.transcript[len(c.transcript)-1].content)
+	}
+}
+
+func TestHandoffToolMovesTheClientAfterTheReply(t *testing.T) {
+	var c *client
+
+	c = tuiClient(t)
+	c.backend = &handoffBackend{}
+	c.sending = true
+
+	c.Update(toolEventMsg{Phase: core.ToolEventFinished, CallId: "h1", Name: core.HandoffToolName, Status: core.MessageCompleted})
+	if c.agent.Name != "naru" {
+		t.Fatal("the agent changed before the reply finished")
+	}
+
+	c.Update(chatDoneMsg{message: &core.Message{Content: "over to the worker"}})
+	if c.agent.Name != "worker" || c.session.AgentId != "w" {
+		t.Fatalf("agent after the reply = %+v", c.agent)
+	}
+	if !strings.Contains(c.transcript[len(c.transcript)-1].content, "worker answers from here on") {
+		t.Fatalf("owner notice = %q", c.transcript[len(c.transcript)-1].content)
+	}
+}
+
+func TestAtPathCompletesAndAttachesTheFile(t *testing.T) {
+	var c *client
+	var dir string
+	var view string
+	var paths []string
+	var entry transcriptEntry
+	var attached bool
+
+	var err error
+
+	dir = t.TempDir()
+	err = os.WriteFile(filepath.Join(dir, "notes.md"), []byte("# notes"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c = tuiClient(t)
+	c.input.SetValue("look at @" + dir + "/no")
+	c.updateSlashMenu()
+	view = c.View()
+	if !c.slashOpen || !strings.Contains(view, "notes.md") {
+		t.Fatalf("path completion did not open: %q", view)
+	}
+
+	c.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if c.input.Value() != "look at @"+dir+"/notes.md " || c.slashOpen {
+		t.Fatalf("tab completed %q with menu open=%t", c.input.Value(), c.slashOpen)
+	}
+
+	paths = mentionPaths("look at @" + dir + "/notes.md, thanks @bob")
+	if len(paths) != 1 || paths[0] != filepath.Join(dir, "notes.md") {
+		t.Fatalf("mentioned paths = %v, want only the existing file", paths)
+	}
+
+	typeEnter(c, "look at @"+dir+"/notes.md")
+	for _, entry = range c.transcript {
+		if entry.kind == transcriptNotice && entry.content == "attached notes.md" {
+			attached = true
+		}
+	}
+	if !c.sending || !attached {
+		t.Fatal("the mentioned file was not attached to the message")
+	}
+}
@@ -64,7 +64,7 @@ func webShow() error {
 	cfg = modules.WebSearchConfig()
 
 	rows = uiTable("PROVIDER", "ENDPOINT", "API KEY")
-	rows.row(cfg.Provider, cfg.Endpoint, maskSecret(cfg.APIKey))
+	rows.row(cfg.Provider, cfg.Endpoint, secretLabel(cfg.APIKey, cfg.APIKeyRef()))
 	rows.flush()
 
 	return nil
@@ -114,7 +114,7 @@ func webKeyExecute(cmd *cobra.Command, args []string) error {
 	var cfg modules.SearchConfig
 
 	cfg = modules.WebSearchConfig()
-	cfg.APIKey = strings.TrimSpace(args[0])
+	cfg.SetAPIKey(strings.TrimSpace(args[0]))
 
 	return webApply(cfg)
 }
@@ -5,7 +5,9 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/devproje/mininaru/util"
@@ -17,28 +19,48 @@ type Thinking struct {
 }
 
 type Context struct {
-	Compact bool `json:"compact"`
+	Compact   bool     `json:"compact"`
+	Strategy  []string `json:"strategy"`
+	KeepTurns int      `json:"keep_turns"`
+}
+
+type Retrieval struct {
+	TopK int `json:"top_k"`
+}
+
+type Git struct {
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
 }
 
 type Tools struct {
 	Enabled bool `json:"enabled"`
+	Git     Git  `json:"git"`
 }
 
 type Update struct {
 	Check bool `json:"check"`
 }
 
+type Retention struct {
+	SessionDays      int `json:"session_days"`
+	SessionsPerAgent int `json:"sessions_per_agent"`
+	UsageDays        int `json:"usage_days"`
+}
+
 type Server struct {
 	Address string `json:"address"`
 }
 
 type ClientConfig struct {
-	Mode     string   `json:"mode,omitempty"`
-	Thinking Thinking `json:"thinking"`
-	Context  Context  `json:"context"`
-	Tools    Tools    `json:"tools"`
-	Update   Update   `json:"update"`
-	Server   Server   `json:"server"`
+	Mode      string    `json:"mode,omitempty"`
+	Thinking  Thinking  `json:"thinking"`
+	Context   Context   `json:"context"`
+	Retrieval Retrieval `json:"retrieval"`
+	Tools     Tools     `json:"tools"`
+	Update    Update    `json:"update"`
+	Retention Retention `json:"retention"`
+	Server    Server    `json:"server"`
 }
 
 const CLIENT_PATH = "client.json"
@@ -58,15 +80,22 @@ const (
 	ThinkingMax    = "max"
 )
 
+const (
+	StrategyElide     = "elide"
+	StrategyWindow    = "window"
+	StrategySummarize = "summarize"
+)
+
 var Client ClientConfig
 
 var AllowDangerousTools bool
 
 var defaultClient ClientConfig = ClientConfig{
-	Thinking: Thinking{Level: ThinkingOff, Show: true},
-	Context:  Context{Compact: true},
-	Tools:    Tools{Enabled: true},
-	Update:   Update{Check: true},
+	Thinking:  Thinking{Level: ThinkingOff, Show: true},
+	Context:   Context{Compact: true, Strategy: []string{StrategyElide, StrategySummarize}, KeepTurns: 4},
+	Retrieval: Retrieval{TopK: 8},
+	Tools:     Tools{Enabled: true},
+	Update:    Update{Check: true},
 }
 
 func ThinkingLevels() []string {
@@ -87,6 +116,58 @@ func ThinkingValid(level string) bool {
 	return false
 }
 
+func CompactStrategies() []string {
+	return []string{StrategyElide, StrategyWindow, StrategySummarize}
+}
+
+func CompactStrategyOrder(names []string) ([]string, error) {
+	var ordered []string
+	var unknown []string
+	var name string
+
+	for _, name = range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(ordered, name) {
+			continue
+		}
+		if !slices.Contains(CompactStrategies(), name) {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		ordered = append(ordered, name)
+	}
+
+	if len(unknown) > 0 {
+		return ordered, fmt.Errorf("unknown compaction strategy %q, want %s", strings.Join(unknown, ", "), strings.Join(CompactStrategies(), ", "))
+	}
+	if len(ordered) == 0 {
+		return nil, fmt.Errorf("at least one compaction strategy is required")
+	}
+
+	return ordered, nil
+}
+
+func ContextStrategy() []string {
+	if len(Client.Context.Strategy) == 0 {
+		return defaultClient.Context.Strategy
+	}
+
+	return Client.Context.Strategy
+}
+
+func ContextKeepTurns() int {
+	if Client.Context.KeepTurns <= 0 {
+		return defaultClient.Context.KeepTurns
+	}
+
+	return Client.Context.KeepTurns
+}
+
+func RetentionEnabled() bool {
+	return Client.Retention.SessionDays > 0 || Client.Retention.SessionsPerAgent > 0 || Client.Retention.UsageDays > 0
+}
+
 func ThinkingEnabled() bool {
 	return Client.Thinking.Level != "" && Client.Thinking.Level != ThinkingOff
 }
@@ -110,10 +191,12 @@ func RemoteClient() bool {
 func ClientInit() error {
 	var path string
 	var buf []byte
+	var strategy []string
 
 	var err error
 
 	Client = defaultClient
+	Client.Context.Strategy = slices.Clone(defaultClient.Context.Strategy)
 
 	path = util.Path(CLIENT_PATH)
 	buf, err = os.ReadFile(path)
@@ -144,6 +227,21 @@ func ClientInit() error {
 		Client.Thinking.Level = defaultClient.Thinking.Level
 	}
 
+	strategy, err = CompactStrategyOrder(Client.Context.Strategy)
+	if err != nil && len(strategy) > 0 {
+		util.Log.Warn("ignoring an invalid compaction strategy", "config", CLIENT_PATH, "error", err)
+	} else if err != nil {
+		util.Log.Warn("ignoring an invalid compaction strategy",
+			"config", CLIENT_PATH, "error", err, "fallback", strings.Join(defaultClient.Context.Strategy, ","))
+
+		strategy = slices.Clone(defaultClient.Context.Strategy)
+	}
+	Client.Context.Strategy = strategy
+
+	if Client.Context.KeepTurns <= 0 {
+		Client.Context.KeepTurns = defaultClient.Context.KeepTurns
+	}
+
 	return nil
 }
 
@@ -5,6 +5,7 @@ package config
 
 import (
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/devproje/mininaru/util"
@@ -101,3 +102,48 @@ func TestLegacyPairedConfigStillUsesTheServer(t *testing.T) {
 		t.Fatal("legacy paired config stopped using the server")
 	}
 }
+
+func TestCompactStrategiesKeepTheConfiguredOrder(t *testing.T) {
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(util.Path(CLIENT_PATH), []byte(`{"context":{"compact":true,"strategy":["Summarize","window","forget","summarize"]}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ClientInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(Client.Context.Strategy, []string{StrategySummarize, StrategyWindow}) {
+		t.Fatalf("strategy = %v, want summarize then window", Client.Context.Strategy)
+	}
+	if Client.Context.KeepTurns != defaultClient.Context.KeepTurns {
+		t.Fatalf("keep_turns = %d, want the default", Client.Context.KeepTurns)
+	}
+}
+
+func TestUnknownCompactStrategyFallsBackToTheDefault(t *testing.T) {
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(util.Path(CLIENT_PATH), []byte(`{"context":{"compact":true,"strategy":["forget"],"keep_turns":2}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ClientInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(Client.Context.Strategy, defaultClient.Context.Strategy) || Client.Context.KeepTurns != 2 {
+		t.Fatalf("strategy = %v keep_turns = %d, want the default strategy and 2 turns", Client.Context.Strategy, Client.Context.KeepTurns)
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/anthropics/anthropic-sdk-go"
 	anthropicoption "github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/devproje/mininaru/config"
 	"github.com/devproje/mininaru/util"
 	"github.com/google/uuid"
 	"github.com/openai/openai-go"
@@ -31,8 +32,19 @@ type NaruAgent struct {
 	Model      string `json:"model"`
 	ProviderId string `json:"provider_id"`
 
+	ContextWindow   int64    `json:"context_window,omitempty"`
+	ContextStrategy []string `json:"context_strategy,omitempty"`
+
+	Sampling       Sampling       `json:"sampling,omitzero"`
+	SamplingBounds SamplingBounds `json:"sampling_bounds,omitzero"`
+
+	Vision  *bool  `json:"vision,omitempty"`
+	Sandbox string `json:"sandbox,omitempty"`
+
 	AI        *openai.Client    `json:"-"`
 	Anthropic *anthropic.Client `json:"-"`
+
+	persona *agentPersona
 }
 
 var modelContextWindows sync.Map
@@ -58,6 +70,9 @@ func (a *NaruAgent) CachedModelContextWindow() int64 {
 	var cacheKey string
 	var ok bool
 
+	if a != nil && a.ContextWindow > 0 {
+		return a.ContextWindow
+	}
 	cacheKey = a.modelContextCacheKey()
 	if cacheKey == "" {
 		return 0
@@ -70,6 +85,28 @@ func (a *NaruAgent) CachedModelContextWindow() int64 {
 	return cached.(int64)
 }
 
+func (a *NaruAgent) SeesImages() bool {
+	return a.Vision == nil || *a.Vision
+}
+
+func (a *NaruAgent) CompactStrategies() []string {
+	var ordered []string
+
+	var err error
+
+	if a == nil || len(a.ContextStrategy) == 0 {
+		return config.ContextStrategy()
+	}
+
+	ordered, err = config.CompactStrategyOrder(a.ContextStrategy)
+	if err != nil {
+		util.Log.Warn("ignoring the agent compaction strategy", "agent", a.Id, "error", err)
+		return config.ContextStrategy()
+	}
+
+	return ordered
+}
+
 func (a *NaruAgent) ModelContextWindow(ctx context.Context) int64 {
 	var provider *Provider
 	var requestCtx context.Context
@@ -94,6 +131,9 @@ func (a *NaruAgent) ModelContextWindow(ctx context.Context) int64 {
 	if a == nil {
 		return 0
 	}
+	if a.ContextWindow > 0 {
+		return a.ContextWindow
+	}
 	provider, err = ProviderFind(a.ProviderId)
 	if err != nil || provider.BaseURL == "" || provider.ProviderKind() == ProviderAnthropic {
 		return 0
@@ -252,6 +292,7 @@ func AgentInit() error {
 	var path string
 	var buf []byte
 	var cfg AgentConfig
+	var created bool
 	var cur *NaruAgent
 
 	var err error
@@ -279,6 +320,18 @@ func AgentInit() error {
 	Global = cfg.Global
 	Agents = cfg.Agents
 
+	created, err = personaLoad()
+	if err != nil {
+		return err
+	}
+
+	if created {
+		err = AgentSave()
+		if err != nil {
+			return err
+		}
+	}
+
 	if Global != nil {
 		configureAgentClients(Global, agentProvider(Global))
 	}
@@ -292,14 +345,15 @@ func AgentInit() error {
 
 func AgentSave() error {
 	var cfg AgentConfig
+	var cur *NaruAgent
 	var path string
 	var buf []byte
 
 	var err error
 
-	cfg = AgentConfig{
-		Global: Global,
-		Agents: Agents,
+	cfg = AgentConfig{Global: agentStored(Global)}
+	for _, cur = range Agents {
+		cfg.Agents = append(cfg.Agents, agentStored(cur))
 	}
 
 	path = util.Path(AGENT_PATH)
@@ -459,7 +513,8 @@ func AgentRefreshClient(agent *NaruAgent) error {
 	return nil
 }
 
-func AgentUpdateFields(id string, name, role, soul, model, providerId *string) error {
+func AgentUpdateFields(id string, name, role, soul, model, providerId *string, contextWindow *int64, contextStrategy *[]string,
+	sampling *Sampling, bounds *SamplingBounds, vision *bool, sandbox *string) error {
 	var index int
 	var cur *NaruAgent
 	var update NaruAgent
@@ -471,6 +526,11 @@ func AgentUpdateFields(id string, name, role, soul, model, providerId *string) e
 			continue
 		}
 
+		err = cur.PersonaCheck(name, role, soul, model, providerId)
+		if err != nil {
+			return err
+		}
+
 		update = *cur
 
 		if name != nil {
@@ -494,6 +554,30 @@ func AgentUpdateFields(id string, name, role, soul, model, providerId *string) e
 			configureAgentClients(&update, agentProvider(&update))
 		}
 
+		if contextWindow != nil {
+			update.ContextWindow = *contextWindow
+		}
+
+		if contextStrategy != nil {
+			update.ContextStrategy = *contextStrategy
+		}
+
+		if sampling != nil {
+			update.Sampling = sampling.Clone()
+		}
+
+		if bounds != nil {
+			update.SamplingBounds = *bounds
+		}
+
+		if vision != nil {
+			update.Vision = vision
+		}
+
+		if sandbox != nil {
+			update.Sandbox = *sandbox
+		}
+
 		Agents[index] = &update
 		err = AgentSave()
 		if err != nil {
@@ -527,7 +611,7 @@ func AgentUpdate(id string, payload NaruAgent) error {
 		providerId = &payload.ProviderId
 	}
 
-	return AgentUpdateFields(id, name, role, soul, model, providerId)
+	return AgentUpdateFields(id, name, role, soul, model, providerId, nil, nil, nil, nil, nil, nil)
 }
 
 func AgentDelete(ref string) error {
@@ -542,6 +626,10 @@ func AgentDelete(ref string) error {
 		return err
 	}
 
+	if target.PersonaDir() != "" {
+		return fmt.Errorf("agent %s is loaded from %s, remove that directory first", target.Name, target.PersonaDir())
+	}
+
 	for _, cur = range Agents {
 		if cur.Id == target.Id {
 			continue
@@ -303,7 +303,7 @@ func TestAgentDeleteGlobalPromotesSuccessorAndDropsSessions(t *testing.T) {
 		t.Fatalf("removed agent left %d orphan sessions", count)
 	}
 
-	sessions, err = SessionList(Global.Id)
+	sessions, err = SessionList(Global.Id, SessionFilter{})
 	if err != nil {
 		t.Fatal(err)
 	}
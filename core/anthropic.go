@@ -275,6 +275,23 @@ func (r *completionRun) executeAnthropic(ctx context.Context) (*Completion, erro
 	if r.Params.ReasoningEffort != "" {
 		params.Thinking = anthropic.ThinkingConfigParamUnion{OfAdaptive: &anthropic.ThinkingConfigAdaptiveParam{}}
 	}
+	if r.Params.MaxTokens.Valid() {
+		params.MaxTokens = r.Params.MaxTokens.Value
+	}
+	if r.Params.Temperature.Valid() {
+		params.Temperature = anthropic.Float(r.Params.Temperature.Value)
+	}
+	if r.Params.TopP.Valid() {
+		params.TopP = anthropic.Float(r.Params.TopP.Value)
+	}
+	if len(r.Params.Stop.OfStringArray) > 0 {
+		params.StopSequences = r.Params.Stop.OfStringArray
+	}
+	if len(r.Params.ExtraFields()) > 0 {
+		params.SetExtraFields(r.Params.ExtraFields())
+	}
+
+	ctx = r.toolContext(ctx)
 
 	for round = 0; round < maxToolRounds; round++ {
 		result.Content = ""
@@ -309,14 +326,14 @@ func (r *completionRun) executeAnthropic(ctx context.Context) (*Completion, erro
 				call = openai.ChatCompletionMessageToolCall{ID: block.ID}
 				call.Function.Name = block.Name
 				call.Function.Arguments = string(block.Input)
-				record, err = toolCallStart(r.MessageId, call)
+				record, err = toolCallStart(r.MessageId, r.AgentId, call)
 				if err != nil {
 					return nil, err
 				}
 				if r.OnTool != nil {
 					r.OnTool(ToolEvent{Phase: ToolEventStarted, CallId: record.CallId, Name: record.Name, Arguments: record.Arguments, Status: record.Status})
 				}
-				record, err = executeTool(ctx, r.SessionId, record, r.Defs, r.AllowDangerous, r.AllowPrivileged, r.Approve)
+				record, err = executeTool(toolProgressContext(ctx, record, r.OnTool), r.SessionId, record, r.Defs, r.AllowDangerous, r.AllowPrivileged, r.Approve)
 				if err != nil {
 					return nil, err
 				}
@@ -324,7 +341,7 @@ func (r *completionRun) executeAnthropic(ctx context.Context) (*Completion, erro
 					r.OnTool(ToolEvent{Phase: ToolEventFinished, CallId: record.CallId, Name: record.Name, Arguments: record.Arguments,
 						Result: record.Result, Status: record.Status, Error: record.Error})
 				}
-				toolResults = append(toolResults, anthropic.NewToolResultBlock(block.ID, record.Result, record.Status != MessageCompleted))
+				toolResults = append(toolResults, anthropicToolResult(block.ID, record))
 			}
 		}
 		if len(toolResults) == 0 {
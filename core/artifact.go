@@ -0,0 +1,267 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"github.com/google/uuid"
+)
+
+type Artifact struct {
+	Id         string `json:"id"`
+	SessionId  string `json:"session_id"`
+	ToolCallId string `json:"tool_call_id"`
+	Name       string `json:"name"`
+	MediaType  string `json:"media_type,omitempty"`
+	Content    string `json:"content"`
+}
+
+const ArtifactToolName = "artifact_read"
+
+const (
+	artifactThreshold    = 16 << 10
+	artifactExcerptChars = 2048
+	artifactPageLines    = 200
+	artifactMaxLines     = 2000
+	artifactLineChars    = 512
+)
+
+var artifactToolInstalled bool
+
+func artifactSave(sessionId, toolCallId, name, content string) (string, error) {
+	var id string
+
+	var err error
+
+	id = uuid.NewString()
+	_, err = util.DB.Exec(`INSERT INTO artifacts (id, session_id, tool_call_id, name, content) VALUES (?, ?, ?, ?, ?);`,
+		id, sessionId, toolCallId, name, content)
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func ArtifactLoad(sessionId, id string) (*Artifact, error) {
+	var artifact Artifact
+
+	var err error
+
+	err = util.DB.QueryRow(`SELECT id, session_id, tool_call_id, name, media_type, content FROM artifacts WHERE id = ? AND session_id = ?;`,
+		id, sessionId).Scan(&artifact.Id, &artifact.SessionId, &artifact.ToolCallId, &artifact.Name, &artifact.MediaType, &artifact.Content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("no artifact %s in this conversation", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &artifact, nil
+}
+
+func artifactExcerpt(id, name, content string) string {
+	var runes []rune
+
+	runes = []rune(content)
+	if len(runes) <= 2*artifactExcerptChars {
+		return content
+	}
+
+	return fmt.Sprintf("%s\n\n[... %d of %d characters of %s output omitted. The full output is artifact %q: "+
+		"call %s with that id and a line range or a grep pattern to read more ...]\n\n%s",
+		string(runes[:artifactExcerptChars]), len(runes)-2*artifactExcerptChars, len(runes), name,
+		id, ArtifactToolName, string(runes[len(runes)-artifactExcerptChars:]))
+}
+
+func artifactOffload(sessionId string, record *ToolCall) string {
+	var id string
+
+	var err error
+
+	if sessionId == "" || record.Id == "" || len(record.Result) <= artifactThreshold {
+		return record.Result
+	}
+
+	id, err = artifactSave(sessionId, record.Id, record.Name, record.Result)
+	if err != nil {
+		util.Log.Warn("storing a large tool result as an artifact failed, keeping it inline",
+			"tool", record.Name, "chars", len(record.Result), "error", err)
+
+		return record.Result
+	}
+
+	return artifactExcerpt(id, record.Name, record.Result)
+}
+
+func clipLine(line string) string {
+	var runes []rune
+
+	runes = []rune(line)
+	if len(runes) <= artifactLineChars {
+		return line
+	}
+
+	return string(runes[:artifactLineChars]) + "…"
+}
+
+func artifactGrep(lines []string, expr *regexp.Regexp, offset, limit int) string {
+	var found []string
+	var index int
+
+	for index = offset - 1; index < len(lines); index++ {
+		if !expr.MatchString(lines[index]) {
+			continue
+		}
+
+		if len(found) == limit {
+			return strings.Join(found, "\n") + fmt.Sprintf("\n[more matches; pass offset %d to continue]", index+1)
+		}
+
+		found = append(found, fmt.Sprintf("%d:%s", index+1, clipLine(lines[index])))
+	}
+
+	if len(found) == 0 {
+		return "no match for " + expr.String()
+	}
+
+	return strings.Join(found, "\n")
+}
+
+func artifactLines(lines []string, offset, limit int) string {
+	var end int
+	var index int
+	var page []string
+
+	end = min(offset-1+limit, len(lines))
+	for index = offset - 1; index < end; index++ {
+		page = append(page, clipLine(lines[index]))
+	}
+
+	if end == len(lines) {
+		return strings.Join(page, "\n") + fmt.Sprintf("\n[lines %d-%d of %d]", offset, end, len(lines))
+	}
+
+	return strings.Join(page, "\n") + fmt.Sprintf("\n[lines %d-%d of %d; pass offset %d to continue]", offset, end, len(lines), end+1)
+}
+
+func ArtifactReadTool() modules.Def {
+	return modules.Def{
+		Name: ArtifactToolName,
+		Description: "Read part of a large tool output that was stored as an artifact instead of being kept in the conversation. " +
+			"Pass the artifact id from the excerpt, then either offset and limit to read a range of lines, " +
+			"or grep with a regular expression to list matching lines with their line numbers.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":          map[string]any{"type": "string"},
+				"offset":      map[string]any{"type": "integer", "minimum": 1},
+				"limit":       map[string]any{"type": "integer", "minimum": 1, "maximum": artifactMaxLines},
+				"grep":        map[string]any{"type": "string"},
+				"ignore_case": map[string]any{"type": "boolean"},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+		Permission: modules.PermissionSafe,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Id         string `json:"id"`
+				Offset     int    `json:"offset"`
+				Limit      int    `json:"limit"`
+				Grep       string `json:"grep"`
+				IgnoreCase bool   `json:"ignore_case"`
+			}
+			var policy subagentPolicy
+			var ok bool
+			var artifact *Artifact
+			var lines []string
+			var source string
+			var expr *regexp.Regexp
+
+			var err error
+
+			if err = ctx.Err(); err != nil {
+				return "", err
+			}
+
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+
+			payload.Id = strings.TrimSpace(payload.Id)
+			if payload.Id == "" {
+				return "", fmt.Errorf("id is required")
+			}
+			if payload.Offset <= 0 {
+				payload.Offset = 1
+			}
+			if payload.Limit <= 0 {
+				payload.Limit = artifactPageLines
+			}
+			if payload.Limit > artifactMaxLines {
+				return "", fmt.Errorf("limit cannot exceed %d", artifactMaxLines)
+			}
+
+			policy, ok = subagentPolicyFrom(ctx)
+			if !ok || policy.SessionId == "" {
+				return "", fmt.Errorf("%s is only available inside a chat session", ArtifactToolName)
+			}
+
+			artifact, err = ArtifactLoad(policy.SessionId, payload.Id)
+			if err != nil {
+				return "", err
+			}
+			if artifact.MediaType != "" {
+				return "", fmt.Errorf("artifact %s is an image from %s and is already shown in the conversation", artifact.Id, artifact.Name)
+			}
+
+			lines = strings.Split(artifact.Content, "\n")
+			if payload.Offset > len(lines) {
+				return "", fmt.Errorf("offset %d is past the last line %d", payload.Offset, len(lines))
+			}
+
+			if payload.Grep == "" {
+				return artifactLines(lines, payload.Offset, payload.Limit), nil
+			}
+
+			source = payload.Grep
+			if payload.IgnoreCase {
+				source = "(?i)" + source
+			}
+
+			expr, err = regexp.Compile(source)
+			if err != nil {
+				return "", fmt.Errorf("invalid pattern: %w", err)
+			}
+
+			return artifactGrep(lines, expr, payload.Offset, payload.Limit), nil
+		},
+	}
+}
+
+func InstallArtifactTool() {
+	if artifactToolInstalled {
+		return
+	}
+
+	artifactToolInstalled = true
+
+	modules.RegisterBuiltin(ArtifactReadTool, modules.BuiltinHints{
+		Title:       "read a stored tool output",
+		ReadOnly:    true,
+		Destructive: false,
+		OpenWorld:   false,
+	})
+}
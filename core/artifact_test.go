@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+func artifactLog(lines int) string {
+	var builder strings.Builder
+	var index int
+
+	for index = 1; index <= lines; index++ {
+		fmt.Fprintf(&builder, "line %05d %s\n", index, strings.Repeat("-", 40))
+	}
+
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+func TestLargeToolResultsBecomeArtifacts(t *testing.T) {
+	var srv *httptest.Server
+	var requests []string
+	var session *Session
+	var agent *NaruAgent
+	var output string
+	var def modules.Def
+	var history []*Message
+	var calls []*ToolCall
+	var id string
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+
+		body, _ = io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		if len(requests) == 1 {
+			io.WriteString(w, toolChunk("r1", `{"role":"assistant","tool_calls":[{"index":0,"id":"call-1","type":"function","function":{"name":"echo","arguments":"{}"}}]}`, `"tool_calls"`))
+		} else {
+			io.WriteString(w, toolChunk("r2", `{"role":"assistant","content":"done"}`, `"stop"`))
+		}
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	session, agent = thinkingSetup(t, srv.URL)
+	output = artifactLog(1000)
+	def = modules.Def{
+		Name: "echo", Permission: modules.PermissionSafe, Parameters: map[string]any{"type": "object"},
+		Execute: func(context.Context, string) (string, error) { return output, nil },
+	}
+
+	_, err = chatWithTools(context.Background(), session, agent, "dump the log", nil, []modules.Def{def}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(requests[1], "line 00500") || !strings.Contains(requests[1], "line 00001") || !strings.Contains(requests[1], "line 01000") {
+		t.Fatal("the model was sent the whole output instead of its head and tail")
+	}
+
+	history, err = MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls, err = ToolCallList(history[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls[0].Result) >= len(output) || !strings.Contains(calls[0].Result, ArtifactToolName) {
+		t.Fatalf("stored result is %d characters, want an excerpt", len(calls[0].Result))
+	}
+
+	err = util.DB.QueryRow("SELECT id FROM artifacts WHERE session_id = ? AND tool_call_id = ? AND content = ?;",
+		session.Id, calls[0].Id, output).Scan(&id)
+	if err != nil {
+		t.Fatalf("the full output was not kept as an artifact: %v", err)
+	}
+	if !strings.Contains(calls[0].Result, id) {
+		t.Fatal("the excerpt does not name its artifact")
+	}
+}
+
+func TestSmallToolResultsStayInline(t *testing.T) {
+	var record ToolCall
+
+	record = ToolCall{Id: "t1", Name: "echo", Result: "short"}
+	if artifactOffload("s1", &record) != "short" {
+		t.Fatal("a small result was offloaded")
+	}
+}
+
+func TestArtifactReadPagesAndGreps(t *testing.T) {
+	var session *Session
+	var agent *NaruAgent
+	var toolCallId string
+	var id string
+	var ctx context.Context
+	var out string
+	var other *Session
+
+	var err error
+
+	session, agent = thinkingSetup(t, "http://127.0.0.1")
+	toolCallId = seedToolTurn(t, session.Id, "excerpt")
+	id, err = artifactSave(session.Id, toolCallId, "bash_exec", artifactLog(300))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx = subagentContext(context.Background(), subagentPolicy{SessionId: session.Id})
+
+	out, err = ArtifactReadTool().Execute(ctx, fmt.Sprintf(`{"id":%q}`, id))
+	if err != nil || !strings.HasPrefix(out, "line 00001") || !strings.Contains(out, "pass offset 201") {
+		t.Fatalf("first page = %q err = %v", out, err)
+	}
+
+	out, err = ArtifactReadTool().Execute(ctx, fmt.Sprintf(`{"id":%q,"offset":299,"limit":5}`, id))
+	if err != nil || !strings.HasPrefix(out, "line 00299") || !strings.HasSuffix(out, "[lines 299-300 of 300]") {
+		t.Fatalf("last page = %q err = %v", out, err)
+	}
+
+	out, err = ArtifactReadTool().Execute(ctx, fmt.Sprintf(`{"id":%q,"grep":"LINE 0012[0-2]","ignore_case":true,"limit":2}`, id))
+	if err != nil || !strings.HasPrefix(out, "120:line 00120") || !strings.Contains(out, "pass offset 122") {
+		t.Fatalf("grep = %q err = %v", out, err)
+	}
+
+	other, err = SessionCreate(agent, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ArtifactReadTool().Execute(subagentContext(context.Background(), subagentPolicy{SessionId: other.Id}),
+		fmt.Sprintf(`{"id":%q}`, id))
+	if err == nil {
+		t.Fatal("another session read this session's artifact")
+	}
+}
@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devproje/mininaru/util"
+	"github.com/google/uuid"
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
+)
+
+type Attachment struct {
+	Id        string `json:"id"`
+	MessageId string `json:"message_id"`
+	Name      string `json:"name"`
+	MediaType string `json:"media_type"`
+	Content   []byte `json:"-"`
+}
+
+const (
+	MaxAttachments        = 4
+	MaxAttachmentBytes    = 10 * 1024 * 1024
+	MaxAttachmentsTotal   = 20 * 1024 * 1024
+	attachmentImageTokens = 800
+)
+
+const attachmentPrompt = "Analyze the attached content."
+
+const imageWithheld = "(the image is not shown, this agent's model does not take images)"
+
+func AttachmentTextType(mediaType, name string) bool {
+	var ext string
+
+	mediaType, _, _ = mime.ParseMediaType(mediaType)
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/yaml", "application/x-yaml", "application/javascript":
+		return true
+	}
+	ext = strings.ToLower(filepath.Ext(name))
+	switch ext {
+	case ".go", ".py", ".js", ".ts", ".tsx", ".jsx", ".rs", ".java", ".c", ".h", ".cpp", ".hpp",
+		".sh", ".sql", ".md", ".txt", ".json", ".yaml", ".yml", ".toml", ".xml", ".csv", ".log":
+		return true
+	}
+	return false
+}
+
+func AttachmentImageType(mediaType string) bool {
+	mediaType, _, _ = mime.ParseMediaType(mediaType)
+	switch mediaType {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+func AttachmentNew(name, mediaType string, content []byte) (*Attachment, error) {
+	var detected string
+
+	if len(content) > MaxAttachmentBytes {
+		return nil, fmt.Errorf("attachment %q exceeds the 10 MiB limit", name)
+	}
+
+	mediaType, _, _ = mime.ParseMediaType(mediaType)
+	detected, _, _ = mime.ParseMediaType(http.DetectContentType(content))
+	if AttachmentImageType(mediaType) {
+		if !AttachmentImageType(detected) {
+			return nil, fmt.Errorf("attachment %q content does not match type %q", name, mediaType)
+		}
+		mediaType = detected
+	}
+	if mediaType == "" || mediaType == "application/octet-stream" {
+		mediaType = detected
+	}
+
+	switch {
+	case AttachmentImageType(mediaType):
+	case AttachmentTextType(mediaType, name):
+	case mediaType == "application/pdf":
+	default:
+		return nil, fmt.Errorf("attachment %q has unsupported type %q", name, mediaType)
+	}
+
+	return &Attachment{Name: name, MediaType: mediaType, Content: content}, nil
+}
+
+func AttachmentRead(path string) (*Attachment, error) {
+	var info os.FileInfo
+	var content []byte
+
+	var err error
+
+	info, err = os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory, attach the files inside it", path)
+	}
+	if info.Size() > MaxAttachmentBytes {
+		return nil, fmt.Errorf("attachment %q exceeds the 10 MiB limit", filepath.Base(path))
+	}
+
+	content, err = os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return AttachmentNew(filepath.Base(path), mime.TypeByExtension(filepath.Ext(path)), content)
+}
+
+func AttachmentsCheck(attachments []*Attachment) error {
+	var attachment *Attachment
+	var total int
+
+	if len(attachments) > MaxAttachments {
+		return fmt.Errorf("at most %d attachments are supported", MaxAttachments)
+	}
+
+	for _, attachment = range attachments {
+		total += len(attachment.Content)
+	}
+	if total > MaxAttachmentsTotal {
+		return fmt.Errorf("attachments exceed the 20 MiB total limit")
+	}
+
+	return nil
+}
+
+func attachmentParts(content string, attachments []*Attachment) []openai.ChatCompletionContentPartUnionParam {
+	var parts []openai.ChatCompletionContentPartUnionParam
+	var attachment *Attachment
+	var encoded string
+
+	if strings.TrimSpace(content) == "" {
+		content = attachmentPrompt
+	}
+	parts = append(parts, openai.TextContentPart(content))
+
+	for _, attachment = range attachments {
+		encoded = base64.StdEncoding.EncodeToString(attachment.Content)
+
+		switch {
+		case AttachmentImageType(attachment.MediaType):
+			parts = append(parts, openai.TextContentPart(fmt.Sprintf("Attachment %q:", attachment.Name)))
+			parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+				URL: "data:" + attachment.MediaType + ";base64," + encoded, Detail: "auto",
+			}))
+		case attachment.MediaType == "application/pdf":
+			parts = append(parts, openai.FileContentPart(openai.ChatCompletionContentPartFileFileParam{
+				FileData: param.NewOpt(encoded), Filename: param.NewOpt(attachment.Name),
+			}))
+		default:
+			parts = append(parts, openai.TextContentPart(fmt.Sprintf("Attachment %q:\n%s", attachment.Name, string(attachment.Content))))
+		}
+	}
+
+	return parts
+}
+
+func UserMessage(content string, attachments []*Attachment) openai.ChatCompletionMessageParamUnion {
+	if len(attachments) == 0 {
+		return openai.UserMessage(content)
+	}
+
+	return openai.UserMessage(attachmentParts(content, attachments))
+}
+
+func withholdImages(messages []openai.ChatCompletionMessageParamUnion) []openai.ChatCompletionMessageParamUnion {
+	var filtered []openai.ChatCompletionMessageParamUnion
+	var message openai.ChatCompletionMessageParamUnion
+	var parts []openai.ChatCompletionContentPartUnionParam
+	var part openai.ChatCompletionContentPartUnionParam
+
+	for _, message = range messages {
+		if message.OfUser == nil || len(message.OfUser.Content.OfArrayOfContentParts) == 0 {
+			filtered = append(filtered, message)
+			continue
+		}
+
+		parts = nil
+		for _, part = range message.OfUser.Content.OfArrayOfContentParts {
+			if part.OfImageURL != nil {
+				part = openai.TextContentPart(imageWithheld)
+			}
+
+			parts = append(parts, part)
+		}
+
+		filtered = append(filtered, openai.UserMessage(parts))
+	}
+
+	return filtered
+}
+
+func attachmentSave(tx *sql.Tx, messageId string, attachments []*Attachment) error {
+	var attachment *Attachment
+
+	var err error
+
+	for _, attachment = range attachments {
+		attachment.Id = uuid.NewString()
+		attachment.MessageId = messageId
+
+		_, err = tx.Exec("INSERT INTO attachments (id, message_id, name, media_type, content) VALUES (?, ?, ?, ?, ?);",
+			attachment.Id, attachment.MessageId, attachment.Name, attachment.MediaType, attachment.Content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func attachmentsLoad(sessionId string, history []*Message) error {
+	var rows *sql.Rows
+	var cur Attachment
+	var byMessage map[string][]*Attachment
+	var message *Message
+
+	var err error
+
+	rows, err = util.DB.Query(`SELECT a.id, a.message_id, a.name, a.media_type, a.content FROM attachments a
+		JOIN messages m ON m.id = a.message_id WHERE m.session_id = ? AND m.status = ? ORDER BY a.rowid ASC;`,
+		sessionId, MessageCompleted)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byMessage = make(map[string][]*Attachment)
+	for rows.Next() {
+		err = rows.Scan(&cur.Id, &cur.MessageId, &cur.Name, &cur.MediaType, &cur.Content)
+		if err != nil {
+			return err
+		}
+
+		byMessage[cur.MessageId] = append(byMessage[cur.MessageId], &Attachment{
+			Id: cur.Id, MessageId: cur.MessageId, Name: cur.Name, MediaType: cur.MediaType, Content: cur.Content,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, message = range history {
+		message.Attachments = byMessage[message.Id]
+	}
+
+	return nil
+}
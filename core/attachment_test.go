@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/util"
+)
+
+const attachmentPNG = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89"
+
+func attachmentServer(requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+
+		body, _ = io.ReadAll(r.Body)
+		*requests = append(*requests, string(body))
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, toolChunk("r1", `{"role":"assistant","content":"seen"}`, `"stop"`))
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+}
+
+func TestAttachmentTypes(t *testing.T) {
+	if !AttachmentImageType("image/png") || AttachmentImageType("image/svg+xml") {
+		t.Fatal("image allowlist is incorrect")
+	}
+	if !AttachmentTextType("application/octet-stream", "main.go") || AttachmentTextType("application/octet-stream", "app.exe") {
+		t.Fatal("text extension allowlist is incorrect")
+	}
+}
+
+func TestAttachmentNewChecksContent(t *testing.T) {
+	var attachment *Attachment
+
+	var err error
+
+	_, err = AttachmentNew("cat.png", "image/png", []byte("not an image"))
+	if err == nil {
+		t.Fatal("text disguised as an image was accepted")
+	}
+
+	attachment, err = AttachmentNew("shot", "", []byte(attachmentPNG))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.MediaType != "image/png" {
+		t.Fatalf("media type = %q, want it sniffed as image/png", attachment.MediaType)
+	}
+
+	_, err = AttachmentNew("app.exe", "application/octet-stream", []byte{0x4d, 0x5a, 0x90, 0x00, 0x03})
+	if err == nil {
+		t.Fatal("a binary was accepted")
+	}
+}
+
+func TestAttachmentReadAndLimits(t *testing.T) {
+	var dir string
+	var path string
+	var attachment *Attachment
+	var many []*Attachment
+
+	var err error
+
+	dir = t.TempDir()
+	path = filepath.Join(dir, "notes.md")
+	err = os.WriteFile(path, []byte("# notes"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attachment, err = AttachmentRead(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attachment.Name != "notes.md" || string(attachment.Content) != "# notes" {
+		t.Fatalf("read %q with %q", attachment.Name, attachment.Content)
+	}
+
+	_, err = AttachmentRead(dir)
+	if err == nil {
+		t.Fatal("a directory was attached")
+	}
+
+	many = []*Attachment{attachment, attachment, attachment, attachment, attachment}
+	err = AttachmentsCheck(many)
+	if err == nil {
+		t.Fatal("more than the attachment limit was accepted")
+	}
+}
+
+func TestAttachmentsReplayWithTheirMessage(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var image *Attachment
+	var text *Attachment
+	var history []*Message
+	var count int
+
+	var err error
+
+	srv = attachmentServer(&requests)
+	defer srv.Close()
+
+	session, agent = thinkingSetup(t, srv.URL)
+	image, err = AttachmentNew("shot.png", "image/png", []byte(attachmentPNG))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err = AttachmentNew("main.go", "", []byte("package main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = chatWithTools(context.Background(), session, agent, "what is this", []*Attachment{image, text}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = chatWithTools(context.Background(), session, agent, "and again?", nil, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(requests[0], "data:image/png;base64,") || !strings.Contains(requests[0], "package main") {
+		t.Fatal("the first turn did not carry its attachments")
+	}
+	if !strings.Contains(requests[1], "data:image/png;base64,") || !strings.Contains(requests[1], "package main") {
+		t.Fatal("replayed history lost the attachments")
+	}
+
+	err = util.DB.QueryRow("SELECT COUNT(*) FROM attachments a JOIN messages m ON m.id = a.message_id WHERE m.session_id = ?;",
+		session.Id).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("stored %d attachments, want 2", count)
+	}
+
+	history, err = MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SessionDelete(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = util.DB.QueryRow("SELECT COUNT(*) FROM attachments WHERE message_id = ?;", history[0].Id).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatal("attachments outlived their session")
+	}
+}
+
+func TestTextOnlyAgentsGetAnImagePlaceholder(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var image *Attachment
+	var vision bool
+
+	var err error
+
+	srv = attachmentServer(&requests)
+	defer srv.Close()
+
+	session, agent = thinkingSetup(t, srv.URL)
+	agent.Vision = &vision
+	image, err = AttachmentNew("shot.png", "image/png", []byte(attachmentPNG))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = chatWithTools(context.Background(), session, agent, "describe it", []*Attachment{image}, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(requests[0], "data:image/png") {
+		t.Fatal("an image was sent to a text-only agent")
+	}
+	if !strings.Contains(requests[0], "does not take images") {
+		t.Fatal("the text-only agent was not told an image was withheld")
+	}
+}
@@ -20,6 +20,8 @@ type Bot struct {
 	Agent   string `json:"agent"`
 	GuildId string `json:"guild_id"`
 	Enabled bool   `json:"enabled"`
+
+	tokenRef string
 }
 
 type BotConfig struct {
@@ -52,10 +54,40 @@ func BotKindValid(kind string) bool {
 	return false
 }
 
+func (b *Bot) TokenRef() string {
+	return b.tokenRef
+}
+
+func botStored(bot *Bot) *Bot {
+	var stored Bot
+
+	stored = *bot
+	stored.Token = util.SecretStored(bot.Token, bot.tokenRef)
+
+	return &stored
+}
+
+func BotSecrets() []util.SecretField {
+	var fields []util.SecretField
+	var cur *Bot
+	var field util.SecretField
+	var ok bool
+
+	for _, cur = range Bots {
+		field, ok = util.SecretOf("bot "+cur.Name+" token", cur.Token, cur.tokenRef)
+		if ok {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
+
 func BotInit() error {
 	var path string
 	var buf []byte
 	var cfg BotConfig
+	var cur *Bot
 
 	var err error
 
@@ -80,20 +112,24 @@ func BotInit() error {
 	}
 
 	Bots = cfg.Bots
+	for _, cur = range Bots {
+		cur.Token, cur.tokenRef = util.SecretLoad("bot "+cur.Name, cur.Token)
+	}
 
 	return nil
 }
 
 func BotSave() error {
 	var cfg BotConfig
+	var cur *Bot
 	var path string
 	var buf []byte
 
 	var err error
 
-	cfg = BotConfig{Bots: Bots}
-	if cfg.Bots == nil {
-		cfg.Bots = []*Bot{}
+	cfg = BotConfig{Bots: []*Bot{}}
+	for _, cur = range Bots {
+		cfg.Bots = append(cfg.Bots, botStored(cur))
 	}
 
 	path = util.Path(BOT_PATH)
@@ -165,6 +201,7 @@ func BotCreate(payload Bot) (*Bot, error) {
 
 	payload.Id = uuid.NewString()
 	payload.Enabled = true
+	payload.Token, payload.tokenRef = util.SecretLoad("bot "+payload.Name, payload.Token)
 	Bots = append(Bots, &payload)
 
 	err = BotSave()
@@ -202,7 +239,7 @@ func BotUpdateFields(ref string, name, token, agent, guildId *string, enabled *b
 			return fmt.Errorf("bot token cannot be empty")
 		}
 
-		update.Token = *token
+		update.Token, update.tokenRef = util.SecretLoad("bot "+update.Name, *token)
 	}
 
 	if agent != nil {
@@ -0,0 +1,562 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"github.com/google/uuid"
+)
+
+type AgentBundle struct {
+	Format   int                 `json:"format"`
+	Agent    NaruAgent           `json:"agent"`
+	Provider BundleProvider      `json:"provider"`
+	Skills   []string            `json:"skills,omitempty"`
+	MCP      []modules.MCPServer `json:"mcp,omitempty"`
+
+	SkillFiles map[string]map[string][]byte `json:"-"`
+}
+
+type BundleProvider struct {
+	Name    string `json:"name,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+type AgentImported struct {
+	Agent    *NaruAgent
+	Replaced bool
+	Skills   []string
+	Kept     []string
+	Servers  []string
+	Disabled []string
+}
+
+const bundleFormat = 1
+
+const bundleManifest = "bundle.json"
+
+const bundleSkillDir = "skills/"
+
+const maxBundleBytes = 32 << 20
+
+var bundleSecretPattern *regexp.Regexp = regexp.MustCompile(`(?i)(token|secret|passw(or)?d|api[-_]?key|auth|credential|signature|^key$|^sig$)`)
+
+func bundleMentions(text, name string) bool {
+	return regexp.MustCompile(`(^|[^a-zA-Z0-9_-])` + regexp.QuoteMeta(name) + `($|[^a-zA-Z0-9_-])`).MatchString(text)
+}
+
+func bundleSkills(agent *NaruAgent, explicit []string) ([]string, error) {
+	var picked map[string]bool
+	var name string
+	var names []string
+
+	picked = make(map[string]bool)
+	for _, name = range explicit {
+		if modules.SkillFind(name) == nil {
+			return nil, fmt.Errorf("skill %q not found", name)
+		}
+
+		picked[name] = true
+	}
+
+	for _, name = range modules.SkillNames() {
+		if bundleMentions(agent.Role, name) || bundleMentions(agent.Soul, name) {
+			picked[name] = true
+		}
+	}
+
+	for name = range picked {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func bundleSecretName(name string) bool {
+	return bundleSecretPattern.MatchString(name)
+}
+
+func bundleArgsCheck(name string, args []string) error {
+	var index int
+	var arg string
+	var flag string
+	var value string
+	var assigned bool
+
+	for index, arg = range args {
+		flag, value, assigned = strings.Cut(arg, "=")
+		if !bundleSecretName(strings.TrimLeft(flag, "-")) {
+			continue
+		}
+		if !assigned && strings.HasPrefix(flag, "-") && index+1 < len(args) && !strings.HasPrefix(args[index+1], "-") {
+			value, assigned = args[index+1], true
+		}
+		if assigned && value != "" && !util.SecretIsRef(value) {
+			return fmt.Errorf("mcp server %q passes %s as a command-line argument, move it into env as an env:, file:, or cmd: reference before exporting", name, flag)
+		}
+	}
+
+	return nil
+}
+
+func bundleURL(name, raw string) (string, error) {
+	var parsed *url.URL
+	var query url.Values
+	var key string
+	var values []string
+	var index int
+	var stripped bool
+
+	var err error
+
+	if raw == "" {
+		return "", nil
+	}
+
+	parsed, err = url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("mcp server %q url: %w", name, err)
+	}
+	if parsed.User != nil {
+		return "", fmt.Errorf("mcp server %q has credentials in its url, move them into a header before exporting", name)
+	}
+
+	query = parsed.Query()
+	for key, values = range query {
+		if !bundleSecretName(key) {
+			continue
+		}
+
+		for index = range values {
+			if values[index] != "" && !util.SecretIsRef(values[index]) {
+				values[index] = ""
+				stripped = true
+			}
+		}
+	}
+	if !stripped {
+		return raw, nil
+	}
+
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String(), nil
+}
+
+func bundleURLBlank(raw string) bool {
+	var parsed *url.URL
+	var key string
+	var values []string
+	var value string
+
+	var err error
+
+	parsed, err = url.Parse(raw)
+	if err != nil {
+		return false
+	}
+
+	for key, values = range parsed.Query() {
+		for _, value = range values {
+			if value == "" && bundleSecretName(key) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func bundleServer(name string) (modules.MCPServer, error) {
+	var entry modules.MCPServer
+
+	var err error
+
+	for _, entry = range modules.MCP.Servers {
+		if entry.Name != name {
+			continue
+		}
+
+		entry = modules.MCPServerStored(entry)
+		entry.Enabled = nil
+		entry.Env = bundleBlank(entry.Env)
+		entry.Headers = bundleBlank(entry.Headers)
+
+		err = bundleArgsCheck(name, entry.Args)
+		if err != nil {
+			return modules.MCPServer{}, err
+		}
+
+		entry.URL, err = bundleURL(name, entry.URL)
+		if err != nil {
+			return modules.MCPServer{}, err
+		}
+
+		return entry, nil
+	}
+
+	return modules.MCPServer{}, fmt.Errorf("mcp server %q not found", name)
+}
+
+func bundleBlank(values map[string]string) map[string]string {
+	var blank map[string]string
+	var key string
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	blank = make(map[string]string, len(values))
+	for key = range values {
+		blank[key] = ""
+		if util.SecretIsRef(values[key]) {
+			blank[key] = values[key]
+		}
+	}
+
+	return blank
+}
+
+func AgentBundleNew(agent *NaruAgent, skills, servers []string) (*AgentBundle, error) {
+	var bundle AgentBundle
+	var prov *Provider
+	var name string
+	var files map[string][]byte
+	var entry modules.MCPServer
+
+	var err error
+
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required")
+	}
+
+	bundle = AgentBundle{Format: bundleFormat, Agent: *agent, SkillFiles: make(map[string]map[string][]byte)}
+	bundle.Agent.Id = ""
+	bundle.Agent.ProviderId = ""
+	bundle.Agent.AI = nil
+	bundle.Agent.Anthropic = nil
+
+	prov, err = ProviderFind(agent.ProviderId)
+	if err == nil {
+		bundle.Provider = BundleProvider{Name: prov.Name, Kind: prov.Kind, BaseURL: prov.BaseURL}
+	}
+
+	bundle.Skills, err = bundleSkills(agent, skills)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name = range bundle.Skills {
+		files, err = modules.SkillFiles(name)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.SkillFiles[name] = files
+	}
+
+	for _, name = range servers {
+		entry, err = bundleServer(name)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.MCP = append(bundle.MCP, entry)
+	}
+
+	return &bundle, nil
+}
+
+func bundleWriteFile(archive *tar.Writer, name string, buf []byte) error {
+	var err error
+
+	err = archive.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(buf)), ModTime: time.Now(), Typeflag: tar.TypeReg})
+	if err != nil {
+		return err
+	}
+
+	_, err = archive.Write(buf)
+	return err
+}
+
+func AgentBundleWrite(w io.Writer, bundle *AgentBundle) error {
+	var compressed *gzip.Writer
+	var archive *tar.Writer
+	var manifest []byte
+	var name string
+	var rel string
+	var rels []string
+
+	var err error
+
+	manifest, err = json.MarshalIndent(bundle, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	compressed = gzip.NewWriter(w)
+	archive = tar.NewWriter(compressed)
+
+	err = bundleWriteFile(archive, bundleManifest, manifest)
+	if err != nil {
+		return err
+	}
+
+	for _, name = range bundle.Skills {
+		rels = rels[:0]
+		for rel = range bundle.SkillFiles[name] {
+			rels = append(rels, rel)
+		}
+		sort.Strings(rels)
+
+		for _, rel = range rels {
+			err = bundleWriteFile(archive, bundleSkillDir+name+"/"+rel, bundle.SkillFiles[name][rel])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return err
+	}
+
+	return compressed.Close()
+}
+
+func AgentBundleRead(r io.Reader) (*AgentBundle, error) {
+	var compressed *gzip.Reader
+	var archive *tar.Reader
+	var header *tar.Header
+	var buf []byte
+	var total int64
+	var manifest []byte
+	var files map[string]map[string][]byte
+	var name string
+	var rel string
+	var ok bool
+	var bundle AgentBundle
+
+	var err error
+
+	compressed, err = gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an agent bundle: %w", err)
+	}
+	defer compressed.Close()
+
+	files = make(map[string]map[string][]byte)
+	archive = tar.NewReader(compressed)
+	for {
+		header, err = archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("not an agent bundle: %w", err)
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("bundle entry %q is not a regular file", header.Name)
+		}
+
+		total += header.Size
+		if header.Size < 0 || total > maxBundleBytes {
+			return nil, fmt.Errorf("bundle is larger than %d bytes", maxBundleBytes)
+		}
+
+		buf, err = io.ReadAll(io.LimitReader(archive, header.Size))
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Name == bundleManifest {
+			manifest = buf
+			continue
+		}
+
+		name, rel, ok = strings.Cut(strings.TrimPrefix(header.Name, bundleSkillDir), "/")
+		if !strings.HasPrefix(header.Name, bundleSkillDir) || !ok {
+			return nil, fmt.Errorf("unexpected bundle entry %q", header.Name)
+		}
+
+		if files[name] == nil {
+			files[name] = make(map[string][]byte)
+		}
+		files[name][rel] = buf
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("bundle has no %s", bundleManifest)
+	}
+
+	err = json.Unmarshal(manifest, &bundle)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", bundleManifest, err)
+	}
+
+	if bundle.Format != bundleFormat {
+		return nil, fmt.Errorf("bundle format %d is not supported, expected %d", bundle.Format, bundleFormat)
+	}
+	if strings.TrimSpace(bundle.Agent.Name) == "" || strings.TrimSpace(bundle.Agent.Model) == "" {
+		return nil, fmt.Errorf("bundle agent needs a name and a model")
+	}
+
+	for _, name = range bundle.Skills {
+		if files[name] == nil {
+			return nil, fmt.Errorf("bundle lists skill %q but carries no files for it", name)
+		}
+	}
+	if len(files) != len(bundle.Skills) {
+		return nil, fmt.Errorf("bundle carries files for skills it does not list")
+	}
+
+	bundle.SkillFiles = files
+
+	return &bundle, nil
+}
+
+func bundleImportServers(servers []modules.MCPServer, replace bool, imported *AgentImported) error {
+	var entry modules.MCPServer
+	var index int
+	var found int
+	var disabled bool
+	var value string
+
+	var err error
+
+	for _, entry = range servers {
+		err = modules.MCPValidate(&entry)
+		if err != nil {
+			return err
+		}
+
+		disabled = false
+		for _, value = range entry.Env {
+			disabled = disabled || value == "" || strings.HasPrefix(value, util.SecretCmd)
+		}
+		for _, value = range entry.Headers {
+			disabled = disabled || value == "" || strings.HasPrefix(value, util.SecretCmd)
+		}
+		disabled = disabled || bundleURLBlank(entry.URL)
+		entry.Enabled = nil
+		if disabled {
+			entry.Enabled = new(bool)
+			imported.Disabled = append(imported.Disabled, entry.Name)
+		}
+
+		found = -1
+		for index = range modules.MCP.Servers {
+			if modules.MCP.Servers[index].Name == entry.Name {
+				found = index
+			}
+		}
+
+		if found >= 0 && !replace {
+			imported.Kept = append(imported.Kept, "mcp server "+entry.Name)
+			continue
+		}
+
+		if found >= 0 {
+			modules.MCP.Servers[found] = entry
+		} else {
+			modules.MCP.Servers = append(modules.MCP.Servers, entry)
+		}
+		imported.Servers = append(imported.Servers, entry.Name)
+	}
+
+	if len(imported.Servers) == 0 {
+		return nil
+	}
+
+	return modules.MCPSave()
+}
+
+func AgentBundleImport(bundle *AgentBundle, name string, prov *Provider, replace bool, scope string) (*AgentImported, error) {
+	var imported AgentImported
+	var existing *NaruAgent
+	var agent NaruAgent
+	var skill string
+
+	var err error
+
+	if prov == nil || prov.Id == "" {
+		return nil, fmt.Errorf("provider is required to import an agent")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = bundle.Agent.Name
+	}
+
+	existing, err = AgentByName(name)
+	if err == nil && !replace {
+		return nil, fmt.Errorf("agent %q already exists", name)
+	}
+	if err == nil && existing.PersonaDir() != "" {
+		return nil, fmt.Errorf("agent %q is loaded from %s, import it under another name", name, existing.PersonaDir())
+	}
+
+	for _, skill = range bundle.Skills {
+		if modules.SkillFind(skill) != nil && !replace {
+			imported.Kept = append(imported.Kept, "skill "+skill)
+			continue
+		}
+
+		_, err = modules.SkillInstall(skill, scope, bundle.SkillFiles[skill], replace)
+		if err != nil {
+			return nil, err
+		}
+
+		imported.Skills = append(imported.Skills, skill)
+	}
+
+	err = bundleImportServers(bundle.MCP, replace, &imported)
+	if err != nil {
+		return nil, err
+	}
+
+	agent = bundle.Agent
+	agent.Name = name
+	agent.ProviderId = prov.Id
+	configureAgentClients(&agent, prov)
+
+	if existing != nil {
+		agent.Id = existing.Id
+		*existing = agent
+		imported.Agent = existing
+		imported.Replaced = true
+	} else {
+		agent.Id = uuid.NewString()
+		imported.Agent = &agent
+		if Global == nil {
+			Global = imported.Agent
+		} else {
+			Agents = append(Agents, imported.Agent)
+		}
+	}
+
+	err = AgentSave()
+	if err != nil {
+		return nil, err
+	}
+
+	return &imported, nil
+}
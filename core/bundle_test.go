@@ -0,0 +1,254 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/modules"
+)
+
+func bundleSetup(t *testing.T) (*Provider, *Provider) {
+	var hitA, hitB int
+	var alpha, beta *Provider
+
+	var err error
+
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	alpha, beta = setup(t, &hitA, &hitB)
+	modules.MCP = modules.MCPConfig{}
+
+	err = modules.SkillInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		modules.MCP = modules.MCPConfig{}
+		modules.SkillInitAt(t.TempDir(), "")
+	})
+
+	return alpha, beta
+}
+
+func bundleExport(t *testing.T) []byte {
+	var alpha *Provider
+	var temperature float64
+	var agent *NaruAgent
+	var bundle *AgentBundle
+	var buf bytes.Buffer
+
+	var err error
+
+	t.Helper()
+
+	alpha, _ = bundleSetup(t)
+
+	_, err = modules.SkillCreateResult("pr-review", "Review a pull request.", "read the tests first", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = modules.SkillCreateResult("unrelated", "Something else.", "not packaged", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	modules.MCP.Servers = []modules.MCPServer{{Name: "github", Transport: modules.TransportStdio, Command: "gh-mcp",
+		Env: map[string]string{"GITHUB_TOKEN": "ghp_secret"}}}
+
+	temperature = 0.2
+	agent = AgentNew("reviewer", "Reviews code, load pr-review first.", "terse", "m", alpha)
+	agent.Sampling.Temperature = &temperature
+	Global = agent
+
+	bundle, err = AgentBundleNew(agent, nil, []string{"github"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = AgentBundleWrite(&buf, bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if modules.MCP.Servers[0].Env["GITHUB_TOKEN"] != "ghp_secret" {
+		t.Fatal("exporting blanked the local mcp secret")
+	}
+
+	return buf.Bytes()
+}
+
+func TestAgentBundleRoundTrip(t *testing.T) {
+	var data []byte
+	var beta *Provider
+	var bundle *AgentBundle
+	var imported *AgentImported
+
+	var err error
+
+	data = bundleExport(t)
+
+	_, beta = bundleSetup(t)
+	bundle, err = AgentBundleRead(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundle.MCP) != 1 || bundle.MCP[0].Env["GITHUB_TOKEN"] != "" {
+		t.Fatalf("mcp entries = %+v, want the secret blanked", bundle.MCP)
+	}
+	if len(bundle.Skills) != 1 || bundle.Skills[0] != "pr-review" {
+		t.Fatalf("skills = %v, want only the one the role mentions", bundle.Skills)
+	}
+	if bundle.Provider.Name != "alpha" {
+		t.Fatalf("provider hint = %+v", bundle.Provider)
+	}
+
+	imported, err = AgentBundleImport(bundle, "", beta, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if imported.Agent.ProviderId != beta.Id || imported.Agent.AI == nil {
+		t.Fatal("the agent was not mapped onto the chosen provider")
+	}
+	if imported.Agent.Sampling.Temperature == nil || *imported.Agent.Sampling.Temperature != 0.2 {
+		t.Fatal("sampling settings were lost")
+	}
+	if modules.SkillFind("pr-review") == nil {
+		t.Fatal("the skill was not installed")
+	}
+	if len(modules.MCP.Servers) != 1 || modules.MCP.Servers[0].Enabled == nil || *modules.MCP.Servers[0].Enabled {
+		t.Fatal("an mcp server without its secrets was left enabled")
+	}
+
+	reload(t)
+	if Global == nil || Global.Name != "reviewer" || Global.Role != "Reviews code, load pr-review first." {
+		t.Fatal("the imported agent was not saved")
+	}
+}
+
+func TestBundleServerKeepsReferencesAndStripsSecrets(t *testing.T) {
+	var entry modules.MCPServer
+	var imported AgentImported
+
+	var err error
+
+	bundleSetup(t)
+	t.Setenv("NARU_BUNDLE_TOKEN", "resolved-secret")
+	modules.MCP.Servers = []modules.MCPServer{
+		{Name: "github", Transport: modules.TransportStdio, Command: "gh-mcp", Args: []string{"--verbose", "--token", "env:NARU_BUNDLE_TOKEN"},
+			Env: map[string]string{"GITHUB_TOKEN": "env:NARU_BUNDLE_TOKEN", "PLAIN": "literal", "FETCHED": "file:/nonexistent/github-token"}},
+		{Name: "remote", Transport: modules.TransportHTTP, URL: "https://mcp.example.com/sse?region=eu&api_key=sk-live",
+			Headers: map[string]string{"Authorization": "Bearer literal"}},
+		{Name: "leaky", Transport: modules.TransportStdio, Command: "leaky-mcp", Args: []string{"--api-key=sk-live"}},
+	}
+	err = modules.MCPSave()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = modules.MCPLoad()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modules.MCP.Servers[0].Env["GITHUB_TOKEN"] != "resolved-secret" {
+		t.Fatalf("loaded env = %v", modules.MCP.Servers[0].Env)
+	}
+
+	entry, err = bundleServer("github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Env["GITHUB_TOKEN"] != "env:NARU_BUNDLE_TOKEN" || entry.Env["PLAIN"] != "" || entry.Env["FETCHED"] != "file:/nonexistent/github-token" {
+		t.Fatalf("exported env = %v, want references kept and literals blanked", entry.Env)
+	}
+
+	entry, err = bundleServer("remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.URL != "https://mcp.example.com/sse?api_key=&region=eu" || entry.Headers["Authorization"] != "" {
+		t.Fatalf("exported url = %q headers = %v", entry.URL, entry.Headers)
+	}
+
+	_, err = bundleServer("leaky")
+	if err == nil || !strings.Contains(err.Error(), "--api-key") {
+		t.Fatalf("a literal key in args = %v, want the export refused", err)
+	}
+
+	modules.MCP = modules.MCPConfig{}
+	err = bundleImportServers([]modules.MCPServer{
+		{Name: "github", Transport: modules.TransportStdio, Command: "gh-mcp", Env: map[string]string{"GITHUB_TOKEN": "env:NARU_BUNDLE_TOKEN"}},
+		{Name: "fetched", Transport: modules.TransportStdio, Command: "gh-mcp", Env: map[string]string{"GITHUB_TOKEN": "cmd:pass show github"}},
+		{Name: "remote", Transport: modules.TransportHTTP, URL: "https://mcp.example.com/sse?api_key=&region=eu"},
+	}, false, &imported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(imported.Disabled, ",") != "fetched,remote" {
+		t.Fatalf("disabled on import = %v, want the command reference and the stripped url", imported.Disabled)
+	}
+}
+
+func TestAgentBundleImportConflicts(t *testing.T) {
+	var data []byte
+	var beta *Provider
+	var bundle *AgentBundle
+	var imported *AgentImported
+	var previous string
+
+	var err error
+
+	data = bundleExport(t)
+	bundle, err = AgentBundleRead(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	beta = Providers[1]
+	previous = Global.Id
+	_, err = AgentBundleImport(bundle, "", beta, false, "")
+	if err == nil {
+		t.Fatal("an existing agent was overwritten without --replace")
+	}
+
+	imported, err = AgentBundleImport(bundle, "reviewer-2", beta, false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported.Skills) != 0 || len(imported.Kept) != 2 {
+		t.Fatalf("installed %v and kept %v, want the existing skill and server kept", imported.Skills, imported.Kept)
+	}
+
+	imported, err = AgentBundleImport(bundle, "reviewer", beta, true, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !imported.Replaced || imported.Agent.Id != previous || imported.Agent.ProviderId != beta.Id {
+		t.Fatal("replacing did not keep the agent id or switch its provider")
+	}
+}
+
+func TestAgentBundleReadRejectsStrayEntries(t *testing.T) {
+	var buf bytes.Buffer
+	var compressed *gzip.Writer
+	var archive *tar.Writer
+
+	var err error
+
+	compressed = gzip.NewWriter(&buf)
+	archive = tar.NewWriter(compressed)
+	bundleWriteFile(archive, bundleManifest, []byte(`{"format":1,"agent":{"name":"a","model":"m"}}`))
+	bundleWriteFile(archive, "../outside", []byte("x"))
+	archive.Close()
+	compressed.Close()
+
+	_, err = AgentBundleRead(&buf)
+	if err == nil {
+		t.Fatal("an entry outside skills/ was accepted")
+	}
+}
@@ -41,6 +41,33 @@ func TestOpenAICachePolicyAddsTopLevelControl(t *testing.T) {
 	}
 }
 
+func TestOpenAICacheKeepsSamplingExtraFields(t *testing.T) {
+	var params openai.ChatCompletionNewParams
+	var raw []byte
+	var body map[string]any
+	var control map[string]any
+
+	var err error
+
+	params.Model = "anthropic/claude-sonnet-4"
+	params.Messages = []openai.ChatCompletionMessageParamUnion{openai.UserMessage("hello")}
+	applySampling(&params, Sampling{Extra: map[string]any{"top_k": 40}})
+	applyOpenAICache(&params, &Provider{Cache: CacheEphemeral})
+
+	raw, err = json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal(raw, &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	control, _ = body["cache_control"].(map[string]any)
+	if control["type"] != "ephemeral" || body["top_k"] != float64(40) {
+		t.Fatalf("request = %s, want both the extra body and cache_control", raw)
+	}
+}
+
 func TestOpenRouterClaudeAutoEnablesPromptCache(t *testing.T) {
 	var params openai.ChatCompletionNewParams
 	var raw []byte
@@ -21,11 +21,15 @@ import (
 type Message struct {
 	Id        string `json:"id"`
 	SessionId string `json:"session_id"`
+	AgentId   string `json:"agent_id,omitempty"`
 	Role      string `json:"role"`
 	Content   string `json:"content"`
 	Reasoning string `json:"reasoning"`
 	Status    string `json:"status"`
 	Error     string `json:"error"`
+	VariantOf string `json:"variant_of,omitempty"`
+
+	Attachments []*Attachment `json:"attachments,omitempty"`
 }
 
 const (
@@ -36,20 +40,25 @@ const (
 )
 
 func MessageList(sessionId string) ([]*Message, error) {
+	return messageQuery(`SELECT id, session_id, agent_id, role, content, reasoning, status, error, variant_of FROM messages
+		WHERE session_id = ? AND status = ? AND selected = 1 ORDER BY rowid ASC;`, sessionId, MessageCompleted)
+}
+
+func messageQuery(query string, args ...any) ([]*Message, error) {
 	var rows *sql.Rows
 	var cur Message
 	var messages []*Message
 
 	var err error
 
-	rows, err = util.DB.Query("SELECT id, session_id, role, content, reasoning, status, error FROM messages WHERE session_id = ? AND status = ? ORDER BY rowid ASC;", sessionId, MessageCompleted)
+	rows, err = util.DB.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		err = rows.Scan(&cur.Id, &cur.SessionId, &cur.Role, &cur.Content, &cur.Reasoning, &cur.Status, &cur.Error)
+		err = rows.Scan(&cur.Id, &cur.SessionId, &cur.AgentId, &cur.Role, &cur.Content, &cur.Reasoning, &cur.Status, &cur.Error, &cur.VariantOf)
 		if err != nil {
 			return nil, err
 		}
@@ -57,11 +66,13 @@ func MessageList(sessionId string) ([]*Message, error) {
 		messages = append(messages, &Message{
 			Id:        cur.Id,
 			SessionId: cur.SessionId,
+			AgentId:   cur.AgentId,
 			Role:      cur.Role,
 			Content:   cur.Content,
 			Reasoning: cur.Reasoning,
 			Status:    cur.Status,
 			Error:     cur.Error,
+			VariantOf: cur.VariantOf,
 		})
 	}
 	if err = rows.Err(); err != nil {
@@ -94,14 +105,31 @@ func MessageSave(sessionId, role, content, reasoning string) (*Message, error) {
 	return &message, nil
 }
 
-func messageStart(sessionId, content string) (*Message, error) {
+func messageStart(sessionId, content string, attachments []*Attachment, variantOf string) (*Message, error) {
 	var message Message
+	var tx *sql.Tx
 
 	var err error
 
-	message = Message{Id: uuid.NewString(), SessionId: sessionId, Role: "user", Content: content, Status: MessagePending}
-	_, err = util.DB.Exec("INSERT INTO messages (id, session_id, role, content, reasoning, status, error) VALUES (?, ?, ?, ?, '', ?, '');",
-		message.Id, message.SessionId, message.Role, message.Content, message.Status)
+	message = Message{Id: uuid.NewString(), SessionId: sessionId, Role: "user", Content: content, Status: MessagePending,
+		VariantOf: variantOf, Attachments: attachments}
+
+	tx, err = util.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec("INSERT INTO messages (id, session_id, role, content, reasoning, status, error, variant_of) VALUES (?, ?, ?, ?, '', ?, '', ?);",
+		message.Id, message.SessionId, message.Role, message.Content, message.Status, message.VariantOf)
+	if err == nil {
+		err = attachmentSave(tx, message.Id, attachments)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
@@ -122,13 +150,14 @@ func messageFail(id, status string, chatErr error) error {
 	return err
 }
 
-func messageCompleteTurn(userId, sessionId, assistantContent, reasoning string) (*Message, error) {
+func messageCompleteTurn(userId, replacedId, sessionId, agentId, assistantContent, reasoning string) (*Message, error) {
 	var assistant Message
 	var tx *sql.Tx
 
 	var err error
 
-	assistant = Message{Id: uuid.NewString(), SessionId: sessionId, Role: "assistant", Content: assistantContent, Reasoning: reasoning, Status: MessageCompleted}
+	assistant = Message{Id: uuid.NewString(), SessionId: sessionId, AgentId: agentId, Role: "assistant",
+		Content: assistantContent, Reasoning: reasoning, Status: MessageCompleted}
 
 	tx, err = util.DB.Begin()
 	if err != nil {
@@ -141,8 +170,18 @@ func messageCompleteTurn(userId, sessionId, assistantContent, reasoning string)
 		return nil, err
 	}
 
-	_, err = tx.Exec("INSERT INTO messages (id, session_id, role, content, reasoning, status, error) VALUES (?, ?, ?, ?, ?, ?, '');",
-		assistant.Id, assistant.SessionId, assistant.Role, assistant.Content, assistant.Reasoning, assistant.Status)
+	if replacedId != "" {
+		_, err = tx.Exec(`UPDATE messages SET selected = 0 WHERE session_id = ? AND selected = 1
+			AND rowid >= (SELECT rowid FROM messages WHERE id = ?) AND rowid < (SELECT rowid FROM messages WHERE id = ?);`,
+			sessionId, replacedId, userId)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO messages (id, session_id, agent_id, role, content, reasoning, status, error) VALUES (?, ?, ?, ?, ?, ?, ?, '');",
+		assistant.Id, assistant.SessionId, assistant.AgentId, assistant.Role, assistant.Content, assistant.Reasoning, assistant.Status)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
@@ -184,35 +223,118 @@ func deltaReasoning(delta openai.ChatCompletionChunkChoiceDelta) string {
 	return text
 }
 
-func chatWithToolPolicy(ctx context.Context, session *Session, agent *NaruAgent, content string, parts []openai.ChatCompletionContentPartUnionParam,
-	defs []modules.Def, thinking string, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc, allowDangerous bool) (*Message, error) {
-	var history []*Message
-	var calls map[string][]*ToolCall
-	var prompt string
-	var summary string
-	var messages []openai.ChatCompletionMessageParamUnion
+func chatParams(agent *NaruAgent, messages []openai.ChatCompletionMessageParamUnion, defs []modules.Def, thinking string) openai.ChatCompletionNewParams {
 	var params openai.ChatCompletionNewParams
-	var pending *Message
-	var run completionRun
-	var result *Completion
-	var contextWindow int64
+
+	params = openai.ChatCompletionNewParams{
+		Model:    agent.Model,
+		Messages: messages,
+	}
+	params.StreamOptions.IncludeUsage = param.NewOpt(true)
+	applySampling(&params, agent.Sampling)
+	applyOpenAICache(&params, agentProvider(agent))
+	if len(defs) > 0 {
+		params.Tools = toolParams(defs)
+	}
+
+	if thinking != "" && thinking != config.ThinkingOff {
+		params.ReasoningEffort = openai.ReasoningEffort(thinking)
+	}
+	if !agent.SeesImages() {
+		params.Messages = withholdImages(params.Messages)
+	}
+
+	return params
+}
+
+func turnFailed(ctx context.Context, messageId string, chatErr error) error {
 	var status string
-	var saveErr error
 
 	var err error
 
+	status = MessageFailed
+	if ctx.Err() != nil {
+		status = MessageCancelled
+	}
+
+	err = messageFail(messageId, status, chatErr)
+	if err != nil {
+		return fmt.Errorf("chat failed: %v; recording failure also failed: %w", chatErr, err)
+	}
+
+	return chatErr
+}
+
+func chatReady(session *Session, agent *NaruAgent) error {
 	if session == nil || agent == nil {
-		return nil, fmt.Errorf("session and agent are required to chat")
+		return fmt.Errorf("session and agent are required to chat")
 	}
 	if agent.AI == nil && agent.Anthropic == nil {
-		return nil, fmt.Errorf("agent %s has no available provider client", agent.Id)
+		return fmt.Errorf("agent %s has no available provider client", agent.Id)
+	}
+
+	return nil
+}
+
+func chatHistory(sessionId string) ([]*Message, error) {
+	var history []*Message
+
+	var err error
+
+	history, err = MessageList(sessionId)
+	if err != nil {
+		return nil, err
 	}
 
-	history, err = MessageList(session.Id)
+	err = attachmentsLoad(sessionId, history)
 	if err != nil {
 		return nil, err
 	}
 
+	return history, nil
+}
+
+func chatWithToolPolicy(ctx context.Context, session *Session, agent *NaruAgent, content string, attachments []*Attachment,
+	defs []modules.Def, thinking string, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc, allowDangerous bool) (*Message, error) {
+	var history []*Message
+
+	var err error
+
+	err = chatReady(session, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	err = AttachmentsCheck(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err = chatHistory(session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return chatTurn(ctx, session, agent, content, attachments, history, nil, defs, thinking, onContent, onReasoning, onTool, approve, allowDangerous)
+}
+
+func chatTurn(ctx context.Context, session *Session, agent *NaruAgent, content string, attachments []*Attachment, history []*Message,
+	replaced *Message, defs []modules.Def, thinking string, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc,
+	allowDangerous bool) (*Message, error) {
+	var calls map[string][]*ToolCall
+	var prompt string
+	var summary string
+	var messages []openai.ChatCompletionMessageParamUnion
+	var params openai.ChatCompletionNewParams
+	var variantOf string
+	var replacedId string
+	var pending *Message
+	var run completionRun
+	var result *Completion
+	var contextWindow int64
+
+	var err error
+
 	defs = permittedTools(defs)
 
 	if len(defs) > 0 {
@@ -222,70 +344,53 @@ func chatWithToolPolicy(ctx context.Context, session *Session, agent *NaruAgent,
 		}
 	}
 
-	prompt = systemPrompt(agent, defs)
+	prompt = systemPrompt(agent, defs, recallFor(ctx, agent, defs, content))
 	contextWindow = agent.CachedModelContextWindow()
 
-	summary, history = compactHistory(ctx, agent, session, history)
+	summary, history, calls = compactHistory(ctx, agent, session, history, calls, prompt, defs, content)
 	if summary != "" {
 		prompt = prompt + "\n\n" + summaryBlock(summary)
 	}
 
 	messages = append(messages, openai.SystemMessage(prompt))
 	messages = append(messages, historyMessages(history, calls)...)
-	if len(parts) > 0 {
-		messages = append(messages, openai.UserMessage(parts))
-	} else {
-		messages = append(messages, openai.UserMessage(content))
-	}
+	messages = append(messages, UserMessage(content, attachments))
 
-	params = openai.ChatCompletionNewParams{
-		Model:    agent.Model,
-		Messages: messages,
-	}
-	params.StreamOptions.IncludeUsage = param.NewOpt(true)
-	applyOpenAICache(&params, agentProvider(agent))
-	if len(defs) > 0 {
-		params.Tools = toolParams(defs)
-	}
+	params = chatParams(agent, messages, defs, thinking)
 
-	if thinking != "" && thinking != config.ThinkingOff {
-		params.ReasoningEffort = openai.ReasoningEffort(thinking)
+	if replaced != nil {
+		replacedId = replaced.Id
+		variantOf = replaced.VariantOf
+		if variantOf == "" {
+			variantOf = replaced.Id
+		}
 	}
 
-	pending, err = messageStart(session.Id, content)
+	pending, err = messageStart(session.Id, content, attachments, variantOf)
 	if err != nil {
 		return nil, err
 	}
 
 	run = completionRun{
 		AI: agent.AI, Anthropic: agent.Anthropic, Provider: agentProvider(agent), Params: params, Defs: defs, AllowDangerous: allowDangerous, AllowPrivileged: true,
-		AgentId:   agent.Id,
+		AgentId: agent.Id, Vision: agent.SeesImages(), Sandbox: agent.Sandbox,
 		SessionId: session.Id, MessageId: pending.Id,
 		OnContent: onContent, OnReasoning: onReasoning, OnTool: onTool, Approve: approve,
 	}
 
 	result, err = run.execute(ctx)
 	if err != nil {
-		status = MessageFailed
-		if ctx.Err() != nil {
-			status = MessageCancelled
-		}
-
-		saveErr = messageFail(pending.Id, status, err)
-		if saveErr != nil {
-			return nil, fmt.Errorf("chat failed: %v; recording failure also failed: %w", err, saveErr)
-		}
-
-		return nil, err
+		return nil, turnFailed(ctx, pending.Id, err)
 	}
 
-	usageRecordWithContext(session.Id, pending.Id, UsageTurn, result.Usage, result.ContextTokens, contextWindow)
+	usageRecordWithContext(session.Id, pending.Id, agent.Id, UsageTurn, result.Usage, result.ContextTokens, contextWindow)
 
-	return messageCompleteTurn(pending.Id, session.Id, result.Content, result.Reasoning)
+	return messageCompleteTurn(pending.Id, replacedId, session.Id, agent.Id, result.Content, result.Reasoning)
 }
 
-func chatWithTools(ctx context.Context, session *Session, agent *NaruAgent, content string, defs []modules.Def, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
-	return chatWithToolPolicy(ctx, session, agent, content, nil, defs, config.Client.Thinking.Level, onContent, onReasoning, onTool, approve, config.AllowDangerousTools)
+func chatWithTools(ctx context.Context, session *Session, agent *NaruAgent, content string, attachments []*Attachment, defs []modules.Def,
+	onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
+	return chatWithToolPolicy(ctx, session, agent, content, attachments, defs, config.Client.Thinking.Level, onContent, onReasoning, onTool, approve, config.AllowDangerousTools)
 }
 
 func Chat(ctx context.Context, session *Session, agent *NaruAgent, content string, onContent, onReasoning func(string)) (*Message, error) {
@@ -295,19 +400,24 @@ func Chat(ctx context.Context, session *Session, agent *NaruAgent, content strin
 		defs = modules.DefaultTools()
 	}
 
-	return chatWithTools(ctx, session, agent, content, defs, onContent, onReasoning, nil, nil)
+	return chatWithTools(ctx, session, agent, content, nil, defs, onContent, onReasoning, nil, nil)
 }
 
-func ChatWithApproval(ctx context.Context, session *Session, agent *NaruAgent, content string, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
+func ChatWithAttachments(ctx context.Context, session *Session, agent *NaruAgent, content string, attachments []*Attachment,
+	onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
 	var defs []modules.Def
 
 	if config.Client.Tools.Enabled {
 		defs = modules.DefaultTools()
 	}
 
-	return chatWithTools(ctx, session, agent, content, defs, onContent, onReasoning, onTool, approve)
+	return chatWithTools(ctx, session, agent, content, attachments, defs, onContent, onReasoning, onTool, approve)
+}
+
+func ChatWithApproval(ctx context.Context, session *Session, agent *NaruAgent, content string, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
+	return ChatWithAttachments(ctx, session, agent, content, nil, onContent, onReasoning, onTool, approve)
 }
 
 func ChatWithTools(ctx context.Context, session *Session, agent *NaruAgent, content string, defs []modules.Def, onContent, onReasoning func(string)) (*Message, error) {
-	return chatWithTools(ctx, session, agent, content, defs, onContent, onReasoning, nil, nil)
+	return chatWithTools(ctx, session, agent, content, nil, defs, onContent, onReasoning, nil, nil)
 }
@@ -11,14 +11,16 @@ import (
 	"strings"
 
 	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/modules"
 	"github.com/devproje/mininaru/util"
 	"github.com/openai/openai-go"
 )
 
 type Summary struct {
-	SessionId        string `json:"session_id"`
-	Content          string `json:"content"`
-	ThroughMessageId string `json:"through_message_id"`
+	SessionId              string `json:"session_id"`
+	Content                string `json:"content"`
+	ThroughMessageId       string `json:"through_message_id"`
+	ElidedThroughMessageId string `json:"elided_through_message_id"`
 }
 
 const maxSummaryChars = 2048
@@ -37,8 +39,9 @@ func SummaryLoad(sessionId string) (*Summary, error) {
 
 	var err error
 
-	err = util.DB.QueryRow("SELECT session_id, content, through_message_id FROM session_summaries WHERE session_id = ?;", sessionId).
-		Scan(&summary.SessionId, &summary.Content, &summary.ThroughMessageId)
+	err = util.DB.QueryRow(`SELECT session_id, content, COALESCE(through_message_id, ''), elided_through_message_id
+		FROM session_summaries WHERE session_id = ?;`, sessionId).
+		Scan(&summary.SessionId, &summary.Content, &summary.ThroughMessageId, &summary.ElidedThroughMessageId)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -60,9 +63,24 @@ func SummarySave(sessionId, content, throughMessageId string) error {
 	return err
 }
 
+func summaryElide(sessionId, throughMessageId string) error {
+	var err error
+
+	_, err = util.DB.Exec(`INSERT INTO session_summaries (session_id, content, through_message_id, elided_through_message_id)
+		VALUES (?, '', NULL, ?)
+		ON CONFLICT(session_id) DO UPDATE SET elided_through_message_id = excluded.elided_through_message_id;`,
+		sessionId, throughMessageId)
+
+	return err
+}
+
 func summaryTail(history []*Message, throughMessageId string) []*Message {
 	var index int
 
+	if throughMessageId == "" {
+		return history
+	}
+
 	for index = range history {
 		if history[index].Id != throughMessageId {
 			continue
@@ -167,7 +185,7 @@ func CompactNow(ctx context.Context, agent *NaruAgent, session *Session) (bool,
 	}
 
 	updated, usage, err = summarize(ctx, agent, text, tail)
-	usageRecord(session.Id, "", UsageCompaction, usage)
+	usageRecord(session.Id, "", agent.Id, UsageCompaction, usage)
 
 	if err != nil {
 		return false, err
@@ -184,36 +202,87 @@ func CompactNow(ctx context.Context, agent *NaruAgent, session *Session) (bool,
 	return true, nil
 }
 
-func compactHistory(ctx context.Context, agent *NaruAgent, session *Session, history []*Message) (string, []*Message) {
-	var previous *Summary
-	var text string
-	var tail []*Message
-	var updated string
-	var usage TokenUsage
-	var tokens int64
-	var window int64
-	var known bool
+func keptTurnsStart(tail []*Message, keep int) int {
+	var index int
+	var turns int
+
+	for index = len(tail) - 1; index >= 0; index-- {
+		if tail[index].Role != "user" {
+			continue
+		}
+
+		turns++
+		if turns == keep {
+			return index
+		}
+	}
+
+	return 0
+}
+
+func compactElide(agent *NaruAgent, session *Session, tail []*Message, calls map[string][]*ToolCall) (map[string][]*ToolCall, int64) {
+	var start int
+	var elided map[string][]*ToolCall
+	var saved int64
 
 	var err error
 
-	previous, err = SummaryLoad(session.Id)
+	start = keptTurnsStart(tail, config.ContextKeepTurns())
+	if start == 0 {
+		return calls, 0
+	}
+
+	elided = elideCalls(tail[:start], calls, tail[start-1].Id)
+	saved = estimateHistory(agent.Model, tail[:start], calls) - estimateHistory(agent.Model, tail[:start], elided)
+	if saved <= 0 {
+		return calls, 0
+	}
+
+	err = summaryElide(session.Id, tail[start-1].Id)
 	if err != nil {
-		util.Log.Warn("loading the conversation summary failed", "session", session.Id, "error", err)
+		util.Log.Warn("saving the elided tool results failed", "session", session.Id, "error", err)
+
+		return calls, 0
 	}
 
-	tail = history
-	if previous != nil {
-		text = previous.Content
-		tail = summaryTail(history, previous.ThroughMessageId)
+	util.Log.Debug("elided old tool results", "session", session.Id, "turns", len(tail[:start]), "saved_tokens", saved)
+
+	return elided, saved
+}
+
+func compactWindow(agent *NaruAgent, session *Session, text string, tail []*Message, calls map[string][]*ToolCall) ([]*Message, int64) {
+	var start int
+	var saved int64
+
+	var err error
+
+	start = keptTurnsStart(tail, config.ContextKeepTurns())
+	if start == 0 {
+		return tail, 0
 	}
 
-	tokens, window, known, err = SessionContextTokens(session.Id)
-	if !config.Client.Context.Compact || !known || window <= 0 || tokens*100 < window*90 || len(tail) == 0 {
-		return text, tail
+	err = SummarySave(session.Id, text, tail[start-1].Id)
+	if err != nil {
+		util.Log.Warn("saving the conversation window failed", "session", session.Id, "error", err)
+
+		return tail, 0
 	}
 
+	saved = estimateHistory(agent.Model, tail[:start], calls)
+
+	util.Log.Debug("dropped turns outside the window", "session", session.Id, "turns", start, "saved_tokens", saved)
+
+	return tail[start:], saved
+}
+
+func compactSummarize(ctx context.Context, agent *NaruAgent, session *Session, text string, tail []*Message) (string, []*Message) {
+	var updated string
+	var usage TokenUsage
+
+	var err error
+
 	updated, usage, err = summarize(ctx, agent, text, tail)
-	usageRecord(session.Id, "", UsageCompaction, usage)
+	usageRecord(session.Id, "", agent.Id, UsageCompaction, usage)
 
 	if err != nil {
 		util.Log.Warn("compacting the conversation failed",
@@ -235,3 +304,61 @@ func compactHistory(ctx context.Context, agent *NaruAgent, session *Session, his
 
 	return updated, nil
 }
+
+func compactHistory(ctx context.Context, agent *NaruAgent, session *Session, history []*Message, calls map[string][]*ToolCall,
+	prompt string, defs []modules.Def, content string) (string, []*Message, map[string][]*ToolCall) {
+	var previous *Summary
+	var text string
+	var tail []*Message
+	var tokens int64
+	var window int64
+	var known bool
+	var strategy string
+	var saved int64
+
+	var err error
+
+	previous, err = SummaryLoad(session.Id)
+	if err != nil {
+		util.Log.Warn("loading the conversation summary failed", "session", session.Id, "error", err)
+	}
+
+	tail = history
+	if previous != nil {
+		text = previous.Content
+		tail = summaryTail(history, previous.ThroughMessageId)
+		calls = elideCalls(history, calls, previous.ElidedThroughMessageId)
+	}
+
+	if !config.Client.Context.Compact || len(tail) == 0 {
+		return text, tail, calls
+	}
+
+	tokens, window, known, err = contextEstimate(agent, session.Id, text, tail, calls, prompt, defs, content)
+	if err != nil {
+		util.Log.Warn("measuring the conversation context failed", "session", session.Id, "error", err)
+	}
+	if !known || window <= 0 || tokens*100 < window*90 {
+		return text, tail, calls
+	}
+
+	for _, strategy = range agent.CompactStrategies() {
+		switch strategy {
+		case config.StrategyElide:
+			calls, saved = compactElide(agent, session, tail, calls)
+		case config.StrategyWindow:
+			tail, saved = compactWindow(agent, session, text, tail, calls)
+		case config.StrategySummarize:
+			text, tail = compactSummarize(ctx, agent, session, text, tail)
+
+			return text, tail, calls
+		}
+
+		tokens -= saved
+		if tokens*100 < window*90 {
+			break
+		}
+	}
+
+	return text, tail, calls
+}
@@ -12,7 +12,9 @@ import (
 	"testing"
 
 	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/modules"
 	"github.com/devproje/mininaru/util"
+	"github.com/google/uuid"
 )
 
 func compactSetup(t *testing.T, srvURL string, compact bool) (*Session, *NaruAgent) {
@@ -71,7 +73,7 @@ func seedContextUsage(t *testing.T, sessionId string, tokens, window int64) {
 	if len(history) == 0 {
 		t.Fatal("context usage needs at least one message")
 	}
-	usageRecordWithContext(sessionId, history[len(history)-1].Id, UsageTurn, usageOf(tokens, 1), tokens, window)
+	usageRecordWithContext(sessionId, history[len(history)-1].Id, "", UsageTurn, usageOf(tokens, 1), tokens, window)
 }
 
 func compactServer(t *testing.T, requests *[]string) *httptest.Server {
@@ -571,3 +573,168 @@ func TestCompactNowReturnsTheFailureInsteadOfSwallowingIt(t *testing.T) {
 		t.Fatal("a summary was saved even though the call failed")
 	}
 }
+
+func seedToolTurn(t *testing.T, sessionId, result string) string {
+	var message *Message
+	var id string
+
+	var err error
+
+	t.Helper()
+
+	message, err = MessageSave(sessionId, "user", "read the log", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id = uuid.NewString()
+	_, err = util.DB.Exec(`INSERT INTO tool_calls (id, call_id, message_id, name, arguments, result, status, error)
+		VALUES (?, ?, ?, 'echo', '{}', ?, ?, '');`, id, "call-"+id, message.Id, result, MessageCompleted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = MessageSave(sessionId, "assistant", "the log is long", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+func echoDefs() []modules.Def {
+	return []modules.Def{{
+		Name: "echo", Permission: modules.PermissionSafe, Parameters: map[string]any{"type": "object"},
+		Execute: func(context.Context, string) (string, error) { return "", nil },
+	}}
+}
+
+func TestCompactionElidesOldToolResultsBeforeSummarising(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var id string
+	var found *Summary
+	var unset bool
+
+	var err error
+
+	srv = compactServer(t, &requests)
+	defer srv.Close()
+
+	session, agent = compactSetup(t, srv.URL, true)
+	config.Client.Context.KeepTurns = 1
+	id = seedToolTurn(t, session.Id, strings.Repeat("x", 800))
+	seedTurns(t, session.Id, 1)
+	seedContextUsage(t, session.Id, 95, 100)
+
+	_, err = ChatWithTools(context.Background(), session, agent, "hello", echoDefs(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 {
+		t.Fatalf("request count = %d, want the elision to make room without a summary", len(requests))
+	}
+	if strings.Contains(requests[0], strings.Repeat("x", 800)) || !strings.Contains(requests[0], id) {
+		t.Fatalf("old tool result was not replaced by a stub: %s", requests[0])
+	}
+
+	found = summaryRow(t, session.Id)
+	if found == nil || found.Content != "" || found.ThroughMessageId != "" || found.ElidedThroughMessageId == "" {
+		t.Fatalf("summary row = %+v, want only an elision marker", found)
+	}
+	err = util.DB.QueryRow("SELECT through_message_id IS NULL FROM session_summaries WHERE session_id = ?;", session.Id).Scan(&unset)
+	if err != nil || !unset {
+		t.Fatalf("through_message_id unset = %t, %v, want NULL rather than an empty id", unset, err)
+	}
+
+	requests = nil
+	_, err = ChatWithTools(context.Background(), session, agent, "again", echoDefs(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(requests[0], strings.Repeat("x", 800)) {
+		t.Fatal("the elided result came back on the next turn")
+	}
+}
+
+func TestCompactionWindowDropsOldTurnsWithoutTheModel(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var history []*Message
+	var found *Summary
+
+	var err error
+
+	srv = compactServer(t, &requests)
+	defer srv.Close()
+
+	session, agent = compactSetup(t, srv.URL, true)
+	agent.ContextStrategy = []string{config.StrategyWindow}
+	config.Client.Context.KeepTurns = 1
+	seedTurns(t, session.Id, 3)
+	seedContextUsage(t, session.Id, 95, 100)
+
+	history, err = MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ChatWithTools(context.Background(), session, agent, "hello", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 || strings.Count(requests[0], strings.Repeat("q", 64)) != 1 {
+		t.Fatalf("request count = %d, want one request with only the last turn: %v", len(requests), requests)
+	}
+
+	found = summaryRow(t, session.Id)
+	if found == nil || found.Content != "" || found.ThroughMessageId != history[3].Id {
+		t.Fatalf("summary row = %+v, want the window marker after the second turn", found)
+	}
+}
+
+func TestCompactionFallsThroughToTheSummaryWhenElisionIsNotEnough(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+
+	var err error
+
+	srv = compactServer(t, &requests)
+	defer srv.Close()
+
+	session, agent = compactSetup(t, srv.URL, true)
+	config.Client.Context.KeepTurns = 1
+	seedToolTurn(t, session.Id, strings.Repeat("x", 600))
+	seedTurns(t, session.Id, 1)
+	seedContextUsage(t, session.Id, 400, 100)
+
+	_, err = ChatWithTools(context.Background(), session, agent, "hello", echoDefs(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 2 || !strings.Contains(requests[0], "turns-to-fold-in") {
+		t.Fatalf("request count = %d, want a summary after the elision", len(requests))
+	}
+	if summaryRow(t, session.Id).Content != "the user likes tea" {
+		t.Fatalf("summary = %+v", summaryRow(t, session.Id))
+	}
+}
+
+func TestKeptTurnsStartCountsUserMessages(t *testing.T) {
+	var tail []*Message
+
+	tail = []*Message{{Role: "user"}, {Role: "assistant"}, {Role: "user"}, {Role: "assistant"}, {Role: "user"}}
+
+	if keptTurnsStart(tail, 2) != 2 || keptTurnsStart(tail, 3) != 0 || keptTurnsStart(tail, 5) != 0 {
+		t.Fatalf("keptTurnsStart = %d %d %d, want 2 0 0", keptTurnsStart(tail, 2), keptTurnsStart(tail, 3), keptTurnsStart(tail, 5))
+	}
+}
@@ -27,6 +27,8 @@ type completionRun struct {
 
 	AgentId string
 	Depth   int
+	Vision  bool
+	Sandbox string
 
 	SessionId   string
 	MessageId   string
@@ -35,6 +37,7 @@ type completionRun struct {
 	OnTool      ToolEventFunc
 	Approve     ToolApprovalFunc
 	cacheWrites int64
+	images      *modules.ToolImages
 }
 
 type Completion struct {
@@ -99,22 +102,37 @@ func (r *completionRun) stream(ctx context.Context, reply, reasoning *strings.Bu
 	return &accumulator, nil
 }
 
+func (r *completionRun) toolContext(ctx context.Context) context.Context {
+	ctx = subagentContext(ctx, subagentPolicy{
+		CallerId: r.AgentId, SessionId: r.SessionId, Defs: r.Defs,
+		AllowDangerous: r.AllowDangerous, AllowPrivileged: r.AllowPrivileged,
+		Approve: r.Approve, Depth: r.Depth, Sandbox: r.Sandbox,
+	})
+
+	ctx = modules.ToolSessionContext(ctx, r.SessionId)
+	if r.MessageId != "" {
+		ctx = modules.ToolTurnContext(ctx, r.MessageId)
+	}
+	ctx = modules.ToolSandboxContext(ctx, r.Sandbox)
+
+	return modules.ToolImagesContext(ctx, r.images)
+}
+
 func (r *completionRun) dispatch(ctx context.Context, message openai.ChatCompletionMessage) error {
 	var call openai.ChatCompletionMessageToolCall
 	var record *ToolCall
+	var records []*ToolCall
+	var images openai.ChatCompletionMessageParamUnion
+	var ok bool
 
 	var err error
 
 	r.Params.Messages = append(r.Params.Messages, assistantToolCallMessage(message))
 
-	ctx = subagentContext(ctx, subagentPolicy{
-		CallerId: r.AgentId, SessionId: r.SessionId, Defs: r.Defs,
-		AllowDangerous: r.AllowDangerous, AllowPrivileged: r.AllowPrivileged,
-		Approve: r.Approve, Depth: r.Depth,
-	})
+	ctx = r.toolContext(ctx)
 
 	for _, call = range message.ToolCalls {
-		record, err = toolCallStart(r.MessageId, call)
+		record, err = toolCallStart(r.MessageId, r.AgentId, call)
 		if err != nil {
 			return err
 		}
@@ -124,7 +142,7 @@ func (r *completionRun) dispatch(ctx context.Context, message openai.ChatComplet
 				Arguments: record.Arguments, Status: record.Status})
 		}
 
-		record, err = executeTool(ctx, r.SessionId, record, r.Defs, r.AllowDangerous, r.AllowPrivileged, r.Approve)
+		record, err = executeTool(toolProgressContext(ctx, record, r.OnTool), r.SessionId, record, r.Defs, r.AllowDangerous, r.AllowPrivileged, r.Approve)
 		if err != nil {
 			return err
 		}
@@ -135,6 +153,12 @@ func (r *completionRun) dispatch(ctx context.Context, message openai.ChatComplet
 		}
 
 		r.Params.Messages = append(r.Params.Messages, openai.ToolMessage(record.Result, call.ID))
+		records = append(records, record)
+	}
+
+	images, ok = toolImageMessage(records)
+	if ok {
+		r.Params.Messages = append(r.Params.Messages, images)
 	}
 
 	return nil
@@ -150,6 +174,9 @@ func (r *completionRun) execute(ctx context.Context) (*Completion, error) {
 
 	var err error
 
+	if r.Vision {
+		r.images = &modules.ToolImages{Left: toolImageTurnBytes}
+	}
 	if r.Anthropic != nil {
 		return r.executeAnthropic(ctx)
 	}
@@ -190,9 +217,52 @@ func (r *completionRun) execute(ctx context.Context) (*Completion, error) {
 	return nil, fmt.Errorf("tool call limit exceeded after %d rounds", maxToolRounds)
 }
 
-func Complete(ctx context.Context, agent *NaruAgent, messages []openai.ChatCompletionMessageParamUnion,
-	defs []modules.Def, thinking string, onContent, onReasoning func(string)) (*Completion, error) {
+func lastUserText(messages []openai.ChatCompletionMessageParamUnion) string {
+	var index int
+	var user *openai.ChatCompletionUserMessageParam
+	var part openai.ChatCompletionContentPartUnionParam
+	var texts []string
+
+	for index = len(messages) - 1; index >= 0; index-- {
+		user = messages[index].OfUser
+		if user == nil {
+			continue
+		}
+		if user.Content.OfString.Valid() {
+			return user.Content.OfString.Value
+		}
+
+		for _, part = range user.Content.OfArrayOfContentParts {
+			if part.OfText != nil {
+				texts = append(texts, part.OfText.Text)
+			}
+		}
+
+		return strings.Join(texts, "\n")
+	}
+
+	return ""
+}
+
+func sessionlessTools(defs []modules.Def) []modules.Def {
+	var def modules.Def
+	var kept []modules.Def
+
+	for _, def = range defs {
+		if def.Name == ToolResultToolName || def.Name == ArtifactToolName || def.Name == modules.BashOutputToolName {
+			continue
+		}
+
+		kept = append(kept, def)
+	}
+
+	return kept
+}
+
+func completeSampled(ctx context.Context, agent *NaruAgent, messages []openai.ChatCompletionMessageParamUnion,
+	defs []modules.Def, thinking string, sampling Sampling, recalling bool, onContent, onReasoning func(string)) (*Completion, error) {
 	var params openai.ChatCompletionNewParams
+	var recalled *recall
 	var run completionRun
 
 	if agent == nil {
@@ -205,13 +275,17 @@ func Complete(ctx context.Context, agent *NaruAgent, messages []openai.ChatCompl
 		return nil, fmt.Errorf("at least one message is required")
 	}
 
-	defs = permittedTools(defs)
+	defs = permittedTools(sessionlessTools(defs))
 
-	params.Messages = append(params.Messages, openai.SystemMessage(systemPrompt(agent, defs)))
+	if recalling {
+		recalled = recallFor(ctx, agent, defs, lastUserText(messages))
+	}
+	params.Messages = append(params.Messages, openai.SystemMessage(systemPrompt(agent, defs, recalled)))
 	params.Messages = append(params.Messages, messages...)
 
 	params.Model = agent.Model
 	params.StreamOptions.IncludeUsage = param.NewOpt(true)
+	applySampling(&params, sampling)
 	applyOpenAICache(&params, agentProvider(agent))
 
 	if len(defs) > 0 {
@@ -221,9 +295,30 @@ func Complete(ctx context.Context, agent *NaruAgent, messages []openai.ChatCompl
 	if thinking != "" && thinking != config.ThinkingOff && config.ThinkingValid(thinking) {
 		params.ReasoningEffort = openai.ReasoningEffort(thinking)
 	}
+	if !agent.SeesImages() {
+		params.Messages = withholdImages(params.Messages)
+	}
 
 	run = completionRun{AI: agent.AI, Anthropic: agent.Anthropic, Provider: agentProvider(agent), Params: params, Defs: defs, AgentId: agent.Id,
-		OnContent: onContent, OnReasoning: onReasoning}
+		Vision: agent.SeesImages(), Sandbox: agent.Sandbox, OnContent: onContent, OnReasoning: onReasoning}
 
 	return run.execute(ctx)
 }
+
+func Complete(ctx context.Context, agent *NaruAgent, messages []openai.ChatCompletionMessageParamUnion,
+	defs []modules.Def, thinking string, onContent, onReasoning func(string)) (*Completion, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required to complete")
+	}
+
+	return completeSampled(ctx, agent, messages, defs, thinking, agent.Sampling, false, onContent, onReasoning)
+}
+
+func CompleteTurn(ctx context.Context, agent *NaruAgent, messages []openai.ChatCompletionMessageParamUnion,
+	defs []modules.Def, thinking string, onContent, onReasoning func(string)) (*Completion, error) {
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required to complete")
+	}
+
+	return completeSampled(ctx, agent, messages, defs, thinking, agent.Sampling, true, onContent, onReasoning)
+}
@@ -71,11 +71,16 @@ func TestCompleteRunsToolsWithoutTouchingDatabase(t *testing.T) {
 	}
 
 	result, err = Complete(context.Background(), agent,
-		[]openai.ChatCompletionMessageParamUnion{openai.UserMessage("hi")}, []modules.Def{def}, "", nil, nil)
+		[]openai.ChatCompletionMessageParamUnion{openai.UserMessage("hi")}, []modules.Def{def, ArtifactReadTool(), ToolResultTool(), modules.BashOutput()}, "", nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
+	if strings.Contains(requests[0], ArtifactToolName) || strings.Contains(requests[0], ToolResultToolName) ||
+		strings.Contains(requests[0], modules.BashOutputToolName) {
+		t.Fatal("a stateless completion offered tools that need a session")
+	}
+
 	if result.Content != "done" || executions != 1 || len(requests) != 2 {
 		t.Fatalf("content=%q executions=%d requests=%d", result.Content, executions, len(requests))
 	}
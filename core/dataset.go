@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/devproje/mininaru/util"
+	"github.com/openai/openai-go"
+)
+
+type DatasetFilter struct {
+	Rated string
+}
+
+type datasetRow struct {
+	messageId string
+	sessionId string
+}
+
+type datasetRecord struct {
+	Messages []openai.ChatCompletionMessageParamUnion `json:"messages"`
+}
+
+const DatasetOpenAI = "openai-jsonl"
+
+func datasetRated(filter DatasetFilter) ([]datasetRow, error) {
+	var rows *sql.Rows
+	var cur datasetRow
+	var rated []datasetRow
+
+	var err error
+
+	rows, err = util.DB.Query(`SELECT r.message_id, m.session_id FROM message_ratings r
+		JOIN messages m ON m.id = r.message_id
+		WHERE m.status = ? AND (? = '' OR r.rating = ?) ORDER BY m.rowid ASC;`,
+		MessageCompleted, filter.Rated, filter.Rated)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&cur.messageId, &cur.sessionId)
+		if err != nil {
+			return nil, err
+		}
+
+		rated = append(rated, cur)
+	}
+
+	return rated, rows.Err()
+}
+
+func datasetThread(sessionId, messageId string) ([]*Message, error) {
+	var thread []*Message
+
+	var err error
+
+	thread, err = messageQuery(`SELECT id, session_id, agent_id, role, content, reasoning, status, error, variant_of FROM messages
+		WHERE session_id = ?1 AND status = ?2 AND (
+			(selected = 1 AND rowid < (SELECT MAX(rowid) FROM messages WHERE session_id = ?1 AND role = 'user' AND status = ?2
+				AND rowid < (SELECT rowid FROM messages WHERE id = ?3)))
+			OR rowid BETWEEN (SELECT MAX(rowid) FROM messages WHERE session_id = ?1 AND role = 'user' AND status = ?2
+				AND rowid < (SELECT rowid FROM messages WHERE id = ?3)) AND (SELECT rowid FROM messages WHERE id = ?3)
+		) ORDER BY rowid ASC;`, sessionId, MessageCompleted, messageId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = attachmentsLoad(sessionId, thread)
+	if err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+func DatasetExport(w io.Writer, filter DatasetFilter, format string) (int, error) {
+	var rated []datasetRow
+	var row datasetRow
+	var calls map[string][]*ToolCall
+	var callsSession string
+	var thread []*Message
+	var line []byte
+	var written int
+
+	var err error
+
+	if format != DatasetOpenAI {
+		return 0, fmt.Errorf("unknown dataset format %q; use %s", format, DatasetOpenAI)
+	}
+	if filter.Rated != "" && !RatingValid(filter.Rated) {
+		return 0, fmt.Errorf("rating must be %s or %s, got %q", RatingGood, RatingBad, filter.Rated)
+	}
+
+	rated, err = datasetRated(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row = range rated {
+		if calls == nil || callsSession != row.sessionId {
+			calls, err = toolCallsBySession(row.sessionId)
+			if err != nil {
+				return written, err
+			}
+			callsSession = row.sessionId
+		}
+
+		thread, err = datasetThread(row.sessionId, row.messageId)
+		if err != nil {
+			return written, err
+		}
+		if len(thread) == 0 {
+			continue
+		}
+
+		line, err = json.Marshal(datasetRecord{Messages: historyMessages(thread, calls)})
+		if err != nil {
+			return written, err
+		}
+
+		_, err = w.Write(append(line, '\n'))
+		if err != nil {
+			return written, err
+		}
+		written++
+	}
+
+	return written, nil
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/modules"
+)
+
+func TestDatasetExportReplaysRatedTurnsWithToolCalls(t *testing.T) {
+	var requests int
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var def modules.Def
+	var good, bad *Message
+	var out bytes.Buffer
+	var written int
+	var lines []string
+	var record struct {
+		Messages []map[string]any `json:"messages"`
+	}
+	var roles []string
+	var message map[string]any
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		switch requests {
+		case 1:
+			io.WriteString(w, toolChunk("r1",
+				`{"role":"assistant","tool_calls":[{"index":0,"id":"c1","type":"function","function":{"name":"echo","arguments":"{}"}}]}`,
+				`"tool_calls"`))
+		case 2:
+			io.WriteString(w, toolChunk("r2", `{"role":"assistant","content":"echo says hi"}`, `"stop"`))
+		default:
+			io.WriteString(w, toolChunk("r3", `{"role":"assistant","content":"wrong"}`, `"stop"`))
+		}
+
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	session, agent = compactSetup(t, srv.URL, false)
+
+	def = modules.Def{
+		Name: "echo", Description: "echo", Permission: modules.PermissionSafe,
+		Parameters: map[string]any{"type": "object"},
+		Execute:    func(context.Context, string) (string, error) { return "hi", nil },
+	}
+
+	good, err = ChatWithTools(context.Background(), session, agent, "ask echo", []modules.Def{def}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad, err = Chat(context.Background(), session, agent, "and now?", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = MessageRate(good.Id, RatingGood, "used the tool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = MessageRate(bad.Id, RatingBad, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	written, err = DatasetExport(&out, DatasetFilter{Rated: RatingGood}, DatasetOpenAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = strings.Split(strings.TrimSpace(out.String()), "\n")
+	if written != 1 || len(lines) != 1 {
+		t.Fatalf("exported %d records: %q", written, out.String())
+	}
+
+	err = json.Unmarshal([]byte(lines[0]), &record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, message = range record.Messages {
+		roles = append(roles, message["role"].(string))
+	}
+	if strings.Join(roles, ",") != "user,assistant,tool,assistant" {
+		t.Fatalf("roles = %v", roles)
+	}
+	if record.Messages[1]["tool_calls"] == nil || record.Messages[3]["content"] != "echo says hi" {
+		t.Fatalf("record = %s", lines[0])
+	}
+
+	out.Reset()
+	written, err = DatasetExport(&out, DatasetFilter{}, DatasetOpenAI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != 2 || !strings.Contains(out.String(), "wrong") {
+		t.Fatalf("unfiltered export wrote %d records", written)
+	}
+}
+
+func TestMessageRateOnlyAcceptsAssistantReplies(t *testing.T) {
+	var session *Session
+	var question, answer *Message
+	var rating *Rating
+
+	var err error
+
+	session, _ = thinkingSetup(t, "http://127.0.0.1:0")
+
+	question, err = MessageSave(session.Id, "user", "q", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err = MessageSave(session.Id, "assistant", "a", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = MessageRate(question.Id, RatingGood, "")
+	if err == nil {
+		t.Fatal("a user message was rated")
+	}
+	err = MessageRate(answer.Id, "meh", "")
+	if err == nil {
+		t.Fatal("an unknown rating was accepted")
+	}
+
+	err = MessageRate(answer.Id, RatingBad, "too short")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = MessageRate(answer.Id, RatingGood, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = MessageRating(answer.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rating == nil || rating.Rating != RatingGood || rating.Note != "" {
+		t.Fatalf("rating after change = %+v", rating)
+	}
+
+	err = MessageRate(answer.Id, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = MessageRating(answer.Id)
+	if err != nil || rating != nil {
+		t.Fatalf("cleared rating = %+v, %v", rating, err)
+	}
+}
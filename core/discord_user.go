@@ -188,3 +188,23 @@ func DiscordPairClaim(botId, code, userId string) (bool, error) {
 	}
 	return true, tx.Commit()
 }
+
+func DiscordAnswerSave(discordMessageId, messageId string) error {
+	var err error
+
+	_, err = util.DB.Exec(`INSERT INTO discord_answers (discord_message_id, message_id) VALUES (?, ?)
+		ON CONFLICT(discord_message_id) DO UPDATE SET message_id = excluded.message_id;`, discordMessageId, messageId)
+	return err
+}
+
+func DiscordAnswerMessage(discordMessageId string) (string, error) {
+	var messageId string
+
+	var err error
+
+	err = util.DB.QueryRow("SELECT message_id FROM discord_answers WHERE discord_message_id = ?;", discordMessageId).Scan(&messageId)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return messageId, err
+}
@@ -0,0 +1,281 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/devproje/mininaru/util"
+	"github.com/openai/openai-go"
+)
+
+type embeddingItem struct {
+	Id      string
+	Content string
+}
+
+type storedEmbedding struct {
+	Digest string
+	Vector []float32
+}
+
+const (
+	EmbeddingMemory    = "memory"
+	EmbeddingKnowledge = "knowledge"
+)
+
+const embeddingBatch = 64
+
+func (p *Provider) Embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	var ai *openai.Client
+	var vectors [][]float32
+	var start int
+	var end int
+	var response *openai.CreateEmbeddingResponse
+	var data openai.Embedding
+	var vector []float32
+	var index int
+
+	var err error
+
+	if p == nil || p.EmbeddingModel == "" {
+		return nil, fmt.Errorf("provider has no embedding model")
+	}
+	if p.ProviderKind() != ProviderOpenAI {
+		return nil, fmt.Errorf("embeddings are only supported for openai providers")
+	}
+
+	ai = newClient(p)
+	vectors = make([][]float32, len(inputs))
+
+	for start = 0; start < len(inputs); start += embeddingBatch {
+		end = min(start+embeddingBatch, len(inputs))
+		response, err = ai.Embeddings.New(ctx, openai.EmbeddingNewParams{
+			Model:          p.EmbeddingModel,
+			Input:          openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs[start:end]},
+			EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, data = range response.Data {
+			if data.Index < 0 || int(data.Index) >= end-start {
+				return nil, fmt.Errorf("embedding index %d is out of range", data.Index)
+			}
+
+			vector = make([]float32, len(data.Embedding))
+			for index = range data.Embedding {
+				vector[index] = float32(data.Embedding[index])
+			}
+			vectors[start+int(data.Index)] = vector
+		}
+	}
+
+	for index = range vectors {
+		if len(vectors[index]) == 0 {
+			return nil, fmt.Errorf("embedding %d is missing from the response", index)
+		}
+	}
+
+	return vectors, nil
+}
+
+func vectorEncode(vector []float32) []byte {
+	var buf []byte
+	var index int
+
+	buf = make([]byte, len(vector)*4)
+	for index = range vector {
+		binary.LittleEndian.PutUint32(buf[index*4:], math.Float32bits(vector[index]))
+	}
+
+	return buf
+}
+
+func vectorDecode(buf []byte) []float32 {
+	var vector []float32
+	var index int
+
+	vector = make([]float32, len(buf)/4)
+	for index = range vector {
+		vector[index] = math.Float32frombits(binary.LittleEndian.Uint32(buf[index*4:]))
+	}
+
+	return vector
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, normA, normB float64
+	var index int
+
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	for index = range a {
+		dot += float64(a[index]) * float64(b[index])
+		normA += float64(a[index]) * float64(a[index])
+		normB += float64(b[index]) * float64(b[index])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func embeddingDigest(content string) string {
+	var sum [sha256.Size]byte
+
+	sum = sha256.Sum256([]byte(content))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func embeddingsLoad(source, model string) (map[string]storedEmbedding, error) {
+	var stored map[string]storedEmbedding
+	var rows *sql.Rows
+	var id string
+	var digest string
+	var buf []byte
+
+	var err error
+
+	stored = make(map[string]storedEmbedding)
+
+	rows, err = util.DB.Query("SELECT source_id, digest, vector FROM embeddings WHERE source = ? AND model = ?;", source, model)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&id, &digest, &buf)
+		if err != nil {
+			return nil, err
+		}
+		stored[id] = storedEmbedding{Digest: digest, Vector: vectorDecode(buf)}
+	}
+
+	return stored, rows.Err()
+}
+
+func embeddingSave(source, id, model, digest string, vector []float32) error {
+	var err error
+
+	_, err = util.DB.Exec(`INSERT INTO embeddings (source, source_id, model, digest, vector) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(source, source_id, model) DO UPDATE SET digest = excluded.digest, vector = excluded.vector, created_at = CURRENT_TIMESTAMP;`,
+		source, id, model, digest, vectorEncode(vector))
+
+	return err
+}
+
+func embeddingDelete(source, id, model string) error {
+	var err error
+
+	_, err = util.DB.Exec("DELETE FROM embeddings WHERE source = ? AND source_id = ? AND model = ?;", source, id, model)
+
+	return err
+}
+
+func embeddingVectors(ctx context.Context, prov *Provider, source string, items []embeddingItem) ([][]float32, error) {
+	var stored map[string]storedEmbedding
+	var vectors [][]float32
+	var digests []string
+	var missing []int
+	var inputs []string
+	var fresh [][]float32
+	var current map[string]bool
+	var cached storedEmbedding
+	var found bool
+	var index int
+	var id string
+
+	var err error
+
+	if util.DB == nil {
+		return nil, fmt.Errorf("database is not initialized")
+	}
+
+	stored, err = embeddingsLoad(source, prov.EmbeddingModel)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors = make([][]float32, len(items))
+	digests = make([]string, len(items))
+	current = make(map[string]bool, len(items))
+	for index = range items {
+		current[items[index].Id] = true
+		digests[index] = embeddingDigest(items[index].Content)
+
+		cached, found = stored[items[index].Id]
+		if found && cached.Digest == digests[index] {
+			vectors[index] = cached.Vector
+			continue
+		}
+
+		missing = append(missing, index)
+		inputs = append(inputs, items[index].Content)
+	}
+
+	if len(inputs) > 0 {
+		fresh, err = prov.Embed(ctx, inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		for index = range missing {
+			vectors[missing[index]] = fresh[index]
+			err = embeddingSave(source, items[missing[index]].Id, prov.EmbeddingModel, digests[missing[index]], fresh[index])
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for id = range stored {
+		if current[id] {
+			continue
+		}
+
+		err = embeddingDelete(source, id, prov.EmbeddingModel)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return vectors, nil
+}
+
+func embeddingRank(query []float32, vectors [][]float32, limit int) []int {
+	var order []int
+	var scores []float64
+	var index int
+
+	order = make([]int, len(vectors))
+	scores = make([]float64, len(vectors))
+	for index = range vectors {
+		order[index] = index
+		scores[index] = cosine(query, vectors[index])
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return scores[order[i]] > scores[order[j]]
+	})
+
+	if len(order) > limit {
+		order = order[:limit]
+	}
+
+	return order
+}
@@ -0,0 +1,241 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/modules"
+	"github.com/pkoukk/tiktoken-go"
+	tiktokenloader "github.com/pkoukk/tiktoken-go-loader"
+)
+
+const estimateMessageTokens = 4
+
+var tokenLoaderOnce sync.Once
+
+var tokenEncoders sync.Map
+
+func tokenEncodingName(model string) string {
+	var name string
+
+	name = strings.ToLower(model)
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	switch {
+	case strings.HasPrefix(name, "gpt-4o"), strings.HasPrefix(name, "gpt-4.1"), strings.HasPrefix(name, "gpt-4.5"),
+		strings.HasPrefix(name, "gpt-5"), strings.HasPrefix(name, "gpt-oss"), strings.HasPrefix(name, "chatgpt-"),
+		name == "o1", name == "o3", name == "o4-mini", strings.HasPrefix(name, "o1-"), strings.HasPrefix(name, "o3-"),
+		strings.HasPrefix(name, "o4-"):
+		return tiktoken.MODEL_O200K_BASE
+	case strings.HasPrefix(name, "gpt-4"), strings.HasPrefix(name, "gpt-3.5"), strings.HasPrefix(name, "text-embedding-"):
+		return tiktoken.MODEL_CL100K_BASE
+	}
+
+	return ""
+}
+
+func tokenEncoder(model string) *tiktoken.Tiktoken {
+	var name string
+	var cached any
+	var encoder *tiktoken.Tiktoken
+	var ok bool
+
+	var err error
+
+	name = tokenEncodingName(model)
+	if name == "" {
+		return nil
+	}
+
+	cached, ok = tokenEncoders.Load(name)
+	if ok {
+		return cached.(*tiktoken.Tiktoken)
+	}
+
+	tokenLoaderOnce.Do(func() {
+		tiktoken.SetBpeLoader(tiktokenloader.NewOfflineLoader())
+	})
+
+	encoder, err = tiktoken.GetEncoding(name)
+	if err != nil {
+		return nil
+	}
+	cached, _ = tokenEncoders.LoadOrStore(name, encoder)
+
+	return cached.(*tiktoken.Tiktoken)
+}
+
+func estimateHeuristic(text string) int64 {
+	var ascii int64
+	var wide int64
+	var r rune
+
+	for _, r = range text {
+		if r < utf8.RuneSelf {
+			ascii++
+			continue
+		}
+
+		wide++
+	}
+
+	return (ascii+3)/4 + wide
+}
+
+func EstimateTokens(model, text string) int64 {
+	var encoder *tiktoken.Tiktoken
+
+	if text == "" {
+		return 0
+	}
+
+	encoder = tokenEncoder(model)
+	if encoder == nil {
+		return estimateHeuristic(text)
+	}
+
+	return int64(len(encoder.EncodeOrdinary(text)))
+}
+
+func estimateTools(model string, defs []modules.Def) int64 {
+	var tokens int64
+	var def modules.Def
+	var schema []byte
+
+	for _, def = range defs {
+		schema, _ = json.Marshal(def.Parameters)
+		tokens += estimateMessageTokens + EstimateTokens(model, def.Name+"\n"+def.Description+"\n"+string(schema))
+	}
+
+	return tokens
+}
+
+func estimateAttachments(model string, attachments []*Attachment) int64 {
+	var tokens int64
+	var attachment *Attachment
+
+	for _, attachment = range attachments {
+		switch {
+		case AttachmentImageType(attachment.MediaType):
+			tokens += attachmentImageTokens
+		case attachment.MediaType == "application/pdf":
+			tokens += int64(len(attachment.Content)) / 4
+		default:
+			tokens += EstimateTokens(model, string(attachment.Content))
+		}
+	}
+
+	return tokens
+}
+
+func estimateHistory(model string, history []*Message, calls map[string][]*ToolCall) int64 {
+	var tokens int64
+	var message *Message
+	var call *ToolCall
+
+	for _, message = range history {
+		tokens += estimateMessageTokens + EstimateTokens(model, message.Content) + estimateAttachments(model, message.Attachments)
+
+		if !replayableCalls(calls[message.Id]) {
+			continue
+		}
+
+		for _, call = range calls[message.Id] {
+			tokens += 2*estimateMessageTokens + EstimateTokens(model, call.Name+call.Arguments) + EstimateTokens(model, call.Result)
+			tokens += int64(len(call.Images)) * attachmentImageTokens
+		}
+	}
+
+	return tokens
+}
+
+func contextEstimate(agent *NaruAgent, sessionId, summary string, tail []*Message, calls map[string][]*ToolCall,
+	prompt string, defs []modules.Def, content string) (int64, int64, bool, error) {
+	var tokens int64
+	var window int64
+	var marked string
+	var known bool
+	var pending int64
+	var index int
+
+	var err error
+
+	tokens, window, marked, known, err = sessionContextMark(sessionId)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if agent.CachedModelContextWindow() > 0 {
+		window = agent.CachedModelContextWindow()
+	}
+
+	if content != "" {
+		pending = estimateMessageTokens + EstimateTokens(agent.Model, content)
+	}
+
+	if known {
+		for index = range tail {
+			if tail[index].Id == marked {
+				return tokens + estimateHistory(agent.Model, tail[index+1:], calls) + pending, window, true, nil
+			}
+		}
+	}
+
+	tokens = estimateMessageTokens + EstimateTokens(agent.Model, prompt) + estimateTools(agent.Model, defs)
+	if summary != "" {
+		tokens += EstimateTokens(agent.Model, summaryBlock(summary))
+	}
+	tokens += estimateHistory(agent.Model, tail, calls) + pending
+
+	return tokens, window, true, nil
+}
+
+func SessionContext(agent *NaruAgent, sessionId string) (int64, int64, bool, error) {
+	var history []*Message
+	var full []*Message
+	var previous *Summary
+	var summary string
+	var defs []modules.Def
+	var calls map[string][]*ToolCall
+
+	var err error
+
+	if agent == nil {
+		return SessionContextTokens(sessionId)
+	}
+
+	full, err = MessageList(sessionId)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	previous, err = SummaryLoad(sessionId)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	history = full
+	if previous != nil {
+		summary = previous.Content
+		history = summaryTail(full, previous.ThroughMessageId)
+	}
+
+	if config.Client.Tools.Enabled {
+		defs = permittedTools(modules.DefaultTools())
+	}
+	if len(defs) > 0 {
+		calls, err = toolCallsBySession(sessionId)
+		if err != nil {
+			return 0, 0, false, err
+		}
+	}
+	if previous != nil {
+		calls = elideCalls(full, calls, previous.ElidedThroughMessageId)
+	}
+
+	return contextEstimate(agent, sessionId, summary, history, calls, systemPrompt(agent, defs, nil), defs, "")
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokensPicksTheEncoding(t *testing.T) {
+	if tokenEncodingName("gpt-4o-mini") != "o200k_base" || tokenEncodingName("openai/gpt-4-turbo") != "cl100k_base" {
+		t.Fatalf("openai families mapped to %q and %q", tokenEncodingName("gpt-4o-mini"), tokenEncodingName("openai/gpt-4-turbo"))
+	}
+	if tokenEncodingName("qwen3-32b") != "" {
+		t.Fatalf("a non-openai model got an encoding %q", tokenEncodingName("qwen3-32b"))
+	}
+
+	if EstimateTokens("gpt-4o", "hello world") != 2 {
+		t.Fatalf("bpe estimate = %d, want 2", EstimateTokens("gpt-4o", "hello world"))
+	}
+	if EstimateTokens("llama", "hello world") != 3 {
+		t.Fatalf("heuristic estimate = %d, want 3", EstimateTokens("llama", "hello world"))
+	}
+	if EstimateTokens("llama", "안녕하세요") != 5 {
+		t.Fatalf("heuristic estimate for hangul = %d, want one per rune", EstimateTokens("llama", "안녕하세요"))
+	}
+}
+
+func TestSessionContextEstimatesBeforeTheFirstResponse(t *testing.T) {
+	var session *Session
+	var agent *NaruAgent
+	var tokens int64
+	var window int64
+	var known bool
+
+	var err error
+
+	session, agent = thinkingSetup(t, "http://127.0.0.1")
+	agent.ContextWindow = 8192
+
+	tokens, window, known, err = SessionContext(agent, session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !known || tokens <= 0 || window != 8192 {
+		t.Fatalf("tokens=%d window=%d known=%v, want an estimate against the configured window", tokens, window, known)
+	}
+}
+
+func TestSessionContextAddsTurnsAfterTheLastReport(t *testing.T) {
+	var session *Session
+	var agent *NaruAgent
+	var tokens int64
+
+	var err error
+
+	session, agent = thinkingSetup(t, "http://127.0.0.1")
+	seedTurns(t, session.Id, 1)
+	seedContextUsage(t, session.Id, 500, 1000)
+
+	tokens, _, _, err = SessionContext(agent, session.Id)
+	if err != nil || tokens != 500 {
+		t.Fatalf("tokens=%d err=%v, want the reported 500", tokens, err)
+	}
+
+	seedTurns(t, session.Id, 1)
+	tokens, _, _, err = SessionContext(agent, session.Id)
+	if err != nil || tokens != 500+2*(estimateMessageTokens+16) {
+		t.Fatalf("tokens=%d err=%v, want the report plus the newer turn", tokens, err)
+	}
+}
+
+func TestSessionContextDropsAMarkThatLeftTheTail(t *testing.T) {
+	var session *Session
+	var agent *NaruAgent
+	var tail []*Message
+	var full int64
+	var tokens int64
+
+	var err error
+
+	session, agent = thinkingSetup(t, "http://127.0.0.1")
+	seedTurns(t, session.Id, 1)
+	seedContextUsage(t, session.Id, 500, 1000)
+
+	tail = []*Message{{Id: "after-undo", SessionId: session.Id, Role: "user", Content: "a newer question"}}
+	full = estimateMessageTokens + EstimateTokens(agent.Model, "prompt") + estimateHistory(agent.Model, tail, nil)
+
+	tokens, _, _, err = contextEstimate(agent, session.Id, "", tail, nil, "prompt", nil, "")
+	if err != nil || tokens != full {
+		t.Fatalf("tokens=%d err=%v, want a full estimate of %d without the stale 500", tokens, err, full)
+	}
+}
+
+func TestCompactionUsesTheConfiguredWindowWithoutUsage(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+
+	var err error
+
+	srv = compactServer(t, &requests)
+	defer srv.Close()
+
+	session, agent = compactSetup(t, srv.URL, true)
+	agent.ContextWindow = 4096
+	seedTurns(t, session.Id, 2)
+
+	_, err = ChatWithTools(context.Background(), session, agent, "hello", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || summaryRow(t, session.Id) != nil {
+		t.Fatalf("request count = %d, want no compaction inside the configured window", len(requests))
+	}
+
+	_, err = MessageSave(session.Id, "user", strings.Repeat("long question ", 1200), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests = nil
+	_, err = ChatWithTools(context.Background(), session, agent, "hello", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || !strings.Contains(requests[0], "turns-to-fold-in") || summaryRow(t, session.Id) == nil {
+		t.Fatalf("request count = %d, want the estimate to trigger a summary", len(requests))
+	}
+}
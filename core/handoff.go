@@ -0,0 +1,327 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"github.com/google/uuid"
+)
+
+type copiedRow struct {
+	id     string
+	parent string
+	result string
+}
+
+const HandoffToolName = "agent_handoff"
+
+var handoffToolInstalled bool
+
+func handoffCheck(session *Session, target *NaruAgent) error {
+	if session == nil {
+		return fmt.Errorf("session is required to hand off")
+	}
+	if target == nil {
+		return fmt.Errorf("agent is required to hand off")
+	}
+	if session.TurnMode != "" {
+		return fmt.Errorf("session %s is a roundtable, seat the agent there instead of handing off", session.Id)
+	}
+	if session.AgentId == target.Id {
+		return fmt.Errorf("session %s already belongs to %s", session.Id, target.Name)
+	}
+
+	return nil
+}
+
+func SessionHandoff(session *Session, target *NaruAgent) (*Session, error) {
+	var moved Session
+	var result sql.Result
+	var affected int64
+
+	var err error
+
+	err = handoffCheck(session, target)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err = util.DB.Exec("UPDATE sessions SET agent_id = ? WHERE id = ? AND turn_mode = '';", target.Id, session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err = result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 0 {
+		return nil, fmt.Errorf("session id %s not found", session.Id)
+	}
+
+	moved = *session
+	moved.AgentId = target.Id
+
+	return &moved, nil
+}
+
+func copiedRows(tx *sql.Tx, query string, args ...any) ([]copiedRow, error) {
+	var rows *sql.Rows
+	var cur copiedRow
+	var copied []copiedRow
+
+	var err error
+
+	rows, err = tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&cur.id, &cur.parent, &cur.result)
+		if err != nil {
+			return nil, err
+		}
+
+		copied = append(copied, cur)
+	}
+
+	return copied, rows.Err()
+}
+
+func summaryCopy(tx *sql.Tx, from, to string, ids map[string]string) error {
+	var content, elided string
+	var through sql.NullString
+
+	var err error
+
+	err = tx.QueryRow("SELECT content, through_message_id, elided_through_message_id FROM session_summaries WHERE session_id = ?;", from).
+		Scan(&content, &through, &elided)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO session_summaries (session_id, content, through_message_id, elided_through_message_id)
+		VALUES (?, ?, ?, ?);`, to, content, sql.NullString{String: ids[through.String], Valid: through.Valid}, ids[elided])
+
+	return err
+}
+
+func sessionCopy(tx *sql.Tx, from, to string) error {
+	var messages []copiedRow
+	var attachments []copiedRow
+	var calls []copiedRow
+	var artifacts []copiedRow
+	var row copiedRow
+	var ids map[string]string
+	var pairs []string
+	var renamed *strings.Replacer
+
+	var err error
+
+	messages, err = copiedRows(tx, "SELECT id, '', '' FROM messages WHERE session_id = ? AND status = ? AND selected = 1 ORDER BY rowid ASC;",
+		from, MessageCompleted)
+	if err != nil {
+		return err
+	}
+	attachments, err = copiedRows(tx, `SELECT a.id, a.message_id, '' FROM attachments a JOIN messages m ON m.id = a.message_id
+		WHERE m.session_id = ? AND m.status = ? AND m.selected = 1 ORDER BY a.rowid ASC;`, from, MessageCompleted)
+	if err != nil {
+		return err
+	}
+	calls, err = copiedRows(tx, `SELECT t.id, t.message_id, t.result FROM tool_calls t JOIN messages m ON m.id = t.message_id
+		WHERE m.session_id = ? AND m.status = ? AND m.selected = 1 ORDER BY t.rowid ASC;`, from, MessageCompleted)
+	if err != nil {
+		return err
+	}
+	artifacts, err = copiedRows(tx, "SELECT id, tool_call_id, '' FROM artifacts WHERE session_id = ? ORDER BY rowid ASC;", from)
+	if err != nil {
+		return err
+	}
+
+	ids = make(map[string]string)
+	for _, row = range messages {
+		ids[row.id] = uuid.NewString()
+		_, err = tx.Exec(`INSERT INTO messages (id, session_id, agent_id, role, content, reasoning, status, error, created_at)
+			SELECT ?, ?, agent_id, role, content, reasoning, status, error, created_at FROM messages WHERE id = ?;`,
+			ids[row.id], to, row.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, row = range attachments {
+		_, err = tx.Exec(`INSERT INTO attachments (id, message_id, name, media_type, content, created_at)
+			SELECT ?, ?, name, media_type, content, created_at FROM attachments WHERE id = ?;`, uuid.NewString(), ids[row.parent], row.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, row = range artifacts {
+		ids[row.id] = uuid.NewString()
+		pairs = append(pairs, row.id, ids[row.id])
+	}
+	renamed = strings.NewReplacer(pairs...)
+
+	for _, row = range calls {
+		ids[row.id] = uuid.NewString()
+		_, err = tx.Exec(`INSERT INTO tool_calls (id, call_id, message_id, agent_id, name, arguments, result, status, error, created_at)
+			SELECT ?, call_id, ?, agent_id, name, arguments, ?, status, error, created_at FROM tool_calls WHERE id = ?;`,
+			ids[row.id], ids[row.parent], renamed.Replace(row.result), row.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, row = range artifacts {
+		if ids[row.parent] == "" {
+			continue
+		}
+
+		_, err = tx.Exec(`INSERT INTO artifacts (id, session_id, tool_call_id, name, content, media_type, data, created_at)
+			SELECT ?, ?, ?, name, content, media_type, data, created_at FROM artifacts WHERE id = ?;`, ids[row.id], to, ids[row.parent], row.id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return summaryCopy(tx, from, to, ids)
+}
+
+func SessionHandoffCopy(session *Session, target *NaruAgent, name string) (*Session, error) {
+	var copied *Session
+	var tx *sql.Tx
+
+	var err error
+
+	err = handoffCheck(session, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = session.Name + " (" + target.Name + ")"
+	}
+
+	copied = NewSession(target, name)
+
+	tx, err = util.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sessionInsert(tx, copied, nil)
+	if err == nil {
+		err = sessionCopy(tx, session.Id, copied.Id)
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
+
+func AgentHandoffTool() modules.Def {
+	return modules.Def{
+		Name: HandoffToolName,
+		Description: "Hand this conversation over to another configured agent. The current reply still comes from you, " +
+			"and from the user's next message on the other agent answers with the whole conversation so far. " +
+			"Use it when the user asks for that agent or the work clearly belongs to it.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"agent": map[string]any{"type": "string"},
+			},
+			"required":             []string{"agent"},
+			"additionalProperties": false,
+		},
+		Permission: modules.PermissionPrivileged,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Agent string `json:"agent"`
+			}
+			var policy subagentPolicy
+			var ok bool
+			var target *NaruAgent
+			var session *Session
+
+			var err error
+
+			if err = ctx.Err(); err != nil {
+				return "", err
+			}
+
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+
+			payload.Agent = strings.TrimSpace(payload.Agent)
+			if payload.Agent == "" {
+				return "", fmt.Errorf("agent is required")
+			}
+
+			policy, ok = subagentPolicyFrom(ctx)
+			if !ok || policy.SessionId == "" {
+				return "", fmt.Errorf("%s needs a stored conversation to hand over", HandoffToolName)
+			}
+			if policy.Depth > 0 {
+				return "", fmt.Errorf("a delegated agent cannot hand off the conversation it was called from")
+			}
+
+			target, err = AgentByName(payload.Agent)
+			if err != nil {
+				return "", err
+			}
+
+			session, err = SessionFind(policy.SessionId)
+			if err != nil {
+				return "", err
+			}
+
+			_, err = SessionHandoff(session, target)
+			if err != nil {
+				return "", err
+			}
+
+			util.Log.Debug("handed a session off", "session", session.Id, "from", policy.CallerId, "to", target.Name)
+
+			return fmt.Sprintf("handed off to %s, who answers from the next message on", target.Name), nil
+		},
+	}
+}
+
+func InstallHandoffTool() {
+	if handoffToolInstalled {
+		return
+	}
+
+	handoffToolInstalled = true
+
+	modules.RegisterBuiltin(AgentHandoffTool, modules.BuiltinHints{
+		Title:       "hand the conversation to another agent",
+		ReadOnly:    false,
+		Destructive: false,
+		OpenWorld:   false,
+	})
+}
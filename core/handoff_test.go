@@ -0,0 +1,231 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+func handoffCall(agent string) string {
+	return `{"role":"assistant","tool_calls":[{"index":0,"id":"h1","type":"function","function":{"name":"agent_handoff","arguments":"{\"agent\":\"` +
+		agent + `\"}"}}]}`
+}
+
+func TestHandoffReplaysTheHistoryUnderTheNewPersona(t *testing.T) {
+	var body string
+	var srv *httptest.Server
+	var session *Session
+	var worker *NaruAgent
+	var moved *Session
+	var found *Session
+
+	var err error
+
+	srv = thinkingServer(t, &body, "reasoning_content")
+
+	session, _, worker = subagentSetup(t, srv.URL)
+	seedTurns(t, session.Id, 1)
+
+	moved, err = SessionHandoff(session, worker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.Id != session.Id || moved.AgentId != worker.Id {
+		t.Fatalf("moved = %+v", moved)
+	}
+
+	found, err = SessionFind(session.Id)
+	if err != nil || found.AgentId != worker.Id {
+		t.Fatalf("stored owner = %+v, %v", found, err)
+	}
+
+	_, err = ChatWithApproval(context.Background(), moved, worker, "carry on", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, `"model":"worker-model"`) || !strings.Contains(body, "you are the worker") {
+		t.Fatalf("the next turn did not use the new agent: %s", body)
+	}
+	if !strings.Contains(body, strings.Repeat("q", 64)) || !strings.Contains(body, strings.Repeat("a", 64)) {
+		t.Fatalf("the earlier turn was not replayed: %s", body)
+	}
+}
+
+func TestHandoffRefusesRoundtablesAndTheCurrentOwner(t *testing.T) {
+	var srv *httptest.Server
+	var session *Session
+	var parent, worker *NaruAgent
+	var table *Session
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	session, parent, worker = subagentSetup(t, srv.URL)
+
+	_, err = SessionHandoff(session, parent)
+	if err == nil || !strings.Contains(err.Error(), "already belongs") {
+		t.Fatalf("self handoff error = %v", err)
+	}
+
+	table, err = RoundtableCreate([]*NaruAgent{parent, worker}, TurnRoundRobin, "table")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SessionHandoffCopy(table, worker, "copy")
+	if err == nil || !strings.Contains(err.Error(), "roundtable") {
+		t.Fatalf("roundtable handoff error = %v", err)
+	}
+}
+
+func TestHandoffCopyLeavesTheOriginalAlone(t *testing.T) {
+	var srv *httptest.Server
+	var session *Session
+	var worker *NaruAgent
+	var callId string
+	var artifactId string
+	var copied *Session
+	var original, history []*Message
+	var calls map[string][]*ToolCall
+	var summary *Summary
+	var read *Artifact
+	var owner *Session
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	session, _, worker = subagentSetup(t, srv.URL)
+	callId = seedToolTurn(t, session.Id, "")
+	artifactId, err = artifactSave(session.Id, callId, "log", "the whole log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = util.DB.Exec("UPDATE tool_calls SET result = ? WHERE id = ?;", "stored as "+artifactId, callId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original, err = MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SummarySave(session.Id, "earlier work", original[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	copied, err = SessionHandoffCopy(session, worker, "copy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied.Id == session.Id || copied.AgentId != worker.Id {
+		t.Fatalf("copied = %+v", copied)
+	}
+
+	history, err = MessageList(copied.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(original) || history[0].Id == original[0].Id || history[1].Content != original[1].Content {
+		t.Fatalf("copied history = %+v", history)
+	}
+
+	calls, err = toolCallsBySession(copied.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls[history[0].Id]) != 1 || strings.Contains(calls[history[0].Id][0].Result, artifactId) {
+		t.Fatalf("copied tool calls = %+v", calls)
+	}
+
+	read, err = ArtifactLoad(copied.Id, strings.TrimPrefix(calls[history[0].Id][0].Result, "stored as "))
+	if err != nil || read.Content != "the whole log" {
+		t.Fatalf("the copied result does not point at the copied artifact: %+v, %v", read, err)
+	}
+
+	summary = summaryRow(t, copied.Id)
+	if summary == nil || summary.Content != "earlier work" || summary.ThroughMessageId != history[0].Id {
+		t.Fatalf("copied summary = %+v", summary)
+	}
+
+	owner, err = SessionFind(session.Id)
+	if err != nil || owner.AgentId == worker.Id {
+		t.Fatalf("the original session changed owner: %+v, %v", owner, err)
+	}
+}
+
+func TestAgentHandoffToolMovesTheSessionAfterThisTurn(t *testing.T) {
+	var srv *httptest.Server
+	var requests []string
+	var session *Session
+	var parent, worker *NaruAgent
+	var instance *Instance
+	var def modules.Def
+	var message *Message
+	var found *Session
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+
+		body, _ = io.ReadAll(r.Body)
+		requests = append(requests, string(body))
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		if len(requests) == 1 {
+			io.WriteString(w, toolChunk("r1", handoffCall("worker"), `"tool_calls"`))
+		} else {
+			io.WriteString(w, toolChunk("r2", `{"role":"assistant","content":"worker takes it from here"}`, `"stop"`))
+		}
+
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	session, parent, worker = subagentSetup(t, srv.URL)
+	instance = &Instance{Agent: parent, locks: newSessionLocks()}
+	for _, def = range modules.DefaultTools() {
+		if def.Name == HandoffToolName {
+			break
+		}
+	}
+
+	message, err = instance.ChatInput(context.Background(), session, "hand me to the worker", nil, []modules.Def{def}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if message.Content != "worker takes it from here" || !strings.Contains(requests[1], `"model":"m"`) {
+		t.Fatalf("the handing-off agent did not finish its reply: %q", message.Content)
+	}
+
+	found, err = SessionFind(session.Id)
+	if err != nil || found.AgentId != worker.Id {
+		t.Fatalf("owner after the tool = %+v, %v", found, err)
+	}
+}
+
+func TestAgentHandoffToolRefusesOutsideAStoredTurn(t *testing.T) {
+	var err error
+
+	_, err = AgentHandoffTool().Execute(context.Background(), `{"agent":"worker"}`)
+	if err == nil || !strings.Contains(err.Error(), "stored conversation") {
+		t.Fatalf("out of turn error = %v", err)
+	}
+
+	_, err = AgentHandoffTool().Execute(subagentContext(context.Background(), subagentPolicy{SessionId: "s", Depth: 1}), `{"agent":"worker"}`)
+	if err == nil || !strings.Contains(err.Error(), "delegated") {
+		t.Fatalf("delegated handoff error = %v", err)
+	}
+}
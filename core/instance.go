@@ -72,8 +72,8 @@ func (s *sessionLocks) release(id string) {
 }
 
 func (i *Instance) Complete(ctx context.Context, messages []openai.ChatCompletionMessageParamUnion,
-	thinking string, onContent, onReasoning func(string)) (*Completion, error) {
-	return Complete(ctx, i.Agent, messages, i.Tools, thinking, onContent, onReasoning)
+	thinking string, sampling Sampling, onContent, onReasoning func(string)) (*Completion, error) {
+	return completeSampled(ctx, i.Agent, messages, i.Tools, thinking, sampling, true, onContent, onReasoning)
 }
 
 func (i *Instance) Chat(ctx context.Context, session *Session, content string,
@@ -97,7 +97,7 @@ func (i *Instance) Chat(ctx context.Context, session *Session, content string,
 	return chatWithToolPolicy(ctx, session, i.Agent, content, nil, i.Tools, config.Client.Thinking.Level, onContent, onReasoning, onTool, nil, false)
 }
 
-func (i *Instance) ChatWithTools(ctx context.Context, session *Session, content string, defs []modules.Def, thinking string,
+func (i *Instance) ChatWithTools(ctx context.Context, session *Session, content string, attachments []*Attachment, defs []modules.Def, thinking string,
 	onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
 	var err error
 
@@ -113,10 +113,10 @@ func (i *Instance) ChatWithTools(ctx context.Context, session *Session, content
 	}
 	defer i.locks.release(session.Id)
 
-	return chatWithToolPolicy(ctx, session, i.Agent, content, nil, defs, thinking, onContent, onReasoning, onTool, approve, false)
+	return chatWithToolPolicy(ctx, session, i.Agent, content, attachments, defs, thinking, onContent, onReasoning, onTool, approve, false)
 }
 
-func (i *Instance) ChatInput(ctx context.Context, session *Session, content string, parts []openai.ChatCompletionContentPartUnionParam,
+func (i *Instance) ChatInput(ctx context.Context, session *Session, content string, attachments []*Attachment,
 	defs []modules.Def, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
 	var err error
 
@@ -132,7 +132,26 @@ func (i *Instance) ChatInput(ctx context.Context, session *Session, content stri
 	}
 	defer i.locks.release(session.Id)
 
-	return chatWithToolPolicy(ctx, session, i.Agent, content, parts, defs, config.Client.Thinking.Level, nil, onReasoning, onTool, approve, false)
+	return chatWithToolPolicy(ctx, session, i.Agent, content, attachments, defs, config.Client.Thinking.Level, nil, onReasoning, onTool, approve, false)
+}
+
+func (i *Instance) ChatRetry(ctx context.Context, session *Session, content string, defs []modules.Def, thinking string,
+	onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
+	var err error
+
+	if session == nil {
+		return nil, fmt.Errorf("session is required to chat")
+	}
+	if session.AgentId != i.Agent.Id {
+		return nil, fmt.Errorf("session %s does not belong to agent %s", session.Id, i.Agent.Name)
+	}
+	err = i.locks.acquire(ctx, session.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer i.locks.release(session.Id)
+
+	return chatRetry(ctx, session, i.Agent, content, defs, thinking, onContent, onReasoning, onTool, approve, false)
 }
 
 func (i *Instance) Session(name string) (*Session, error) {
@@ -156,6 +175,23 @@ func (i *Instance) Bind(origin, externalId, name string) (*Session, error) {
 	return SessionAttach(i.Agent, origin, externalId, name)
 }
 
+func (r *Registry) Roundtable(ctx context.Context, session *Session, content string, attachments []*Attachment,
+	defs []modules.Def, thinking string, onSpeaker SpeakerFunc, onContent, onReasoning func(string), onTool ToolEventFunc,
+	approve ToolApprovalFunc) ([]*Message, error) {
+	var err error
+
+	if session == nil {
+		return nil, fmt.Errorf("session is required to chat")
+	}
+	err = r.locks.acquire(ctx, session.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer r.locks.release(session.Id)
+
+	return roundtableChat(ctx, session, content, attachments, defs, thinking, false, onSpeaker, onContent, onReasoning, onTool, approve)
+}
+
 func NewRegistry() *Registry {
 	var registry Registry
 
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	anthropicpagination "github.com/anthropics/anthropic-sdk-go/packages/pagination"
+	"github.com/openai/openai-go"
+	openaipagination "github.com/openai/openai-go/packages/pagination"
+)
+
+func ProviderModels(ctx context.Context, provider *Provider) ([]string, error) {
+	var claude *anthropicpagination.PageAutoPager[anthropic.ModelInfo]
+	var pager *openaipagination.PageAutoPager[openai.Model]
+	var models []string
+
+	if provider == nil {
+		return nil, fmt.Errorf("provider is required")
+	}
+
+	if provider.ProviderKind() == ProviderAnthropic {
+		claude = newAnthropicClient(provider).Models.ListAutoPaging(ctx, anthropic.ModelListParams{})
+		for claude.Next() {
+			models = append(models, claude.Current().ID)
+		}
+		if claude.Err() != nil {
+			return nil, claude.Err()
+		}
+	} else {
+		pager = newClient(provider).Models.ListAutoPaging(ctx)
+		for pager.Next() {
+			models = append(models, pager.Current().ID)
+		}
+		if pager.Err() != nil {
+			return nil, pager.Err()
+		}
+	}
+
+	sort.Strings(models)
+
+	return models, nil
+}
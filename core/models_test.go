@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestProviderModelsListsTheEndpoint(t *testing.T) {
+	var srv *httptest.Server
+	var models []string
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/models" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","data":[{"id":"zeta","object":"model"},{"id":"alpha","object":"model"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	models, err = ProviderModels(context.Background(), &Provider{Name: "local", BaseURL: srv.URL + "/v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != 2 || models[0] != "alpha" || models[1] != "zeta" {
+		t.Fatalf("models = %v", models)
+	}
+
+	_, err = ProviderModels(context.Background(), &Provider{Name: "missing", BaseURL: srv.URL + "/nope"})
+	if err == nil {
+		t.Fatal("a missing models endpoint listed cleanly")
+	}
+}
@@ -0,0 +1,268 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"go.yaml.in/yaml/v3"
+)
+
+type agentPersona struct {
+	dir string
+
+	role     bool
+	soul     bool
+	model    bool
+	provider bool
+
+	stored NaruAgent
+}
+
+type personaMeta struct {
+	Model    string `yaml:"model"`
+	Provider string `yaml:"provider"`
+}
+
+const (
+	AGENT_DIR = "agents"
+	ROLE_FILE = "ROLE.md"
+	SOUL_FILE = "SOUL.md"
+)
+
+const maxPersonaBytes = 65536
+
+func personaRead(path string) (personaMeta, string, bool, error) {
+	var buf []byte
+	var text string
+	var front string
+	var body string
+	var meta personaMeta
+
+	var err error
+
+	buf, err = os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return meta, "", false, nil
+	}
+	if err != nil {
+		return meta, "", false, err
+	}
+
+	if len(buf) > maxPersonaBytes {
+		return meta, "", false, fmt.Errorf("%s is larger than %d bytes", path, maxPersonaBytes)
+	}
+
+	text = strings.ReplaceAll(strings.TrimPrefix(string(buf), "\uFEFF"), "\r\n", "\n")
+	if !strings.HasPrefix(text, "---\n") {
+		return meta, strings.TrimSpace(text), true, nil
+	}
+
+	front, body, err = modules.SplitFrontmatter(text)
+	if err != nil {
+		return meta, "", false, fmt.Errorf("%s: %w", path, err)
+	}
+
+	err = yaml.Unmarshal([]byte(front), &meta)
+	if err != nil {
+		return meta, "", false, fmt.Errorf("%s: invalid frontmatter: %w", path, err)
+	}
+
+	return meta, body, true, nil
+}
+
+func personaAgent(name string) *NaruAgent {
+	var cur *NaruAgent
+
+	for _, cur = range AgentAll() {
+		if cur.Name == name {
+			return cur
+		}
+	}
+
+	return nil
+}
+
+func personaApply(agent *NaruAgent, dir string, meta personaMeta, role, soul string, hasRole, hasSoul bool) {
+	var persona agentPersona
+	var prov *Provider
+
+	var err error
+
+	persona = agentPersona{dir: dir, stored: *agent}
+
+	if hasRole {
+		agent.Role = role
+		persona.role = true
+	}
+
+	if hasSoul {
+		agent.Soul = soul
+		persona.soul = true
+	}
+
+	if meta.Model != "" {
+		agent.Model = meta.Model
+		persona.model = true
+	}
+
+	if meta.Provider != "" {
+		prov, err = ProviderFind(meta.Provider)
+		if err != nil {
+			util.Log.Warn("persona names an unknown provider, keeping the one in agent.json",
+				"agent", agent.Name, "provider", meta.Provider)
+		} else {
+			agent.ProviderId = prov.Id
+			persona.provider = true
+		}
+	}
+
+	agent.persona = &persona
+}
+
+func personaLoad() (bool, error) {
+	var root string
+	var entries []os.DirEntry
+	var entry os.DirEntry
+	var dir string
+	var roleMeta, soulMeta personaMeta
+	var role, soul string
+	var hasRole, hasSoul bool
+	var agent *NaruAgent
+	var prov *Provider
+	var created bool
+
+	var err error
+
+	root = util.Path(AGENT_DIR)
+	entries, err = os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry = range entries {
+		if !entry.IsDir() || util.SafeSegment(entry.Name()) != nil {
+			continue
+		}
+
+		dir = filepath.Join(root, entry.Name())
+		roleMeta, role, hasRole, err = personaRead(filepath.Join(dir, ROLE_FILE))
+		if err == nil {
+			soulMeta, soul, hasSoul, err = personaRead(filepath.Join(dir, SOUL_FILE))
+		}
+		if err != nil {
+			util.Log.Warn("skipping agent persona", "dir", dir, "error", err)
+			continue
+		}
+
+		if !hasRole && !hasSoul {
+			continue
+		}
+
+		if roleMeta.Model == "" {
+			roleMeta.Model = soulMeta.Model
+		}
+		if roleMeta.Provider == "" {
+			roleMeta.Provider = soulMeta.Provider
+		}
+
+		agent = personaAgent(entry.Name())
+		if agent == nil {
+			if roleMeta.Model == "" {
+				util.Log.Warn("skipping agent persona without a model, set model in its frontmatter", "dir", dir)
+				continue
+			}
+
+			prov = DefaultProvider
+			if roleMeta.Provider != "" {
+				prov, err = ProviderFind(roleMeta.Provider)
+				if err != nil {
+					util.Log.Warn("skipping agent persona with an unknown provider", "dir", dir, "provider", roleMeta.Provider)
+					continue
+				}
+			}
+
+			agent = AgentNew(entry.Name(), "", "", roleMeta.Model, prov)
+			if agent == nil {
+				util.Log.Warn("skipping agent persona, no provider is configured", "dir", dir)
+				continue
+			}
+
+			if Global == nil {
+				Global = agent
+			} else {
+				Agents = append(Agents, agent)
+			}
+			created = true
+		}
+
+		personaApply(agent, dir, roleMeta, role, soul, hasRole, hasSoul)
+	}
+
+	return created, nil
+}
+
+func agentStored(agent *NaruAgent) *NaruAgent {
+	var stored NaruAgent
+
+	if agent == nil || agent.persona == nil {
+		return agent
+	}
+
+	stored = *agent
+	if agent.persona.role {
+		stored.Role = agent.persona.stored.Role
+	}
+	if agent.persona.soul {
+		stored.Soul = agent.persona.stored.Soul
+	}
+	if agent.persona.model {
+		stored.Model = agent.persona.stored.Model
+	}
+	if agent.persona.provider {
+		stored.ProviderId = agent.persona.stored.ProviderId
+	}
+
+	return &stored
+}
+
+func (a *NaruAgent) PersonaDir() string {
+	if a == nil || a.persona == nil {
+		return ""
+	}
+
+	return a.persona.dir
+}
+
+func (a *NaruAgent) PersonaCheck(name, role, soul, model, providerId *string) error {
+	if a == nil || a.persona == nil {
+		return nil
+	}
+
+	if name != nil && *name != a.Name {
+		return fmt.Errorf("agent %s is loaded from %s, rename that directory instead", a.Name, a.persona.dir)
+	}
+	if a.persona.role && role != nil && *role != a.Role {
+		return fmt.Errorf("the role of %s comes from %s, edit that file instead", a.Name, filepath.Join(a.persona.dir, ROLE_FILE))
+	}
+	if a.persona.soul && soul != nil && *soul != a.Soul {
+		return fmt.Errorf("the soul of %s comes from %s, edit that file instead", a.Name, filepath.Join(a.persona.dir, SOUL_FILE))
+	}
+	if a.persona.model && model != nil && *model != a.Model {
+		return fmt.Errorf("the model of %s is set in the frontmatter under %s, edit it there instead", a.Name, a.persona.dir)
+	}
+	if a.persona.provider && providerId != nil && *providerId != a.ProviderId {
+		return fmt.Errorf("the provider of %s is set in the frontmatter under %s, edit it there instead", a.Name, a.persona.dir)
+	}
+
+	return nil
+}
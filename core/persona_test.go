@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/util"
+)
+
+func personaWrite(t *testing.T, name, file, content string) {
+	var dir string
+
+	var err error
+
+	t.Helper()
+
+	dir = filepath.Join(util.Path(AGENT_DIR), name)
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, file), []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPersonaFilesDefineAnAgent(t *testing.T) {
+	var hitA, hitB int
+	var beta *Provider
+	var writer *NaruAgent
+	var id string
+	var buf []byte
+
+	var err error
+
+	_, beta = setup(t, &hitA, &hitB)
+	personaWrite(t, "writer", ROLE_FILE, "---\nmodel: m2\nprovider: beta\n---\n\nYou edit prose.\n")
+	personaWrite(t, "writer", SOUL_FILE, "Dry and brief.\n")
+
+	reload(t)
+	writer, err = AgentByName("writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if writer.Role != "You edit prose." || writer.Soul != "Dry and brief." || writer.Model != "m2" || writer.ProviderId != beta.Id {
+		t.Fatalf("agent = %+v", writer)
+	}
+	id = writer.Id
+
+	buf, err = os.ReadFile(util.Path(AGENT_PATH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(buf), "You edit prose.") {
+		t.Fatal("the persona text was copied into agent.json")
+	}
+
+	reload(t)
+	writer, err = AgentByName("writer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if writer.Id != id {
+		t.Fatal("the agent id changed across reloads, its sessions would be orphaned")
+	}
+}
+
+func TestPersonaFilesOverlayAgentJSON(t *testing.T) {
+	var hitA, hitB int
+	var alpha *Provider
+	var sub *NaruAgent
+	var role string
+	var window int64
+	var buf []byte
+
+	var err error
+
+	alpha, _ = setup(t, &hitA, &hitB)
+	Global = AgentNew("global", "", "", "m", alpha)
+	err = AgentCreate("sub", "json role", "json soul", "m", alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	personaWrite(t, "sub", ROLE_FILE, "file role")
+
+	reload(t)
+	sub, err = AgentByName("sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Role != "file role" || sub.Soul != "json soul" {
+		t.Fatalf("role = %q soul = %q, want the file to override only the role", sub.Role, sub.Soul)
+	}
+
+	role = "changed"
+	err = AgentUpdateFields(sub.Id, nil, &role, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	if err == nil || !strings.Contains(err.Error(), ROLE_FILE) {
+		t.Fatalf("err = %v, want a pointer to the role file", err)
+	}
+
+	window = 4096
+	err = AgentUpdateFields(sub.Id, nil, nil, nil, nil, nil, &window, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err = os.ReadFile(util.Path(AGENT_PATH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(buf), "json role") || strings.Contains(string(buf), "file role") {
+		t.Fatal("saving wrote the file role over the one in agent.json")
+	}
+
+	err = AgentDelete("sub")
+	if err == nil {
+		t.Fatal("an agent that its persona directory would bring back was deleted")
+	}
+}
+
+func TestRegistryReloadRereadsPersonaFiles(t *testing.T) {
+	var hitA, hitB int
+	var alpha *Provider
+	var registry *Registry
+	var found *Instance
+
+	var err error
+
+	alpha, _ = setup(t, &hitA, &hitB)
+	Global = AgentNew("naru", "old role", "", "m", alpha)
+	err = AgentSave()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registry = NewRegistry()
+	err = registry.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	personaWrite(t, "naru", ROLE_FILE, "new role")
+	err = registry.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err = registry.Get("naru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Agent.Role != "new role" {
+		t.Fatalf("role = %q, want the edited file", found.Agent.Role)
+	}
+}
@@ -37,7 +37,7 @@ func TestPrivilegedToolRefusedWithoutInteractiveFrontEnd(t *testing.T) {
 	call.Function.Name = "memory"
 	call.Function.Arguments = `{"action":"list"}`
 
-	record, err = toolCallStart("", call)
+	record, err = toolCallStart("", "", call)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -80,7 +80,7 @@ func TestPrivilegedToolRunsForInteractiveFrontEnd(t *testing.T) {
 	call.Function.Name = "memory"
 	call.Function.Arguments = `{"action":"list"}`
 
-	record, err = toolCallStart("", call)
+	record, err = toolCallStart("", "", call)
 	if err != nil {
 		t.Fatal(err)
 	}
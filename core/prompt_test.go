@@ -40,7 +40,7 @@ func TestSystemPromptPinsRuntimeIdentity(t *testing.T) {
 
 	pinSetup(t)
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru", Soul: "be brief"}, nil)
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru", Soul: "be brief"}, nil, nil)
 
 	if !strings.Contains(prompt, "mininaru v9.9.9-deadbee (branch: release)") {
 		t.Fatalf("system prompt is missing the host line:\n%s", prompt)
@@ -60,7 +60,7 @@ func TestSystemPromptPinsRuntimeIdentity(t *testing.T) {
 func TestSystemPromptIncludesOnlyActiveAgentIdentity(t *testing.T) {
 	var prompt string
 
-	prompt = systemPrompt(&NaruAgent{Id: "agent-123", Name: "naru", Role: "be helpful"}, nil)
+	prompt = systemPrompt(&NaruAgent{Id: "agent-123", Name: "naru", Role: "be helpful"}, nil, nil)
 
 	if !strings.Contains(prompt, agentOpenTag) || !strings.Contains(prompt, "id: agent-123") ||
 		!strings.Contains(prompt, `name: "naru"`) {
@@ -73,7 +73,7 @@ func TestSystemPromptIncludesOnlyActiveAgentIdentity(t *testing.T) {
 		t.Fatal("persona was placed before agent identity")
 	}
 
-	prompt = systemPrompt(nil, nil)
+	prompt = systemPrompt(nil, nil, nil)
 	if strings.Contains(prompt, agentOpenTag) {
 		t.Fatalf("nil agent emitted an identity block:\n%s", prompt)
 	}
@@ -84,7 +84,7 @@ func TestSystemPromptSurvivesEmptyPersona(t *testing.T) {
 
 	pinSetup(t)
 
-	prompt = systemPrompt(&NaruAgent{}, nil)
+	prompt = systemPrompt(&NaruAgent{}, nil, nil)
 	if !strings.Contains(prompt, "mininaru v9.9.9-deadbee") {
 		t.Fatalf("an agent without a persona lost the host line:\n%s", prompt)
 	}
@@ -92,7 +92,7 @@ func TestSystemPromptSurvivesEmptyPersona(t *testing.T) {
 		t.Fatalf("empty persona left a trailing separator: %q", prompt[len(prompt)-8:])
 	}
 
-	prompt = systemPrompt(nil, nil)
+	prompt = systemPrompt(nil, nil, nil)
 	if !strings.Contains(prompt, "mininaru v9.9.9-deadbee") {
 		t.Fatalf("a nil agent lost the host line:\n%s", prompt)
 	}
@@ -137,7 +137,7 @@ func TestSystemPromptAdvertisesSkills(t *testing.T) {
 	pinSetup(t)
 	defs = skillSetup(t)
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, defs)
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, defs, nil)
 
 	if !strings.Contains(prompt, skillOpenTag) || !strings.Contains(prompt, "deploy: how to ship this repository") {
 		t.Fatalf("catalog missing:\n%s", prompt)
@@ -160,12 +160,12 @@ func TestSystemPromptHidesSkillsWithoutTheTool(t *testing.T) {
 	pinSetup(t)
 	skillSetup(t)
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, nil)
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, nil, nil)
 	if strings.Contains(prompt, skillOpenTag) {
 		t.Fatalf("skills were advertised without the tool that loads them:\n%s", prompt)
 	}
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, []modules.Def{modules.CurrentTime()})
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, []modules.Def{modules.CurrentTime()}, nil)
 	if strings.Contains(prompt, skillOpenTag) {
 		t.Fatalf("an unrelated tool set advertised skills:\n%s", prompt)
 	}
@@ -183,7 +183,7 @@ func TestSystemPromptOmitsEmptyCatalog(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, []modules.Def{modules.SkillLoad()})
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, []modules.Def{modules.SkillLoad()}, nil)
 	if strings.Contains(prompt, skillOpenTag) {
 		t.Fatalf("an empty catalog still emitted a block:\n%s", prompt)
 	}
@@ -197,7 +197,7 @@ func TestSystemPromptStatesPrecedence(t *testing.T) {
 
 	pinSetup(t)
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, nil)
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, nil, nil)
 
 	if !strings.Contains(prompt, "outranks") {
 		t.Fatal("the runtime block does not claim precedence over the persona")
@@ -221,12 +221,12 @@ func TestSystemPromptShowsMemoryOnlyWithPrivilegedTool(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, []modules.Def{modules.Memory()})
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, []modules.Def{modules.Memory()}, nil)
 	if !strings.Contains(prompt, memoryOpenTag) || !strings.Contains(prompt, "User prefers concise Korean replies") {
 		t.Fatalf("memory missing for privileged caller:\n%s", prompt)
 	}
 
-	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, modules.SafeTools())
+	prompt = systemPrompt(&NaruAgent{Role: "you are naru"}, modules.SafeTools(), nil)
 	if strings.Contains(prompt, memoryOpenTag) || strings.Contains(prompt, "User prefers concise Korean replies") {
 		t.Fatalf("memory leaked to an unprivileged caller:\n%s", prompt)
 	}
@@ -242,7 +242,7 @@ func TestSystemPromptAdvertisesEmptyMemoryStore(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	prompt = systemPrompt(&NaruAgent{}, []modules.Def{modules.Memory()})
+	prompt = systemPrompt(&NaruAgent{}, []modules.Def{modules.Memory()}, nil)
 	if !strings.Contains(prompt, memoryOpenTag) || !strings.Contains(prompt, "(empty)") ||
 		!strings.Contains(prompt, "Use the memory tool proactively") {
 		t.Fatalf("empty memory capability was not advertised:\n%s", prompt)
@@ -6,6 +6,7 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/url"
 	"os"
 	"strings"
@@ -24,6 +25,9 @@ type Provider struct {
 	Cache            string `json:"cache,omitempty"`
 	ResponseCache    bool   `json:"response_cache,omitempty"`
 	ResponseCacheTTL int    `json:"response_cache_ttl,omitempty"`
+	EmbeddingModel   string `json:"embedding_model,omitempty"`
+
+	apiKeyRef string
 }
 
 type ProviderConfig struct {
@@ -81,7 +85,9 @@ func isOpenRouter(baseURL string) bool {
 
 func applyOpenAICache(params *openai.ChatCompletionNewParams, provider *Provider) {
 	var control map[string]any
+	var extra map[string]any
 	var policy string
+	var ok bool
 
 	if params == nil || provider == nil {
 		return
@@ -98,7 +104,45 @@ func applyOpenAICache(params *openai.ChatCompletionNewParams, provider *Provider
 	if policy == CacheEphemeral1h {
 		control["ttl"] = "1h"
 	}
-	params.SetExtraFields(map[string]any{"cache_control": control})
+	extra = maps.Clone(params.ExtraFields())
+	if extra == nil {
+		extra = map[string]any{}
+	}
+	_, ok = extra["cache_control"]
+	if ok {
+		return
+	}
+	extra["cache_control"] = control
+	params.SetExtraFields(extra)
+}
+
+func (p *Provider) ApiKeyRef() string {
+	return p.apiKeyRef
+}
+
+func providerStored(provider *Provider) *Provider {
+	var stored Provider
+
+	stored = *provider
+	stored.ApiKey = util.SecretStored(provider.ApiKey, provider.apiKeyRef)
+
+	return &stored
+}
+
+func ProviderSecrets() []util.SecretField {
+	var fields []util.SecretField
+	var cur *Provider
+	var field util.SecretField
+	var ok bool
+
+	for _, cur = range Providers {
+		field, ok = util.SecretOf("provider "+cur.Name+" api_key", cur.ApiKey, cur.apiKeyRef)
+		if ok {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
 }
 
 func ProviderValidate(provider Provider) error {
@@ -122,6 +166,9 @@ func ProviderValidate(provider Provider) error {
 	if provider.ResponseCacheTTL < 0 || provider.ResponseCacheTTL > 86400 {
 		return fmt.Errorf("response cache TTL must be 0 or between 1 and 86400 seconds")
 	}
+	if provider.EmbeddingModel != "" && kind != ProviderOpenAI {
+		return fmt.Errorf("embeddings are only supported for openai providers")
+	}
 
 	return nil
 }
@@ -185,6 +232,7 @@ func ProviderInit() error {
 
 	Providers = cfg.Providers
 	for _, provider = range Providers {
+		provider.ApiKey, provider.apiKeyRef = util.SecretLoad("provider "+provider.Name, provider.ApiKey)
 		err = ProviderValidate(*provider)
 		if err != nil {
 			return fmt.Errorf("provider %s: %w", provider.Name, err)
@@ -205,12 +253,16 @@ func ProviderInit() error {
 
 func ProviderSave() error {
 	var cfg ProviderConfig
+	var cur *Provider
 	var path string
 	var buf []byte
 
 	var err error
 
-	cfg = ProviderConfig{Providers: Providers}
+	cfg = ProviderConfig{Providers: []*Provider{}}
+	for _, cur = range Providers {
+		cfg.Providers = append(cfg.Providers, providerStored(cur))
+	}
 	if DefaultProvider != nil {
 		cfg.DefaultId = DefaultProvider.Id
 	}
@@ -231,6 +283,7 @@ func ProviderSave() error {
 
 func ProviderCreate(payload Provider) {
 	payload.Id = uuid.NewString()
+	payload.ApiKey, payload.apiKeyRef = util.SecretLoad("provider "+payload.Name, payload.ApiKey)
 	Providers = append(Providers, &payload)
 
 	if DefaultProvider == nil {
@@ -253,7 +306,7 @@ func ProviderDefault(ref string) error {
 	return ProviderSave()
 }
 
-func ProviderUpdateConfig(id string, name, apiKey, baseURL, kind, cache *string, responseCache *bool, responseCacheTTL *int) error {
+func ProviderUpdateConfig(id string, name, apiKey, baseURL, kind, cache *string, responseCache *bool, responseCacheTTL *int, embeddingModel *string) error {
 	var index int
 	var current *Provider
 	var update Provider
@@ -269,7 +322,7 @@ func ProviderUpdateConfig(id string, name, apiKey, baseURL, kind, cache *string,
 			update.Name = *name
 		}
 		if apiKey != nil {
-			update.ApiKey = *apiKey
+			update.ApiKey, update.apiKeyRef = util.SecretLoad("provider "+update.Name, *apiKey)
 		}
 		if baseURL != nil {
 			update.BaseURL = *baseURL
@@ -286,6 +339,9 @@ func ProviderUpdateConfig(id string, name, apiKey, baseURL, kind, cache *string,
 		if responseCacheTTL != nil {
 			update.ResponseCacheTTL = *responseCacheTTL
 		}
+		if embeddingModel != nil {
+			update.EmbeddingModel = *embeddingModel
+		}
 		err = ProviderValidate(update)
 		if err != nil {
 			return err
@@ -301,11 +357,11 @@ func ProviderUpdateConfig(id string, name, apiKey, baseURL, kind, cache *string,
 }
 
 func ProviderUpdateOptions(id string, kind, cache *string, responseCache *bool, responseCacheTTL *int) error {
-	return ProviderUpdateConfig(id, nil, nil, nil, kind, cache, responseCache, responseCacheTTL)
+	return ProviderUpdateConfig(id, nil, nil, nil, kind, cache, responseCache, responseCacheTTL, nil)
 }
 
 func ProviderUpdateFields(id string, name, apiKey, baseURL *string) error {
-	return ProviderUpdateConfig(id, name, apiKey, baseURL, nil, nil, nil, nil)
+	return ProviderUpdateConfig(id, name, apiKey, baseURL, nil, nil, nil, nil, nil)
 }
 
 func ProviderUpdate(id string, payload Provider) error {
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/devproje/mininaru/util"
+)
+
+type Rating struct {
+	MessageId string `json:"message_id"`
+	Rating    string `json:"rating"`
+	Note      string `json:"note,omitempty"`
+}
+
+const (
+	RatingGood = "good"
+	RatingBad  = "bad"
+)
+
+const ratingNoteMax = 4096
+
+func RatingValid(rating string) bool {
+	return rating == RatingGood || rating == RatingBad
+}
+
+func MessageRate(messageId, rating, note string) error {
+	var role, status string
+
+	var err error
+
+	if rating != "" && !RatingValid(rating) {
+		return fmt.Errorf("rating must be %s or %s, got %q", RatingGood, RatingBad, rating)
+	}
+	if len(note) > ratingNoteMax {
+		return fmt.Errorf("rating note is longer than %d bytes", ratingNoteMax)
+	}
+
+	err = util.DB.QueryRow("SELECT role, status FROM messages WHERE id = ?;", messageId).Scan(&role, &status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("message id %s not found", messageId)
+		}
+
+		return err
+	}
+	if role != "assistant" || status != MessageCompleted {
+		return fmt.Errorf("only a completed assistant reply can be rated")
+	}
+
+	if rating == "" {
+		_, err = util.DB.Exec("DELETE FROM message_ratings WHERE message_id = ?;", messageId)
+		return err
+	}
+
+	_, err = util.DB.Exec(`INSERT INTO message_ratings (message_id, rating, note) VALUES (?, ?, ?)
+		ON CONFLICT(message_id) DO UPDATE SET rating = excluded.rating, note = excluded.note, created_at = CURRENT_TIMESTAMP;`,
+		messageId, rating, note)
+
+	return err
+}
+
+func MessageRating(messageId string) (*Rating, error) {
+	var rating Rating
+
+	var err error
+
+	err = util.DB.QueryRow("SELECT message_id, rating, note FROM message_ratings WHERE message_id = ?;", messageId).
+		Scan(&rating.MessageId, &rating.Rating, &rating.Note)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &rating, nil
+}
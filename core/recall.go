@@ -0,0 +1,322 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+type knowledgeChunk struct {
+	Id      string
+	Path    string
+	Content string
+}
+
+type recall struct {
+	Memory    []string
+	Knowledge []knowledgeChunk
+}
+
+type knowledgeFile struct {
+	modTime time.Time
+	size    int64
+	chunks  []knowledgeChunk
+}
+
+type knowledgeCache struct {
+	mu         sync.Mutex
+	files      map[string]knowledgeFile
+	generation int
+	provider   string
+	model      string
+	vectors    [][]float32
+}
+
+const KNOWLEDGE_DIR = "knowledge"
+
+const knowledgeChunkChars = 1200
+
+const maxKnowledgeFile = 1 << 20
+
+var knowledgeIndex knowledgeCache
+
+func knowledgeSplit(text string) []string {
+	var chunks []string
+	var current strings.Builder
+	var paragraph string
+	var runes []rune
+
+	for _, paragraph = range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+
+		if current.Len() > 0 && current.Len()+len(paragraph)+2 > knowledgeChunkChars {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+
+		runes = []rune(paragraph)
+		for len(runes) > knowledgeChunkChars {
+			chunks = append(chunks, string(runes[:knowledgeChunkChars]))
+			runes = runes[knowledgeChunkChars:]
+		}
+		paragraph = string(runes)
+
+		if current.Len() > 0 {
+			current.WriteString("\n\n")
+		}
+		current.WriteString(paragraph)
+	}
+
+	if current.Len() > 0 {
+		chunks = append(chunks, current.String())
+	}
+
+	return chunks
+}
+
+func (c *knowledgeCache) scan(root string) ([]knowledgeChunk, bool, error) {
+	var files map[string]knowledgeFile
+	var chunks []knowledgeChunk
+	var changed bool
+
+	var err error
+
+	files = make(map[string]knowledgeFile)
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, walkErr error) error {
+		var info fs.FileInfo
+		var rel string
+		var buf []byte
+		var piece string
+		var index int
+		var file knowledgeFile
+		var found bool
+
+		var err error
+
+		if walkErr != nil {
+			if os.IsNotExist(walkErr) && path == root {
+				return filepath.SkipDir
+			}
+
+			return walkErr
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".md", ".markdown", ".txt":
+		default:
+			return nil
+		}
+
+		info, err = entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxKnowledgeFile {
+			util.Log.Warn("skipping an oversized knowledge file", "path", path, "size", info.Size())
+			return nil
+		}
+
+		file, found = c.files[path]
+		if found && file.modTime.Equal(info.ModTime()) && file.size == info.Size() {
+			files[path] = file
+			chunks = append(chunks, file.chunks...)
+			return nil
+		}
+
+		rel, err = filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		buf, err = os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		file = knowledgeFile{modTime: info.ModTime(), size: info.Size()}
+		for index, piece = range knowledgeSplit(string(buf)) {
+			file.chunks = append(file.chunks, knowledgeChunk{Id: fmt.Sprintf("%s#%d", filepath.ToSlash(rel), index),
+				Path: filepath.ToSlash(rel), Content: piece})
+		}
+		files[path] = file
+		chunks = append(chunks, file.chunks...)
+		changed = true
+
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	changed = changed || len(files) != len(c.files)
+	c.files = files
+
+	return chunks, changed, nil
+}
+
+func recallMemory(ctx context.Context, prov *Provider, query func() ([]float32, error), limit int) ([]string, error) {
+	var entries []modules.MemoryEntry
+	var items []embeddingItem
+	var vectors [][]float32
+	var vector []float32
+	var selected []string
+	var index int
+
+	var err error
+
+	entries, err = modules.MemoryEntries()
+	if err != nil || len(entries) <= limit {
+		return nil, err
+	}
+
+	items = make([]embeddingItem, len(entries))
+	for index = range entries {
+		items[index] = embeddingItem{Id: entries[index].Id, Content: entries[index].Content}
+	}
+
+	vectors, err = embeddingVectors(ctx, prov, EmbeddingMemory, items)
+	if err != nil {
+		return nil, err
+	}
+	vector, err = query()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index = range embeddingRank(vector, vectors, limit) {
+		selected = append(selected, entries[index].Content)
+	}
+
+	return selected, nil
+}
+
+func recallKnowledge(ctx context.Context, prov *Provider, query func() ([]float32, error), limit int) ([]knowledgeChunk, error) {
+	var chunks []knowledgeChunk
+	var changed bool
+	var generation int
+	var items []embeddingItem
+	var vectors [][]float32
+	var vector []float32
+	var selected []knowledgeChunk
+	var index int
+
+	var err error
+
+	knowledgeIndex.mu.Lock()
+	chunks, changed, err = knowledgeIndex.scan(util.Path(KNOWLEDGE_DIR))
+	if changed {
+		knowledgeIndex.generation++
+		knowledgeIndex.vectors = nil
+	}
+	if knowledgeIndex.provider == prov.Id && knowledgeIndex.model == prov.EmbeddingModel {
+		vectors = knowledgeIndex.vectors
+	}
+	generation = knowledgeIndex.generation
+	knowledgeIndex.mu.Unlock()
+	if err != nil || len(chunks) <= limit {
+		return chunks, err
+	}
+
+	if vectors == nil {
+		items = make([]embeddingItem, len(chunks))
+		for index = range chunks {
+			items[index] = embeddingItem{Id: chunks[index].Id, Content: chunks[index].Content}
+		}
+
+		vectors, err = embeddingVectors(ctx, prov, EmbeddingKnowledge, items)
+		if err != nil {
+			return nil, err
+		}
+
+		knowledgeIndex.mu.Lock()
+		if knowledgeIndex.generation == generation {
+			knowledgeIndex.provider = prov.Id
+			knowledgeIndex.model = prov.EmbeddingModel
+			knowledgeIndex.vectors = vectors
+		}
+		knowledgeIndex.mu.Unlock()
+	}
+
+	vector, err = query()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index = range embeddingRank(vector, vectors, limit) {
+		selected = append(selected, chunks[index])
+	}
+
+	return selected, nil
+}
+
+func recallFor(ctx context.Context, agent *NaruAgent, defs []modules.Def, content string) *recall {
+	var prov *Provider
+	var limit int
+	var cached []float32
+	var query func() ([]float32, error)
+	var found recall
+
+	var err error
+
+	if agent == nil || strings.TrimSpace(content) == "" {
+		return nil
+	}
+	limit = config.Client.Retrieval.TopK
+	prov = agentProvider(agent)
+	if limit <= 0 || prov == nil || prov.EmbeddingModel == "" {
+		return nil
+	}
+
+	query = func() ([]float32, error) {
+		var vectors [][]float32
+
+		var err error
+
+		if cached != nil {
+			return cached, nil
+		}
+
+		vectors, err = prov.Embed(ctx, []string{content})
+		if err != nil {
+			return nil, err
+		}
+		cached = vectors[0]
+
+		return cached, nil
+	}
+
+	if findTool(defs, modules.MemoryToolName) != nil {
+		found.Memory, err = recallMemory(ctx, prov, query, limit)
+		if err != nil {
+			util.Log.Warn("recalling memories failed, sending every memory", "agent", agent.Id, "error", err)
+		}
+	}
+
+	found.Knowledge, err = recallKnowledge(ctx, prov, query, limit)
+	if err != nil {
+		util.Log.Warn("recalling knowledge failed", "agent", agent.Id, "error", err)
+	}
+
+	return &found
+}
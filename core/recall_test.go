@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"github.com/openai/openai-go"
+)
+
+func embeddingVector(text string) []float64 {
+	var vector []float64
+	var index int
+	var words []string
+	var word string
+
+	vector = make([]float64, 3)
+	for index, words = range [][]string{{"coffee", "drink", "espresso"}, {"go", "golang", "compiler"}, {"cat", "pet", "kitten"}} {
+		for _, word = range words {
+			if strings.Contains(strings.ToLower(text), word) {
+				vector[index]++
+			}
+		}
+	}
+
+	return vector
+}
+
+func embeddingServer(t *testing.T, inputs *[]string) *httptest.Server {
+	var srv *httptest.Server
+
+	t.Helper()
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		var data []map[string]any
+		var index int
+
+		if r.URL.Path != "/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Model != "embed" {
+			http.Error(w, "unknown model", http.StatusBadRequest)
+			return
+		}
+		*inputs = append(*inputs, payload.Input...)
+
+		for index = len(payload.Input) - 1; index >= 0; index-- {
+			data = append(data, map[string]any{"object": "embedding", "index": index, "embedding": embeddingVector(payload.Input[index])})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"object": "list", "model": payload.Model, "data": data,
+			"usage": map[string]any{"prompt_tokens": 1, "total_tokens": 1}})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func recallSetup(t *testing.T, inputs *[]string, model string) *NaruAgent {
+	var agent *NaruAgent
+	var retrieval config.Retrieval
+
+	t.Helper()
+
+	_, agent = thinkingSetup(t, embeddingServer(t, inputs).URL)
+	Providers[0].EmbeddingModel = model
+
+	retrieval = config.Client.Retrieval
+	t.Cleanup(func() { config.Client.Retrieval = retrieval })
+	config.Client.Retrieval = config.Retrieval{TopK: 1}
+
+	return agent
+}
+
+func addMemory(t *testing.T, content string) {
+	var err error
+
+	t.Helper()
+
+	_, err = modules.Memory().Execute(context.Background(), fmt.Sprintf(`{"action":"add","content":%q}`, content))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEmbedKeepsResponseOrder(t *testing.T) {
+	var inputs []string
+	var vectors [][]float32
+
+	var err error
+
+	recallSetup(t, &inputs, "embed")
+
+	vectors, err = Providers[0].Embed(context.Background(), []string{"espresso", "kitten"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) != 2 || vectors[0][0] != 1 || vectors[1][2] != 1 {
+		t.Fatalf("vectors = %v, want them in input order", vectors)
+	}
+}
+
+func TestRecallPicksTheClosestMemory(t *testing.T) {
+	var inputs []string
+	var agent *NaruAgent
+	var defs []modules.Def
+	var prompt string
+
+	agent = recallSetup(t, &inputs, "embed")
+	defs = []modules.Def{modules.Memory()}
+	addMemory(t, "User drinks espresso every morning")
+	addMemory(t, "User writes golang at work")
+	addMemory(t, "User has a kitten named Mochi")
+
+	prompt = systemPrompt(agent, defs, recallFor(context.Background(), agent, defs, "what should my pet eat"))
+	if !strings.Contains(prompt, "kitten named Mochi") || strings.Contains(prompt, "espresso") || strings.Contains(prompt, "golang") {
+		t.Fatalf("memory block did not narrow to the closest entry:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, memoryRecallRules) {
+		t.Fatalf("ranked memory block is missing its rules:\n%s", prompt)
+	}
+
+	inputs = nil
+	prompt = systemPrompt(agent, defs, recallFor(context.Background(), agent, defs, "a good drink"))
+	if !strings.Contains(prompt, "espresso") || len(inputs) != 1 {
+		t.Fatalf("second turn embedded %v, want only the query; prompt:\n%s", inputs, prompt)
+	}
+}
+
+func TestRecallWithoutEmbeddingModelSendsEverything(t *testing.T) {
+	var inputs []string
+	var agent *NaruAgent
+	var defs []modules.Def
+	var prompt string
+
+	agent = recallSetup(t, &inputs, "")
+	defs = []modules.Def{modules.Memory()}
+	addMemory(t, "User drinks espresso every morning")
+	addMemory(t, "User has a kitten named Mochi")
+
+	if recallFor(context.Background(), agent, defs, "what should my pet eat") != nil {
+		t.Fatal("recall ran without an embedding model")
+	}
+	prompt = systemPrompt(agent, defs, nil)
+	if !strings.Contains(prompt, "espresso") || !strings.Contains(prompt, "Mochi") || len(inputs) != 0 {
+		t.Fatalf("fallback prompt lost memories or called the embedder %v:\n%s", inputs, prompt)
+	}
+}
+
+func TestRecallKnowledgeChunks(t *testing.T) {
+	var inputs []string
+	var agent *NaruAgent
+	var defs []modules.Def
+	var root string
+	var prompt string
+	var recalled *recall
+
+	var err error
+
+	agent = recallSetup(t, &inputs, "embed")
+	defs = []modules.Def{modules.Memory()}
+	root = util.Path(KNOWLEDGE_DIR)
+	err = os.MkdirAll(filepath.Join(root, "notes"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(root, "notes", "build.md"), []byte("The compiler flags live in the Makefile."), 0600)
+	os.WriteFile(filepath.Join(root, "cafe.txt"), []byte("The office espresso machine is on floor two."), 0600)
+	os.WriteFile(filepath.Join(root, "image.png"), []byte("kitten"), 0600)
+
+	prompt = systemPrompt(agent, defs, recallFor(context.Background(), agent, defs, "which golang compiler flags"))
+	if !strings.Contains(prompt, "[notes/build.md]\nThe compiler flags") || strings.Contains(prompt, "espresso") {
+		t.Fatalf("knowledge block did not pick the build note:\n%s", prompt)
+	}
+	if !strings.Contains(prompt, knowledgeOpenTag) {
+		t.Fatalf("knowledge prompt has no knowledge block:\n%s", prompt)
+	}
+
+	recalled = recallFor(context.Background(), agent, nil, "which golang compiler flags")
+	if recalled == nil || len(recalled.Knowledge) != 1 || recalled.Knowledge[0].Path != "notes/build.md" || recalled.Memory != nil {
+		t.Fatalf("recall without the memory tool = %+v, want knowledge only", recalled)
+	}
+}
+
+func TestRecallKnowledgeEmbedsOutsideTheIndexLock(t *testing.T) {
+	var inputs []string
+	var backend *httptest.Server
+	var target *url.URL
+	var proxy *httputil.ReverseProxy
+	var front *httptest.Server
+	var held atomic.Bool
+	var agent *NaruAgent
+	var root string
+
+	var err error
+
+	agent = recallSetup(t, &inputs, "embed")
+	backend = embeddingServer(t, &inputs)
+	target, err = url.Parse(backend.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy = httputil.NewSingleHostReverseProxy(target)
+	front = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !knowledgeIndex.mu.TryLock() {
+			held.Store(true)
+		} else {
+			knowledgeIndex.mu.Unlock()
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(front.Close)
+	Providers[0].BaseURL = front.URL
+
+	root = util.Path(KNOWLEDGE_DIR)
+	err = os.MkdirAll(root, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(root, "build.md"), []byte("The compiler flags live in the Makefile."), 0600)
+	os.WriteFile(filepath.Join(root, "cafe.txt"), []byte("The office espresso machine is on floor two."), 0600)
+
+	recallFor(context.Background(), agent, nil, "which golang compiler flags")
+	if len(inputs) == 0 {
+		t.Fatal("knowledge was not embedded")
+	}
+	if held.Load() {
+		t.Fatal("the knowledge index was locked while embedding")
+	}
+	if knowledgeIndex.provider != Providers[0].Id || knowledgeIndex.model != "embed" || knowledgeIndex.vectors == nil {
+		t.Fatalf("index cached for %q/%q, want %q/embed", knowledgeIndex.provider, knowledgeIndex.model, Providers[0].Id)
+	}
+
+	ProviderCreate(Provider{Name: "second", BaseURL: front.URL, ApiKey: "k", EmbeddingModel: "embed"})
+	agent.ProviderId = Providers[1].Id
+	recallFor(context.Background(), agent, nil, "which golang compiler flags")
+	if knowledgeIndex.provider != Providers[1].Id {
+		t.Fatalf("index cached for %q after switching provider, want %q", knowledgeIndex.provider, Providers[1].Id)
+	}
+}
+
+func TestKnowledgeScanRereadsOnlyChangedFiles(t *testing.T) {
+	var cache knowledgeCache
+	var root string
+	var chunks []knowledgeChunk
+	var changed bool
+	var later time.Time
+
+	var err error
+
+	root = t.TempDir()
+	os.WriteFile(filepath.Join(root, "a.md"), []byte("alpha"), 0600)
+	os.WriteFile(filepath.Join(root, "b.md"), []byte("beta"), 0600)
+
+	chunks, changed, err = cache.scan(root)
+	if err != nil || !changed || len(chunks) != 2 {
+		t.Fatalf("first scan = %v, %v, %v", chunks, changed, err)
+	}
+	_, changed, err = cache.scan(root)
+	if err != nil || changed {
+		t.Fatalf("unchanged scan = %v, %v", changed, err)
+	}
+
+	os.WriteFile(filepath.Join(root, "a.md"), []byte("alpha, revised"), 0600)
+	later = time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(root, "a.md"), later, later)
+	chunks, changed, err = cache.scan(root)
+	if err != nil || !changed || chunks[0].Content != "alpha, revised" {
+		t.Fatalf("edited scan = %v, %v, %v", chunks, changed, err)
+	}
+
+	os.Remove(filepath.Join(root, "b.md"))
+	chunks, changed, err = cache.scan(root)
+	if err != nil || !changed || len(chunks) != 1 {
+		t.Fatalf("scan after a removal = %v, %v, %v", chunks, changed, err)
+	}
+}
+
+func TestInternalCompletionsSkipRecall(t *testing.T) {
+	var inputs []string
+	var agent *NaruAgent
+	var defs []modules.Def
+
+	agent = recallSetup(t, &inputs, "embed")
+	defs = []modules.Def{modules.Memory()}
+	addMemory(t, "User drinks espresso every morning")
+	addMemory(t, "User has a kitten named Mochi")
+
+	Complete(context.Background(), agent, []openai.ChatCompletionMessageParamUnion{openai.UserMessage("summarize the kitten talk")},
+		defs, config.ThinkingOff, nil, nil)
+	if len(inputs) != 0 {
+		t.Fatalf("an internal completion embedded %v", inputs)
+	}
+
+	CompleteTurn(context.Background(), agent, []openai.ChatCompletionMessageParamUnion{openai.UserMessage("what does my pet eat")},
+		defs, config.ThinkingOff, nil, nil)
+	if len(inputs) == 0 {
+		t.Fatal("a user turn did not recall")
+	}
+}
+
+func TestKnowledgeSplitKeepsChunksBounded(t *testing.T) {
+	var chunks []string
+	var chunk string
+
+	chunks = knowledgeSplit("first paragraph\n\n" + strings.Repeat("x", knowledgeChunkChars*2+10) + "\n\nlast")
+	if len(chunks) != 4 || chunks[0] != "first paragraph" || chunks[3] != strings.Repeat("x", 10)+"\n\nlast" {
+		t.Fatalf("chunks = %q", chunks)
+	}
+	for _, chunk = range chunks {
+		if len([]rune(chunk)) > knowledgeChunkChars {
+			t.Fatalf("chunk of %d runes exceeds the limit", len([]rune(chunk)))
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/util"
+)
+
+type RetentionResult struct {
+	Sessions  int64
+	Usage     int64
+	SkillUses int64
+}
+
+const sessionActivity = `COALESCE((SELECT MAX(m.created_at) FROM messages m WHERE m.session_id = s.id), s.created_at)`
+
+func retentionDelete(tx *sql.Tx, query string, args ...any) (int64, error) {
+	var result sql.Result
+
+	var err error
+
+	result, err = tx.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+func RetentionApply(policy config.Retention) (*RetentionResult, error) {
+	var tx *sql.Tx
+	var result RetentionResult
+	var affected int64
+
+	var err error
+
+	tx, err = util.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if policy.SessionDays > 0 {
+		affected, err = retentionDelete(tx, `DELETE FROM sessions WHERE id IN (
+			SELECT s.id FROM sessions s WHERE s.pinned = 0 AND `+sessionActivity+` < datetime('now', ?));`,
+			fmt.Sprintf("-%d days", policy.SessionDays))
+		if err != nil {
+			return nil, err
+		}
+		result.Sessions += affected
+	}
+
+	if policy.SessionsPerAgent > 0 {
+		affected, err = retentionDelete(tx, `DELETE FROM sessions WHERE id IN (
+			SELECT id FROM (
+				SELECT s.id, ROW_NUMBER() OVER (PARTITION BY s.agent_id ORDER BY `+sessionActivity+` DESC, s.rowid DESC) AS rank
+				FROM sessions s WHERE s.pinned = 0
+			) WHERE rank > ?);`, policy.SessionsPerAgent)
+		if err != nil {
+			return nil, err
+		}
+		result.Sessions += affected
+	}
+
+	if policy.UsageDays > 0 {
+		result.Usage, err = retentionDelete(tx, "DELETE FROM token_usage WHERE created_at < datetime('now', ?);",
+			fmt.Sprintf("-%d days", policy.UsageDays))
+		if err != nil {
+			return nil, err
+		}
+
+		result.SkillUses, err = retentionDelete(tx, "DELETE FROM skill_uses WHERE created_at < datetime('now', ?);",
+			fmt.Sprintf("-%d days", policy.UsageDays))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if result.Sessions > 0 {
+		checkpointsPrune()
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,148 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/util"
+)
+
+func retentionSeed(t *testing.T) {
+	var statement string
+
+	var err error
+
+	t.Helper()
+
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "retention.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { util.DB.Close() })
+
+	for _, statement = range []string{
+		"INSERT INTO sessions (id, agent_id, name, created_at) VALUES ('old', 'a', 'old', datetime('now', '-40 days'));",
+		"INSERT INTO sessions (id, agent_id, name, created_at) VALUES ('revived', 'a', 'revived', datetime('now', '-40 days'));",
+		"INSERT INTO sessions (id, agent_id, name, created_at) VALUES ('recent', 'a', 'recent', datetime('now', '-2 days'));",
+		"INSERT INTO sessions (id, agent_id, name, created_at) VALUES ('other', 'b', 'other', datetime('now', '-1 days'));",
+		"INSERT INTO messages (id, session_id, role, content, created_at) VALUES ('m1', 'revived', 'user', 'hi', datetime('now', '-1 hours'));",
+		"INSERT INTO token_usage (id, session_id, kind, created_at) VALUES ('u1', 'recent', 'chat', datetime('now', '-100 days'));",
+		"INSERT INTO token_usage (id, session_id, kind) VALUES ('u2', 'recent', 'chat');",
+		"INSERT INTO skill_uses (id, skill, created_at) VALUES ('k1', 'notes', datetime('now', '-100 days'));",
+	} {
+		_, err = util.DB.Exec(statement)
+		if err != nil {
+			t.Fatalf("%s: %v", statement, err)
+		}
+	}
+}
+
+func retentionSessions(t *testing.T) int {
+	var count int
+
+	var err error
+
+	t.Helper()
+
+	err = util.DB.QueryRow("SELECT COUNT(*) FROM sessions;").Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return count
+}
+
+func TestRetentionDropsSessionsByLastActivity(t *testing.T) {
+	var result *RetentionResult
+
+	var err error
+
+	retentionSeed(t)
+
+	result, err = RetentionApply(config.Retention{SessionDays: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sessions != 1 || retentionSessions(t) != 3 {
+		t.Fatalf("result = %+v, sessions left = %d, want only the idle one gone", result, retentionSessions(t))
+	}
+}
+
+func TestRetentionSparesPinnedSessions(t *testing.T) {
+	var result *RetentionResult
+
+	var err error
+
+	retentionSeed(t)
+
+	err = SessionPin("old", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = RetentionApply(config.Retention{SessionDays: 30, SessionsPerAgent: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sessions != 1 || retentionSessions(t) != 3 {
+		t.Fatalf("result = %+v, sessions left = %d, want only recent gone", result, retentionSessions(t))
+	}
+
+	_, err = SessionFind("old")
+	if err != nil {
+		t.Fatalf("the pinned session was pruned: %v", err)
+	}
+}
+
+func TestRetentionKeepsTheNewestSessionsPerAgent(t *testing.T) {
+	var result *RetentionResult
+	var kept string
+
+	var err error
+
+	retentionSeed(t)
+
+	result, err = RetentionApply(config.Retention{SessionsPerAgent: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sessions != 2 || retentionSessions(t) != 2 {
+		t.Fatalf("result = %+v, sessions left = %d", result, retentionSessions(t))
+	}
+
+	err = util.DB.QueryRow("SELECT id FROM sessions WHERE agent_id = 'a';").Scan(&kept)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept != "revived" {
+		t.Fatalf("kept %s, want the session with the latest message", kept)
+	}
+}
+
+func TestRetentionPrunesUsage(t *testing.T) {
+	var result *RetentionResult
+
+	var err error
+
+	retentionSeed(t)
+
+	result, err = RetentionApply(config.Retention{UsageDays: 90})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Usage != 1 || result.SkillUses != 1 || result.Sessions != 0 {
+		t.Fatalf("result = %+v", result)
+	}
+
+	result, err = RetentionApply(config.Retention{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (RetentionResult{}) || retentionSessions(t) != 4 {
+		t.Fatalf("an empty policy deleted something: %+v", result)
+	}
+}
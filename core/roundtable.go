@@ -0,0 +1,673 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"github.com/openai/openai-go"
+)
+
+type SpeakerFunc func(agent *NaruAgent)
+
+type roundtableRun struct {
+	Session        *Session
+	Seats          []*NaruAgent
+	History        []*Message
+	Calls          map[string][]*ToolCall
+	Summary        string
+	Content        string
+	Attachments    []*Attachment
+	Defs           []modules.Def
+	Thinking       string
+	AllowDangerous bool
+
+	OnSpeaker   SpeakerFunc
+	OnContent   func(string)
+	OnReasoning func(string)
+	OnTool      ToolEventFunc
+	Approve     ToolApprovalFunc
+
+	pendingId string
+	spoken    []*Message
+}
+
+type seatMention struct {
+	seat  *NaruAgent
+	index int
+}
+
+const (
+	TurnRoundRobin = "round-robin"
+	TurnModerator  = "moderator"
+	TurnAddressed  = "addressed"
+)
+
+const maxRoundtableSeats = 8
+
+const moderatorTranscriptMessages = 8
+
+const moderatorInstruction = `You moderate a group conversation between one person and the agents listed
+below. Decide which agents should answer the person's latest message.
+
+Reply with the chosen names only, separated by commas, the most relevant first.
+Pick one agent unless the message clearly needs several voices.`
+
+func TurnModes() []string {
+	return []string{TurnRoundRobin, TurnModerator, TurnAddressed}
+}
+
+func TurnModeValid(mode string) bool {
+	return slices.Contains(TurnModes(), mode)
+}
+
+func agentById(id string) *NaruAgent {
+	var cur *NaruAgent
+
+	for _, cur = range AgentAll() {
+		if cur.Id != id {
+			continue
+		}
+
+		return cur
+	}
+
+	return nil
+}
+
+func roundtableSeatsSave(tx *sql.Tx, sessionId string, seats []*NaruAgent) error {
+	var index int
+
+	var err error
+
+	for index = range seats {
+		_, err = tx.Exec("INSERT INTO session_agents (session_id, agent_id, seat) VALUES (?, ?, ?);",
+			sessionId, seats[index].Id, index)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func RoundtableCheck(seats []*NaruAgent, mode string) error {
+	var seen map[string]bool
+	var seat *NaruAgent
+
+	if !TurnModeValid(mode) {
+		return fmt.Errorf("turn mode must be one of %s", strings.Join(TurnModes(), ", "))
+	}
+	if len(seats) < 2 {
+		return fmt.Errorf("a roundtable needs at least two agents")
+	}
+	if len(seats) > maxRoundtableSeats {
+		return fmt.Errorf("a roundtable seats at most %d agents", maxRoundtableSeats)
+	}
+
+	seen = make(map[string]bool)
+	for _, seat = range seats {
+		if seat == nil {
+			return fmt.Errorf("every roundtable seat needs an agent")
+		}
+		if seen[seat.Id] {
+			return fmt.Errorf("agent %s is seated twice", seat.Name)
+		}
+
+		seen[seat.Id] = true
+	}
+
+	return nil
+}
+
+func newRoundtable(seats []*NaruAgent, mode, name string) (*Session, error) {
+	var session *Session
+
+	var err error
+
+	err = RoundtableCheck(seats, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	session = NewSession(seats[0], name)
+	session.TurnMode = mode
+
+	return session, nil
+}
+
+func RoundtableCreate(seats []*NaruAgent, mode, name string) (*Session, error) {
+	var session *Session
+	var tx *sql.Tx
+
+	var err error
+
+	session, err = newRoundtable(seats, mode, name)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err = util.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sessionInsert(tx, session, seats)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func RoundtableAttach(seats []*NaruAgent, mode, origin, externalId, name string) (*Session, error) {
+	var session *Session
+
+	var err error
+
+	if origin == "" || externalId == "" {
+		return nil, fmt.Errorf("origin and external id are required")
+	}
+
+	session, err = newRoundtable(seats, mode, name)
+	if err != nil {
+		return nil, err
+	}
+	session.Origin = origin
+	session.ExternalId = externalId
+
+	return sessionAttach(session, seats)
+}
+
+func RoundtableSeats(sessionId string) ([]*NaruAgent, error) {
+	var rows *sql.Rows
+	var agentId string
+	var seat *NaruAgent
+	var seats []*NaruAgent
+
+	var err error
+
+	rows, err = util.DB.Query("SELECT agent_id FROM session_agents WHERE session_id = ? ORDER BY seat ASC;", sessionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&agentId)
+		if err != nil {
+			return nil, err
+		}
+
+		seat = agentById(agentId)
+		if seat == nil {
+			util.Log.Warn("skipping a roundtable seat whose agent is gone", "session", sessionId, "agent", agentId)
+			continue
+		}
+
+		seats = append(seats, seat)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	if len(seats) == 0 {
+		return nil, fmt.Errorf("session %s has no agents left at its roundtable", sessionId)
+	}
+
+	return seats, nil
+}
+
+func nameBoundary(text string, index int) bool {
+	var next rune
+
+	if index >= len(text) {
+		return true
+	}
+
+	next, _ = utf8.DecodeRuneInString(text[index:])
+
+	return !unicode.IsLetter(next) && !unicode.IsDigit(next) && next != '-' && next != '_'
+}
+
+func mentionedSeats(seats []*NaruAgent, content string) []*NaruAgent {
+	var lowered string
+	var found []seatMention
+	var seat *NaruAgent
+	var needle string
+	var from int
+	var at int
+	var mention seatMention
+	var addressed []*NaruAgent
+
+	lowered = strings.ToLower(content)
+
+	for _, seat = range seats {
+		needle = "@" + strings.ToLower(seat.Name)
+		for from = 0; from < len(lowered); from = at + len(needle) {
+			at = strings.Index(lowered[from:], needle)
+			if at < 0 {
+				break
+			}
+
+			at += from
+			if nameBoundary(lowered, at+len(needle)) {
+				found = append(found, seatMention{seat: seat, index: at})
+				break
+			}
+		}
+	}
+
+	slices.SortFunc(found, func(a, b seatMention) int {
+		return a.index - b.index
+	})
+
+	for _, mention = range found {
+		addressed = append(addressed, mention.seat)
+	}
+
+	return addressed
+}
+
+func seatByName(seats []*NaruAgent, name string) *NaruAgent {
+	var seat *NaruAgent
+
+	for _, seat = range seats {
+		if !strings.EqualFold(seat.Name, name) {
+			continue
+		}
+
+		return seat
+	}
+
+	return nil
+}
+
+func lastSpeaker(seats []*NaruAgent, history []*Message) *NaruAgent {
+	var index int
+	var seat *NaruAgent
+
+	for index = len(history) - 1; index >= 0; index-- {
+		if history[index].Role != "assistant" {
+			continue
+		}
+
+		for _, seat = range seats {
+			if seat.Id == history[index].AgentId {
+				return seat
+			}
+		}
+	}
+
+	return seats[0]
+}
+
+func speakerName(message *Message, names map[string]string) string {
+	var name string
+	var ok bool
+
+	if message.Role != "assistant" {
+		return "person"
+	}
+
+	name, ok = names[message.AgentId]
+	if !ok {
+		return "agent"
+	}
+
+	return name
+}
+
+func seatNames(seats []*NaruAgent) map[string]string {
+	var names map[string]string
+	var seat *NaruAgent
+	var agent *NaruAgent
+
+	names = make(map[string]string)
+	for _, agent = range AgentAll() {
+		names[agent.Id] = agent.Name
+	}
+	for _, seat = range seats {
+		names[seat.Id] = seat.Name
+	}
+
+	return names
+}
+
+func moderatorTranscript(seats []*NaruAgent, history []*Message, content string) string {
+	var builder strings.Builder
+	var names map[string]string
+	var seat *NaruAgent
+	var role string
+	var message *Message
+
+	names = seatNames(seats)
+
+	builder.WriteString(moderatorInstruction)
+	builder.WriteString("\n\n<agents>\n")
+	for _, seat = range seats {
+		role = strings.TrimSpace(strings.SplitN(seat.Role, "\n", 2)[0])
+		if role == "" {
+			role = "no role given"
+		}
+		fmt.Fprintf(&builder, "%s: %s\n", seat.Name, role)
+	}
+	builder.WriteString("</agents>\n\n<recent-turns>\n")
+
+	if len(history) > moderatorTranscriptMessages {
+		history = history[len(history)-moderatorTranscriptMessages:]
+	}
+	for _, message = range history {
+		fmt.Fprintf(&builder, "%s: %s\n", speakerName(message, names), message.Content)
+	}
+
+	builder.WriteString("</recent-turns>\n\n<latest-message>\n")
+	builder.WriteString(content)
+	builder.WriteString("\n</latest-message>")
+
+	return builder.String()
+}
+
+func moderatorPick(ctx context.Context, session *Session, seats []*NaruAgent, history []*Message, content string) []*NaruAgent {
+	var moderator *NaruAgent
+	var result *Completion
+	var field string
+	var seat *NaruAgent
+	var picked []*NaruAgent
+
+	var err error
+
+	moderator = seats[0]
+
+	result, err = Complete(ctx, moderator, []openai.ChatCompletionMessageParamUnion{
+		openai.UserMessage(moderatorTranscript(seats, history, content)),
+	}, nil, config.ThinkingOff, nil, nil)
+	if err != nil {
+		util.Log.Warn("asking the moderator who speaks failed, handing the turn to the moderator",
+			"session", session.Id, "moderator", moderator.Name, "error", err)
+
+		return seats[:1]
+	}
+
+	usageRecord(session.Id, "", moderator.Id, UsageModerator, result.Usage)
+
+	for _, field = range strings.FieldsFunc(result.Content, func(value rune) bool {
+		return value == ',' || value == '\n'
+	}) {
+		seat = seatByName(seats, strings.Trim(field, " @*`.\"'"))
+		if seat == nil || slices.Contains(picked, seat) {
+			continue
+		}
+
+		picked = append(picked, seat)
+	}
+
+	if len(picked) == 0 {
+		util.Log.Debug("the moderator named no seated agent, handing the turn to the moderator",
+			"session", session.Id, "reply", result.Content)
+
+		return seats[:1]
+	}
+
+	return picked
+}
+
+func roundtableSpeakers(ctx context.Context, session *Session, seats []*NaruAgent, history []*Message, content string) []*NaruAgent {
+	var addressed []*NaruAgent
+
+	addressed = mentionedSeats(seats, content)
+	if len(addressed) > 0 {
+		return addressed
+	}
+
+	switch session.TurnMode {
+	case TurnModerator:
+		return moderatorPick(ctx, session, seats, history, content)
+	case TurnAddressed:
+		return []*NaruAgent{lastSpeaker(seats, history)}
+	}
+
+	return seats
+}
+
+func seatCalls(calls []*ToolCall, agentId string) []*ToolCall {
+	var call *ToolCall
+	var kept []*ToolCall
+
+	for _, call = range calls {
+		if call.AgentId == agentId {
+			kept = append(kept, call)
+		}
+	}
+
+	return kept
+}
+
+func roundtableMessages(history []*Message, calls map[string][]*ToolCall, speaker *NaruAgent, names map[string]string) []openai.ChatCompletionMessageParamUnion {
+	var message *Message
+	var messages []openai.ChatCompletionMessageParamUnion
+	var turnId string
+	var turn []*ToolCall
+	var call *ToolCall
+	var images openai.ChatCompletionMessageParamUnion
+	var ok bool
+
+	for _, message = range history {
+		if message.Role != "assistant" {
+			turnId = message.Id
+			messages = append(messages, UserMessage(message.Content, message.Attachments))
+			continue
+		}
+
+		if message.AgentId != speaker.Id {
+			messages = append(messages, openai.UserMessage(fmt.Sprintf("[%s] %s", speakerName(message, names), message.Content)))
+			continue
+		}
+
+		turn = seatCalls(calls[turnId], speaker.Id)
+		if replayableCalls(turn) {
+			messages = append(messages, storedToolCallMessage(turn))
+			for _, call = range turn {
+				messages = append(messages, openai.ToolMessage(call.Result, call.CallId))
+			}
+
+			images, ok = toolImageMessage(turn)
+			if ok {
+				messages = append(messages, images)
+			}
+		}
+
+		messages = append(messages, openai.AssistantMessage(message.Content))
+	}
+
+	return messages
+}
+
+func roundtableCompactor(speakers []*NaruAgent) *NaruAgent {
+	var compactor *NaruAgent
+	var speaker *NaruAgent
+	var window int64
+	var smallest int64
+
+	for _, speaker = range speakers {
+		window = speaker.CachedModelContextWindow()
+		if compactor == nil || (window > 0 && (smallest <= 0 || window < smallest)) {
+			compactor = speaker
+			smallest = window
+		}
+	}
+
+	return compactor
+}
+
+func (r *roundtableRun) compact(ctx context.Context, speakers []*NaruAgent) {
+	var compactor *NaruAgent
+	var prompt string
+
+	compactor = roundtableCompactor(speakers)
+	if compactor == nil {
+		return
+	}
+
+	prompt = systemPrompt(compactor, r.Defs, nil) + "\n\n" + roundtableBlock(compactor, r.Seats)
+	r.Summary, r.History, r.Calls = compactHistory(ctx, compactor, r.Session, r.History, r.Calls, prompt, r.Defs, r.Content)
+}
+
+func (r *roundtableRun) speak(ctx context.Context, speaker *NaruAgent) (*Message, error) {
+	var names map[string]string
+	var prompt string
+	var contextWindow int64
+	var messages []openai.ChatCompletionMessageParamUnion
+	var run completionRun
+	var result *Completion
+
+	var err error
+
+	if speaker.AI == nil && speaker.Anthropic == nil {
+		return nil, fmt.Errorf("agent %s has no available provider client", speaker.Id)
+	}
+
+	names = seatNames(r.Seats)
+	prompt = systemPrompt(speaker, r.Defs, recallFor(ctx, speaker, r.Defs, r.Content)) + "\n\n" + roundtableBlock(speaker, r.Seats)
+	contextWindow = speaker.CachedModelContextWindow()
+
+	if r.Summary != "" {
+		prompt = prompt + "\n\n" + summaryBlock(r.Summary)
+	}
+
+	messages = append(messages, openai.SystemMessage(prompt))
+	messages = append(messages, roundtableMessages(r.History, r.Calls, speaker, names)...)
+	messages = append(messages, UserMessage(r.Content, r.Attachments))
+	messages = append(messages, roundtableMessages(r.spoken, nil, speaker, names)...)
+
+	run = completionRun{
+		AI: speaker.AI, Anthropic: speaker.Anthropic, Provider: agentProvider(speaker),
+		Params: chatParams(speaker, messages, r.Defs, r.Thinking), Defs: r.Defs,
+		AllowDangerous: r.AllowDangerous, AllowPrivileged: true,
+		AgentId: speaker.Id, Vision: speaker.SeesImages(), Sandbox: speaker.Sandbox,
+		SessionId: r.Session.Id, MessageId: r.pendingId,
+		OnContent: r.OnContent, OnReasoning: r.OnReasoning, OnTool: r.OnTool, Approve: r.Approve,
+	}
+
+	result, err = run.execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usageRecordWithContext(r.Session.Id, r.pendingId, speaker.Id, UsageTurn, result.Usage, result.ContextTokens, contextWindow)
+
+	return messageCompleteTurn(r.pendingId, "", r.Session.Id, speaker.Id, result.Content, result.Reasoning)
+}
+
+func (r *roundtableRun) execute(ctx context.Context) ([]*Message, error) {
+	var speakers []*NaruAgent
+	var pending *Message
+	var speaker *NaruAgent
+	var reply *Message
+
+	var err error
+
+	speakers = roundtableSpeakers(ctx, r.Session, r.Seats, r.History, r.Content)
+	r.compact(ctx, speakers)
+
+	pending, err = messageStart(r.Session.Id, r.Content, r.Attachments, "")
+	if err != nil {
+		return nil, err
+	}
+	r.pendingId = pending.Id
+
+	for _, speaker = range speakers {
+		if r.OnSpeaker != nil {
+			r.OnSpeaker(speaker)
+		}
+
+		reply, err = r.speak(ctx, speaker)
+		if err != nil && len(r.spoken) == 0 {
+			return nil, turnFailed(ctx, pending.Id, err)
+		}
+		if err != nil {
+			util.Log.Warn("a roundtable speaker failed, ending the round early",
+				"session", r.Session.Id, "agent", speaker.Name, "spoken", len(r.spoken), "error", err)
+
+			return r.spoken, err
+		}
+
+		r.spoken = append(r.spoken, reply)
+	}
+
+	return r.spoken, nil
+}
+
+func roundtableChat(ctx context.Context, session *Session, content string, attachments []*Attachment,
+	defs []modules.Def, thinking string, allowDangerous bool,
+	onSpeaker SpeakerFunc, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) ([]*Message, error) {
+	var run roundtableRun
+
+	var err error
+
+	if session == nil || session.TurnMode == "" {
+		return nil, fmt.Errorf("a roundtable session is required")
+	}
+
+	run = roundtableRun{
+		Session: session, Content: content, Attachments: attachments, Defs: permittedTools(defs),
+		Thinking: thinking, AllowDangerous: allowDangerous,
+		OnSpeaker: onSpeaker, OnContent: onContent, OnReasoning: onReasoning, OnTool: onTool, Approve: approve,
+	}
+
+	run.Seats, err = RoundtableSeats(session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = AttachmentsCheck(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	run.History, err = MessageList(session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = attachmentsLoad(session.Id, run.History)
+	if err != nil {
+		return nil, err
+	}
+
+	run.Calls, err = toolCallsBySession(session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return run.execute(ctx)
+}
+
+func RoundtableChat(ctx context.Context, session *Session, content string, attachments []*Attachment,
+	onSpeaker SpeakerFunc, onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) ([]*Message, error) {
+	var defs []modules.Def
+
+	if config.Client.Tools.Enabled {
+		defs = modules.DefaultTools()
+	}
+
+	return roundtableChat(ctx, session, content, attachments, defs, config.Client.Thinking.Level, config.AllowDangerousTools,
+		onSpeaker, onContent, onReasoning, onTool, approve)
+}
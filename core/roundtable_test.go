@@ -0,0 +1,311 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/devproje/mininaru/config"
+)
+
+func roundtableServer(t *testing.T, moderatorReply string, bodies *[]string) *httptest.Server {
+	var srv *httptest.Server
+	var mu sync.Mutex
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw []byte
+		var body struct {
+			Model string `json:"model"`
+		}
+		var reply string
+
+		raw, _ = io.ReadAll(r.Body)
+		json.Unmarshal(raw, &body)
+
+		mu.Lock()
+		*bodies = append(*bodies, string(raw))
+		mu.Unlock()
+
+		reply = "from " + body.Model
+		if strings.Contains(string(raw), "You moderate") {
+			reply = moderatorReply
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, toolChunk("c", `{"role":"assistant","content":"`+reply+`"}`, `"stop"`))
+		io.WriteString(w, usageChunk("c", 10, 2))
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func roundtableSetup(t *testing.T, srvURL, mode string) (*Session, []*NaruAgent) {
+	var first *NaruAgent
+	var seats []*NaruAgent
+	var session *Session
+
+	var err error
+
+	t.Helper()
+
+	_, first = thinkingSetup(t, srvURL)
+	first.Name = "alpha"
+	first.Model = "m-alpha"
+	seats = []*NaruAgent{first, AgentNew("beta", "", "", "m-beta", Providers[0]), AgentNew("gamma", "", "", "m-gamma", Providers[0])}
+
+	Global = seats[0]
+	Agents = seats[1:]
+	t.Cleanup(func() {
+		Global = nil
+		Agents = nil
+	})
+
+	session, err = RoundtableCreate(seats, mode, "table")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return session, seats
+}
+
+func TestRoundRobinGivesEverySeatATurnWithAttribution(t *testing.T) {
+	var bodies []string
+	var srv *httptest.Server
+	var session *Session
+	var seats []*NaruAgent
+	var speakers []string
+	var replies []*Message
+	var totals *UsageTotals
+
+	var err error
+
+	srv = roundtableServer(t, "", &bodies)
+	session, seats = roundtableSetup(t, srv.URL, TurnRoundRobin)
+
+	replies, err = roundtableChat(context.Background(), session, "hello table", nil, nil, "", false,
+		func(agent *NaruAgent) { speakers = append(speakers, agent.Name) }, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(speakers, ",") != "alpha,beta,gamma" || len(replies) != 3 {
+		t.Fatalf("speakers = %v, replies = %d", speakers, len(replies))
+	}
+	if replies[1].AgentId != seats[1].Id || replies[1].Content != "from m-beta" {
+		t.Fatalf("second reply = %+v", replies[1])
+	}
+	if !strings.Contains(bodies[2], `[alpha] from m-alpha`) || !strings.Contains(bodies[2], `[beta] from m-beta`) ||
+		!strings.Contains(bodies[2], `you: \"gamma\"`) {
+		t.Fatalf("the last speaker did not see the attributed round: %s", bodies[2])
+	}
+
+	totals, err = SessionUsage(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(totals.Agents) != 3 || totals.Agents[2].Agent != "gamma" || totals.Agents[2].TotalTokens != 12 {
+		t.Fatalf("per-agent usage = %+v", totals.Agents)
+	}
+}
+
+func TestRoundtableReplaysOwnTurnsAsAssistant(t *testing.T) {
+	var bodies []string
+	var srv *httptest.Server
+	var session *Session
+
+	var err error
+
+	srv = roundtableServer(t, "", &bodies)
+	session, _ = roundtableSetup(t, srv.URL, TurnAddressed)
+
+	_, err = roundtableChat(context.Background(), session, "@beta start", nil, nil, "", false, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bodies = nil
+
+	_, err = roundtableChat(context.Background(), session, "go on", nil, nil, "", false, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"model":"m-beta"`) ||
+		!strings.Contains(bodies[0], `{"content":"from m-beta","role":"assistant"}`) {
+		t.Fatalf("an unaddressed turn did not go back to the last speaker as itself: %v", bodies)
+	}
+}
+
+func TestRoundtableReplaysOnlyTheSeatsOwnToolCalls(t *testing.T) {
+	var alpha *NaruAgent
+	var beta *NaruAgent
+	var history []*Message
+	var calls map[string][]*ToolCall
+	var raw []byte
+
+	var err error
+
+	alpha = &NaruAgent{Id: "alpha", Name: "alpha"}
+	beta = &NaruAgent{Id: "beta", Name: "beta"}
+	history = []*Message{
+		{Id: "u1", Role: "user", Content: "look around"},
+		{Id: "a1", Role: "assistant", AgentId: "alpha", Content: "alpha looked"},
+		{Id: "a2", Role: "assistant", AgentId: "beta", Content: "beta looked"},
+	}
+	calls = map[string][]*ToolCall{"u1": {
+		{Id: "t1", CallId: "call-alpha", AgentId: "alpha", Name: "glob", Arguments: "{}", Result: "alpha-result", Status: MessageCompleted},
+		{Id: "t2", CallId: "call-beta", AgentId: "beta", Name: "grep", Arguments: "{}", Result: "beta-result", Status: MessageCompleted},
+	}}
+
+	raw, err = json.Marshal(roundtableMessages(history, calls, alpha, seatNames([]*NaruAgent{alpha, beta})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"call-alpha"`) || !strings.Contains(string(raw), "alpha-result") || strings.Contains(string(raw), "call-beta") || strings.Contains(string(raw), "beta-result") {
+		t.Fatalf("alpha's replay = %s, want its own call and result and neither of beta's", raw)
+	}
+	if strings.Index(string(raw), "alpha-result") > strings.Index(string(raw), "alpha looked") {
+		t.Fatalf("alpha's tool result came after its reply: %s", raw)
+	}
+
+	raw, err = json.Marshal(roundtableMessages(history, calls, beta, seatNames([]*NaruAgent{alpha, beta})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"call-beta"`) || !strings.Contains(string(raw), "beta-result") || strings.Contains(string(raw), "call-alpha") {
+		t.Fatalf("beta's replay = %s", raw)
+	}
+}
+
+func TestRoundtableCompactsOncePerRound(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var seats []*NaruAgent
+	var seat *NaruAgent
+	var provider *Provider
+	var summaries int
+	var body string
+	var previous config.ClientConfig
+
+	var err error
+
+	srv = compactServer(t, &requests)
+	defer srv.Close()
+
+	previous = config.Client
+	t.Cleanup(func() { config.Client = previous })
+	config.Client.Context.Compact = true
+
+	session, seats = roundtableSetup(t, srv.URL, TurnRoundRobin)
+	provider, err = ProviderFind(seats[0].ProviderId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, seat = range seats {
+		modelContextWindows.Store(seat.ProviderId+"\x00"+provider.BaseURL+"\x00"+seat.Model, int64(100))
+	}
+	seedTurns(t, session.Id, 2)
+	seedContextUsage(t, session.Id, 95, 100)
+
+	_, err = roundtableChat(context.Background(), session, "hello table", nil, nil, "", false, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body = range requests {
+		if strings.Contains(body, "turns-to-fold-in") {
+			summaries++
+		}
+	}
+	if summaries != 1 || len(requests) != 4 {
+		t.Fatalf("summaries = %d, requests = %d, want one summary for the round and one call per seat", summaries, len(requests))
+	}
+	if !strings.Contains(requests[3], "the user likes tea") {
+		t.Fatalf("the last seat did not get the round's summary: %s", requests[3])
+	}
+}
+
+func TestModeratorPicksTheSpeakers(t *testing.T) {
+	var bodies []string
+	var srv *httptest.Server
+	var session *Session
+	var replies []*Message
+	var totals *UsageTotals
+	var line UsageLine
+	var moderated bool
+
+	var err error
+
+	srv = roundtableServer(t, "Gamma, @beta", &bodies)
+	session, _ = roundtableSetup(t, srv.URL, TurnModerator)
+
+	replies, err = roundtableChat(context.Background(), session, "who knows sql?", nil, nil, "", false, nil, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(replies) != 2 || replies[0].Content != "from m-gamma" || replies[1].Content != "from m-beta" {
+		t.Fatalf("replies = %v", replies)
+	}
+
+	totals, err = SessionUsage(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line = range totals.Lines {
+		moderated = moderated || line.Kind == UsageModerator
+	}
+	if !moderated {
+		t.Fatalf("the moderator call was not recorded: %+v", totals.Lines)
+	}
+}
+
+func TestMentionedSeatsFollowTheMessageOrder(t *testing.T) {
+	var seats []*NaruAgent
+	var picked []*NaruAgent
+
+	seats = []*NaruAgent{{Id: "1", Name: "ann"}, {Id: "2", Name: "bob"}, {Id: "3", Name: "anna"}}
+
+	picked = mentionedSeats(seats, "@Bob what do you and @anna think? cc @annabel")
+	if len(picked) != 2 || picked[0].Name != "bob" || picked[1].Name != "anna" {
+		t.Fatalf("picked = %v", picked)
+	}
+
+	if len(mentionedSeats(seats, "mail ann@bob.com")) != 1 {
+		t.Fatal("an @ inside an address should still only match a whole name")
+	}
+}
+
+func TestRoundtableCreateRejectsBadSeating(t *testing.T) {
+	var bodies []string
+	var srv *httptest.Server
+	var seats []*NaruAgent
+
+	var err error
+
+	srv = roundtableServer(t, "", &bodies)
+	_, seats = roundtableSetup(t, srv.URL, TurnRoundRobin)
+
+	_, err = RoundtableCreate(seats[:1], TurnRoundRobin, "solo")
+	if err == nil {
+		t.Fatal("a one-seat roundtable was accepted")
+	}
+	_, err = RoundtableCreate([]*NaruAgent{seats[0], seats[0]}, TurnRoundRobin, "twice")
+	if err == nil {
+		t.Fatal("a repeated seat was accepted")
+	}
+	_, err = RoundtableCreate(seats, "shouting", "bad")
+	if err == nil {
+		t.Fatal("an unknown turn mode was accepted")
+	}
+}
@@ -0,0 +1,212 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
+)
+
+type Sampling struct {
+	Temperature      *float64       `json:"temperature,omitempty"`
+	TopP             *float64       `json:"top_p,omitempty"`
+	MaxTokens        *int64         `json:"max_tokens,omitempty"`
+	Stop             []string       `json:"stop,omitempty"`
+	Seed             *int64         `json:"seed,omitempty"`
+	PresencePenalty  *float64       `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *float64       `json:"frequency_penalty,omitempty"`
+	Extra            map[string]any `json:"extra_body,omitempty"`
+}
+
+type Bound struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+type SamplingBounds struct {
+	Temperature      *Bound `json:"temperature,omitempty"`
+	TopP             *Bound `json:"top_p,omitempty"`
+	PresencePenalty  *Bound `json:"presence_penalty,omitempty"`
+	FrequencyPenalty *Bound `json:"frequency_penalty,omitempty"`
+	MaxTokens        int64  `json:"max_tokens,omitempty"`
+	Seed             bool   `json:"seed,omitempty"`
+	Stop             bool   `json:"stop,omitempty"`
+}
+
+const maxStopSequences = 4
+
+func boundCheck(name string, value float64, bound Bound) error {
+	if value < bound.Min || value > bound.Max {
+		return fmt.Errorf("%s must be between %g and %g", name, bound.Min, bound.Max)
+	}
+
+	return nil
+}
+
+func (s Sampling) Clone() Sampling {
+	s.Stop = slices.Clone(s.Stop)
+	s.Extra = maps.Clone(s.Extra)
+
+	return s
+}
+
+func (s Sampling) Validate() error {
+	var err error
+
+	if s.Temperature != nil {
+		err = boundCheck("temperature", *s.Temperature, Bound{Min: 0, Max: 2})
+		if err != nil {
+			return err
+		}
+	}
+	if s.TopP != nil {
+		err = boundCheck("top_p", *s.TopP, Bound{Min: 0, Max: 1})
+		if err != nil {
+			return err
+		}
+	}
+	if s.PresencePenalty != nil {
+		err = boundCheck("presence_penalty", *s.PresencePenalty, Bound{Min: -2, Max: 2})
+		if err != nil {
+			return err
+		}
+	}
+	if s.FrequencyPenalty != nil {
+		err = boundCheck("frequency_penalty", *s.FrequencyPenalty, Bound{Min: -2, Max: 2})
+		if err != nil {
+			return err
+		}
+	}
+	if s.MaxTokens != nil && *s.MaxTokens <= 0 {
+		return fmt.Errorf("max_tokens must be a positive token count")
+	}
+	if len(s.Stop) > maxStopSequences {
+		return fmt.Errorf("at most %d stop sequences are allowed", maxStopSequences)
+	}
+
+	return nil
+}
+
+func (b SamplingBounds) Validate() error {
+	var name string
+	var bound *Bound
+
+	for name, bound = range map[string]*Bound{
+		"temperature": b.Temperature, "top_p": b.TopP,
+		"presence_penalty": b.PresencePenalty, "frequency_penalty": b.FrequencyPenalty,
+	} {
+		if bound != nil && bound.Min > bound.Max {
+			return fmt.Errorf("%s override range is empty, %g is above %g", name, bound.Min, bound.Max)
+		}
+	}
+	if b.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens override ceiling must be 0 or a positive token count")
+	}
+
+	return nil
+}
+
+func overrideFloat(name string, requested *float64, bound *Bound, current **float64) error {
+	var err error
+
+	if requested == nil {
+		return nil
+	}
+	if bound == nil {
+		return fmt.Errorf("%s cannot be overridden for this agent", name)
+	}
+
+	err = boundCheck(name, *requested, *bound)
+	if err != nil {
+		return err
+	}
+	*current = requested
+
+	return nil
+}
+
+func (a *NaruAgent) SamplingOverride(requested Sampling) (Sampling, error) {
+	var merged Sampling
+
+	var err error
+
+	merged = a.Sampling.Clone()
+
+	err = overrideFloat("temperature", requested.Temperature, a.SamplingBounds.Temperature, &merged.Temperature)
+	if err != nil {
+		return Sampling{}, err
+	}
+	err = overrideFloat("top_p", requested.TopP, a.SamplingBounds.TopP, &merged.TopP)
+	if err != nil {
+		return Sampling{}, err
+	}
+	err = overrideFloat("presence_penalty", requested.PresencePenalty, a.SamplingBounds.PresencePenalty, &merged.PresencePenalty)
+	if err != nil {
+		return Sampling{}, err
+	}
+	err = overrideFloat("frequency_penalty", requested.FrequencyPenalty, a.SamplingBounds.FrequencyPenalty, &merged.FrequencyPenalty)
+	if err != nil {
+		return Sampling{}, err
+	}
+
+	if requested.MaxTokens != nil {
+		if a.SamplingBounds.MaxTokens == 0 {
+			return Sampling{}, fmt.Errorf("max_tokens cannot be overridden for this agent")
+		}
+		if *requested.MaxTokens <= 0 || *requested.MaxTokens > a.SamplingBounds.MaxTokens {
+			return Sampling{}, fmt.Errorf("max_tokens must be between 1 and %d", a.SamplingBounds.MaxTokens)
+		}
+		merged.MaxTokens = requested.MaxTokens
+	}
+	if requested.Seed != nil {
+		if !a.SamplingBounds.Seed {
+			return Sampling{}, fmt.Errorf("seed cannot be overridden for this agent")
+		}
+		merged.Seed = requested.Seed
+	}
+	if len(requested.Stop) > 0 {
+		if !a.SamplingBounds.Stop {
+			return Sampling{}, fmt.Errorf("stop cannot be overridden for this agent")
+		}
+		merged.Stop = slices.Clone(requested.Stop)
+	}
+
+	err = merged.Validate()
+	if err != nil {
+		return Sampling{}, err
+	}
+
+	return merged, nil
+}
+
+func applySampling(params *openai.ChatCompletionNewParams, sampling Sampling) {
+	if sampling.Temperature != nil {
+		params.Temperature = param.NewOpt(*sampling.Temperature)
+	}
+	if sampling.TopP != nil {
+		params.TopP = param.NewOpt(*sampling.TopP)
+	}
+	if sampling.MaxTokens != nil {
+		params.MaxTokens = param.NewOpt(*sampling.MaxTokens)
+	}
+	if len(sampling.Stop) > 0 {
+		params.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: slices.Clone(sampling.Stop)}
+	}
+	if sampling.Seed != nil {
+		params.Seed = param.NewOpt(*sampling.Seed)
+	}
+	if sampling.PresencePenalty != nil {
+		params.PresencePenalty = param.NewOpt(*sampling.PresencePenalty)
+	}
+	if sampling.FrequencyPenalty != nil {
+		params.FrequencyPenalty = param.NewOpt(*sampling.FrequencyPenalty)
+	}
+	if len(sampling.Extra) > 0 {
+		params.SetExtraFields(maps.Clone(sampling.Extra))
+	}
+}
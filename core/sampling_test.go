@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+)
+
+func samplingOf(temperature float64, maxTokens int64) Sampling {
+	return Sampling{Temperature: &temperature, MaxTokens: &maxTokens}
+}
+
+func TestChatSendsTheAgentSampling(t *testing.T) {
+	var srv *httptest.Server
+	var body map[string]any
+	var session *Session
+	var agent *NaruAgent
+	var seed int64
+	var penalty float64
+	var sent bool
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, toolChunk("c", `{"role":"assistant","content":"ok"}`, `"stop"`))
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	session, agent = thinkingSetup(t, srv.URL)
+	seed = 42
+	penalty = 0.5
+	agent.Sampling = samplingOf(0.2, 300)
+	agent.Sampling.Seed = &seed
+	agent.Sampling.FrequencyPenalty = &penalty
+	agent.Sampling.Stop = []string{"</answer>"}
+	agent.Sampling.Extra = map[string]any{"top_k": 20}
+
+	_, err = ChatWithTools(context.Background(), session, agent, "hi", nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body["temperature"] != 0.2 || body["max_tokens"] != float64(300) || body["seed"] != float64(42) ||
+		body["frequency_penalty"] != 0.5 || body["top_k"] != float64(20) || fmt.Sprint(body["stop"]) != "[</answer>]" {
+		t.Fatalf("request body = %v", body)
+	}
+	_, sent = body["top_p"]
+	if sent {
+		t.Fatal("an unset parameter was sent")
+	}
+}
+
+func TestAnthropicRequestCarriesTheSampling(t *testing.T) {
+	var srv *httptest.Server
+	var body map[string]any
+	var provider *Provider
+	var agent *NaruAgent
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\n"+
+			`data: {"type":"message_start","message":{"id":"m","type":"message","role":"assistant","model":"c","content":[],"stop_reason":null,"stop_sequence":null,"usage":{"input_tokens":1,"output_tokens":0}}}`+"\n\n")
+		fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer srv.Close()
+
+	provider = &Provider{Id: "anthropic-test", Name: "anthropic", Kind: ProviderAnthropic, BaseURL: srv.URL, ApiKey: "test"}
+	Providers = []*Provider{provider}
+	DefaultProvider = provider
+	t.Cleanup(func() {
+		Providers = nil
+		DefaultProvider = nil
+	})
+	agent = AgentNew("claude", "", "", "claude-test", provider)
+	agent.Sampling = samplingOf(0.7, 1024)
+	agent.Sampling.Stop = []string{"STOP"}
+
+	_, err = Complete(context.Background(), agent, []openai.ChatCompletionMessageParamUnion{openai.UserMessage("hello")}, nil, "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body["temperature"] != 0.7 || body["max_tokens"] != float64(1024) || fmt.Sprint(body["stop_sequences"]) != "[STOP]" {
+		t.Fatalf("anthropic request = %v", body)
+	}
+}
+
+func TestSamplingOverrideStaysInsideTheBounds(t *testing.T) {
+	var agent NaruAgent
+	var requested Sampling
+	var merged Sampling
+	var seed int64
+	var half float64
+	var above float64
+
+	var err error
+
+	half = 0.5
+	above = 0.95
+	agent.Sampling = samplingOf(0.3, 100)
+	agent.SamplingBounds = SamplingBounds{Temperature: &Bound{Min: 0.1, Max: 0.9}}
+	seed = 9
+	requested = Sampling{Temperature: &half}
+
+	merged, err = agent.SamplingOverride(requested)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *merged.Temperature != 0.5 || *merged.MaxTokens != 100 {
+		t.Fatalf("merged = %+v, want only the bounded temperature taken", merged)
+	}
+
+	_, err = agent.SamplingOverride(samplingOf(0.5, 5000))
+	if err == nil || !strings.Contains(err.Error(), "max_tokens cannot be overridden") {
+		t.Fatalf("a max_tokens the agent does not open = %v, want an error", err)
+	}
+
+	_, err = agent.SamplingOverride(Sampling{TopP: &half})
+	if err == nil || !strings.Contains(err.Error(), "top_p cannot be overridden") {
+		t.Fatalf("a top_p the agent does not open = %v, want an error", err)
+	}
+
+	_, err = agent.SamplingOverride(Sampling{Stop: []string{}})
+	if err != nil {
+		t.Fatalf("an empty stop list = %v, want it treated as absent", err)
+	}
+	if *agent.Sampling.Temperature != 0.3 {
+		t.Fatal("overriding changed the agent's own sampling")
+	}
+
+	_, err = agent.SamplingOverride(Sampling{Temperature: &above})
+	if err == nil || !strings.Contains(err.Error(), "between") {
+		t.Fatal("a temperature above the bound was accepted")
+	}
+
+	requested.Seed = &seed
+	_, err = agent.SamplingOverride(requested)
+	if err == nil || !strings.Contains(err.Error(), "seed") {
+		t.Fatalf("a seed the agent does not open = %v, want an error", err)
+	}
+
+	_, err = agent.SamplingOverride(Sampling{Stop: []string{"END"}})
+	if err == nil || !strings.Contains(err.Error(), "stop") {
+		t.Fatalf("a stop the agent does not open = %v, want an error", err)
+	}
+
+	agent.SamplingBounds.Seed = true
+	merged, err = agent.SamplingOverride(requested)
+	if err != nil || *merged.Seed != 9 {
+		t.Fatalf("an opened seed = %+v, %v", merged, err)
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/util"
+)
+
+func TestProviderKeyReferencesStayReferences(t *testing.T) {
+	var hitA, hitB int
+	var alpha *Provider
+	var key string
+	var buf []byte
+
+	var err error
+
+	alpha, _ = setup(t, &hitA, &hitB)
+	t.Setenv("NARU_TEST_KEY", "sk-resolved")
+
+	key = "env:NARU_TEST_KEY"
+	err = ProviderUpdateFields(alpha.Id, nil, &key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reload(t)
+	alpha, err = ProviderFind("alpha")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alpha.ApiKey != "sk-resolved" || alpha.ApiKeyRef() != "env:NARU_TEST_KEY" {
+		t.Fatalf("api key = %q, ref = %q", alpha.ApiKey, alpha.ApiKeyRef())
+	}
+
+	err = ProviderSave()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err = os.ReadFile(util.Path(PROVIDER_PATH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(buf), "sk-resolved") || !strings.Contains(string(buf), "env:NARU_TEST_KEY") {
+		t.Fatal("the resolved key was written back to provider.json")
+	}
+
+	if len(ProviderSecrets()) != 2 || ProviderSecrets()[0].Ref != "env:NARU_TEST_KEY" || !ProviderSecrets()[1].Plain {
+		t.Fatalf("secrets = %+v", ProviderSecrets())
+	}
+}
+
+func TestBotTokenReferencesStayReferences(t *testing.T) {
+	var bot *Bot
+	var buf []byte
+
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	Bots = nil
+	Global = nil
+	Agents = nil
+	t.Setenv("NARU_TEST_TOKEN", "discord-resolved")
+
+	bot, err = BotCreate(Bot{Name: "naru", Kind: BotDiscord, Token: "env:NARU_TEST_TOKEN"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bot.Token != "discord-resolved" {
+		t.Fatalf("token = %q, want it resolved", bot.Token)
+	}
+
+	buf, err = os.ReadFile(util.Path(BOT_PATH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(buf), "discord-resolved") {
+		t.Fatal("the resolved token was written to bot.json")
+	}
+
+	os.Unsetenv("NARU_TEST_TOKEN")
+	err = BotInit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Bots[0].Token != "" || Bots[0].TokenRef() != "env:NARU_TEST_TOKEN" {
+		t.Fatalf("an unresolved token loaded as %q", Bots[0].Token)
+	}
+}
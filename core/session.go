@@ -7,20 +7,35 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/devproje/mininaru/modules"
 	"github.com/devproje/mininaru/util"
 	"github.com/google/uuid"
 )
 
 type Session struct {
-	Id         string `json:"id"`
-	AgentId    string `json:"agent_id"`
-	Name       string `json:"name"`
-	Origin     string `json:"origin"`
-	ExternalId string `json:"external_id"`
+	Id         string   `json:"id"`
+	AgentId    string   `json:"agent_id"`
+	Name       string   `json:"name"`
+	Origin     string   `json:"origin"`
+	ExternalId string   `json:"external_id"`
+	TurnMode   string   `json:"turn_mode,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+	Pinned     bool     `json:"pinned,omitempty"`
+	Archived   bool     `json:"archived,omitempty"`
 }
 
-const sessionColumns = "id, agent_id, name, origin, external_id"
+type SessionFilter struct {
+	Tag      string
+	Archived bool
+}
+
+const (
+	sessionColumns = "id, agent_id, name, origin, external_id, turn_mode, pinned, archived"
+	sessionTagMax  = 32
+	sessionTagsMax = 16
+)
 
 func NewSession(agent *NaruAgent, name string) *Session {
 	var session Session
@@ -57,13 +72,84 @@ func SessionCreate(agent *NaruAgent, name string) (*Session, error) {
 	return session, nil
 }
 
-func SessionFind(id string) (*Session, error) {
+func sessionScanRow(scan func(...any) error) (*Session, error) {
 	var session Session
 
 	var err error
 
-	err = util.DB.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE id = ?;", id).
-		Scan(&session.Id, &session.AgentId, &session.Name, &session.Origin, &session.ExternalId)
+	err = scan(&session.Id, &session.AgentId, &session.Name, &session.Origin, &session.ExternalId, &session.TurnMode,
+		&session.Pinned, &session.Archived)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func sessionScan(scan func(...any) error) (*Session, error) {
+	var session *Session
+
+	var err error
+
+	session, err = sessionScanRow(scan)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Tags, err = SessionTags(session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func sessionTagsFill(agentId string, sessions []*Session) error {
+	var rows *sql.Rows
+	var byId map[string]*Session
+	var session *Session
+	var id string
+	var tag string
+
+	var err error
+
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	byId = make(map[string]*Session, len(sessions))
+	for _, session = range sessions {
+		byId[session.Id] = session
+	}
+
+	rows, err = util.DB.Query(`SELECT t.session_id, t.tag FROM session_tags t
+	JOIN sessions s ON s.id = t.session_id WHERE s.agent_id = ? ORDER BY t.tag;`, agentId)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&id, &tag)
+		if err != nil {
+			return err
+		}
+
+		session = byId[id]
+		if session != nil {
+			session.Tags = append(session.Tags, tag)
+		}
+	}
+
+	return rows.Err()
+}
+
+func SessionFind(id string) (*Session, error) {
+	var session *Session
+
+	var err error
+
+	session, err = sessionScan(util.DB.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE id = ?;", id).Scan)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("session id %s not found", id)
@@ -72,20 +158,21 @@ func SessionFind(id string) (*Session, error) {
 		return nil, err
 	}
 
-	return &session, nil
+	return session, nil
 }
 
 func SessionLatest(agentId string) (*Session, error) {
 	var query string
-	var session Session
+	var session *Session
 
 	var err error
 
 	query = `SELECT ` + sessionColumns + ` FROM sessions
-	WHERE agent_id = ? AND EXISTS (SELECT 1 FROM messages WHERE messages.session_id = sessions.id AND messages.status = 'completed')
+	WHERE agent_id = ? AND archived = 0
+	AND EXISTS (SELECT 1 FROM messages WHERE messages.session_id = sessions.id AND messages.status = 'completed')
 	ORDER BY rowid DESC LIMIT 1;`
 
-	err = util.DB.QueryRow(query, agentId).Scan(&session.Id, &session.AgentId, &session.Name, &session.Origin, &session.ExternalId)
+	session, err = sessionScan(util.DB.QueryRow(query, agentId).Scan)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -94,37 +181,62 @@ func SessionLatest(agentId string) (*Session, error) {
 		return nil, err
 	}
 
-	return &session, nil
+	return session, nil
 }
 
-func SessionList(agentId string) ([]*Session, error) {
+func SessionList(agentId string, filter SessionFilter) ([]*Session, error) {
+	var query string
+	var args []any
+	var tag string
 	var rows *sql.Rows
-	var cur Session
+	var session *Session
 	var sessions []*Session
 
 	var err error
 
-	rows, err = util.DB.Query("SELECT "+sessionColumns+" FROM sessions WHERE agent_id = ?;", agentId)
+	query = "SELECT " + sessionColumns + " FROM sessions WHERE agent_id = ? AND archived = ?"
+	args = []any{agentId, filter.Archived}
+
+	if filter.Tag != "" {
+		tag, err = SessionTagNormalize(filter.Tag)
+		if err != nil {
+			return nil, err
+		}
+
+		query += " AND id IN (SELECT session_id FROM session_tags WHERE tag = ?)"
+		args = append(args, tag)
+	}
+
+	rows, err = util.DB.Query(query+" ORDER BY rowid;", args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
 	for rows.Next() {
-		err = rows.Scan(&cur.Id, &cur.AgentId, &cur.Name, &cur.Origin, &cur.ExternalId)
+		session, err = sessionScanRow(rows.Scan)
 		if err != nil {
 			return nil, err
 		}
 
-		sessions = append(sessions, &Session{Id: cur.Id, AgentId: cur.AgentId, Name: cur.Name,
-			Origin: cur.Origin, ExternalId: cur.ExternalId})
+		sessions = append(sessions, session)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	err = sessionTagsFill(agentId, sessions)
+	if err != nil {
+		return nil, err
 	}
 
 	return sessions, nil
 }
 
 func SessionByExternal(origin, externalId string) (*Session, error) {
-	var session Session
+	var session *Session
 
 	var err error
 
@@ -132,8 +244,7 @@ func SessionByExternal(origin, externalId string) (*Session, error) {
 		return nil, fmt.Errorf("origin and external id are required")
 	}
 
-	err = util.DB.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE origin = ? AND external_id = ?;", origin, externalId).
-		Scan(&session.Id, &session.AgentId, &session.Name, &session.Origin, &session.ExternalId)
+	session, err = sessionScan(util.DB.QueryRow("SELECT "+sessionColumns+" FROM sessions WHERE origin = ? AND external_id = ?;", origin, externalId).Scan)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -142,40 +253,38 @@ func SessionByExternal(origin, externalId string) (*Session, error) {
 		return nil, err
 	}
 
-	return &session, nil
+	return session, nil
 }
 
-func SessionAttach(agent *NaruAgent, origin, externalId, name string) (*Session, error) {
-	var session *Session
-	var tx *sql.Tx
-
+func sessionInsert(tx *sql.Tx, session *Session, seats []*NaruAgent) error {
 	var err error
 
-	if agent == nil {
-		return nil, fmt.Errorf("agent is required to attach a session")
+	_, err = tx.Exec("INSERT INTO sessions (id, agent_id, name, origin, external_id, turn_mode) VALUES (?, ?, ?, ?, ?, ?);",
+		session.Id, session.AgentId, session.Name, session.Origin, session.ExternalId, session.TurnMode)
+	if err != nil {
+		return err
 	}
 
-	if origin == "" || externalId == "" {
-		return nil, fmt.Errorf("origin and external id are required")
-	}
+	return roundtableSeatsSave(tx, session.Id, seats)
+}
 
-	session = NewSession(agent, name)
-	session.Origin = origin
-	session.ExternalId = externalId
+func sessionAttach(session *Session, seats []*NaruAgent) (*Session, error) {
+	var tx *sql.Tx
+
+	var err error
 
 	tx, err = util.DB.Begin()
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = tx.Exec("UPDATE sessions SET external_id = '' WHERE origin = ? AND external_id = ?;", origin, externalId)
+	_, err = tx.Exec("UPDATE sessions SET external_id = '' WHERE origin = ? AND external_id = ?;", session.Origin, session.ExternalId)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	_, err = tx.Exec("INSERT INTO sessions (id, agent_id, name, origin, external_id) VALUES (?, ?, ?, ?, ?);",
-		session.Id, session.AgentId, session.Name, session.Origin, session.ExternalId)
+	err = sessionInsert(tx, session, seats)
 	if err != nil {
 		tx.Rollback()
 		return nil, err
@@ -189,13 +298,31 @@ func SessionAttach(agent *NaruAgent, origin, externalId, name string) (*Session,
 	return session, nil
 }
 
-func SessionUpdate(id, name string) error {
+func SessionAttach(agent *NaruAgent, origin, externalId, name string) (*Session, error) {
+	var session *Session
+
+	if agent == nil {
+		return nil, fmt.Errorf("agent is required to attach a session")
+	}
+
+	if origin == "" || externalId == "" {
+		return nil, fmt.Errorf("origin and external id are required")
+	}
+
+	session = NewSession(agent, name)
+	session.Origin = origin
+	session.ExternalId = externalId
+
+	return sessionAttach(session, nil)
+}
+
+func sessionExec(id, query string, args ...any) error {
 	var result sql.Result
 	var affected int64
 
 	var err error
 
-	result, err = util.DB.Exec("UPDATE sessions SET name = ? WHERE id = ?;", name, id)
+	result, err = util.DB.Exec(query, append(args, id)...)
 	if err != nil {
 		return err
 	}
@@ -212,12 +339,137 @@ func SessionUpdate(id, name string) error {
 	return nil
 }
 
+func SessionUpdate(id, name string) error {
+	return sessionExec(id, "UPDATE sessions SET name = ? WHERE id = ?;", name)
+}
+
+func SessionPin(id string, pinned bool) error {
+	return sessionExec(id, "UPDATE sessions SET pinned = ? WHERE id = ?;", pinned)
+}
+
+func SessionArchive(id string, archived bool) error {
+	return sessionExec(id, "UPDATE sessions SET archived = ? WHERE id = ?;", archived)
+}
+
+func SessionTagNormalize(tag string) (string, error) {
+	var r rune
+
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	if tag == "" {
+		return "", fmt.Errorf("tag is empty")
+	}
+	if len(tag) > sessionTagMax {
+		return "", fmt.Errorf("tag %s is longer than %d characters", tag, sessionTagMax)
+	}
+
+	for _, r = range tag {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' || r == '_' || r == '.' {
+			continue
+		}
+
+		return "", fmt.Errorf("tag %s may only use letters, digits, '-', '_' and '.'", tag)
+	}
+
+	return tag, nil
+}
+
+func SessionTags(id string) ([]string, error) {
+	var rows *sql.Rows
+	var tag string
+	var tags []string
+
+	var err error
+
+	rows, err = util.DB.Query("SELECT tag FROM session_tags WHERE session_id = ? ORDER BY tag;", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&tag)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags, rows.Err()
+}
+
+func SessionTag(id string, tags []string) error {
+	var tx *sql.Tx
+	var tag string
+	var count int
+
+	var err error
+
+	_, err = SessionFind(id)
+	if err != nil {
+		return err
+	}
+
+	tx, err = util.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, tag = range tags {
+		tag, err = SessionTagNormalize(tag)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("INSERT OR IGNORE INTO session_tags (session_id, tag) VALUES (?, ?);", id, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.QueryRow("SELECT COUNT(*) FROM session_tags WHERE session_id = ?;", id).Scan(&count)
+	if err != nil {
+		return err
+	}
+	if count > sessionTagsMax {
+		return fmt.Errorf("a session holds at most %d tags", sessionTagsMax)
+	}
+
+	return tx.Commit()
+}
+
+func SessionUntag(id string, tags []string) error {
+	var tag string
+
+	var err error
+
+	for _, tag = range tags {
+		tag, err = SessionTagNormalize(tag)
+		if err != nil {
+			return err
+		}
+
+		_, err = util.DB.Exec("DELETE FROM session_tags WHERE session_id = ? AND tag = ?;", id, tag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func SessionDeleteByAgent(agentId string) error {
 	var err error
 
 	_, err = util.DB.Exec("DELETE FROM sessions WHERE agent_id = ?;", agentId)
+	if err != nil {
+		return err
+	}
 
-	return err
+	checkpointsPrune()
+
+	return nil
 }
 
 func SessionDelete(id string) error {
@@ -240,5 +492,13 @@ func SessionDelete(id string) error {
 		return fmt.Errorf("session is not found, aborted.")
 	}
 
+	modules.BashJobsCloseOwner(id)
+	modules.BashShellsCloseOwner(id)
+
+	err = modules.CheckpointsDrop(id)
+	if err != nil {
+		util.Log.Warn("could not drop the session's undo checkpoints", "session", id, "error", err)
+	}
+
 	return nil
 }
@@ -5,6 +5,7 @@ package core
 
 import (
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/devproje/mininaru/util"
@@ -104,3 +105,173 @@ func TestSessionUpdateRenamesSession(t *testing.T) {
 		t.Fatalf("session name = %q, want new", got.Name)
 	}
 }
+
+func TestSessionListFiltersByTagAndArchive(t *testing.T) {
+	var agent *NaruAgent
+	var work, scratch, old *Session
+	var got []*Session
+
+	var err error
+
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent = &NaruAgent{Id: "agent-1", Name: "naru"}
+
+	work, err = SessionCreate(agent, "work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scratch, err = SessionCreate(agent, "scratch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err = SessionCreate(agent, "old")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SessionTag(work.Id, []string{"#Project", "release"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SessionTag(old.Id, []string{"project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SessionArchive(old.Id, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SessionPin(work.Id, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = SessionList(agent.Id, SessionFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Id != work.Id || got[1].Id != scratch.Id {
+		t.Fatalf("active sessions = %+v", got)
+	}
+	if !got[0].Pinned || len(got[0].Tags) != 2 || got[0].Tags[0] != "project" || len(got[1].Tags) != 0 {
+		t.Fatalf("work = %+v, scratch = %+v", got[0], got[1])
+	}
+
+	got, err = SessionList(agent.Id, SessionFilter{Tag: "project"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Id != work.Id {
+		t.Fatalf("tagged sessions = %+v", got)
+	}
+
+	got, err = SessionList(agent.Id, SessionFilter{Tag: "project", Archived: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Id != old.Id || !got[0].Archived {
+		t.Fatalf("archived sessions = %+v", got)
+	}
+
+	err = SessionUntag(work.Id, []string{"release"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	work, err = SessionFind(work.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(work.Tags) != 1 || work.Tags[0] != "project" {
+		t.Fatalf("tags after untag = %v", work.Tags)
+	}
+}
+
+func TestSessionTagRejectsBadTags(t *testing.T) {
+	var session *Session
+	var tags []string
+	var index int
+
+	var err error
+
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	session, err = SessionCreate(&NaruAgent{Id: "agent-1"}, "s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SessionTag(session.Id, []string{"two words"})
+	if err == nil {
+		t.Fatal("a tag with a space was accepted")
+	}
+	err = SessionTag("missing", []string{"ok"})
+	if err == nil {
+		t.Fatal("a missing session was tagged")
+	}
+
+	for index = 0; index <= sessionTagsMax; index++ {
+		tags = append(tags, "t"+strconv.Itoa(index))
+	}
+	err = SessionTag(session.Id, tags)
+	if err == nil {
+		t.Fatalf("%d tags were accepted", len(tags))
+	}
+
+	tags, err = SessionTags(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 0 {
+		t.Fatalf("a rejected tag call left %v behind", tags)
+	}
+}
+
+func TestSessionLatestSkipsArchived(t *testing.T) {
+	var agent *NaruAgent
+	var kept, archived, got *Session
+
+	var err error
+
+	util.DB, err = util.InitDatabase(filepath.Join(t.TempDir(), "t.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	agent = &NaruAgent{Id: "agent-1", Name: "naru"}
+
+	kept, err = SessionCreate(agent, "kept")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = MessageSave(kept.Id, "user", "first", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived, err = SessionCreate(agent, "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = MessageSave(archived.Id, "user", "second", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SessionArchive(archived.Id, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = SessionLatest(agent.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Id != kept.Id {
+		t.Fatalf("latest = %+v, want %s", got, kept.Id)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/devproje/mininaru/modules"
@@ -25,6 +26,7 @@ type subagentPolicy struct {
 	AllowPrivileged bool
 	Approve         ToolApprovalFunc
 	Depth           int
+	Sandbox         string
 }
 
 const AgentToolName = "agent_call"
@@ -51,7 +53,7 @@ func childDefs(defs []modules.Def) []modules.Def {
 	var inherited []modules.Def
 
 	for _, def = range defs {
-		if def.Name == AgentToolName {
+		if def.Name == AgentToolName || def.Name == HandoffToolName {
 			continue
 		}
 
@@ -61,6 +63,14 @@ func childDefs(defs []modules.Def) []modules.Def {
 	return inherited
 }
 
+func subagentSandbox(caller, target string) string {
+	if slices.Index(modules.SandboxModes, caller) > slices.Index(modules.SandboxModes, target) {
+		return caller
+	}
+
+	return target
+}
+
 func runSubagent(ctx context.Context, policy subagentPolicy, target *NaruAgent, prompt string) (string, error) {
 	var defs []modules.Def
 	var params openai.ChatCompletionNewParams
@@ -73,8 +83,9 @@ func runSubagent(ctx context.Context, policy subagentPolicy, target *NaruAgent,
 
 	params.Model = target.Model
 	params.StreamOptions.IncludeUsage = param.NewOpt(true)
-	params.Messages = append(params.Messages, openai.SystemMessage(systemPrompt(target, defs)))
+	params.Messages = append(params.Messages, openai.SystemMessage(systemPrompt(target, defs, recallFor(ctx, target, defs, prompt))))
 	params.Messages = append(params.Messages, openai.UserMessage(prompt))
+	applySampling(&params, target.Sampling)
 	applyOpenAICache(&params, agentProvider(target))
 
 	if len(defs) > 0 {
@@ -84,7 +95,8 @@ func runSubagent(ctx context.Context, policy subagentPolicy, target *NaruAgent,
 	run = completionRun{
 		AI: target.AI, Anthropic: target.Anthropic, Provider: agentProvider(target), Params: params, Defs: defs,
 		AllowDangerous: policy.AllowDangerous, AllowPrivileged: policy.AllowPrivileged,
-		AgentId: target.Id, SessionId: policy.SessionId, Depth: policy.Depth + 1,
+		AgentId: target.Id, SessionId: policy.SessionId, Depth: policy.Depth + 1, Vision: target.SeesImages(),
+		Sandbox: subagentSandbox(policy.Sandbox, target.Sandbox),
 		Approve: policy.Approve,
 	}
 
@@ -93,7 +105,7 @@ func runSubagent(ctx context.Context, policy subagentPolicy, target *NaruAgent,
 		return "", err
 	}
 
-	usageRecord(policy.SessionId, "", UsageSubagent, result.Usage)
+	usageRecord(policy.SessionId, "", target.Id, UsageSubagent, result.Usage)
 
 	return strings.TrimSpace(result.Content), nil
 }
@@ -17,6 +17,9 @@ import (
 
 func TestMain(m *testing.M) {
 	InstallAgentTool()
+	InstallHandoffTool()
+	InstallToolResultTool()
+	InstallArtifactTool()
 
 	os.Exit(m.Run())
 }
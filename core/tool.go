@@ -23,11 +23,14 @@ type ToolCall struct {
 	Id        string `json:"id"`
 	CallId    string `json:"call_id"`
 	MessageId string `json:"message_id"`
+	AgentId   string `json:"agent_id"`
 	Name      string `json:"name"`
 	Arguments string `json:"arguments"`
 	Result    string `json:"result"`
 	Status    string `json:"status"`
 	Error     string `json:"error"`
+
+	Images []modules.ToolImage `json:"-"`
 }
 
 type ToolApprovalFunc func(ctx context.Context, def modules.Def, arguments string) (bool, error)
@@ -40,6 +43,7 @@ type ToolEvent struct {
 	Result    string `json:"result"`
 	Status    string `json:"status"`
 	Error     string `json:"error"`
+	Output    string `json:"output"`
 }
 
 type ToolEventFunc func(event ToolEvent)
@@ -48,6 +52,7 @@ const maxToolRounds = 8
 
 const (
 	ToolEventStarted  = "started"
+	ToolEventOutput   = "output"
 	ToolEventFinished = "finished"
 )
 
@@ -147,13 +152,13 @@ func assistantToolCallMessage(message openai.ChatCompletionMessage) openai.ChatC
 	return openai.ChatCompletionMessageParamUnion{OfAssistant: &assistant}
 }
 
-func toolCallStart(messageId string, call openai.ChatCompletionMessageToolCall) (*ToolCall, error) {
+func toolCallStart(messageId, agentId string, call openai.ChatCompletionMessageToolCall) (*ToolCall, error) {
 	var record ToolCall
 
 	var err error
 
 	record = ToolCall{
-		CallId: call.ID, MessageId: messageId, Name: call.Function.Name,
+		CallId: call.ID, MessageId: messageId, AgentId: agentId, Name: call.Function.Name,
 		Arguments: call.Function.Arguments, Status: MessagePending,
 	}
 	if messageId == "" {
@@ -161,8 +166,8 @@ func toolCallStart(messageId string, call openai.ChatCompletionMessageToolCall)
 	}
 
 	record.Id = uuid.NewString()
-	_, err = util.DB.Exec(`INSERT INTO tool_calls (id, call_id, message_id, name, arguments, result, status, error)
-		VALUES (?, ?, ?, ?, ?, '', ?, '');`, record.Id, record.CallId, record.MessageId, record.Name, record.Arguments, record.Status)
+	_, err = util.DB.Exec(`INSERT INTO tool_calls (id, call_id, message_id, agent_id, name, arguments, result, status, error)
+		VALUES (?, ?, ?, ?, ?, ?, '', ?, '');`, record.Id, record.CallId, record.MessageId, record.AgentId, record.Name, record.Arguments, record.Status)
 	if err != nil {
 		return nil, fmt.Errorf("recording tool call %s failed: %w", call.ID, err)
 	}
@@ -170,9 +175,22 @@ func toolCallStart(messageId string, call openai.ChatCompletionMessageToolCall)
 	return &record, nil
 }
 
+func toolProgressContext(ctx context.Context, record *ToolCall, onTool ToolEventFunc) context.Context {
+	ctx = modules.ToolCallContext(ctx, record.CallId)
+	if onTool == nil {
+		return ctx
+	}
+
+	return modules.ToolProgressContext(ctx, func(chunk string) {
+		onTool(ToolEvent{Phase: ToolEventOutput, CallId: record.CallId, Name: record.Name, Arguments: record.Arguments, Output: chunk})
+	})
+}
+
 func executeTool(ctx context.Context, sessionId string, record *ToolCall, defs []modules.Def, allowDangerous, allowPrivileged bool, approve ToolApprovalFunc) (*ToolCall, error) {
 	var def *modules.Def
 	var approved bool
+	var images *modules.ToolImages
+	var mark int
 
 	var err error
 
@@ -197,7 +215,15 @@ func executeTool(ctx context.Context, sessionId string, record *ToolCall, defs [
 		}
 	}
 	if err == nil && def != nil {
+		images = modules.ToolImagesFrom(ctx)
+		if images != nil {
+			mark = len(images.Images)
+		}
+
 		record.Result, err = def.Execute(ctx, record.Arguments)
+		if err == nil && images != nil {
+			record.Images = images.Images[mark:]
+		}
 	}
 	if err != nil {
 		record.Status = MessageFailed
@@ -208,6 +234,10 @@ func executeTool(ctx context.Context, sessionId string, record *ToolCall, defs [
 	if record.Status == MessageCompleted && record.Name == modules.SkillToolName {
 		skillUseRecord(sessionId, record)
 	}
+	if record.Status == MessageCompleted {
+		record.Result = artifactOffload(sessionId, record)
+		toolImagesSave(sessionId, record)
+	}
 
 	if record.Id == "" {
 		return record, nil
@@ -226,11 +256,14 @@ func toolCallsBySession(sessionId string) (map[string][]*ToolCall, error) {
 	var query string
 	var rows *sql.Rows
 	var calls map[string][]*ToolCall
+	var byId map[string]*ToolCall
 	var call ToolCall
+	var loaded *ToolCall
+	var latest string
 
 	var err error
 
-	query = `SELECT t.id, t.call_id, t.message_id, t.name, t.arguments, t.result, t.status, t.error
+	query = `SELECT t.id, t.call_id, t.message_id, t.agent_id, t.name, t.arguments, t.result, t.status, t.error
 		FROM tool_calls t JOIN messages m ON m.id = t.message_id
 		WHERE m.session_id = ? ORDER BY t.rowid ASC;`
 
@@ -241,21 +274,34 @@ func toolCallsBySession(sessionId string) (map[string][]*ToolCall, error) {
 	defer rows.Close()
 
 	calls = make(map[string][]*ToolCall)
+	byId = make(map[string]*ToolCall)
 
 	for rows.Next() {
-		err = rows.Scan(&call.Id, &call.CallId, &call.MessageId, &call.Name, &call.Arguments, &call.Result, &call.Status, &call.Error)
+		err = rows.Scan(&call.Id, &call.CallId, &call.MessageId, &call.AgentId, &call.Name, &call.Arguments, &call.Result, &call.Status, &call.Error)
 		if err != nil {
 			return nil, err
 		}
 
-		calls[call.MessageId] = append(calls[call.MessageId], &ToolCall{
-			Id: call.Id, CallId: call.CallId, MessageId: call.MessageId, Name: call.Name, Arguments: call.Arguments,
+		loaded = &ToolCall{
+			Id: call.Id, CallId: call.CallId, MessageId: call.MessageId, AgentId: call.AgentId, Name: call.Name, Arguments: call.Arguments,
 			Result: call.Result, Status: call.Status, Error: call.Error,
-		})
+		}
+		calls[call.MessageId] = append(calls[call.MessageId], loaded)
+		latest = call.MessageId
 	}
 	if err = rows.Err(); err != nil {
 		return nil, err
 	}
+	rows.Close()
+
+	for _, loaded = range calls[latest] {
+		byId[loaded.Id] = loaded
+	}
+
+	err = toolImagesLoad(sessionId, latest, byId)
+	if err != nil {
+		return nil, err
+	}
 
 	return calls, nil
 }
@@ -298,6 +344,8 @@ func historyMessages(history []*Message, calls map[string][]*ToolCall) []openai.
 	var messages []openai.ChatCompletionMessageParamUnion
 	var turn []*ToolCall
 	var call *ToolCall
+	var images openai.ChatCompletionMessageParamUnion
+	var ok bool
 
 	for _, cur = range history {
 		if cur.Role == "assistant" {
@@ -305,7 +353,7 @@ func historyMessages(history []*Message, calls map[string][]*ToolCall) []openai.
 			continue
 		}
 
-		messages = append(messages, openai.UserMessage(cur.Content))
+		messages = append(messages, UserMessage(cur.Content, cur.Attachments))
 
 		turn = calls[cur.Id]
 		if !replayableCalls(turn) {
@@ -317,6 +365,11 @@ func historyMessages(history []*Message, calls map[string][]*ToolCall) []openai.
 		for _, call = range turn {
 			messages = append(messages, openai.ToolMessage(call.Result, call.CallId))
 		}
+
+		images, ok = toolImageMessage(turn)
+		if ok {
+			messages = append(messages, images)
+		}
 	}
 
 	return messages
@@ -329,7 +382,7 @@ func ToolCallList(messageId string) ([]*ToolCall, error) {
 
 	var err error
 
-	rows, err = util.DB.Query(`SELECT id, call_id, message_id, name, arguments, result, status, error
+	rows, err = util.DB.Query(`SELECT id, call_id, message_id, agent_id, name, arguments, result, status, error
 		FROM tool_calls WHERE message_id = ? ORDER BY rowid ASC;`, messageId)
 	if err != nil {
 		return nil, err
@@ -337,12 +390,12 @@ func ToolCallList(messageId string) ([]*ToolCall, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		err = rows.Scan(&call.Id, &call.CallId, &call.MessageId, &call.Name, &call.Arguments, &call.Result, &call.Status, &call.Error)
+		err = rows.Scan(&call.Id, &call.CallId, &call.MessageId, &call.AgentId, &call.Name, &call.Arguments, &call.Result, &call.Status, &call.Error)
 		if err != nil {
 			return nil, err
 		}
 		calls = append(calls, &ToolCall{
-			Id: call.Id, CallId: call.CallId, MessageId: call.MessageId, Name: call.Name, Arguments: call.Arguments,
+			Id: call.Id, CallId: call.CallId, MessageId: call.MessageId, AgentId: call.AgentId, Name: call.Name, Arguments: call.Arguments,
 			Result: call.Result, Status: call.Status, Error: call.Error,
 		})
 	}
@@ -64,7 +64,7 @@ func TestChatExecutesToolAndReturnsFinalAnswer(t *testing.T) {
 		},
 	}
 
-	message, err = chatWithTools(context.Background(), session, agent, "use echo", []modules.Def{def}, nil, nil,
+	message, err = chatWithTools(context.Background(), session, agent, "use echo", nil, []modules.Def{def}, nil, nil,
 		func(event ToolEvent) { events = append(events, event) }, nil)
 	if err != nil {
 		t.Fatal(err)
@@ -119,7 +119,7 @@ func TestDangerousToolRequiresApprovalOrBypass(t *testing.T) {
 	var err error
 
 	session, _ = thinkingSetup(t, "http://127.0.0.1")
-	pending, err = messageStart(session.Id, "danger")
+	pending, err = messageStart(session.Id, "danger", nil, "")
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -134,7 +134,7 @@ func TestDangerousToolRequiresApprovalOrBypass(t *testing.T) {
 	call.Function.Name = "danger"
 	call.Function.Arguments = `{}`
 
-	record, err = toolCallStart(pending.Id, call)
+	record, err = toolCallStart(pending.Id, "", call)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -159,7 +159,7 @@ func TestDangerousToolRequiresApprovalOrBypass(t *testing.T) {
 	}
 
 	call.ID = "call-approved"
-	record, err = toolCallStart(pending.Id, call)
+	record, err = toolCallStart(pending.Id, "", call)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -178,7 +178,7 @@ func TestDangerousToolRequiresApprovalOrBypass(t *testing.T) {
 
 	call.ID = "call-bypass"
 	approved = false
-	record, err = toolCallStart(pending.Id, call)
+	record, err = toolCallStart(pending.Id, "", call)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -228,12 +228,12 @@ func TestResumedSessionReplaysToolCallsToTheModel(t *testing.T) {
 		Execute: func(context.Context, string) (string, error) { return "remembered", nil },
 	}
 
-	_, err = chatWithTools(context.Background(), session, agent, "first", []modules.Def{def}, nil, nil, nil, nil)
+	_, err = chatWithTools(context.Background(), session, agent, "first", nil, []modules.Def{def}, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	_, err = chatWithTools(context.Background(), session, agent, "second", []modules.Def{def}, nil, nil, nil, nil)
+	_, err = chatWithTools(context.Background(), session, agent, "second", nil, []modules.Def{def}, nil, nil, nil, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
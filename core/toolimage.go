@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+	"github.com/google/uuid"
+	"github.com/openai/openai-go"
+)
+
+const toolImageTurnBytes = 10 << 20
+
+func toolImageURL(image modules.ToolImage) string {
+	return "data:" + image.MediaType + ";base64," + base64.StdEncoding.EncodeToString(image.Data)
+}
+
+func toolImagesSave(sessionId string, record *ToolCall) {
+	var image modules.ToolImage
+
+	var err error
+
+	if sessionId == "" || record.Id == "" {
+		return
+	}
+
+	for _, image = range record.Images {
+		_, err = util.DB.Exec(`INSERT INTO artifacts (id, session_id, tool_call_id, name, content, media_type, data)
+			VALUES (?, ?, ?, ?, '', ?, ?);`, uuid.NewString(), sessionId, record.Id, record.Name, image.MediaType, image.Data)
+		if err != nil {
+			util.Log.Warn("storing a tool image failed, later turns will not see it",
+				"tool", record.Name, "bytes", len(image.Data), "error", err)
+		}
+	}
+}
+
+func toolImagesLoad(sessionId, messageId string, byId map[string]*ToolCall) error {
+	var rows *sql.Rows
+	var toolCallId string
+	var image modules.ToolImage
+	var call *ToolCall
+	var ok bool
+
+	var err error
+
+	if len(byId) == 0 {
+		return nil
+	}
+
+	rows, err = util.DB.Query(`SELECT a.tool_call_id, a.media_type, a.data FROM artifacts a JOIN tool_calls t ON t.id = a.tool_call_id
+		WHERE a.session_id = ? AND t.message_id = ? AND a.media_type != '' ORDER BY a.rowid ASC;`, sessionId, messageId)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		image = modules.ToolImage{}
+		err = rows.Scan(&toolCallId, &image.MediaType, &image.Data)
+		if err != nil {
+			return err
+		}
+
+		call, ok = byId[toolCallId]
+		if !ok {
+			continue
+		}
+
+		call.Images = append(call.Images, image)
+	}
+
+	return rows.Err()
+}
+
+func toolImageMessage(calls []*ToolCall) (openai.ChatCompletionMessageParamUnion, bool) {
+	var parts []openai.ChatCompletionContentPartUnionParam
+	var call *ToolCall
+	var image modules.ToolImage
+
+	for _, call = range calls {
+		if len(call.Images) == 0 {
+			continue
+		}
+
+		parts = append(parts, openai.TextContentPart(fmt.Sprintf("Images returned by %s (%s):", call.Name, call.CallId)))
+		for _, image = range call.Images {
+			parts = append(parts, openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{
+				URL: toolImageURL(image), Detail: "auto",
+			}))
+		}
+	}
+
+	if len(parts) == 0 {
+		return openai.ChatCompletionMessageParamUnion{}, false
+	}
+
+	return openai.UserMessage(parts), true
+}
+
+func anthropicToolResult(toolUseId string, record *ToolCall) anthropic.ContentBlockParamUnion {
+	var block anthropic.ToolResultBlockParam
+	var image modules.ToolImage
+
+	if len(record.Images) == 0 {
+		return anthropic.NewToolResultBlock(toolUseId, record.Result, record.Status != MessageCompleted)
+	}
+
+	block = anthropic.ToolResultBlockParam{ToolUseID: toolUseId, IsError: anthropic.Bool(record.Status != MessageCompleted)}
+	block.Content = append(block.Content, anthropic.ToolResultBlockParamContentUnion{OfText: &anthropic.TextBlockParam{Text: record.Result}})
+	for _, image = range record.Images {
+		block.Content = append(block.Content, anthropic.ToolResultBlockParamContentUnion{
+			OfImage: anthropic.NewImageBlockBase64(image.MediaType, base64.StdEncoding.EncodeToString(image.Data)).OfImage,
+		})
+	}
+
+	return anthropic.ContentBlockParamUnion{OfToolResult: &block}
+}
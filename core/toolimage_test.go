@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+func toolImageServer(requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+
+		body, _ = io.ReadAll(r.Body)
+		*requests = append(*requests, string(body))
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		if len(*requests) == 1 {
+			io.WriteString(w, toolChunk("r1", `{"role":"assistant","tool_calls":[{"index":0,"id":"call-1","type":"function","function":{"name":"snap","arguments":"{}"}}]}`, `"tool_calls"`))
+		} else {
+			io.WriteString(w, toolChunk("r2", `{"role":"assistant","content":"a chart"}`, `"stop"`))
+		}
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+}
+
+func snapTool() modules.Def {
+	return modules.Def{
+		Name: "snap", Permission: modules.PermissionSafe, Parameters: map[string]any{"type": "object"},
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			return modules.ToolImageAttach(ctx, []byte(attachmentPNG))
+		},
+	}
+}
+
+func TestToolImagesReachTheModelAndReplay(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var history []*Message
+	var calls []*ToolCall
+	var mediaType string
+
+	var err error
+
+	srv = toolImageServer(&requests)
+	defer srv.Close()
+
+	session, agent = thinkingSetup(t, srv.URL)
+	_, err = chatWithTools(context.Background(), session, agent, "take a picture", nil, []modules.Def{snapTool()}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(requests[1], "data:image/png;base64,") || !strings.Contains(requests[1], "Images returned by snap") {
+		t.Fatal("the tool image was not sent to the model in the next round")
+	}
+
+	history, err = MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls, err = ToolCallList(history[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(calls[0].Result, "base64") || !strings.Contains(calls[0].Result, "image 1 attached") {
+		t.Fatalf("stored result = %q, want a note without the image data", calls[0].Result)
+	}
+
+	err = util.DB.QueryRow("SELECT media_type FROM artifacts WHERE tool_call_id = ?;", calls[0].Id).Scan(&mediaType)
+	if err != nil || mediaType != "image/png" {
+		t.Fatalf("image artifact = %q, %v", mediaType, err)
+	}
+
+	requests = requests[:1]
+	_, err = chatWithTools(context.Background(), session, agent, "and now?", nil, []modules.Def{snapTool()}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(requests[1], "data:image/png;base64,") {
+		t.Fatal("replayed history lost the tool image")
+	}
+}
+
+func TestOnlyTheLatestToolTurnReplaysImages(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var calls map[string][]*ToolCall
+	var turn []*ToolCall
+	var images int
+
+	var err error
+
+	srv = toolImageServer(&requests)
+	defer srv.Close()
+
+	session, agent = thinkingSetup(t, srv.URL)
+	_, err = chatWithTools(context.Background(), session, agent, "take a picture", nil, []modules.Def{snapTool()}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests = nil
+	_, err = chatWithTools(context.Background(), session, agent, "take another", nil, []modules.Def{snapTool()}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls, err = toolCallsBySession(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, turn = range calls {
+		images += len(turn[0].Images)
+	}
+	if len(calls) != 2 || images != 1 {
+		t.Fatalf("loaded %d images across %d tool turns, want only the latest turn's", images, len(calls))
+	}
+
+	requests = requests[:1]
+	_, err = chatWithTools(context.Background(), session, agent, "compare them", nil, []modules.Def{snapTool()}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(requests[1], "data:image/png;base64,") != 1 || strings.Count(requests[1], "Images returned by snap") != 1 {
+		t.Fatalf("replay sent %d images, want only the latest tool turn's", strings.Count(requests[1], "data:image/png;base64,"))
+	}
+}
+
+func TestTextOnlyAgentsGetNoToolImages(t *testing.T) {
+	var requests []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var vision bool
+	var count int
+
+	var err error
+
+	srv = toolImageServer(&requests)
+	defer srv.Close()
+
+	session, agent = thinkingSetup(t, srv.URL)
+	agent.Vision = &vision
+	_, err = chatWithTools(context.Background(), session, agent, "take a picture", nil, []modules.Def{snapTool()}, nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(requests[1], "data:image") || !strings.Contains(requests[1], "cannot take images") {
+		t.Fatal("a text-only agent was sent a tool image")
+	}
+
+	err = util.DB.QueryRow("SELECT COUNT(*) FROM artifacts WHERE session_id = ?;", session.Id).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatal("an image was stored for a text-only agent")
+	}
+}
+
+func TestAnthropicToolResultsCarryImages(t *testing.T) {
+	var record ToolCall
+	var buf []byte
+
+	var err error
+
+	record = ToolCall{Result: "[image 1 attached]", Status: MessageCompleted,
+		Images: []modules.ToolImage{{MediaType: "image/png", Data: []byte(attachmentPNG)}}}
+	buf, err = json.Marshal(anthropicToolResult("toolu_1", &record))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(buf), `"type":"image"`) || !strings.Contains(string(buf), `"media_type":"image/png"`) ||
+		!strings.Contains(string(buf), `"tool_use_id":"toolu_1"`) {
+		t.Fatalf("tool result block = %s", buf)
+	}
+}
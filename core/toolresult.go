@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+const ToolResultToolName = "tool_result"
+
+const (
+	elideMinChars       = 512
+	toolResultPageChars = 8000
+	toolResultMaxChars  = 32000
+)
+
+var toolResultToolInstalled bool
+
+func elidedResult(call *ToolCall) string {
+	return fmt.Sprintf("[%d characters of %s output were elided to save context. Call %s with id %q to read them again.]",
+		utf8.RuneCountInString(call.Result), call.Name, ToolResultToolName, call.Id)
+}
+
+func elideCalls(history []*Message, calls map[string][]*ToolCall, throughMessageId string) map[string][]*ToolCall {
+	var elided map[string][]*ToolCall
+	var id string
+	var turn []*ToolCall
+	var message *Message
+	var stubbed []*ToolCall
+	var index int
+	var call *ToolCall
+
+	if throughMessageId == "" || len(calls) == 0 {
+		return calls
+	}
+
+	elided = make(map[string][]*ToolCall, len(calls))
+	for id, turn = range calls {
+		elided[id] = turn
+	}
+
+	for _, message = range history {
+		turn = calls[message.Id]
+		if len(turn) > 0 {
+			stubbed = make([]*ToolCall, len(turn))
+			for index = range turn {
+				call = new(ToolCall)
+				*call = *turn[index]
+				if call.Id != "" && utf8.RuneCountInString(call.Result) > elideMinChars {
+					call.Result = elidedResult(turn[index])
+				}
+				if len(call.Images) > 0 {
+					call.Result += fmt.Sprintf("\n[%d image(s) from this call were dropped to save context]", len(call.Images))
+					call.Images = nil
+				}
+				stubbed[index] = call
+			}
+			elided[message.Id] = stubbed
+		}
+
+		if message.Id == throughMessageId {
+			return elided
+		}
+	}
+
+	return calls
+}
+
+func toolResultLoad(sessionId, id string) (string, error) {
+	var result string
+
+	var err error
+
+	err = util.DB.QueryRow(`SELECT t.result FROM tool_calls t JOIN messages m ON m.id = t.message_id
+		WHERE t.id = ? AND m.session_id = ?;`, id, sessionId).Scan(&result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("no tool result %s in this conversation", id)
+	}
+
+	return result, err
+}
+
+func ToolResultTool() modules.Def {
+	return modules.Def{
+		Name: ToolResultToolName,
+		Description: "Read back a tool result that was elided from the conversation to save context. " +
+			"Pass the id from the elision note, and page through long results with offset and limit in characters.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":     map[string]any{"type": "string"},
+				"offset": map[string]any{"type": "integer", "minimum": 0},
+				"limit":  map[string]any{"type": "integer", "minimum": 1, "maximum": toolResultMaxChars},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+		Permission: modules.PermissionSafe,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Id     string `json:"id"`
+				Offset int    `json:"offset"`
+				Limit  int    `json:"limit"`
+			}
+			var policy subagentPolicy
+			var ok bool
+			var result string
+			var runes []rune
+			var end int
+
+			var err error
+
+			if err = ctx.Err(); err != nil {
+				return "", err
+			}
+
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+
+			payload.Id = strings.TrimSpace(payload.Id)
+			if payload.Id == "" {
+				return "", fmt.Errorf("id is required")
+			}
+			if payload.Offset < 0 {
+				return "", fmt.Errorf("offset must not be negative")
+			}
+			if payload.Limit <= 0 {
+				payload.Limit = toolResultPageChars
+			}
+			payload.Limit = min(payload.Limit, toolResultMaxChars)
+
+			policy, ok = subagentPolicyFrom(ctx)
+			if !ok || policy.SessionId == "" {
+				return "", fmt.Errorf("%s is only available inside a chat session", ToolResultToolName)
+			}
+
+			result, err = toolResultLoad(policy.SessionId, payload.Id)
+			if err != nil {
+				return "", err
+			}
+
+			runes = []rune(result)
+			if payload.Offset >= len(runes) {
+				return "", fmt.Errorf("offset %d is past the end of the %d character result", payload.Offset, len(runes))
+			}
+
+			end = min(payload.Offset+payload.Limit, len(runes))
+			if payload.Offset == 0 && end == len(runes) {
+				return result, nil
+			}
+			if end == len(runes) {
+				return fmt.Sprintf("%s\n\n[characters %d-%d of %d]", string(runes[payload.Offset:end]), payload.Offset, end, len(runes)), nil
+			}
+
+			return fmt.Sprintf("%s\n\n[characters %d-%d of %d; pass offset %d to continue]",
+				string(runes[payload.Offset:end]), payload.Offset, end, len(runes), end), nil
+		},
+	}
+}
+
+func InstallToolResultTool() {
+	if toolResultToolInstalled {
+		return
+	}
+
+	toolResultToolInstalled = true
+
+	modules.RegisterBuiltin(ToolResultTool, modules.BuiltinHints{
+		Title:       "read an elided tool result",
+		ReadOnly:    true,
+		Destructive: false,
+		OpenWorld:   false,
+	})
+}
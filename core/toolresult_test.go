@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestElideCallsStubsOnlyLongResultsUpToTheMarker(t *testing.T) {
+	var history []*Message
+	var calls map[string][]*ToolCall
+	var elided map[string][]*ToolCall
+
+	history = []*Message{{Id: "u1", Role: "user"}, {Id: "a1", Role: "assistant"}, {Id: "u2", Role: "user"}}
+	calls = map[string][]*ToolCall{
+		"u1": {{Id: "t1", Name: "file_read", Result: strings.Repeat("x", elideMinChars+1)}, {Id: "t2", Name: "echo", Result: "short"}},
+		"u2": {{Id: "t3", Name: "file_read", Result: strings.Repeat("y", elideMinChars+1)}},
+	}
+
+	elided = elideCalls(history, calls, "a1")
+	if !strings.Contains(elided["u1"][0].Result, `"t1"`) || elided["u1"][1].Result != "short" {
+		t.Fatalf("first turn = %q / %q, want only the long result stubbed", elided["u1"][0].Result, elided["u1"][1].Result)
+	}
+	if elided["u2"][0].Result != calls["u2"][0].Result {
+		t.Fatal("a result after the marker was elided")
+	}
+	if calls["u1"][0].Result != strings.Repeat("x", elideMinChars+1) {
+		t.Fatal("eliding changed the loaded calls in place")
+	}
+
+	if elideCalls(history, calls, "missing")["u1"][0].Result != calls["u1"][0].Result {
+		t.Fatal("a marker that is gone from the history elided results")
+	}
+}
+
+func TestToolResultReadsBackWithinTheSession(t *testing.T) {
+	var session *Session
+	var agent *NaruAgent
+	var other *Session
+	var id string
+	var ctx context.Context
+	var out string
+
+	var err error
+
+	session, agent = thinkingSetup(t, "http://127.0.0.1")
+	id = seedToolTurn(t, session.Id, strings.Repeat("a", 10)+strings.Repeat("b", 10))
+
+	ctx = subagentContext(context.Background(), subagentPolicy{SessionId: session.Id})
+
+	out, err = ToolResultTool().Execute(ctx, fmt.Sprintf(`{"id":%q}`, id))
+	if err != nil || out != strings.Repeat("a", 10)+strings.Repeat("b", 10) {
+		t.Fatalf("out = %q err = %v, want the whole result", out, err)
+	}
+
+	out, err = ToolResultTool().Execute(ctx, fmt.Sprintf(`{"id":%q,"offset":5,"limit":10}`, id))
+	if err != nil || !strings.HasPrefix(out, "aaaaabbbbb\n\n") || !strings.Contains(out, "pass offset 15") {
+		t.Fatalf("out = %q err = %v, want the middle page and a continuation hint", out, err)
+	}
+
+	other, err = SessionCreate(agent, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ToolResultTool().Execute(subagentContext(context.Background(), subagentPolicy{SessionId: other.Id}),
+		fmt.Sprintf(`{"id":%q}`, id))
+	if err == nil {
+		t.Fatal("another session read this session's tool result")
+	}
+
+	_, err = ToolResultTool().Execute(context.Background(), fmt.Sprintf(`{"id":%q}`, id))
+	if err == nil {
+		t.Fatal("tool_result ran outside a chat session")
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/devproje/mininaru/modules"
+	"github.com/devproje/mininaru/util"
+)
+
+func TurnMessage(sessionId, messageId string) (string, string, error) {
+	var owner string
+	var role string
+	var position int64
+	var turn string
+
+	var err error
+
+	err = util.DB.QueryRow("SELECT session_id, role, rowid FROM messages WHERE id = ?;", messageId).Scan(&owner, &role, &position)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", fmt.Errorf("message id %s not found", messageId)
+		}
+
+		return "", "", err
+	}
+	if sessionId != "" && owner != sessionId {
+		return "", "", fmt.Errorf("message id %s belongs to another session", messageId)
+	}
+	if role == "user" {
+		return owner, messageId, nil
+	}
+
+	err = util.DB.QueryRow(`SELECT id FROM messages WHERE session_id = ? AND role = 'user' AND rowid < ?
+		ORDER BY rowid DESC LIMIT 1;`, owner, position).Scan(&turn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", fmt.Errorf("message id %s has no turn before it", messageId)
+		}
+
+		return "", "", err
+	}
+
+	return owner, turn, nil
+}
+
+func UndoTurn(sessionId, messageId string) ([]string, error) {
+	var owner string
+	var turn string
+
+	var err error
+
+	owner, turn, err = TurnMessage(sessionId, messageId)
+	if err != nil {
+		return nil, err
+	}
+
+	return modules.CheckpointRestore(owner, turn)
+}
+
+func checkpointsPrune() {
+	var sessions []string
+	var sessionId string
+	var found bool
+
+	var err error
+
+	sessions, err = modules.CheckpointSessions()
+	if err != nil {
+		util.Log.Warn("could not list undo checkpoints", "error", err)
+		return
+	}
+	for _, sessionId = range sessions {
+		err = util.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM sessions WHERE id = ?);", sessionId).Scan(&found)
+		if err != nil || found {
+			continue
+		}
+
+		err = modules.CheckpointsDrop(sessionId)
+		if err != nil {
+			util.Log.Warn("could not drop undo checkpoints", "session", sessionId, "error", err)
+		}
+	}
+}
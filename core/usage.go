@@ -30,20 +30,30 @@ type UsageLine struct {
 	CacheWriteTokens int64  `json:"cache_write_tokens"`
 }
 
+type AgentUsage struct {
+	AgentId          string `json:"agent_id"`
+	Agent            string `json:"agent"`
+	PromptTokens     int64  `json:"prompt_tokens"`
+	CompletionTokens int64  `json:"completion_tokens"`
+	TotalTokens      int64  `json:"total_tokens"`
+}
+
 type UsageTotals struct {
-	SessionId        string      `json:"session_id"`
-	Lines            []UsageLine `json:"lines"`
-	PromptTokens     int64       `json:"prompt_tokens"`
-	CompletionTokens int64       `json:"completion_tokens"`
-	TotalTokens      int64       `json:"total_tokens"`
-	CachedTokens     int64       `json:"cached_tokens"`
-	CacheWriteTokens int64       `json:"cache_write_tokens"`
+	SessionId        string       `json:"session_id"`
+	Lines            []UsageLine  `json:"lines"`
+	Agents           []AgentUsage `json:"agents,omitempty"`
+	PromptTokens     int64        `json:"prompt_tokens"`
+	CompletionTokens int64        `json:"completion_tokens"`
+	TotalTokens      int64        `json:"total_tokens"`
+	CachedTokens     int64        `json:"cached_tokens"`
+	CacheWriteTokens int64        `json:"cache_write_tokens"`
 }
 
 const (
 	UsageTurn       = "turn"
 	UsageCompaction = "compaction"
 	UsageSubagent   = "subagent"
+	UsageModerator  = "moderator"
 )
 
 func cacheWriteTokens(usage openai.CompletionUsage) int64 {
@@ -73,7 +83,7 @@ func cacheWriteTokens(usage openai.CompletionUsage) int64 {
 	return value
 }
 
-func usageRecordWithContext(sessionId, messageId, kind string, usage TokenUsage, contextTokens, contextWindow int64) {
+func usageRecordWithContext(sessionId, messageId, agentId, kind string, usage TokenUsage, contextTokens, contextWindow int64) {
 	var err error
 
 	if sessionId == "" || usage.TotalTokens == 0 {
@@ -81,9 +91,9 @@ func usageRecordWithContext(sessionId, messageId, kind string, usage TokenUsage,
 	}
 
 	_, err = util.DB.Exec(`INSERT INTO token_usage
-		(id, session_id, message_id, kind, prompt_tokens, completion_tokens, total_tokens, context_tokens, context_window, cached_tokens, cache_write_tokens)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
-		uuid.NewString(), sessionId, messageId, kind,
+		(id, session_id, message_id, agent_id, kind, prompt_tokens, completion_tokens, total_tokens, context_tokens, context_window, cached_tokens, cache_write_tokens)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`,
+		uuid.NewString(), sessionId, messageId, agentId, kind,
 		usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens, contextTokens, contextWindow,
 		usage.CachedTokens, usage.CacheWriteTokens)
 	if err != nil {
@@ -92,44 +102,95 @@ func usageRecordWithContext(sessionId, messageId, kind string, usage TokenUsage,
 	}
 }
 
-func usageRecord(sessionId, messageId, kind string, usage TokenUsage) {
-	usageRecordWithContext(sessionId, messageId, kind, usage, 0, 0)
+func usageRecord(sessionId, messageId, agentId, kind string, usage TokenUsage) {
+	usageRecordWithContext(sessionId, messageId, agentId, kind, usage, 0, 0)
 }
 
-func SessionContextTokens(sessionId string) (int64, int64, bool, error) {
+func sessionContextMark(sessionId string) (int64, int64, string, bool, error) {
 	var tokens int64
 	var window int64
+	var messageId string
 
 	var err error
 
-	err = util.DB.QueryRow(`SELECT u.context_tokens, u.context_window
+	err = util.DB.QueryRow(`SELECT u.context_tokens, u.context_window, u.message_id
 		FROM token_usage u
 		JOIN messages m ON m.id = u.message_id
 		LEFT JOIN session_summaries s ON s.session_id = u.session_id
 		LEFT JOIN messages compacted ON compacted.id = s.through_message_id
 		WHERE u.session_id = ? AND u.kind = ?
 			AND (compacted.rowid IS NULL OR m.rowid > compacted.rowid)
-		ORDER BY u.rowid DESC LIMIT 1;`, sessionId, UsageTurn).Scan(&tokens, &window)
+		ORDER BY u.rowid DESC LIMIT 1;`, sessionId, UsageTurn).Scan(&tokens, &window, &messageId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			err = util.DB.QueryRow(`SELECT context_window FROM token_usage
 				WHERE session_id = ? AND kind = ? AND context_window > 0
 				ORDER BY rowid DESC LIMIT 1;`, sessionId, UsageTurn).Scan(&window)
 			if err == sql.ErrNoRows {
-				return 0, 0, false, nil
+				return 0, 0, "", false, nil
 			}
 			if err != nil {
-				return 0, 0, false, err
+				return 0, 0, "", false, err
 			}
-			return 0, window, false, nil
+			return 0, window, "", false, nil
 		}
-		return 0, 0, false, err
+		return 0, 0, "", false, err
 	}
 	if tokens <= 0 {
-		return 0, window, false, nil
+		return 0, window, "", false, nil
+	}
+
+	return tokens, window, messageId, true, nil
+}
+
+func SessionContextTokens(sessionId string) (int64, int64, bool, error) {
+	var tokens int64
+	var window int64
+	var known bool
+
+	var err error
+
+	tokens, window, _, known, err = sessionContextMark(sessionId)
+
+	return tokens, window, known, err
+}
+
+func sessionUsageByAgent(sessionId string) ([]AgentUsage, error) {
+	var rows *sql.Rows
+	var line AgentUsage
+	var agent *NaruAgent
+	var lines []AgentUsage
+
+	var err error
+
+	rows, err = util.DB.Query(`SELECT agent_id, SUM(prompt_tokens), SUM(completion_tokens), SUM(total_tokens)
+		FROM token_usage WHERE session_id = ? AND agent_id != '' GROUP BY agent_id ORDER BY MIN(rowid) ASC;`, sessionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&line.AgentId, &line.PromptTokens, &line.CompletionTokens, &line.TotalTokens)
+		if err != nil {
+			return nil, err
+		}
+
+		line.Agent = line.AgentId
+		agent = agentById(line.AgentId)
+		if agent != nil {
+			line.Agent = agent.Name
+		}
+
+		lines = append(lines, line)
 	}
 
-	return tokens, window, true, nil
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
 }
 
 func SessionUsage(sessionId string) (*UsageTotals, error) {
@@ -167,6 +228,11 @@ func SessionUsage(sessionId string) (*UsageTotals, error) {
 		return nil, err
 	}
 
+	totals.Agents, err = sessionUsageByAgent(sessionId)
+	if err != nil {
+		return nil, err
+	}
+
 	return &totals, nil
 }
 
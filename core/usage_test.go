@@ -230,8 +230,8 @@ func TestUsageIsNotRecordedWithoutASessionOrTokens(t *testing.T) {
 
 	_, _ = compactSetup(t, "http://127.0.0.1:1", true)
 
-	usageRecord("", "m1", UsageTurn, usageOf(10, 1))
-	usageRecord("s-nonexistent", "m1", UsageTurn, usageOf(0, 0))
+	usageRecord("", "m1", "", UsageTurn, usageOf(10, 1))
+	usageRecord("s-nonexistent", "m1", "", UsageTurn, usageOf(0, 0))
 
 	err = util.DB.QueryRow("SELECT COUNT(*) FROM token_usage;").Scan(&count)
 	if err != nil {
@@ -282,7 +282,7 @@ func TestSessionUsageIsRemovedWithItsSession(t *testing.T) {
 
 	session, _ = compactSetup(t, "http://127.0.0.1:1", true)
 
-	usageRecord(session.Id, "m1", UsageTurn, usageOf(10, 1))
+	usageRecord(session.Id, "m1", "", UsageTurn, usageOf(10, 1))
 
 	err = SessionDelete(session.Id)
 	if err != nil {
@@ -313,9 +313,9 @@ func TestSessionUsageAllGroupsBySession(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	usageRecord(first.Id, "", UsageTurn, usageOf(10, 1))
-	usageRecord(first.Id, "", UsageCompaction, usageOf(20, 2))
-	usageRecord(second.Id, "", UsageTurn, usageOf(30, 3))
+	usageRecord(first.Id, "", "", UsageTurn, usageOf(10, 1))
+	usageRecord(first.Id, "", "", UsageCompaction, usageOf(20, 2))
+	usageRecord(second.Id, "", "", UsageTurn, usageOf(30, 3))
 
 	totals, err = SessionUsageAll(agent.Id)
 	if err != nil {
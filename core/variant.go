@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/devproje/mininaru/config"
+	"github.com/devproje/mininaru/modules"
+)
+
+func retryTarget(session *Session, history []*Message) (int, error) {
+	var index int
+	var summary *Summary
+	var later int
+
+	var err error
+
+	if session.TurnMode != "" {
+		return 0, fmt.Errorf("a roundtable turn cannot be regenerated or edited")
+	}
+
+	for index = len(history) - 1; index >= 0; index-- {
+		if history[index].Role == "user" {
+			break
+		}
+	}
+	if index < 0 {
+		return 0, fmt.Errorf("session %s has no answer to regenerate yet", session.Id)
+	}
+
+	summary, err = SummaryLoad(session.Id)
+	if err != nil {
+		return 0, err
+	}
+	if summary == nil || summary.ThroughMessageId == "" {
+		return index, nil
+	}
+
+	for later = index; later < len(history); later++ {
+		if history[later].Id == summary.ThroughMessageId {
+			return 0, fmt.Errorf("the last turn is already folded into the summary and can no longer be replaced")
+		}
+	}
+
+	return index, nil
+}
+
+func chatRetry(ctx context.Context, session *Session, agent *NaruAgent, content string, defs []modules.Def, thinking string,
+	onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc, allowDangerous bool) (*Message, error) {
+	var history []*Message
+	var index int
+	var replaced *Message
+
+	var err error
+
+	err = chatReady(session, agent)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err = chatHistory(session.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err = retryTarget(session, history)
+	if err != nil {
+		return nil, err
+	}
+
+	replaced = history[index]
+	if content == "" {
+		content = replaced.Content
+	}
+
+	return chatTurn(ctx, session, agent, content, replaced.Attachments, history[:index], replaced, defs, thinking,
+		onContent, onReasoning, onTool, approve, allowDangerous)
+}
+
+func ChatRetry(ctx context.Context, session *Session, agent *NaruAgent, content string,
+	onContent, onReasoning func(string), onTool ToolEventFunc, approve ToolApprovalFunc) (*Message, error) {
+	var defs []modules.Def
+
+	if config.Client.Tools.Enabled {
+		defs = modules.DefaultTools()
+	}
+
+	return chatRetry(ctx, session, agent, content, defs, config.Client.Thinking.Level, onContent, onReasoning, onTool, approve,
+		config.AllowDangerousTools)
+}
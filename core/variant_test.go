@@ -0,0 +1,144 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package core
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/util"
+)
+
+func TestChatRetryKeepsEarlierTurnAsVariant(t *testing.T) {
+	var requests int
+	var bodies []string
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var first, retried, edited *Message
+	var messages []*Message
+	var hidden, linked int
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+
+		body, _ = io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		requests++
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, toolChunk("r", `{"role":"assistant","content":"answer `+strconv.Itoa(requests)+`"}`, `"stop"`))
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	session, agent = compactSetup(t, srv.URL, false)
+
+	_, err = Chat(context.Background(), session, agent, "warm up", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err = Chat(context.Background(), session, agent, "question", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	retried, err = ChatRetry(context.Background(), session, agent, "", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if retried.Content != "answer 3" || retried.Id == first.Id {
+		t.Fatalf("regenerated answer = %+v", retried)
+	}
+	if strings.Contains(bodies[2], "answer 2") || !strings.Contains(bodies[2], "question") {
+		t.Fatalf("regenerate replayed the replaced turn: %s", bodies[2])
+	}
+
+	edited, err = ChatRetry(context.Background(), session, agent, "better question", nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if edited.Content != "answer 4" {
+		t.Fatalf("edited answer = %+v", edited)
+	}
+
+	messages, err = MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 4 || messages[2].Content != "better question" || messages[3].Content != "answer 4" {
+		t.Fatalf("selected history = %+v", messages)
+	}
+
+	err = util.DB.QueryRow("SELECT COUNT(*) FROM messages WHERE session_id = ? AND selected = 0;", session.Id).Scan(&hidden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hidden != 4 {
+		t.Fatalf("kept %d earlier variant rows, want 4", hidden)
+	}
+
+	err = util.DB.QueryRow("SELECT COUNT(*) FROM messages WHERE session_id = ? AND role = 'user' AND variant_of = ?;",
+		session.Id, messages[2].VariantOf).Scan(&linked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if messages[2].VariantOf == "" || linked != 2 {
+		t.Fatalf("variant_of = %q links %d user rows, want 2", messages[2].VariantOf, linked)
+	}
+}
+
+func TestChatRetryRefusesWithoutTurnOrPastSummary(t *testing.T) {
+	var srv *httptest.Server
+	var session *Session
+	var agent *NaruAgent
+	var messages []*Message
+
+	var err error
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, toolChunk("r", `{"role":"assistant","content":"answer"}`, `"stop"`))
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	session, agent = compactSetup(t, srv.URL, false)
+
+	_, err = ChatRetry(context.Background(), session, agent, "", nil, nil, nil, nil)
+	if err == nil {
+		t.Fatal("an empty session was regenerated")
+	}
+
+	_, err = Chat(context.Background(), session, agent, "question", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err = MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = SummarySave(session.Id, "summary", messages[len(messages)-1].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ChatRetry(context.Background(), session, agent, "", nil, nil, nil, nil)
+	if err == nil {
+		t.Fatal("a turn folded into the summary was regenerated")
+	}
+
+	session.TurnMode = "sequential"
+	_, err = ChatRetry(context.Background(), session, agent, "", nil, nil, nil, nil)
+	if err == nil {
+		t.Fatal("a roundtable turn was regenerated")
+	}
+}
@@ -82,15 +82,19 @@ func bashShell() (string, error) {
 
 func BashExec(root string) Def {
 	return Def{
-		Name:        "bash_exec",
-		Description: "Execute a Bash command in the process startup directory. Use file_read, file_edit, and file_write for file contents; in-place sed edits are rejected.",
+		Name: "bash_exec",
+		Description: "Execute a Bash command in the process startup directory. Each call starts a fresh shell unless persistent is set, " +
+			"which runs it in this session's long-lived shell so cd, export, and activated virtualenvs carry over to the next persistent call; " +
+			"reset discards that shell first. Use file_read, file_edit, file_patch, and file_write for file contents and git or git_write for the repository; " +
+			"in-place sed edits are rejected.",
 		Parameters: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
 				"command":         map[string]any{"type": "string"},
 				"timeout_seconds": map[string]any{"type": "integer", "minimum": 1, "maximum": maxBashTimeout},
+				"persistent":      map[string]any{"type": "boolean"},
+				"reset":           map[string]any{"type": "boolean", "description": "Kill this session's persistent shell before running command, or on its own."},
 			},
-			"required":             []string{"command"},
 			"additionalProperties": false,
 		},
 		Permission: PermissionDangerous,
@@ -98,12 +102,15 @@ func BashExec(root string) Def {
 			var payload struct {
 				Command        string `json:"command"`
 				TimeoutSeconds int    `json:"timeout_seconds"`
+				Persistent     bool   `json:"persistent"`
+				Reset          bool   `json:"reset"`
 			}
 			var workingDir string
 			var shell string
 			var commandCtx context.Context
 			var cancel context.CancelFunc
 			var command *exec.Cmd
+			var writer *toolProgressWriter
 			var output []byte
 
 			var err error
@@ -112,11 +119,11 @@ func BashExec(root string) Def {
 			if err != nil {
 				return "", fmt.Errorf("invalid arguments: %w", err)
 			}
-			if payload.Command == "" {
+			if payload.Command == "" && !payload.Reset {
 				return "", fmt.Errorf("command is required")
 			}
 			if bashUsesInPlaceSed(payload.Command) {
-				return "", fmt.Errorf("in-place sed edits are not allowed; inspect with file_read, then use file_edit or file_write")
+				return "", fmt.Errorf("in-place sed edits are not allowed; inspect with file_read, then use file_edit, file_patch, or file_write")
 			}
 			if payload.TimeoutSeconds <= 0 {
 				payload.TimeoutSeconds = defaultBashTimeout
@@ -128,6 +135,15 @@ func BashExec(root string) Def {
 			if err != nil {
 				return "", err
 			}
+			if payload.Reset {
+				bashShellDrop(ToolSessionFrom(ctx), workingDir, nil)
+				if payload.Command == "" {
+					return "persistent shell reset", nil
+				}
+			}
+			if payload.Persistent {
+				return bashShellExecute(ctx, ToolSessionFrom(ctx), workingDir, ToolSandboxFrom(ctx), payload.Command, payload.TimeoutSeconds)
+			}
 			shell, err = bashShell()
 			if err != nil {
 				return "", err
@@ -137,11 +153,19 @@ func BashExec(root string) Def {
 			defer cancel()
 			command = exec.CommandContext(commandCtx, shell, "-lc", payload.Command)
 			command.Dir = workingDir
+			writer = &toolProgressWriter{limit: maxBashOutput + 1, progress: toolProgressFrom(ctx)}
+			command.Stdout = writer
+			command.Stderr = writer
 			command.WaitDelay = bashWaitDelay
 			command.Cancel = func() error { return bashTerminate(command) }
 			bashIsolate(command)
+			err = bashConfine(command, workingDir, ToolSandboxFrom(ctx))
+			if err != nil {
+				return "", err
+			}
 
-			output, err = command.CombinedOutput()
+			err = command.Run()
+			output = writer.output
 			if len(output) > maxBashOutput {
 				output = append(output[:maxBashOutput], []byte("\n[truncated]")...)
 			}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -113,3 +114,37 @@ func TestBashExecAllowsReadOnlySed(t *testing.T) {
 		t.Fatalf("result = %q, want read-only sed output", result)
 	}
 }
+
+func TestBashExecStreamsOutputAsItRuns(t *testing.T) {
+	var streamed strings.Builder
+	var streamedMu sync.Mutex
+	var ctx context.Context
+	var arguments string
+	var result string
+
+	var err error
+
+	ctx = ToolProgressContext(ToolSessionContext(context.Background(), "session-a"), func(chunk string) {
+		streamedMu.Lock()
+		streamed.WriteString(chunk)
+		streamedMu.Unlock()
+	})
+	t.Cleanup(BashShellsClose)
+
+	for _, arguments = range []string{`{"command":"echo one; echo two >&2"}`, `{"command":"echo one; echo two >&2","persistent":true}`} {
+		streamedMu.Lock()
+		streamed.Reset()
+		streamedMu.Unlock()
+
+		result, err = BashExec(t.TempDir()).Execute(ctx, arguments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		streamedMu.Lock()
+		if !strings.HasSuffix(result, "one\ntwo\n") || !strings.HasSuffix(streamed.String(), "one\ntwo\n") {
+			t.Fatalf("%s: result = %q, streamed = %q", arguments, result, streamed.String())
+		}
+		streamedMu.Unlock()
+	}
+}
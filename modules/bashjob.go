@@ -0,0 +1,613 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type BashJob struct {
+	Id      string
+	Command string
+	Pid     int
+	Started time.Time
+	Running bool
+	Exit    string
+}
+
+type bashJob struct {
+	id      string
+	owner   string
+	command string
+	started time.Time
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	done    chan struct{}
+
+	mu      sync.Mutex
+	output  []byte
+	dropped int
+	updated chan struct{}
+	exit    string
+}
+
+type toolSessionKey struct{}
+
+const BashOutputToolName = "bash_output"
+
+const maxBashJobs = 8
+const maxBashJobOutput = 1 << 20
+const maxBashJobWait = 30
+
+const bashJobTail = 4096
+
+const bashJobSettle = time.Second
+const bashJobKillWait = 5 * time.Second
+const bashJobLinger = 10 * time.Minute
+
+var bashJobs map[string]*bashJob = make(map[string]*bashJob)
+
+var bashJobsMu sync.Mutex
+
+var bashJobNext atomic.Uint64
+
+func ToolSessionContext(ctx context.Context, sessionId string) context.Context {
+	return context.WithValue(ctx, toolSessionKey{}, sessionId)
+}
+
+func ToolSessionFrom(ctx context.Context) string {
+	var sessionId string
+
+	sessionId, _ = ctx.Value(toolSessionKey{}).(string)
+
+	return sessionId
+}
+
+func (j *bashJob) Write(p []byte) (int, error) {
+	var excess int
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.output = append(j.output, p...)
+	excess = len(j.output) - maxBashJobOutput
+	if excess > 0 {
+		j.output = append([]byte(nil), j.output[excess:]...)
+		j.dropped += excess
+	}
+
+	close(j.updated)
+	j.updated = make(chan struct{})
+
+	return len(p), nil
+}
+
+func (j *bashJob) info() BashJob {
+	var info BashJob
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	info = BashJob{Id: j.id, Command: j.command, Started: j.started, Exit: j.exit}
+	info.Pid = j.cmd.Process.Pid
+	select {
+	case <-j.done:
+	default:
+		info.Running = true
+	}
+
+	return info
+}
+
+func (j *bashJob) wait() {
+	var err error
+	var exit string
+
+	err = j.cmd.Wait()
+	exit = "exit status 0"
+	if err != nil {
+		exit = err.Error()
+	}
+
+	j.mu.Lock()
+	j.exit = exit
+	close(j.updated)
+	j.updated = make(chan struct{})
+	j.mu.Unlock()
+
+	close(j.done)
+	time.AfterFunc(bashJobLinger, j.forget)
+}
+
+func (j *bashJob) forget() {
+	bashJobsMu.Lock()
+	if bashJobs[j.id] == j {
+		delete(bashJobs, j.id)
+	}
+	bashJobsMu.Unlock()
+}
+
+func (j *bashJob) kill() {
+	bashTerminate(j.cmd)
+
+	select {
+	case <-j.done:
+	case <-time.After(bashJobKillWait):
+	}
+}
+
+func (j *bashJob) read(offset int) (string, int, int) {
+	var start int
+	var end int
+	var skipped int
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	start = offset
+	if start < j.dropped {
+		skipped = j.dropped - start
+		start = j.dropped
+	}
+	end = min(j.dropped+len(j.output), start+maxBashOutput)
+	if start > end {
+		start = end
+	}
+
+	return string(j.output[start-j.dropped : end-j.dropped]), end, skipped
+}
+
+func (j *bashJob) status(next int) string {
+	var info BashJob
+	var total int
+
+	info = j.info()
+	j.mu.Lock()
+	total = j.dropped + len(j.output)
+	j.mu.Unlock()
+
+	if info.Running {
+		return fmt.Sprintf("job %s (pid %d) running for %s, %d bytes of output, next offset %d",
+			info.Id, info.Pid, time.Since(info.Started).Round(time.Second), total, next)
+	}
+
+	return fmt.Sprintf("job %s (pid %d) finished with %s, %d bytes of output, next offset %d", info.Id, info.Pid, info.Exit, total, next)
+}
+
+func (j *bashJob) drained(next int) bool {
+	select {
+	case <-j.done:
+	default:
+		return false
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return next == j.dropped+len(j.output)
+}
+
+func bashJobFor(owner, id string) (*bashJob, error) {
+	var job *bashJob
+
+	if owner == "" {
+		return nil, fmt.Errorf("background jobs are only available inside a chat session")
+	}
+
+	bashJobsMu.Lock()
+	defer bashJobsMu.Unlock()
+
+	job = bashJobs[strings.TrimSpace(id)]
+	if job == nil || job.owner != owner {
+		return nil, fmt.Errorf("no background job %q in this session, bash_start returns the id", id)
+	}
+
+	return job, nil
+}
+
+func BashJobs(owner string) []BashJob {
+	var jobs []BashJob
+	var job *bashJob
+
+	bashJobsMu.Lock()
+	for _, job = range bashJobs {
+		if job.owner == owner {
+			jobs = append(jobs, job.info())
+		}
+	}
+	bashJobsMu.Unlock()
+
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Started.Before(jobs[k].Started)
+	})
+
+	return jobs
+}
+
+func BashJobKill(owner, id string) (BashJob, error) {
+	var job *bashJob
+
+	var err error
+
+	job, err = bashJobFor(owner, id)
+	if err != nil {
+		return BashJob{}, err
+	}
+
+	job.kill()
+
+	bashJobsMu.Lock()
+	delete(bashJobs, job.id)
+	bashJobsMu.Unlock()
+
+	return job.info(), nil
+}
+
+func BashJobsClose() {
+	var job *bashJob
+	var ended []*bashJob
+
+	bashJobsMu.Lock()
+	for _, job = range bashJobs {
+		ended = append(ended, job)
+	}
+	bashJobs = make(map[string]*bashJob)
+	bashJobsMu.Unlock()
+
+	for _, job = range ended {
+		job.kill()
+	}
+}
+
+func BashJobsCloseOwner(owner string) {
+	var job *bashJob
+	var ended []*bashJob
+
+	bashJobsMu.Lock()
+	for _, job = range bashJobs {
+		if job.owner == owner {
+			ended = append(ended, job)
+			delete(bashJobs, job.id)
+		}
+	}
+	bashJobsMu.Unlock()
+
+	for _, job = range ended {
+		job.kill()
+	}
+}
+
+func bashJobStart(owner, root, sandbox, command string) (*bashJob, error) {
+	var workingDir string
+	var shell string
+	var job *bashJob
+	var running int
+	var current *bashJob
+
+	var err error
+
+	if owner == "" {
+		return nil, fmt.Errorf("background jobs are only available inside a chat session")
+	}
+	workingDir, err = toolRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	shell, err = bashShell()
+	if err != nil {
+		return nil, err
+	}
+
+	bashJobsMu.Lock()
+	defer bashJobsMu.Unlock()
+
+	for _, current = range bashJobs {
+		if current.owner == owner && current.info().Running {
+			running++
+		}
+	}
+	if running >= maxBashJobs {
+		return nil, fmt.Errorf("this session already runs %d background jobs, stop one with bash_kill first", maxBashJobs)
+	}
+
+	job = &bashJob{
+		id: strconv.FormatUint(bashJobNext.Add(1), 10), owner: owner, command: command, started: time.Now(),
+		done: make(chan struct{}), updated: make(chan struct{}),
+	}
+	job.cmd = exec.Command(shell, "-lc", command)
+	job.cmd.Dir = workingDir
+	job.cmd.Stdout = job
+	job.cmd.Stderr = job
+	job.cmd.WaitDelay = bashWaitDelay
+	bashIsolate(job.cmd)
+	err = bashConfine(job.cmd, workingDir, sandbox)
+	if err != nil {
+		return nil, err
+	}
+
+	job.stdin, err = job.cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = job.cmd.Start()
+	if err != nil {
+		return nil, fmt.Errorf("start command: %w", err)
+	}
+
+	bashJobs[job.id] = job
+	go job.wait()
+
+	return job, nil
+}
+
+func bashJobWait(ctx context.Context, job *bashJob, offset int, wait time.Duration) {
+	var timer *time.Timer
+	var updated chan struct{}
+	var available bool
+
+	timer = time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		job.mu.Lock()
+		updated = job.updated
+		available = job.dropped+len(job.output) > offset || job.exit != ""
+		job.mu.Unlock()
+		if available {
+			return
+		}
+
+		select {
+		case <-updated:
+		case <-job.done:
+			return
+		case <-timer.C:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func bashJobReport(job *bashJob, offset int) string {
+	var output string
+	var next int
+	var skipped int
+	var body strings.Builder
+
+	output, next, skipped = job.read(offset)
+	body.WriteString(job.status(next))
+	if job.drained(next) {
+		job.forget()
+		body.WriteString("\n[the job has finished and all of its output has been read, so its id is forgotten]")
+	}
+	if skipped > 0 {
+		fmt.Fprintf(&body, "\n[%d earlier bytes were dropped, only the last %d are kept]", skipped, maxBashJobOutput)
+	}
+	if output != "" {
+		body.WriteString("\n\n")
+		body.WriteString(output)
+	}
+
+	return body.String()
+}
+
+func BashStart(root string) Def {
+	return Def{
+		Name: "bash_start",
+		Description: "Start a long-running Bash command, such as a dev server or a watcher, in the background in the process startup directory " +
+			"and return its job id with the output printed in the first second. Read more with bash_output, write to its stdin with bash_send, " +
+			"and stop it with bash_kill. Jobs belong to this session and are killed when mininaru exits. Use bash_exec for commands that finish.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"command": map[string]any{"type": "string"},
+			},
+			"required":             []string{"command"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionDangerous,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Command string `json:"command"`
+			}
+			var job *bashJob
+
+			var err error
+
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			if strings.TrimSpace(payload.Command) == "" {
+				return "", fmt.Errorf("command is required")
+			}
+			if bashUsesInPlaceSed(payload.Command) {
+				return "", fmt.Errorf("in-place sed edits are not allowed; inspect with file_read, then use file_edit, file_patch, or file_write")
+			}
+
+			job, err = bashJobStart(ToolSessionFrom(ctx), root, ToolSandboxFrom(ctx), payload.Command)
+			if err != nil {
+				return "", err
+			}
+
+			select {
+			case <-job.done:
+			case <-time.After(bashJobSettle):
+			case <-ctx.Done():
+			}
+
+			return bashJobReport(job, 0), nil
+		},
+	}
+}
+
+func BashOutput() Def {
+	return Def{
+		Name: BashOutputToolName,
+		Description: "Read output of a background job from bash_start, starting at offset, which is 0 for everything and the next offset " +
+			"of the previous call for only what is new. wait_seconds waits for new output or for the job to end first. " +
+			"stdout and stderr are interleaved, and only the last 1 MiB is kept. A finished job is forgotten once its last output has been read.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":           map[string]any{"type": "string"},
+				"offset":       map[string]any{"type": "integer", "minimum": 0},
+				"wait_seconds": map[string]any{"type": "integer", "minimum": 0, "maximum": maxBashJobWait},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionSafe,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Id          string `json:"id"`
+				Offset      int    `json:"offset"`
+				WaitSeconds int    `json:"wait_seconds"`
+			}
+			var job *bashJob
+
+			var err error
+
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			if payload.Offset < 0 {
+				return "", fmt.Errorf("offset cannot be negative")
+			}
+			if payload.WaitSeconds > maxBashJobWait {
+				return "", fmt.Errorf("wait_seconds cannot exceed %d", maxBashJobWait)
+			}
+			job, err = bashJobFor(ToolSessionFrom(ctx), payload.Id)
+			if err != nil {
+				return "", err
+			}
+
+			if payload.WaitSeconds > 0 {
+				bashJobWait(ctx, job, payload.Offset, time.Duration(payload.WaitSeconds)*time.Second)
+			}
+
+			return bashJobReport(job, payload.Offset), nil
+		},
+	}
+}
+
+func BashSend() Def {
+	return Def{
+		Name:        "bash_send",
+		Description: "Write input to the stdin of a background job from bash_start. Include a trailing newline to submit a line; eof closes stdin afterwards.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id":    map[string]any{"type": "string"},
+				"input": map[string]any{"type": "string"},
+				"eof":   map[string]any{"type": "boolean"},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionDangerous,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Id    string `json:"id"`
+				Input string `json:"input"`
+				EOF   bool   `json:"eof"`
+			}
+			var job *bashJob
+			var written int
+
+			var err error
+
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			if payload.Input == "" && !payload.EOF {
+				return "", fmt.Errorf("input or eof is required")
+			}
+			job, err = bashJobFor(ToolSessionFrom(ctx), payload.Id)
+			if err != nil {
+				return "", err
+			}
+			if !job.info().Running {
+				return "", fmt.Errorf("job %s has already finished", job.id)
+			}
+
+			if payload.Input != "" {
+				written, err = io.WriteString(job.stdin, payload.Input)
+				if err != nil {
+					return "", fmt.Errorf("write to job %s: %w", job.id, err)
+				}
+			}
+			if payload.EOF {
+				err = job.stdin.Close()
+				if err != nil {
+					return "", fmt.Errorf("close stdin of job %s: %w", job.id, err)
+				}
+
+				return fmt.Sprintf("sent %d bytes to job %s and closed its stdin", written, job.id), nil
+			}
+
+			return fmt.Sprintf("sent %d bytes to job %s", written, job.id), nil
+		},
+	}
+}
+
+func BashKill() Def {
+	return Def{
+		Name:        "bash_kill",
+		Description: "Stop a background job from bash_start by killing its whole process group, and forget it. Returns its last output.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"id": map[string]any{"type": "string"},
+			},
+			"required":             []string{"id"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionDangerous,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Id string `json:"id"`
+			}
+			var job *bashJob
+			var output string
+			var total int
+
+			var err error
+
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			job, err = bashJobFor(ToolSessionFrom(ctx), payload.Id)
+			if err != nil {
+				return "", err
+			}
+
+			_, err = BashJobKill(job.owner, job.id)
+			if err != nil {
+				return "", err
+			}
+
+			job.mu.Lock()
+			total = job.dropped + len(job.output)
+			job.mu.Unlock()
+			output, _, _ = job.read(max(0, total-bashJobTail))
+
+			return strings.TrimRight(job.status(total)+"\n\n"+output, "\n"), nil
+		},
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var bashJobIdPattern *regexp.Regexp = regexp.MustCompile(`^job (\d+) `)
+
+func bashJobStarted(t *testing.T, ctx context.Context, command string) string {
+	var encoded []byte
+	var result string
+	var match []string
+
+	var err error
+
+	t.Helper()
+
+	encoded, _ = json.Marshal(map[string]string{"command": command})
+	result, err = BashStart(t.TempDir()).Execute(ctx, string(encoded))
+	if err != nil {
+		t.Fatal(err)
+	}
+	match = bashJobIdPattern.FindStringSubmatch(result)
+	if match == nil {
+		t.Fatalf("bash_start = %q", result)
+	}
+	t.Cleanup(BashJobsClose)
+
+	return match[1]
+}
+
+func bashJobUntil(t *testing.T, ctx context.Context, id, text string) string {
+	var deadline time.Time
+	var result string
+
+	var err error
+
+	t.Helper()
+
+	deadline = time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		result, err = BashOutput().Execute(ctx, `{"id":"`+id+`","wait_seconds":1}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(result, text) {
+			return result
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	t.Fatalf("job %s never printed %q: %q", id, text, result)
+	return ""
+}
+
+func TestBashJobTalksOverStdinAndReadsIncrementally(t *testing.T) {
+	var ctx context.Context
+	var id string
+	var result string
+
+	var err error
+
+	ctx = ToolSessionContext(context.Background(), "session-a")
+	id = bashJobStarted(t, ctx, "echo ready; read line; echo got $line")
+
+	result = bashJobUntil(t, ctx, id, "ready")
+	if !strings.Contains(result, "running") {
+		t.Fatalf("first output = %q", result)
+	}
+
+	_, err = BashSend().Execute(ctx, `{"id":"`+id+`","input":"hello\n"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result = bashJobUntil(t, ctx, id, "finished")
+	if !strings.Contains(result, "got hello") || !strings.Contains(result, "exit status 0") || !strings.Contains(result, "forgotten") {
+		t.Fatalf("final output = %q", result)
+	}
+
+	_, err = BashOutput().Execute(ctx, `{"id":"`+id+`"}`)
+	if err == nil || len(BashJobs("session-a")) != 0 {
+		t.Fatalf("a drained job = %v, %+v, want it evicted", err, BashJobs("session-a"))
+	}
+}
+
+func TestBashJobNeedsASession(t *testing.T) {
+	var err error
+
+	_, err = BashStart(t.TempDir()).Execute(context.Background(), `{"command":"sleep 60"}`)
+	if err == nil || !strings.Contains(err.Error(), "chat session") {
+		t.Fatalf("bash_start without a session = %v, want a refusal", err)
+	}
+	_, err = BashOutput().Execute(context.Background(), `{"id":"1"}`)
+	if err == nil || !strings.Contains(err.Error(), "chat session") {
+		t.Fatalf("bash_output without a session = %v, want a refusal", err)
+	}
+}
+
+func TestBashJobBelongsToItsSessionAndDiesWithKill(t *testing.T) {
+	var owner context.Context
+	var other context.Context
+	var id string
+	var result string
+
+	var err error
+
+	owner = ToolSessionContext(context.Background(), "session-a")
+	other = ToolSessionContext(context.Background(), "session-b")
+	id = bashJobStarted(t, owner, "sleep 60 & sleep 60")
+
+	_, err = BashOutput().Execute(other, `{"id":"`+id+`"}`)
+	if err == nil {
+		t.Fatal("another session read the job")
+	}
+	_, err = BashKill().Execute(other, `{"id":"`+id+`"}`)
+	if err == nil {
+		t.Fatal("another session killed the job")
+	}
+	if len(BashJobs("session-a")) != 1 || len(BashJobs("session-b")) != 0 {
+		t.Fatalf("jobs = %+v / %+v", BashJobs("session-a"), BashJobs("session-b"))
+	}
+
+	result, err = BashKill().Execute(owner, `{"id":"`+id+`"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "finished with signal: killed") {
+		t.Fatalf("kill = %q", result)
+	}
+	if len(BashJobs("session-a")) != 0 {
+		t.Fatal("a killed job is still listed")
+	}
+}
+
+func TestBashJobsCloseOwnerEndsOnlyThatSessionsJobs(t *testing.T) {
+	var owner context.Context
+	var other context.Context
+	var ended *bashJob
+	var kept string
+
+	var err error
+
+	owner = ToolSessionContext(context.Background(), "session-a")
+	other = ToolSessionContext(context.Background(), "session-b")
+	ended, err = bashJobFor("session-a", bashJobStarted(t, owner, "sleep 60"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept = bashJobStarted(t, other, "sleep 60")
+
+	BashJobsCloseOwner("session-a")
+
+	select {
+	case <-ended.done:
+	case <-time.After(bashJobKillWait + time.Second):
+		t.Fatal("the closed session's job is still running")
+	}
+	if len(BashJobs("session-a")) != 0 {
+		t.Fatal("the closed session still lists its job")
+	}
+	if len(BashJobs("session-b")) != 1 || !BashJobs("session-b")[0].Running || BashJobs("session-b")[0].Id != kept {
+		t.Fatalf("another session's jobs = %+v, want it left running", BashJobs("session-b"))
+	}
+}
@@ -0,0 +1,90 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/devproje/mininaru/util"
+)
+
+type bashSandboxSpec struct {
+	Root     string   `json:"root"`
+	Hide     []string `json:"hide"`
+	Writable []string `json:"writable"`
+	Offline  bool     `json:"offline"`
+}
+
+type toolSandboxKey struct{}
+
+const (
+	SandboxOff     = "off"
+	SandboxOn      = "on"
+	SandboxOffline = "offline"
+)
+
+const bashSandboxArg = "mininaru-sandbox"
+const bashSandboxEnv = "MININARU_SANDBOX"
+
+var SandboxModes []string = []string{SandboxOff, SandboxOn, SandboxOffline}
+
+var bashSandboxSecrets []string = []string{".ssh", ".gnupg", ".aws", ".azure", ".kube", ".docker", ".netrc", ".git-credentials",
+	".config/gh", ".config/gcloud"}
+
+func SandboxCheck(mode string) error {
+	if mode == "" || slices.Contains(SandboxModes, mode) {
+		return nil
+	}
+
+	return fmt.Errorf("invalid sandbox mode %q, use one of %s", mode, strings.Join(SandboxModes, ", "))
+}
+
+func ToolSandboxContext(ctx context.Context, mode string) context.Context {
+	return context.WithValue(ctx, toolSandboxKey{}, mode)
+}
+
+func ToolSandboxFrom(ctx context.Context) string {
+	var mode string
+
+	mode, _ = ctx.Value(toolSandboxKey{}).(string)
+	if mode == "" {
+		return SandboxOff
+	}
+
+	return mode
+}
+
+func bashSandboxSpecFor(root, mode string) (bashSandboxSpec, error) {
+	var spec bashSandboxSpec
+	var home string
+	var secret string
+	var hidden string
+
+	var err error
+
+	spec = bashSandboxSpec{Root: root, Offline: mode == SandboxOffline, Writable: []string{root, os.TempDir(), "/dev"}}
+
+	if util.RootDir != "" {
+		spec.Hide = append(spec.Hide, util.RootDir)
+	}
+	home, err = os.UserHomeDir()
+	if err == nil {
+		for _, secret = range bashSandboxSecrets {
+			spec.Hide = append(spec.Hide, filepath.Join(home, secret))
+		}
+	}
+
+	for _, hidden = range spec.Hide {
+		if root == hidden || strings.HasPrefix(root, hidden+string(filepath.Separator)) {
+			return spec, fmt.Errorf("the sandbox hides %s, which holds the working root", hidden)
+		}
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build linux
+
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const bashSandboxWrites = unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_REMOVE_DIR | unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+	unix.LANDLOCK_ACCESS_FS_MAKE_CHAR | unix.LANDLOCK_ACCESS_FS_MAKE_DIR | unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SOCK | unix.LANDLOCK_ACCESS_FS_MAKE_FIFO | unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK |
+	unix.LANDLOCK_ACCESS_FS_MAKE_SYM
+
+func init() {
+	var err error
+
+	if len(os.Args) < 3 || os.Args[0] != bashSandboxArg {
+		return
+	}
+
+	err = bashSandboxEnter()
+	fmt.Fprintf(os.Stderr, "mininaru sandbox: %v\n", err)
+	os.Exit(126)
+}
+
+func bashConfine(command *exec.Cmd, root, mode string) error {
+	var spec bashSandboxSpec
+	var encoded []byte
+	var self string
+
+	var err error
+
+	if mode == SandboxOff {
+		return nil
+	}
+
+	spec, err = bashSandboxSpecFor(root, mode)
+	if err != nil {
+		return err
+	}
+	encoded, err = json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	self, err = os.Executable()
+	if err != nil {
+		return fmt.Errorf("locate the sandbox helper: %w", err)
+	}
+
+	command.Args = append([]string{bashSandboxArg, command.Path}, command.Args...)
+	command.Path = self
+	if command.Env == nil {
+		command.Env = os.Environ()
+	}
+	command.Env = append(command.Env, bashSandboxEnv+"="+string(encoded))
+
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	command.SysProcAttr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS
+	if spec.Offline {
+		command.SysProcAttr.Cloneflags |= syscall.CLONE_NEWNET
+	}
+	command.SysProcAttr.UidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getuid(), HostID: os.Getuid(), Size: 1}}
+	command.SysProcAttr.GidMappings = []syscall.SysProcIDMap{{ContainerID: os.Getgid(), HostID: os.Getgid(), Size: 1}}
+	command.SysProcAttr.GidMappingsEnableSetgroups = false
+	command.SysProcAttr.AmbientCaps = []uintptr{unix.CAP_SYS_ADMIN}
+
+	return nil
+}
+
+func bashSandboxEnter() error {
+	var spec bashSandboxSpec
+	var path string
+	var info os.FileInfo
+	var ruleset int
+	var errno syscall.Errno
+
+	var err error
+
+	runtime.LockOSThread()
+
+	err = json.Unmarshal([]byte(os.Getenv(bashSandboxEnv)), &spec)
+	if err != nil {
+		return fmt.Errorf("invalid sandbox spec: %w", err)
+	}
+	os.Unsetenv(bashSandboxEnv)
+
+	err = unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, "")
+	if err != nil {
+		return fmt.Errorf("make mounts private: %w", err)
+	}
+
+	for _, path = range spec.Hide {
+		info, err = os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			err = unix.Mount("tmpfs", path, "tmpfs", unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "size=4k,mode=0500")
+		} else {
+			err = unix.Mount("/dev/null", path, "", unix.MS_BIND, "")
+		}
+		if err != nil {
+			return fmt.Errorf("hide %s: %w", path, err)
+		}
+	}
+
+	ruleset, err = bashSandboxRuleset(spec.Writable)
+	if err != nil {
+		return err
+	}
+
+	err = unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0)
+	if err != nil {
+		return fmt.Errorf("set no_new_privs: %w", err)
+	}
+	err = unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0)
+	if err != nil {
+		return fmt.Errorf("drop capabilities: %w", err)
+	}
+	_, _, errno = unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(ruleset), 0, 0)
+	if errno != 0 {
+		return fmt.Errorf("enforce landlock: %w", errno)
+	}
+	unix.Close(ruleset)
+
+	return unix.Exec(os.Args[1], os.Args[2:], os.Environ())
+}
+
+func bashSandboxRuleset(writable []string) (int, error) {
+	var abi uintptr
+	var handled uint64
+	var attr unix.LandlockRulesetAttr
+	var fd uintptr
+	var path string
+	var target int
+	var rule unix.LandlockPathBeneathAttr
+	var errno syscall.Errno
+
+	var err error
+
+	abi, _, errno = unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return -1, fmt.Errorf("landlock is not available on this kernel: %w", errno)
+	}
+
+	handled = bashSandboxWrites
+	if abi >= 2 {
+		handled |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		handled |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	attr = unix.LandlockRulesetAttr{Access_fs: handled}
+	fd, _, errno = unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return -1, fmt.Errorf("create landlock ruleset: %w", errno)
+	}
+
+	for _, path = range writable {
+		target, err = unix.Open(path, unix.O_PATH|unix.O_CLOEXEC|unix.O_DIRECTORY, 0)
+		if err != nil {
+			continue
+		}
+
+		rule = unix.LandlockPathBeneathAttr{Allowed_access: handled, Parent_fd: int32(target)}
+		_, _, errno = unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, fd, unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&rule)), 0, 0, 0)
+		unix.Close(target)
+		if errno != 0 {
+			unix.Close(int(fd))
+			return -1, fmt.Errorf("allow writes under %s: %w", path, errno)
+		}
+	}
+
+	return int(fd), nil
+}
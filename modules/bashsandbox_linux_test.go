@@ -0,0 +1,96 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build linux
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func bashSandboxed(t *testing.T, mode, root, command string) (string, error) {
+	var encoded []byte
+
+	t.Helper()
+
+	encoded, _ = json.Marshal(map[string]any{"command": command, "timeout_seconds": 20})
+
+	return BashExec(root).Execute(ToolSandboxContext(context.Background(), mode), string(encoded))
+}
+
+func TestBashSandboxConfinesWritesAndHidesSecrets(t *testing.T) {
+	var root string
+	var outside string
+	var home string
+	var result string
+
+	var err error
+
+	root, err = toolRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside, err = os.MkdirTemp(filepath.Dir(root), "outside")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(outside) })
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TMPDIR", root)
+	err = os.MkdirAll(filepath.Join(home, ".ssh"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(home, ".ssh", "id_ed25519"), []byte("secret key"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = bashSandboxed(t, SandboxOn, root, "echo probe > inside.txt && cat inside.txt")
+	if err != nil && strings.Contains(result, "mininaru sandbox:") {
+		t.Skipf("the sandbox is unavailable here: %s", result)
+	}
+	if err != nil || !strings.HasSuffix(result, "probe\n") {
+		t.Fatalf("write inside the root = %q, %v", result, err)
+	}
+
+	_, err = bashSandboxed(t, SandboxOn, root, "echo leak > "+bashQuote(filepath.Join(outside, "leak.txt")))
+	if err == nil {
+		t.Fatal("the sandbox wrote outside the root")
+	}
+	_, err = os.Stat(filepath.Join(outside, "leak.txt"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("outside file = %v", err)
+	}
+
+	result, _ = bashSandboxed(t, SandboxOn, root, "cat ~/.ssh/id_ed25519; ls -A ~/.ssh")
+	if strings.Contains(result, "secret key") || strings.Contains(result, "id_ed25519\n") {
+		t.Fatalf("the sandbox read a hidden secret: %q", result)
+	}
+
+	result, err = bashSandboxed(t, SandboxOffline, root, "echo \"net=$(tail -n +3 /proc/net/dev | cut -d: -f1 | tr -d ' ' | tr '\\n' ,)\"")
+	if err != nil || !strings.HasSuffix(result, "net=lo,\n") {
+		t.Fatalf("offline interfaces = %q, %v", result, err)
+	}
+
+	_, err = bashSandboxed(t, SandboxOn, root, "true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat(filepath.Join(root, "inside.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = bashSandboxed(t, SandboxOn, filepath.Join(home, ".ssh"), "true")
+	if err == nil || !strings.Contains(err.Error(), "holds the working root") {
+		t.Fatalf("root inside a hidden path = %v", err)
+	}
+}
@@ -0,0 +1,19 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+//go:build !linux
+
+package modules
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func bashConfine(command *exec.Cmd, root, mode string) error {
+	if mode == SandboxOff {
+		return nil
+	}
+
+	return fmt.Errorf("the bash sandbox needs Linux; set this agent's sandbox to off to run commands here")
+}
@@ -0,0 +1,313 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type bashShellSession struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	output   *bufio.Reader
+	sentinel string
+	sandbox  string
+	done     chan struct{}
+	mu       sync.Mutex
+}
+
+type bashShellResult struct {
+	status int
+	exited bool
+	err    error
+}
+
+var bashShells map[string]*bashShellSession = make(map[string]*bashShellSession)
+
+var bashShellsMu sync.Mutex
+
+func bashQuote(text string) string {
+	return "'" + strings.ReplaceAll(text, "'", `'\''`) + "'"
+}
+
+func bashShellStart(root, sandbox string) (*bashShellSession, error) {
+	var workingDir string
+	var shell string
+	var session *bashShellSession
+	var reader *os.File
+	var writer *os.File
+
+	var err error
+
+	workingDir, err = toolRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	shell, err = bashShell()
+	if err != nil {
+		return nil, err
+	}
+	reader, writer, err = os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+
+	session = &bashShellSession{sentinel: "__mininaru_done_" + strings.ReplaceAll(uuid.NewString(), "-", ""), sandbox: sandbox, done: make(chan struct{})}
+	session.cmd = exec.Command(shell, "-l")
+	session.cmd.Dir = workingDir
+	session.cmd.Stdout = writer
+	session.cmd.Stderr = writer
+	bashIsolate(session.cmd)
+	err = bashConfine(session.cmd, workingDir, sandbox)
+	if err != nil {
+		reader.Close()
+		writer.Close()
+		return nil, err
+	}
+
+	session.stdin, err = session.cmd.StdinPipe()
+	if err != nil {
+		reader.Close()
+		writer.Close()
+		return nil, err
+	}
+	err = session.cmd.Start()
+	writer.Close()
+	if err != nil {
+		reader.Close()
+		return nil, fmt.Errorf("start shell: %w", err)
+	}
+
+	session.output = bufio.NewReader(reader)
+	go func() {
+		session.cmd.Wait()
+		reader.Close()
+		close(session.done)
+	}()
+
+	return session, nil
+}
+
+func (s *bashShellSession) close() {
+	s.stdin.Close()
+	bashTerminate(s.cmd)
+
+	select {
+	case <-s.done:
+	case <-time.After(bashJobKillWait):
+	}
+}
+
+func (s *bashShellSession) run(ctx context.Context, command string, timeout time.Duration) (string, bashShellResult) {
+	var results chan bashShellResult
+	var captured strings.Builder
+	var capturedMu sync.Mutex
+	var stopped bool
+	var progress ToolProgressFunc
+	var timer *time.Timer
+	var result bashShellResult
+	var output string
+
+	var err error
+
+	results = make(chan bashShellResult, 1)
+	progress = toolProgressFrom(ctx)
+
+	_, err = io.WriteString(s.stdin, "eval "+bashQuote(command)+" </dev/null\nprintf '\\n%s %d\\n' "+s.sentinel+" \"$?\"\n")
+	if err != nil {
+		return "", bashShellResult{exited: true, err: fmt.Errorf("the persistent shell is gone: %w", err)}
+	}
+
+	go func() {
+		var line string
+		var status int
+		var newline string
+
+		var err error
+
+		for {
+			line, err = s.output.ReadString('\n')
+			if strings.HasPrefix(line, s.sentinel+" ") {
+				status, _ = strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, s.sentinel+" ")))
+				results <- bashShellResult{status: status}
+				return
+			}
+
+			capturedMu.Lock()
+			if captured.Len() <= maxBashOutput {
+				captured.WriteString(line)
+				if progress != nil && !stopped && line != "" {
+					progress(newline + strings.TrimSuffix(line, "\n"))
+					newline = ""
+					if strings.HasSuffix(line, "\n") {
+						newline = "\n"
+					}
+				}
+			}
+			capturedMu.Unlock()
+
+			if err != nil {
+				results <- bashShellResult{exited: true}
+				return
+			}
+		}
+	}()
+
+	timer = time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result = <-results:
+	case <-timer.C:
+		result = bashShellResult{exited: true, err: context.DeadlineExceeded}
+	case <-ctx.Done():
+		result = bashShellResult{exited: true, err: ctx.Err()}
+	}
+
+	capturedMu.Lock()
+	output = captured.String()
+	stopped = true
+	capturedMu.Unlock()
+
+	if !result.exited {
+		output = strings.TrimSuffix(output, "\n")
+	}
+	if len(output) > maxBashOutput {
+		output = output[:maxBashOutput] + "\n[truncated]"
+	}
+
+	return output, result
+}
+
+func bashShellFor(owner, root, sandbox string) (*bashShellSession, error) {
+	var key string
+	var session *bashShellSession
+
+	var err error
+
+	key = owner + "\x00" + root
+
+	bashShellsMu.Lock()
+	defer bashShellsMu.Unlock()
+
+	session = bashShells[key]
+	if session != nil {
+		select {
+		case <-session.done:
+			delete(bashShells, key)
+		default:
+			if session.sandbox == sandbox {
+				return session, nil
+			}
+			delete(bashShells, key)
+			go session.close()
+		}
+	}
+
+	session, err = bashShellStart(root, sandbox)
+	if err != nil {
+		return nil, err
+	}
+	bashShells[key] = session
+
+	return session, nil
+}
+
+func bashShellDrop(owner, root string, session *bashShellSession) {
+	var key string
+
+	key = owner + "\x00" + root
+
+	bashShellsMu.Lock()
+	if session == nil || bashShells[key] == session {
+		session = bashShells[key]
+		delete(bashShells, key)
+	}
+	bashShellsMu.Unlock()
+
+	if session != nil {
+		session.close()
+	}
+}
+
+func bashShellExecute(ctx context.Context, owner, root, sandbox, command string, timeoutSeconds int) (string, error) {
+	var session *bashShellSession
+	var output string
+	var result bashShellResult
+
+	var err error
+
+	session, err = bashShellFor(owner, root, sandbox)
+	if err != nil {
+		return "", err
+	}
+
+	session.mu.Lock()
+	output, result = session.run(ctx, command, time.Duration(timeoutSeconds)*time.Second)
+	session.mu.Unlock()
+
+	if result.exited {
+		bashShellDrop(owner, root, session)
+	}
+	if result.err == context.DeadlineExceeded {
+		return output, fmt.Errorf("command timed out after %d seconds; the persistent shell was killed and the next call starts a fresh one", timeoutSeconds)
+	}
+	if result.err != nil {
+		return output, result.err
+	}
+	if result.exited {
+		return output, fmt.Errorf("the persistent shell exited; the next call starts a fresh one in the startup directory")
+	}
+	if result.status != 0 {
+		return output, fmt.Errorf("command failed: exit status %d", result.status)
+	}
+
+	return output, nil
+}
+
+func BashShellsClose() {
+	var session *bashShellSession
+	var ended []*bashShellSession
+
+	bashShellsMu.Lock()
+	for _, session = range bashShells {
+		ended = append(ended, session)
+	}
+	bashShells = make(map[string]*bashShellSession)
+	bashShellsMu.Unlock()
+
+	for _, session = range ended {
+		session.close()
+	}
+}
+
+func BashShellsCloseOwner(owner string) {
+	var key string
+	var session *bashShellSession
+	var ended []*bashShellSession
+
+	bashShellsMu.Lock()
+	for key, session = range bashShells {
+		if strings.HasPrefix(key, owner+"\x00") {
+			ended = append(ended, session)
+			delete(bashShells, key)
+		}
+	}
+	bashShellsMu.Unlock()
+
+	for _, session = range ended {
+		session.close()
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func bashPersistent(t *testing.T, ctx context.Context, root, command string, timeout int) (string, error) {
+	var encoded []byte
+
+	t.Helper()
+
+	encoded, _ = json.Marshal(map[string]any{"command": command, "persistent": true, "timeout_seconds": timeout})
+
+	return BashExec(root).Execute(ctx, string(encoded))
+}
+
+func TestBashPersistentShellCarriesDirectoryAndEnvironment(t *testing.T) {
+	var root string
+	var ctx context.Context
+	var other context.Context
+	var result string
+
+	var err error
+
+	root, err = toolRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Mkdir(filepath.Join(root, "sub"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx = ToolSessionContext(context.Background(), "session-a")
+	other = ToolSessionContext(context.Background(), "session-b")
+	t.Cleanup(BashShellsClose)
+
+	_, err = bashPersistent(t, ctx, root, "cd sub && export NARU_TEST=carried", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = bashPersistent(t, ctx, root, "pwd; echo \"value=$NARU_TEST\"", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, filepath.Join(root, "sub")+"\n") || !strings.HasSuffix(result, "value=carried\n") {
+		t.Fatalf("second call = %q", result)
+	}
+
+	_, err = bashPersistent(t, ctx, root, "false", 10)
+	if err == nil || !strings.Contains(err.Error(), "exit status 1") {
+		t.Fatalf("false = %v", err)
+	}
+	result, err = bashPersistent(t, ctx, root, "printf 'a\\nb'; echo \"$NARU_TEST\" >&2", 10)
+	if err != nil || !strings.HasSuffix(result, "a\nbcarried\n") {
+		t.Fatalf("state after a failure = %q, %v", result, err)
+	}
+
+	result, err = bashPersistent(t, other, root, "echo \"value=$NARU_TEST\"", 10)
+	if err != nil || !strings.HasSuffix(result, "value=\n") {
+		t.Fatalf("another session = %q, %v", result, err)
+	}
+
+	_, err = BashExec(root).Execute(ctx, `{"reset":true}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = bashPersistent(t, ctx, root, "pwd", 10)
+	if err != nil || !strings.HasSuffix(result, root+"\n") {
+		t.Fatalf("after reset = %q, %v", result, err)
+	}
+}
+
+func TestBashPersistentShellEnforcesTimeoutAndSedRule(t *testing.T) {
+	var root string
+	var ctx context.Context
+	var started time.Time
+	var result string
+
+	var err error
+
+	root = t.TempDir()
+	ctx = ToolSessionContext(context.Background(), "session-a")
+	t.Cleanup(BashShellsClose)
+
+	_, err = bashPersistent(t, ctx, root, "export NARU_TEST=lost", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started = time.Now()
+	_, err = bashPersistent(t, ctx, root, "sleep 30", 1)
+	if err == nil || !strings.Contains(err.Error(), "timed out after 1 seconds") {
+		t.Fatalf("timeout error = %v", err)
+	}
+	if time.Since(started) > 10*time.Second {
+		t.Fatalf("timeout took %s", time.Since(started))
+	}
+
+	result, err = bashPersistent(t, ctx, root, "echo \"value=$NARU_TEST\"", 10)
+	if err != nil || !strings.HasSuffix(result, "value=\n") {
+		t.Fatalf("after timeout = %q, %v", result, err)
+	}
+
+	_, err = bashPersistent(t, ctx, root, "sed -i s/a/b/ file.txt", 10)
+	if err == nil || !strings.Contains(err.Error(), "in-place sed") {
+		t.Fatalf("sed error = %v", err)
+	}
+}
+
+func TestBashShellsCloseOwnerEndsOnlyThatSessionsShells(t *testing.T) {
+	var root string
+	var ended *bashShellSession
+	var kept *bashShellSession
+
+	var err error
+
+	root, err = toolRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(BashShellsClose)
+
+	_, err = bashPersistent(t, ToolSessionContext(context.Background(), "session-a"), root, "true", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = bashPersistent(t, ToolSessionContext(context.Background(), "session-b"), root, "true", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bashShellsMu.Lock()
+	ended = bashShells["session-a\x00"+root]
+	kept = bashShells["session-b\x00"+root]
+	bashShellsMu.Unlock()
+	if ended == nil || kept == nil {
+		t.Fatal("persistent shells were not kept per session")
+	}
+
+	BashShellsCloseOwner("session-a")
+
+	select {
+	case <-ended.done:
+	case <-time.After(bashJobKillWait + time.Second):
+		t.Fatal("the closed session's shell is still running")
+	}
+	bashShellsMu.Lock()
+	defer bashShellsMu.Unlock()
+	if bashShells["session-a\x00"+root] != nil || bashShells["session-b\x00"+root] != kept {
+		t.Fatal("closing one session touched the wrong shells")
+	}
+	select {
+	case <-kept.done:
+		t.Fatal("another session's shell was ended")
+	default:
+	}
+}
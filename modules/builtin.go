@@ -106,6 +106,13 @@ func builtinTools() []builtinTool {
 				Title: "edit file", ReadOnlyHint: false, DestructiveHint: hint(true), OpenWorldHint: hint(false),
 			},
 		},
+		{
+			Build:      func() Def { return FilePatch(builtinRoot()) },
+			Permission: PermissionDangerous,
+			Annotations: mcp.ToolAnnotations{
+				Title: "patch files", ReadOnlyHint: false, DestructiveHint: hint(true), OpenWorldHint: hint(false),
+			},
+		},
 		{
 			Build:      func() Def { return Glob(builtinRoot()) },
 			Permission: PermissionDangerous,
@@ -120,6 +127,27 @@ func builtinTools() []builtinTool {
 				Title: "search file contents", ReadOnlyHint: true, IdempotentHint: true, OpenWorldHint: hint(false),
 			},
 		},
+		{
+			Build:      func() Def { return LSP(builtinRoot()) },
+			Permission: PermissionDangerous,
+			Annotations: mcp.ToolAnnotations{
+				Title: "language server", ReadOnlyHint: true, IdempotentHint: true, OpenWorldHint: hint(false),
+			},
+		},
+		{
+			Build:      func() Def { return Git(builtinRoot()) },
+			Permission: PermissionDangerous,
+			Annotations: mcp.ToolAnnotations{
+				Title: "inspect git", ReadOnlyHint: true, IdempotentHint: true, OpenWorldHint: hint(false),
+			},
+		},
+		{
+			Build:      func() Def { return GitWrite(builtinRoot()) },
+			Permission: PermissionDangerous,
+			Annotations: mcp.ToolAnnotations{
+				Title: "change git", ReadOnlyHint: false, DestructiveHint: hint(true), OpenWorldHint: hint(false),
+			},
+		},
 		{
 			Build:      func() Def { return BashExec(builtinRoot()) },
 			Permission: PermissionDangerous,
@@ -127,6 +155,34 @@ func builtinTools() []builtinTool {
 				Title: "run bash", ReadOnlyHint: false, DestructiveHint: hint(true), OpenWorldHint: hint(true),
 			},
 		},
+		{
+			Build:      func() Def { return BashStart(builtinRoot()) },
+			Permission: PermissionDangerous,
+			Annotations: mcp.ToolAnnotations{
+				Title: "start background bash", ReadOnlyHint: false, DestructiveHint: hint(true), OpenWorldHint: hint(true),
+			},
+		},
+		{
+			Build:      BashOutput,
+			Permission: PermissionSafe,
+			Annotations: mcp.ToolAnnotations{
+				Title: "read background output", ReadOnlyHint: true, IdempotentHint: false, OpenWorldHint: hint(false),
+			},
+		},
+		{
+			Build:      BashSend,
+			Permission: PermissionDangerous,
+			Annotations: mcp.ToolAnnotations{
+				Title: "send background input", ReadOnlyHint: false, DestructiveHint: hint(true), OpenWorldHint: hint(true),
+			},
+		},
+		{
+			Build:      BashKill,
+			Permission: PermissionDangerous,
+			Annotations: mcp.ToolAnnotations{
+				Title: "stop background bash", ReadOnlyHint: false, DestructiveHint: hint(true), OpenWorldHint: hint(false),
+			},
+		},
 		{
 			Build:      WebSearch,
 			Permission: PermissionSafe,
@@ -25,8 +25,9 @@ func TestBuiltinServerExposesEveryTool(t *testing.T) {
 	var err error
 
 	expected = map[string]bool{
-		"current_time": true, "file_read": true, "file_write": true, "file_edit": true, "glob": true,
-		"grep": true, "bash_exec": true, "web_search": true, "skill": true, "skill_create": true,
+		"current_time": true, "file_read": true, "file_write": true, "file_edit": true, "file_patch": true, "glob": true,
+		"grep": true, "lsp": true, "git": true, "git_write": true, "bash_exec": true, "bash_start": true,
+		"bash_output": true, "bash_send": true, "bash_kill": true, "web_search": true, "skill": true, "skill_create": true,
 		"web_fetch": true, "memory": true,
 	}
 
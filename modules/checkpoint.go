@@ -0,0 +1,382 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/devproje/mininaru/util"
+)
+
+type checkpointEntry struct {
+	Path    string      `json:"path"`
+	Existed bool        `json:"existed"`
+	Mode    os.FileMode `json:"mode"`
+	Blob    string      `json:"blob,omitempty"`
+	After   string      `json:"after,omitempty"`
+}
+
+type checkpointManifest struct {
+	Files []*checkpointEntry `json:"files"`
+}
+
+type checkpointUndo struct {
+	path     string
+	existed  bool
+	content  []byte
+	mode     os.FileMode
+	revision [sha256.Size]byte
+	tracked  bool
+}
+
+type toolTurnKey struct{}
+
+const CHECKPOINT_PATH = "checkpoints"
+
+const checkpointManifestName = "manifest.json"
+
+var ErrNoCheckpoint error = errors.New("no file changes were recorded for that message")
+
+func ToolTurnContext(ctx context.Context, messageId string) context.Context {
+	return context.WithValue(ctx, toolTurnKey{}, messageId)
+}
+
+func ToolTurnFrom(ctx context.Context) string {
+	var messageId string
+
+	messageId, _ = ctx.Value(toolTurnKey{}).(string)
+
+	return messageId
+}
+
+func checkpointDir(sessionId, messageId string) (string, error) {
+	var err error
+
+	if util.RootDir == "" {
+		return "", fmt.Errorf("the data directory is not set")
+	}
+	err = util.SafeSegment(sessionId)
+	if err != nil {
+		return "", fmt.Errorf("session id: %w", err)
+	}
+	err = util.SafeSegment(messageId)
+	if err != nil {
+		return "", fmt.Errorf("message id: %w", err)
+	}
+
+	return filepath.Join(util.Path(CHECKPOINT_PATH), sessionId, messageId), nil
+}
+
+func checkpointFind(sessionId, messageId string) (string, error) {
+	var matches []string
+
+	var err error
+
+	if sessionId != "" {
+		return checkpointDir(sessionId, messageId)
+	}
+	if util.RootDir == "" {
+		return "", fmt.Errorf("the data directory is not set")
+	}
+	err = util.SafeSegment(messageId)
+	if err != nil {
+		return "", fmt.Errorf("message id: %w", err)
+	}
+
+	matches, err = filepath.Glob(filepath.Join(util.Path(CHECKPOINT_PATH), "*", messageId))
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", ErrNoCheckpoint
+	}
+
+	return matches[0], nil
+}
+
+func checkpointLoad(dir string) (*checkpointManifest, error) {
+	var manifest checkpointManifest
+	var buf []byte
+
+	var err error
+
+	buf, err = os.ReadFile(filepath.Join(dir, checkpointManifestName))
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(buf, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid checkpoint manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+func checkpointHash(buf []byte) string {
+	var revision [sha256.Size]byte
+
+	revision = fileRevision(buf)
+
+	return hex.EncodeToString(revision[:])
+}
+
+func checkpointSave(ctx context.Context, target string, existed bool, before []byte, mode os.FileMode, after []byte, exists bool) error {
+	var sessionId string
+	var messageId string
+	var dir string
+	var manifest *checkpointManifest
+	var current *checkpointEntry
+	var entry *checkpointEntry
+	var encoded []byte
+
+	var err error
+
+	sessionId = ToolSessionFrom(ctx)
+	messageId = ToolTurnFrom(ctx)
+	if sessionId == "" || messageId == "" || util.RootDir == "" {
+		return nil
+	}
+
+	dir, err = checkpointDir(sessionId, messageId)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return err
+	}
+	manifest, err = checkpointLoad(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		manifest, err = &checkpointManifest{}, nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, current = range manifest.Files {
+		if current.Path == target {
+			entry = current
+			break
+		}
+	}
+	if entry == nil {
+		entry = &checkpointEntry{Path: target, Existed: existed, Mode: mode}
+		if existed {
+			entry.Blob = strconv.Itoa(len(manifest.Files))
+			err = util.WriteFileAtomic(filepath.Join(dir, entry.Blob), before, 0600)
+			if err != nil {
+				return err
+			}
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+
+	entry.After = ""
+	if exists {
+		entry.After = checkpointHash(after)
+	}
+
+	encoded, err = json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return util.WriteFileAtomic(filepath.Join(dir, checkpointManifestName), encoded, 0600)
+}
+
+func checkpointRecord(ctx context.Context, target string, existed bool, before []byte, mode os.FileMode, after []byte, exists bool) {
+	var err error
+
+	err = checkpointSave(ctx, target, existed, before, mode, after, exists)
+	if err != nil {
+		util.Log.Warn("could not record an undo checkpoint", "path", target, "error", err)
+	}
+}
+
+func checkpointConflict(entry *checkpointEntry) string {
+	var buf []byte
+
+	var err error
+
+	buf, err = os.ReadFile(entry.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		if entry.After == "" {
+			return ""
+		}
+		return entry.Path + ": deleted since the turn"
+	}
+	if err != nil {
+		return entry.Path + ": " + err.Error()
+	}
+	if entry.After == "" {
+		return entry.Path + ": recreated since the turn deleted it"
+	}
+	if checkpointHash(buf) != entry.After {
+		return entry.Path + ": changed since the turn"
+	}
+
+	return ""
+}
+
+func checkpointSnapshot(path string) (checkpointUndo, error) {
+	var undo checkpointUndo
+	var info os.FileInfo
+
+	var err error
+
+	undo = checkpointUndo{path: path}
+	undo.revision, undo.tracked = fileRevisions[path]
+	info, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return undo, nil
+	}
+	if err != nil {
+		return undo, err
+	}
+
+	undo.content, err = os.ReadFile(path)
+	if err != nil {
+		return undo, err
+	}
+	undo.existed = true
+	undo.mode = info.Mode().Perm()
+
+	return undo, nil
+}
+
+func checkpointRollback(done []checkpointUndo) {
+	var undo checkpointUndo
+	var index int
+
+	for index = len(done) - 1; index >= 0; index-- {
+		undo = done[index]
+		if undo.existed {
+			util.WriteFileAtomic(undo.path, undo.content, undo.mode)
+		} else {
+			os.Remove(undo.path)
+		}
+
+		if undo.tracked {
+			fileRevisions[undo.path] = undo.revision
+		} else {
+			delete(fileRevisions, undo.path)
+		}
+	}
+}
+
+func CheckpointRestore(sessionId, messageId string) ([]string, error) {
+	var dir string
+	var manifest *checkpointManifest
+	var entry *checkpointEntry
+	var conflict string
+	var conflicts []string
+	var undos []checkpointUndo
+	var undo checkpointUndo
+	var index int
+	var buf []byte
+	var restored []string
+
+	var err error
+
+	dir, err = checkpointFind(sessionId, messageId)
+	if err != nil {
+		return nil, err
+	}
+
+	fileRevisionMu.Lock()
+	defer fileRevisionMu.Unlock()
+
+	manifest, err = checkpointLoad(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNoCheckpoint
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry = range manifest.Files {
+		conflict = checkpointConflict(entry)
+		if conflict != "" {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("files changed since the turn, nothing was restored:\n%s", strings.Join(conflicts, "\n"))
+	}
+
+	for _, entry = range manifest.Files {
+		undo, err = checkpointSnapshot(entry.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s failed, nothing was restored: %w", entry.Path, err)
+		}
+		undos = append(undos, undo)
+	}
+
+	for index, entry = range manifest.Files {
+		if entry.Existed {
+			buf, err = os.ReadFile(filepath.Join(dir, entry.Blob))
+			if err == nil {
+				err = os.MkdirAll(filepath.Dir(entry.Path), 0755)
+			}
+			if err == nil {
+				err = util.WriteFileAtomic(entry.Path, buf, entry.Mode)
+			}
+		} else if entry.After != "" {
+			err = os.Remove(entry.Path)
+		}
+		if err != nil {
+			checkpointRollback(undos[:index+1])
+			return nil, fmt.Errorf("restoring %s failed, earlier files were put back: %w", entry.Path, err)
+		}
+
+		delete(fileRevisions, entry.Path)
+		restored = append(restored, entry.Path)
+	}
+
+	return restored, os.RemoveAll(dir)
+}
+
+func CheckpointSessions() ([]string, error) {
+	var entries []os.DirEntry
+	var entry os.DirEntry
+	var sessions []string
+
+	var err error
+
+	if util.RootDir == "" {
+		return nil, nil
+	}
+
+	entries, err = os.ReadDir(util.Path(CHECKPOINT_PATH))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry = range entries {
+		if entry.IsDir() {
+			sessions = append(sessions, entry.Name())
+		}
+	}
+
+	return sessions, nil
+}
+
+func CheckpointsDrop(sessionId string) error {
+	if util.RootDir == "" || util.SafeSegment(sessionId) != nil {
+		return nil
+	}
+
+	return os.RemoveAll(filepath.Join(util.Path(CHECKPOINT_PATH), sessionId))
+}
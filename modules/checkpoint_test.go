@@ -0,0 +1,154 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devproje/mininaru/util"
+)
+
+func TestCheckpointRestoresEveryFileATurnTouched(t *testing.T) {
+	var root string
+	var ctx context.Context
+	var info os.FileInfo
+	var restored []string
+
+	var err error
+
+	util.RootDir = t.TempDir()
+	root = t.TempDir()
+	ctx = ToolTurnContext(ToolSessionContext(context.Background(), "session"), "turn")
+
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("alpha\n"), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBeforeModify(t, root, "a.txt")
+
+	_, err = FileEdit(root).Execute(ctx, `{"path":"a.txt","old_string":"alpha","new_string":"beta"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = FilePatch(root).Execute(ctx, patchArguments(t, "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-beta\n+gamma\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = FileWrite(root).Execute(ctx, `{"path":"new.txt","content":"fresh\n"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("edited by hand\n"), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = CheckpointRestore("session", "turn")
+	if err == nil || !strings.Contains(err.Error(), "a.txt: changed since the turn") {
+		t.Fatalf("restore over a hand edit = %v", err)
+	}
+	if patchReadFile(t, filepath.Join(root, "new.txt")) != "fresh\n" {
+		t.Fatal("a refused undo still removed a file")
+	}
+
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("gamma\n"), 0640)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored, err = CheckpointRestore("", "turn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 2 {
+		t.Fatalf("restored = %v", restored)
+	}
+	if patchReadFile(t, filepath.Join(root, "a.txt")) != "alpha\n" {
+		t.Fatalf("a.txt = %q", patchReadFile(t, filepath.Join(root, "a.txt")))
+	}
+	info, err = os.Stat(filepath.Join(root, "a.txt"))
+	if err != nil || info.Mode().Perm() != 0640 {
+		t.Fatalf("restored mode = %v, %v", info, err)
+	}
+	_, err = os.Stat(filepath.Join(root, "new.txt"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("created file after undo = %v", err)
+	}
+
+	_, err = FileEdit(root).Execute(ctx, `{"path":"a.txt","old_string":"alpha","new_string":"delta"}`)
+	if err == nil || !strings.Contains(err.Error(), "with file_read before modifying") {
+		t.Fatalf("edit after undo without a read = %v", err)
+	}
+
+	_, err = CheckpointRestore("session", "turn")
+	if !errors.Is(err, ErrNoCheckpoint) {
+		t.Fatalf("second undo = %v", err)
+	}
+
+	err = CheckpointsDrop("session")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckpointRestorePutsFilesBackWhenAWriteFails(t *testing.T) {
+	var root string
+	var ctx context.Context
+	var name string
+	var dir string
+	var manifest *checkpointManifest
+
+	var err error
+
+	util.RootDir = t.TempDir()
+	root = t.TempDir()
+	ctx = ToolTurnContext(ToolSessionContext(context.Background(), "session"), "turn")
+	t.Cleanup(func() { CheckpointsDrop("session") })
+
+	for _, name = range []string{"a.txt", "b.txt"} {
+		err = os.WriteFile(filepath.Join(root, name), []byte("before\n"), 0640)
+		if err != nil {
+			t.Fatal(err)
+		}
+		readBeforeModify(t, root, name)
+		_, err = FileEdit(root).Execute(ctx, `{"path":"`+name+`","old_string":"before","new_string":"after"}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = FileWrite(root).Execute(ctx, `{"path":"new.txt","content":"fresh\n"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err = checkpointFind("session", "turn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest, err = checkpointLoad(dir)
+	if err != nil || len(manifest.Files) != 3 {
+		t.Fatalf("manifest = %+v, %v", manifest, err)
+	}
+	err = os.Remove(filepath.Join(dir, manifest.Files[1].Blob))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = CheckpointRestore("session", "turn")
+	if err == nil || !strings.Contains(err.Error(), "earlier files were put back") {
+		t.Fatalf("restore with a missing blob = %v", err)
+	}
+	if patchReadFile(t, filepath.Join(root, "a.txt")) != "after\n" || patchReadFile(t, filepath.Join(root, "b.txt")) != "after\n" ||
+		patchReadFile(t, filepath.Join(root, "new.txt")) != "fresh\n" {
+		t.Fatal("a failed restore left the workspace half restored")
+	}
+	_, err = FileEdit(root).Execute(ctx, `{"path":"a.txt","old_string":"after","new_string":"again"}`)
+	if err != nil {
+		t.Fatalf("edit after a rolled back restore = %v, want the earlier read to still count", err)
+	}
+}
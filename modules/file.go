@@ -116,6 +116,17 @@ func fileDiff(path, before, after string) string {
 	return result
 }
 
+func textCheck(path string, buf []byte) error {
+	if !utf8.Valid(buf) {
+		return fmt.Errorf("file is not valid UTF-8 text: %s", path)
+	}
+	if strings.IndexByte(string(buf), 0) >= 0 {
+		return fmt.Errorf("binary file is not supported: %s", path)
+	}
+
+	return nil
+}
+
 func readTextFile(path string) ([]byte, error) {
 	var buf []byte
 
@@ -125,11 +136,10 @@ func readTextFile(path string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	if !utf8.Valid(buf) {
-		return nil, fmt.Errorf("file is not valid UTF-8 text: %s", path)
-	}
-	if strings.IndexByte(string(buf), 0) >= 0 {
-		return nil, fmt.Errorf("binary file is not supported: %s", path)
+
+	err = textCheck(path, buf)
+	if err != nil {
+		return nil, err
 	}
 
 	return buf, nil
@@ -257,7 +267,8 @@ func FileRead(root string) Def {
 		Name: "file_read",
 		Description: "Read a UTF-8 text file relative to the process startup directory. " +
 			"Pass offset and limit to read a range of lines instead of the whole file. " +
-			"Always read an existing file before file_edit or file_write; modifications are rejected if it changed afterward.",
+			"PNG, JPEG, GIF, and WebP files are shown to you as images when the model takes them. " +
+			"Always read an existing file before file_edit, file_patch, or file_write; modifications are rejected if it changed afterward.",
 		Parameters: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -304,7 +315,14 @@ func FileRead(root string) Def {
 			if err != nil {
 				return "", err
 			}
-			buf, err = readTextFile(target)
+			buf, err = os.ReadFile(target)
+			if err != nil {
+				return "", err
+			}
+			if ToolImageType(buf) != "" {
+				return ToolImageAttach(ctx, buf)
+			}
+			err = textCheck(target, buf)
 			if err != nil {
 				return "", err
 			}
@@ -347,6 +365,7 @@ func FileWrite(root string) Def {
 			var after []byte
 			var info os.FileInfo
 			var mode os.FileMode
+			var existed bool
 
 			var err error
 
@@ -382,6 +401,7 @@ func FileWrite(root string) Def {
 					return "", err
 				}
 				mode = info.Mode().Perm()
+				existed = true
 			} else if !os.IsNotExist(err) {
 				return "", err
 			} else {
@@ -397,6 +417,7 @@ func FileWrite(root string) Def {
 				return "", err
 			}
 			fileRevisions[target] = fileRevision(after)
+			checkpointRecord(ctx, target, existed, before, mode, after, true)
 
 			return fileDiff(payload.Path, string(before), string(after)), nil
 		},
@@ -494,6 +515,7 @@ func FileEdit(root string) Def {
 				return "", err
 			}
 			fileRevisions[target] = fileRevision([]byte(changed))
+			checkpointRecord(ctx, target, true, buf, info.Mode().Perm(), []byte(changed), true)
 
 			return fileDiff(payload.Path, string(buf), changed), nil
 		},
@@ -373,3 +373,31 @@ func TestFileReadRejectsBinaryContent(t *testing.T) {
 		t.Fatalf("binary read error = %v", err)
 	}
 }
+
+func TestFileReadAttachesImages(t *testing.T) {
+	var root string
+	var images ToolImages
+	var out string
+
+	var err error
+
+	root = t.TempDir()
+	err = os.WriteFile(filepath.Join(root, "chart.png"), []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = FileRead(root).Execute(context.Background(), `{"path":"chart.png"}`)
+	if err == nil || !strings.Contains(err.Error(), "cannot take images") {
+		t.Fatalf("image read without a vision turn = %v", err)
+	}
+
+	images = ToolImages{Left: 1 << 20}
+	out, err = FileRead(root).Execute(ToolImagesContext(context.Background(), &images), `{"path":"chart.png"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "image 1 attached: image/png") || len(images.Images) != 1 {
+		t.Fatalf("image read = %q with %d images", out, len(images.Images))
+	}
+}
@@ -0,0 +1,685 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/devproje/mininaru/util"
+)
+
+type GitAuthor struct {
+	Name  string
+	Email string
+}
+
+type gitArguments struct {
+	Action  string   `json:"action"`
+	Path    string   `json:"path"`
+	Paths   []string `json:"paths"`
+	Ref     string   `json:"ref"`
+	Staged  bool     `json:"staged"`
+	Line    int      `json:"line"`
+	Limit   int      `json:"limit"`
+	Message string   `json:"message"`
+	Create  bool     `json:"create"`
+	Stash   string   `json:"stash"`
+}
+
+type gitChange struct {
+	Path     string `json:"path"`
+	From     string `json:"from,omitempty"`
+	Staged   string `json:"staged,omitempty"`
+	Unstaged string `json:"unstaged,omitempty"`
+}
+
+type gitStatus struct {
+	Branch     string      `json:"branch"`
+	Upstream   string      `json:"upstream,omitempty"`
+	Ahead      int         `json:"ahead"`
+	Behind     int         `json:"behind"`
+	Changed    []gitChange `json:"changed"`
+	Conflicted []string    `json:"conflicted"`
+	Untracked  []string    `json:"untracked"`
+	Truncated  bool        `json:"truncated,omitempty"`
+}
+
+type gitCommit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Email   string `json:"email"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+}
+
+const (
+	GitToolName      = "git"
+	GitWriteToolName = "git_write"
+)
+
+const gitTimeout = 30 * time.Second
+const maxGitOutput = 65536
+const maxGitEntries = 500
+
+const defaultGitLimit = 20
+const maxGitLimit = 200
+
+var gitCodes map[byte]string = map[byte]string{
+	'M': "modified", 'T': "type changed", 'A': "added", 'D': "deleted", 'R': "renamed", 'C': "copied", 'U': "unmerged",
+}
+
+var gitAuthor GitAuthor
+
+var gitAuthorMu sync.RWMutex
+
+func SetGitAuthor(name, email string) {
+	gitAuthorMu.Lock()
+	gitAuthor = GitAuthor{Name: strings.TrimSpace(name), Email: strings.TrimSpace(email)}
+	gitAuthorMu.Unlock()
+}
+
+func gitAuthorEnv() []string {
+	var env []string
+
+	gitAuthorMu.RLock()
+	defer gitAuthorMu.RUnlock()
+
+	if gitAuthor.Name != "" {
+		env = append(env, "GIT_AUTHOR_NAME="+gitAuthor.Name, "GIT_COMMITTER_NAME="+gitAuthor.Name)
+	}
+	if gitAuthor.Email != "" {
+		env = append(env, "GIT_AUTHOR_EMAIL="+gitAuthor.Email, "GIT_COMMITTER_EMAIL="+gitAuthor.Email)
+	}
+
+	return env
+}
+
+func gitBound(output string) string {
+	if len(output) <= maxGitOutput {
+		return output
+	}
+
+	return strings.ToValidUTF8(output[:maxGitOutput], "") + "\n[truncated]"
+}
+
+func gitRun(ctx context.Context, root string, env []string, args ...string) (string, error) {
+	var commandCtx context.Context
+	var cancel context.CancelFunc
+	var command *exec.Cmd
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	var message string
+
+	var err error
+
+	commandCtx, cancel = context.WithTimeout(ctx, gitTimeout)
+	defer cancel()
+
+	command = exec.CommandContext(commandCtx, "git",
+		append([]string{"--no-pager", "-c", "core.quotepath=false", "-c", "color.ui=false", "-c", "core.fsmonitor=false"}, args...)...)
+	command.Dir = root
+	command.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_OPTIONAL_LOCKS=0", "GIT_EDITOR=true")
+	command.Env = append(command.Env, env...)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+	command.WaitDelay = bashWaitDelay
+	command.Cancel = func() error { return bashTerminate(command) }
+	bashIsolate(command)
+
+	err = command.Run()
+	if commandCtx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("git %s timed out after %s", args[0], gitTimeout)
+	}
+	if err != nil {
+		message = strings.TrimSpace(stderr.String())
+		if message == "" {
+			message = strings.TrimSpace(stdout.String())
+		}
+		if message == "" {
+			return "", fmt.Errorf("git %s failed: %w", args[0], err)
+		}
+
+		return "", fmt.Errorf("git %s failed: %s", args[0], gitBound(message))
+	}
+
+	return stdout.String(), nil
+}
+
+func gitPathspec(base, path string) (string, error) {
+	var target string
+	var rel string
+
+	var err error
+
+	target, err = util.SafeJoin(base, path)
+	if err != nil {
+		return "", err
+	}
+	rel, err = filepath.Rel(base, target)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path escapes working directory: %q", path)
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+func gitPathspecs(base string, paths []string) ([]string, error) {
+	var specs []string
+	var path string
+	var spec string
+
+	var err error
+
+	for _, path = range paths {
+		spec, err = gitPathspec(base, path)
+		if err != nil {
+			return nil, err
+		}
+
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+func gitRef(ref string) error {
+	if strings.HasPrefix(ref, "-") || strings.IndexFunc(ref, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) >= 0 {
+		return fmt.Errorf("invalid ref %q", ref)
+	}
+
+	return nil
+}
+
+func gitLimit(limit int) (int, error) {
+	if limit <= 0 {
+		return defaultGitLimit, nil
+	}
+	if limit > maxGitLimit {
+		return 0, fmt.Errorf("limit cannot exceed %d", maxGitLimit)
+	}
+
+	return limit, nil
+}
+
+func gitStatusParse(output, prefix string) gitStatus {
+	var status gitStatus
+	var records []string
+	var index int
+	var record string
+	var fields []string
+	var change gitChange
+	var oid string
+
+	status.Changed = []gitChange{}
+	status.Conflicted = []string{}
+	status.Untracked = []string{}
+
+	records = strings.Split(output, "\x00")
+	for index = 0; index < len(records); index++ {
+		record = records[index]
+		if len(status.Changed)+len(status.Conflicted)+len(status.Untracked) >= maxGitEntries && !strings.HasPrefix(record, "#") {
+			status.Truncated = record != ""
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(record, "# branch.oid "):
+			oid = strings.TrimPrefix(record, "# branch.oid ")
+		case strings.HasPrefix(record, "# branch.head "):
+			status.Branch = strings.TrimPrefix(record, "# branch.head ")
+		case strings.HasPrefix(record, "# branch.upstream "):
+			status.Upstream = strings.TrimPrefix(record, "# branch.upstream ")
+		case strings.HasPrefix(record, "# branch.ab "):
+			fields = strings.Fields(strings.TrimPrefix(record, "# branch.ab "))
+			if len(fields) == 2 {
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[0], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "-"))
+			}
+		case strings.HasPrefix(record, "1 "):
+			fields = strings.SplitN(record, " ", 9)
+			if len(fields) < 9 {
+				continue
+			}
+			status.Changed = append(status.Changed, gitChange{
+				Path: strings.TrimPrefix(fields[8], prefix), Staged: gitCodes[fields[1][0]], Unstaged: gitCodes[fields[1][1]],
+			})
+		case strings.HasPrefix(record, "2 "):
+			fields = strings.SplitN(record, " ", 10)
+			if len(fields) < 10 {
+				continue
+			}
+			change = gitChange{
+				Path: strings.TrimPrefix(fields[9], prefix), Staged: gitCodes[fields[1][0]], Unstaged: gitCodes[fields[1][1]],
+			}
+			if index+1 < len(records) {
+				index++
+				change.From = strings.TrimPrefix(records[index], prefix)
+			}
+			status.Changed = append(status.Changed, change)
+		case strings.HasPrefix(record, "u "):
+			fields = strings.SplitN(record, " ", 11)
+			if len(fields) == 11 {
+				status.Conflicted = append(status.Conflicted, strings.TrimPrefix(fields[10], prefix))
+			}
+		case strings.HasPrefix(record, "? "):
+			status.Untracked = append(status.Untracked, strings.TrimPrefix(record[2:], prefix))
+		}
+	}
+
+	if status.Branch == "(detached)" && len(oid) >= 12 {
+		status.Branch = "detached at " + oid[:12]
+	}
+
+	return status
+}
+
+func gitStatusRead(ctx context.Context, base string, specs []string) (string, error) {
+	var prefix string
+	var output string
+	var buf []byte
+
+	var err error
+
+	prefix, err = gitRun(ctx, base, nil, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", err
+	}
+	if len(specs) == 0 {
+		specs = []string{"."}
+	}
+
+	output, err = gitRun(ctx, base, nil, append([]string{"status", "--porcelain=v2", "--branch", "-z", "--untracked-files=all", "--"}, specs...)...)
+	if err != nil {
+		return "", err
+	}
+
+	buf, err = json.Marshal(gitStatusParse(output, strings.TrimSpace(prefix)))
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+func gitLogParse(output string) []gitCommit {
+	var commits []gitCommit
+	var record string
+	var fields []string
+
+	commits = []gitCommit{}
+
+	for _, record = range strings.Split(output, "\x1e") {
+		fields = strings.Split(strings.TrimLeft(record, "\n"), "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+
+		commits = append(commits, gitCommit{Hash: fields[0], Author: fields[1], Email: fields[2], Date: fields[3], Subject: fields[4]})
+	}
+
+	return commits
+}
+
+func gitLogRead(ctx context.Context, base, ref string, limit int, specs []string) ([]gitCommit, error) {
+	var args []string
+	var output string
+
+	var err error
+
+	args = []string{"log", "--no-show-signature", "--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%s%x1e", "-n", strconv.Itoa(limit)}
+	if ref != "" {
+		args = append(args, ref)
+	}
+	args = append(args, "--")
+	args = append(args, specs...)
+
+	output, err = gitRun(ctx, base, nil, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return gitLogParse(output), nil
+}
+
+func gitBlameParse(output string) string {
+	var body strings.Builder
+	var authors map[string]string
+	var dates map[string]string
+	var line string
+	var fields []string
+	var hash string
+	var number string
+	var stamp int64
+
+	authors = make(map[string]string)
+	dates = make(map[string]string)
+
+	for _, line = range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			fmt.Fprintf(&body, "%.8s %s %s %s: %s\n", hash, authors[hash], dates[hash], number, line[1:])
+			continue
+		}
+
+		fields = strings.Fields(line)
+		if len(fields) >= 3 && len(fields[0]) >= 40 && strings.Trim(fields[0], "0123456789abcdef") == "" {
+			hash = fields[0]
+			number = fields[2]
+			continue
+		}
+		if strings.HasPrefix(line, "author ") {
+			authors[hash] = strings.TrimPrefix(line, "author ")
+		} else if strings.HasPrefix(line, "author-time ") {
+			stamp, _ = strconv.ParseInt(strings.TrimPrefix(line, "author-time "), 10, 64)
+			dates[hash] = time.Unix(stamp, 0).Format("2006-01-02")
+		}
+	}
+
+	return body.String()
+}
+
+func gitRead(ctx context.Context, base string, payload gitArguments) (string, error) {
+	var specs []string
+	var limit int
+	var args []string
+	var commits []gitCommit
+	var output string
+	var buf []byte
+
+	var err error
+
+	if payload.Path != "" {
+		specs, err = gitPathspecs(base, []string{payload.Path})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	switch payload.Action {
+	case "status":
+		return gitStatusRead(ctx, base, specs)
+	case "log":
+		limit, err = gitLimit(payload.Limit)
+		if err != nil {
+			return "", err
+		}
+		commits, err = gitLogRead(ctx, base, payload.Ref, limit, specs)
+		if err != nil {
+			return "", err
+		}
+
+		buf, err = json.Marshal(commits)
+		if err != nil {
+			return "", err
+		}
+
+		return string(buf), nil
+	case "diff":
+		args = []string{"diff", "--no-ext-diff", "--no-textconv", "--relative", "--stat", "--patch"}
+		if payload.Staged {
+			args = append(args, "--cached")
+		}
+		if payload.Ref != "" {
+			args = append(args, payload.Ref)
+		}
+		if len(specs) == 0 {
+			specs = []string{"."}
+		}
+		args = append(append(args, "--"), specs...)
+	case "show":
+		if payload.Ref == "" {
+			payload.Ref = "HEAD"
+		}
+		args = append([]string{"show", "--no-ext-diff", "--no-textconv", "--no-show-signature", "--format=fuller", "--relative", "--stat", "--patch",
+			payload.Ref, "--"}, specs...)
+	case "blame":
+		if payload.Path == "" {
+			return "", fmt.Errorf("path is required for blame")
+		}
+		args = []string{"blame", "--porcelain"}
+		if payload.Line > 0 {
+			limit, err = gitLimit(payload.Limit)
+			if err != nil {
+				return "", err
+			}
+			args = append(args, "-L", fmt.Sprintf("%d,+%d", payload.Line, limit))
+		}
+		if payload.Ref != "" {
+			args = append(args, payload.Ref)
+		}
+		args = append(append(args, "--"), specs...)
+	}
+
+	output, err = gitRun(ctx, base, nil, args...)
+	if err != nil {
+		return "", err
+	}
+	if payload.Action == "blame" {
+		output = gitBlameParse(output)
+	}
+	if strings.TrimSpace(output) == "" {
+		return "no changes", nil
+	}
+
+	return gitBound(output), nil
+}
+
+func gitWrite(ctx context.Context, base string, payload gitArguments) (string, error) {
+	var specs []string
+	var args []string
+	var commits []gitCommit
+	var output string
+	var list string
+	var buf []byte
+
+	var err error
+
+	specs, err = gitPathspecs(base, payload.Paths)
+	if err != nil {
+		return "", err
+	}
+
+	switch payload.Action {
+	case "add":
+		if len(specs) == 0 {
+			return "", fmt.Errorf("paths are required for add")
+		}
+		_, err = gitRun(ctx, base, nil, append([]string{"add", "--"}, specs...)...)
+		if err != nil {
+			return "", err
+		}
+
+		return gitStatusRead(ctx, base, nil)
+	case "commit":
+		if strings.TrimSpace(payload.Message) == "" {
+			return "", fmt.Errorf("message is required for commit")
+		}
+		args = []string{"commit", "-m", payload.Message}
+		if len(specs) > 0 {
+			args = append(append(args, "--only", "--"), specs...)
+		}
+		_, err = gitRun(ctx, base, gitAuthorEnv(), args...)
+		if err != nil {
+			return "", err
+		}
+		commits, err = gitLogRead(ctx, base, "HEAD", 1, nil)
+		if err != nil {
+			return "", err
+		}
+		if len(commits) == 0 {
+			return "", fmt.Errorf("commit succeeded but HEAD could not be read")
+		}
+
+		buf, err = json.Marshal(commits[0])
+		if err != nil {
+			return "", err
+		}
+
+		return string(buf), nil
+	case "checkout":
+		if payload.Ref == "" && len(specs) == 0 {
+			return "", fmt.Errorf("ref or paths are required for checkout")
+		}
+		if payload.Create && (payload.Ref == "" || len(specs) > 0) {
+			return "", fmt.Errorf("create needs a ref and no paths")
+		}
+		args = []string{"checkout"}
+		if payload.Create {
+			args = append(args, "-b")
+		}
+		if payload.Ref != "" {
+			args = append(args, payload.Ref)
+		}
+		if len(specs) > 0 {
+			args = append(append(args, "--"), specs...)
+		}
+		_, err = gitRun(ctx, base, nil, args...)
+		if err != nil {
+			return "", err
+		}
+
+		return gitStatusRead(ctx, base, nil)
+	case "stash":
+		switch payload.Stash {
+		case "", "push":
+			args = []string{"stash", "push"}
+			if payload.Message != "" {
+				args = append(args, "-m", payload.Message)
+			}
+			if len(specs) > 0 {
+				args = append(append(args, "--"), specs...)
+			}
+		case "pop", "apply", "drop":
+			args = []string{"stash", payload.Stash}
+			if payload.Ref != "" {
+				args = append(args, payload.Ref)
+			}
+		case "list":
+			args = []string{"stash", "list"}
+		default:
+			return "", fmt.Errorf("invalid stash operation %q", payload.Stash)
+		}
+		output, err = gitRun(ctx, base, gitAuthorEnv(), args...)
+		if err != nil {
+			return "", err
+		}
+		if payload.Stash == "list" {
+			if output == "" {
+				return "no stash entries", nil
+			}
+
+			return gitBound(output), nil
+		}
+		list, err = gitRun(ctx, base, nil, "stash", "list")
+		if err != nil {
+			return "", err
+		}
+		if list == "" {
+			list = "no stash entries"
+		}
+
+		return gitBound(strings.TrimSpace(output) + "\n\nstash:\n" + list), nil
+	}
+
+	return "", fmt.Errorf("invalid action %q", payload.Action)
+}
+
+func gitExecute(root string, actions []string, run func(context.Context, string, gitArguments) (string, error)) func(context.Context, string) (string, error) {
+	return func(ctx context.Context, arguments string) (string, error) {
+		var payload gitArguments
+		var base string
+
+		var err error
+
+		err = json.Unmarshal([]byte(arguments), &payload)
+		if err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+		if !slices.Contains(actions, payload.Action) {
+			return "", fmt.Errorf("invalid action %q, want %s", payload.Action, strings.Join(actions, ", "))
+		}
+		err = gitRef(payload.Ref)
+		if err != nil {
+			return "", err
+		}
+		base, err = toolRoot(root)
+		if err != nil {
+			return "", err
+		}
+
+		return run(ctx, base, payload)
+	}
+}
+
+func Git(root string) Def {
+	var actions []string
+
+	actions = []string{"status", "diff", "log", "show", "blame"}
+
+	return Def{
+		Name: GitToolName,
+		Description: "Inspect the git repository at the process startup directory without changing it. " +
+			"status and log answer with JSON; diff and show answer with a stat summary and a unified diff; " +
+			"blame answers with one \"commit author date line: text\" row per line. " +
+			"Paths are relative to the startup directory. Use git_write to stage, commit, check out, or stash.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action": map[string]any{"type": "string", "enum": actions},
+				"path":   map[string]any{"type": "string", "description": "Limit the answer to this file or directory; required by blame."},
+				"ref":    map[string]any{"type": "string", "description": "Commit, branch, or range such as main..HEAD. diff compares against it, show defaults to HEAD."},
+				"staged": map[string]any{"type": "boolean", "description": "diff the index instead of the working tree."},
+				"line":   map[string]any{"type": "integer", "minimum": 1, "description": "First line blame should cover."},
+				"limit":  map[string]any{"type": "integer", "minimum": 1, "maximum": maxGitLimit, "description": "Commits for log, or lines after line for blame. Defaults to 20."},
+			},
+			"required":             []string{"action"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionDangerous,
+		Execute:    gitExecute(root, actions, gitRead),
+	}
+}
+
+func GitWrite(root string) Def {
+	var actions []string
+
+	actions = []string{"add", "commit", "checkout", "stash"}
+
+	return Def{
+		Name: GitWriteToolName,
+		Description: "Change the git repository at the process startup directory. add stages paths; commit records the staged changes, " +
+			"or only paths when given, with message; checkout switches to ref, creating it with create, or restores paths from ref or the index, " +
+			"discarding their working tree edits; stash pushes, pops, applies, drops, or lists stashed changes. " +
+			"Commit hooks run as usual. Use git for status, diff, and log.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action":  map[string]any{"type": "string", "enum": actions},
+				"paths":   map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"message": map[string]any{"type": "string", "description": "Commit message, or stash message for push."},
+				"ref":     map[string]any{"type": "string", "description": "Branch or commit for checkout, or stash entry such as stash@{1}."},
+				"create":  map[string]any{"type": "boolean", "description": "Create ref as a new branch on checkout."},
+				"stash":   map[string]any{"type": "string", "enum": []string{"push", "pop", "apply", "drop", "list"}},
+			},
+			"required":             []string{"action"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionDangerous,
+		Execute:    gitExecute(root, actions, gitWrite),
+	}
+}
@@ -0,0 +1,186 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gitTestRepo(t *testing.T) string {
+	var root string
+
+	var err error
+
+	t.Helper()
+
+	_, err = exec.LookPath("git")
+	if err != nil {
+		t.Skip("git is not installed")
+	}
+
+	root, err = toolRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = gitRun(context.Background(), root, nil, "init", "-q", "-b", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("alpha\nbeta\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetGitAuthor("Test Author", "author@example.com")
+	t.Cleanup(func() { SetGitAuthor("", "") })
+
+	return root
+}
+
+func TestGitWriteCommitsWithTheConfiguredAuthor(t *testing.T) {
+	var root string
+	var result string
+	var commit gitCommit
+	var commits []gitCommit
+
+	var err error
+
+	root = gitTestRepo(t)
+
+	_, err = GitWrite(root).Execute(context.Background(), `{"action":"add","paths":["a.txt"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = GitWrite(root).Execute(context.Background(), `{"action":"commit","message":"add alpha"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal([]byte(result), &commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Author != "Test Author" || commit.Email != "author@example.com" || commit.Subject != "add alpha" || len(commit.Hash) < 40 {
+		t.Fatalf("commit = %+v", commit)
+	}
+
+	result, err = Git(root).Execute(context.Background(), `{"action":"log","limit":5}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal([]byte(result), &commits)
+	if err != nil || len(commits) != 1 || commits[0].Hash != commit.Hash {
+		t.Fatalf("log = %q, %v", result, err)
+	}
+
+	result, err = Git(root).Execute(context.Background(), `{"action":"blame","path":"a.txt","line":2,"limit":1}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != commit.Hash[:8]+" Test Author "+commit.Date[:10]+" 2: beta\n" {
+		t.Fatalf("blame = %q", result)
+	}
+}
+
+func TestGitReportsStatusAndDiffRelativeToTheRoot(t *testing.T) {
+	var root string
+	var sub string
+	var result string
+	var status gitStatus
+
+	var err error
+
+	root = gitTestRepo(t)
+	sub = filepath.Join(root, "sub")
+	err = os.Mkdir(sub, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(sub, "b.txt"), []byte("one\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GitWrite(root).Execute(context.Background(), `{"action":"add","paths":["a.txt","sub/b.txt"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GitWrite(root).Execute(context.Background(), `{"action":"commit","message":"initial"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(sub, "b.txt"), []byte("two\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(sub, "new.txt"), []byte("new\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("changed\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = Git(sub).Execute(context.Background(), `{"action":"status"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = json.Unmarshal([]byte(result), &status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Branch != "main" || len(status.Changed) != 1 || status.Changed[0].Path != "b.txt" ||
+		status.Changed[0].Unstaged != "modified" || status.Changed[0].Staged != "" ||
+		len(status.Untracked) != 1 || status.Untracked[0] != "new.txt" {
+		t.Fatalf("status = %s", result)
+	}
+
+	result, err = Git(sub).Execute(context.Background(), `{"action":"diff"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "-one") || !strings.Contains(result, "+two") || !strings.Contains(result, "+++ b/b.txt") ||
+		strings.Contains(result, "a.txt") {
+		t.Fatalf("diff = %q", result)
+	}
+
+	_, err = GitWrite(sub).Execute(context.Background(), `{"action":"checkout","paths":["b.txt"]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = Git(sub).Execute(context.Background(), `{"action":"diff"}`)
+	if err != nil || result != "no changes" {
+		t.Fatalf("diff after checkout = %q, %v", result, err)
+	}
+}
+
+func TestGitRejectsEscapesAndOptionRefs(t *testing.T) {
+	var root string
+
+	var err error
+
+	root = gitTestRepo(t)
+
+	_, err = Git(root).Execute(context.Background(), `{"action":"diff","path":"../outside"}`)
+	if err == nil {
+		t.Fatal("git read a path outside the root")
+	}
+	_, err = Git(root).Execute(context.Background(), `{"action":"show","ref":"--output=/tmp/leak"}`)
+	if err == nil || !strings.Contains(err.Error(), "invalid ref") {
+		t.Fatalf("option ref error = %v", err)
+	}
+	_, err = Git(root).Execute(context.Background(), `{"action":"commit","message":"x"}`)
+	if err == nil || !strings.Contains(err.Error(), "invalid action") {
+		t.Fatalf("git accepted a write action: %v", err)
+	}
+	_, err = GitWrite(root).Execute(context.Background(), `{"action":"add","paths":["../outside"]}`)
+	if err == nil {
+		t.Fatal("git_write staged a path outside the root")
+	}
+}
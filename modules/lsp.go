@@ -0,0 +1,617 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+type lspTextEdit struct {
+	Range   lspRange `json:"range"`
+	NewText string   `json:"newText"`
+}
+
+type lspDocumentChange struct {
+	Kind         string `json:"kind"`
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Edits  []lspTextEdit `json:"edits"`
+	URI    string        `json:"uri"`
+	OldURI string        `json:"oldUri"`
+	NewURI string        `json:"newUri"`
+}
+
+type lspWorkspaceEdit struct {
+	Changes         map[string][]lspTextEdit `json:"changes"`
+	DocumentChanges []lspDocumentChange      `json:"documentChanges"`
+}
+
+type lspSymbol struct {
+	Name          string `json:"name"`
+	Kind          int    `json:"kind"`
+	ContainerName string `json:"containerName"`
+	Location      struct {
+		URI   string    `json:"uri"`
+		Range *lspRange `json:"range"`
+	} `json:"location"`
+}
+
+type lspView struct {
+	root  string
+	files map[string][]string
+}
+
+const LSPToolName = "lsp"
+
+const lspMaxResults = 200
+
+var lspSeverities map[int]string = map[int]string{1: "error", 2: "warning", 3: "info", 4: "hint"}
+
+var lspSymbolKinds map[int]string = map[int]string{
+	1: "file", 2: "module", 3: "namespace", 4: "package", 5: "class", 6: "method", 7: "property", 8: "field",
+	9: "constructor", 10: "enum", 11: "interface", 12: "function", 13: "variable", 14: "constant", 15: "string",
+	16: "number", 17: "boolean", 18: "array", 19: "object", 20: "key", 21: "null", 22: "enum member", 23: "struct",
+	24: "event", 25: "operator", 26: "type parameter",
+}
+
+func lspCharacter(line string, column int) int {
+	var units int
+	var index int
+	var current rune
+
+	for _, current = range line {
+		if index >= column {
+			break
+		}
+		units += utf16.RuneLen(current)
+		index++
+	}
+
+	return units
+}
+
+func lspColumn(line string, character int) int {
+	var units int
+	var column int
+	var current rune
+
+	for _, current = range line {
+		if units >= character {
+			break
+		}
+		units += utf16.RuneLen(current)
+		column++
+	}
+
+	return column + 1
+}
+
+func lspOffset(text string, position lspPosition) int {
+	var offset int
+	var line int
+	var next int
+	var end int
+	var units int
+	var current rune
+	var size int
+
+	for line < position.Line {
+		next = strings.IndexByte(text[offset:], '\n')
+		if next < 0 {
+			return len(text)
+		}
+		offset += next + 1
+		line++
+	}
+
+	end = strings.IndexByte(text[offset:], '\n')
+	if end < 0 {
+		end = len(text)
+	} else {
+		end += offset
+	}
+	for offset < end && units < position.Character {
+		current, size = utf8.DecodeRuneInString(text[offset:])
+		units += utf16.RuneLen(current)
+		offset += size
+	}
+
+	return offset
+}
+
+func lspPositionOf(text string, line, column int, symbol string) (lspPosition, error) {
+	var lines []string
+	var current string
+	var index int
+
+	lines = strings.Split(text, "\n")
+	if line < 1 || line > len(lines) {
+		return lspPosition{}, fmt.Errorf("line %d is outside the file, which has %d lines", line, len(lines))
+	}
+	current = lines[line-1]
+
+	if symbol != "" {
+		index = strings.Index(current, symbol)
+		if index < 0 {
+			return lspPosition{}, fmt.Errorf("%q does not appear on line %d", symbol, line)
+		}
+		return lspPosition{Line: line - 1, Character: lspCharacter(current, utf8.RuneCountInString(current[:index]))}, nil
+	}
+	if column < 1 {
+		return lspPosition{}, fmt.Errorf("pass the symbol on that line or a 1-based column")
+	}
+
+	return lspPosition{Line: line - 1, Character: lspCharacter(current, column-1)}, nil
+}
+
+func (v *lspView) relative(path string) (string, bool) {
+	var rel string
+
+	var err error
+
+	rel, err = filepath.Rel(v.root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path, false
+	}
+
+	return rel, true
+}
+
+func (v *lspView) place(uri string, at lspRange) string {
+	var path string
+	var rel string
+	var inside bool
+	var lines []string
+	var cached bool
+	var buf []byte
+	var line string
+
+	var err error
+
+	path = lspPath(uri)
+	rel, inside = v.relative(path)
+	if !inside {
+		return fmt.Sprintf("%s:%d", rel, at.Start.Line+1)
+	}
+
+	lines, cached = v.files[path]
+	if !cached {
+		buf, err = readTextFile(path)
+		if err == nil {
+			lines = strings.Split(string(buf), "\n")
+		}
+		v.files[path] = lines
+	}
+	if at.Start.Line >= len(lines) {
+		return fmt.Sprintf("%s:%d", rel, at.Start.Line+1)
+	}
+	line = strings.TrimRight(lines[at.Start.Line], "\r")
+
+	return fmt.Sprintf("%s:%d:%d: %s", rel, at.Start.Line+1, lspColumn(line, at.Start.Character), strings.TrimSpace(line))
+}
+
+func lspLocations(raw json.RawMessage) ([]lspLocation, error) {
+	var items []json.RawMessage
+	var item json.RawMessage
+	var entry struct {
+		URI                  string    `json:"uri"`
+		Range                lspRange  `json:"range"`
+		TargetURI            string    `json:"targetUri"`
+		TargetSelectionRange *lspRange `json:"targetSelectionRange"`
+	}
+	var locations []lspLocation
+
+	var err error
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] != '[' {
+		items = []json.RawMessage{raw}
+	} else {
+		err = json.Unmarshal(raw, &items)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, item = range items {
+		entry.TargetURI, entry.TargetSelectionRange = "", nil
+		err = json.Unmarshal(item, &entry)
+		if err != nil {
+			return nil, err
+		}
+		if entry.TargetURI != "" && entry.TargetSelectionRange != nil {
+			locations = append(locations, lspLocation{URI: entry.TargetURI, Range: *entry.TargetSelectionRange})
+			continue
+		}
+		locations = append(locations, lspLocation{URI: entry.URI, Range: entry.Range})
+	}
+
+	return locations, nil
+}
+
+func lspHoverText(raw json.RawMessage) string {
+	var hover struct {
+		Contents json.RawMessage `json:"contents"`
+	}
+	var parts []json.RawMessage
+	var part json.RawMessage
+	var marked struct {
+		Language string `json:"language"`
+		Value    string `json:"value"`
+	}
+	var plain string
+	var texts []string
+
+	if json.Unmarshal(raw, &hover) != nil || len(hover.Contents) == 0 || string(hover.Contents) == "null" {
+		return ""
+	}
+
+	parts = []json.RawMessage{hover.Contents}
+	if hover.Contents[0] == '[' {
+		json.Unmarshal(hover.Contents, &parts)
+	}
+	for _, part = range parts {
+		if json.Unmarshal(part, &plain) == nil {
+			texts = append(texts, plain)
+			continue
+		}
+		marked.Language, marked.Value = "", ""
+		if json.Unmarshal(part, &marked) == nil && marked.Value != "" {
+			if marked.Language != "" {
+				texts = append(texts, "```"+marked.Language+"\n"+marked.Value+"\n```")
+			} else {
+				texts = append(texts, marked.Value)
+			}
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(texts, "\n\n"))
+}
+
+func lspList(lines []string, empty string) string {
+	var more int
+
+	if len(lines) == 0 {
+		return empty
+	}
+	if len(lines) > lspMaxResults {
+		more = len(lines) - lspMaxResults
+		lines = append(lines[:lspMaxResults], fmt.Sprintf("[%d more]", more))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func lspApplyEdits(text string, edits []lspTextEdit) string {
+	var ordered []lspTextEdit
+	var edit lspTextEdit
+	var start int
+	var end int
+
+	ordered = append([]lspTextEdit{}, edits...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Range.Start.Line != ordered[j].Range.Start.Line {
+			return ordered[i].Range.Start.Line > ordered[j].Range.Start.Line
+		}
+		return ordered[i].Range.Start.Character > ordered[j].Range.Start.Character
+	})
+
+	for _, edit = range ordered {
+		start = lspOffset(text, edit.Range.Start)
+		end = lspOffset(text, edit.Range.End)
+		if end < start {
+			end = start
+		}
+		text = text[:start] + edit.NewText + text[end:]
+	}
+
+	return text
+}
+
+func (v *lspView) renameDiff(edit lspWorkspaceEdit) (string, error) {
+	var edits map[string][]lspTextEdit
+	var notes []string
+	var change lspDocumentChange
+	var uris []string
+	var uri string
+	var path string
+	var rel string
+	var inside bool
+	var buf []byte
+	var diff strings.Builder
+
+	var err error
+
+	edits = make(map[string][]lspTextEdit)
+	for uri = range edit.Changes {
+		edits[uri] = append(edits[uri], edit.Changes[uri]...)
+	}
+	for _, change = range edit.DocumentChanges {
+		switch change.Kind {
+		case "create":
+			rel, _ = v.relative(lspPath(change.URI))
+			notes = append(notes, "also creates "+rel)
+		case "rename":
+			rel, _ = v.relative(lspPath(change.OldURI))
+			path, _ = v.relative(lspPath(change.NewURI))
+			notes = append(notes, "also renames "+rel+" to "+path)
+		case "delete":
+			rel, _ = v.relative(lspPath(change.URI))
+			notes = append(notes, "also deletes "+rel)
+		default:
+			edits[change.TextDocument.URI] = append(edits[change.TextDocument.URI], change.Edits...)
+		}
+	}
+	if len(edits) == 0 && len(notes) == 0 {
+		return "", fmt.Errorf("the language server proposed no edits for this rename")
+	}
+
+	for uri = range edits {
+		uris = append(uris, uri)
+	}
+	sort.Strings(uris)
+
+	for _, uri = range uris {
+		path = lspPath(uri)
+		rel, inside = v.relative(path)
+		if !inside {
+			notes = append(notes, "skips "+rel+", which is outside the working directory")
+			continue
+		}
+		buf, err = readTextFile(path)
+		if err != nil {
+			return "", err
+		}
+		diff.WriteString(fileDiff(rel, string(buf), lspApplyEdits(string(buf), edits[uri])))
+	}
+
+	if len(notes) > 0 {
+		diff.WriteString("\n" + strings.Join(notes, "\n") + "\n")
+	}
+	diff.WriteString("\npreview only, nothing was written; read these files and apply the diff with file_patch")
+
+	return diff.String(), nil
+}
+
+func lspDiagnosticLines(view *lspView, uri string, diagnostics []lspDiagnostic) []string {
+	var lines []string
+	var diagnostic lspDiagnostic
+	var severity string
+	var line string
+
+	for _, diagnostic = range diagnostics {
+		severity = lspSeverities[diagnostic.Severity]
+		if severity == "" {
+			severity = "error"
+		}
+		line = view.place(uri, diagnostic.Range) + "\n  " + severity + ": " + strings.ReplaceAll(diagnostic.Message, "\n", "\n  ")
+		if diagnostic.Source != "" {
+			line += " (" + diagnostic.Source + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+func lspSymbolLines(view *lspView, symbols []lspSymbol) []string {
+	var lines []string
+	var symbol lspSymbol
+	var line string
+	var at lspRange
+
+	for _, symbol = range symbols {
+		line = lspSymbolKinds[symbol.Kind] + " " + symbol.Name
+		if symbol.ContainerName != "" {
+			line += " in " + symbol.ContainerName
+		}
+		if symbol.Location.Range != nil {
+			at = *symbol.Location.Range
+		} else {
+			at = lspRange{}
+		}
+		lines = append(lines, strings.TrimSpace(line)+"\n  "+view.place(symbol.Location.URI, at))
+	}
+
+	return lines
+}
+
+func LSP(root string) Def {
+	return Def{
+		Name: LSPToolName,
+		Description: "Ask the language server configured for a file type in lsp.json about code relative to the process startup directory. " +
+			"definition, references, hover, and rename_preview take path, a 1-based line, and the symbol on that line or a 1-based column. " +
+			"diagnostics takes path and reports compiler errors and warnings. symbols searches the workspace for query, " +
+			"using the server for path when given or every configured server otherwise. " +
+			"rename_preview returns the unified diff a rename to new_name would make without writing anything.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"action": map[string]any{"type": "string",
+					"enum": []string{"definition", "references", "hover", "diagnostics", "symbols", "rename_preview"}},
+				"path":     map[string]any{"type": "string"},
+				"line":     map[string]any{"type": "integer", "minimum": 1},
+				"column":   map[string]any{"type": "integer", "minimum": 1},
+				"symbol":   map[string]any{"type": "string", "description": "Text on the line to position at, usually the identifier."},
+				"query":    map[string]any{"type": "string", "description": "Symbol name or prefix required by symbols."},
+				"new_name": map[string]any{"type": "string", "description": "Replacement name required by rename_preview."},
+			},
+			"required":             []string{"action"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionDangerous,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Action  string `json:"action"`
+				Path    string `json:"path"`
+				Line    int    `json:"line"`
+				Column  int    `json:"column"`
+				Symbol  string `json:"symbol"`
+				Query   string `json:"query"`
+				NewName string `json:"new_name"`
+			}
+			var cancel context.CancelFunc
+			var base string
+			var view *lspView
+			var target string
+			var servers []LSPServer
+			var server LSPServer
+			var client *lspClient
+			var buf []byte
+			var position lspPosition
+			var document map[string]any
+			var raw json.RawMessage
+			var locations []lspLocation
+			var location lspLocation
+			var lines []string
+			var diagnostics []lspDiagnostic
+			var symbols []lspSymbol
+			var found []lspSymbol
+			var edit lspWorkspaceEdit
+			var hover string
+
+			var err error
+
+			if err = ctx.Err(); err != nil {
+				return "", err
+			}
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			switch payload.Action {
+			case "definition", "references", "hover", "diagnostics", "symbols", "rename_preview":
+			default:
+				return "", fmt.Errorf("invalid action %q", payload.Action)
+			}
+
+			ctx, cancel = context.WithTimeout(ctx, lspRequestTimeout)
+			defer cancel()
+
+			base, err = toolRoot(root)
+			if err != nil {
+				return "", err
+			}
+			view = &lspView{root: base, files: make(map[string][]string)}
+
+			if payload.Path != "" {
+				target, err = readPath(base, payload.Path)
+				if err != nil {
+					return "", err
+				}
+				server, err = lspServerFor(target)
+				if err != nil {
+					return "", err
+				}
+				servers = []LSPServer{server}
+			}
+
+			if payload.Action == "symbols" {
+				if payload.Query == "" {
+					return "", fmt.Errorf("query is required")
+				}
+				if len(servers) == 0 {
+					servers = LSPServers()
+				}
+				if len(servers) == 0 {
+					return "", fmt.Errorf("no language servers are configured in %s", LSP_PATH)
+				}
+				for _, server = range servers {
+					client, err = lspClientFor(ctx, server, base)
+					if err != nil {
+						return "", err
+					}
+					found = nil
+					err = client.call(ctx, "workspace/symbol", map[string]any{"query": payload.Query}, &found)
+					if err != nil {
+						return "", err
+					}
+					symbols = append(symbols, found...)
+				}
+				return lspList(lspSymbolLines(view, symbols), "no symbols match "+payload.Query), nil
+			}
+
+			if payload.Path == "" {
+				return "", fmt.Errorf("path is required")
+			}
+			client, err = lspClientFor(ctx, server, base)
+			if err != nil {
+				return "", err
+			}
+
+			if payload.Action == "diagnostics" {
+				diagnostics, err = client.diagnostics(ctx, target)
+				if err != nil {
+					return "", err
+				}
+				return lspList(lspDiagnosticLines(view, lspURI(target), diagnostics), "no diagnostics for "+payload.Path), nil
+			}
+
+			buf, err = readTextFile(target)
+			if err != nil {
+				return "", err
+			}
+			position, err = lspPositionOf(string(buf), payload.Line, payload.Column, payload.Symbol)
+			if err != nil {
+				return "", err
+			}
+			_, err = client.sync(target)
+			if err != nil {
+				return "", err
+			}
+			document = map[string]any{"textDocument": map[string]string{"uri": lspURI(target)}, "position": position}
+
+			switch payload.Action {
+			case "definition", "references":
+				if payload.Action == "references" {
+					document["context"] = map[string]bool{"includeDeclaration": true}
+				}
+				err = client.call(ctx, "textDocument/"+payload.Action, document, &raw)
+				if err != nil {
+					return "", err
+				}
+				locations, err = lspLocations(raw)
+				if err != nil {
+					return "", err
+				}
+				for _, location = range locations {
+					lines = append(lines, view.place(location.URI, location.Range))
+				}
+				return lspList(lines, "no "+payload.Action+" found"), nil
+			case "hover":
+				err = client.call(ctx, "textDocument/hover", document, &raw)
+				if err != nil {
+					return "", err
+				}
+				hover = lspHoverText(raw)
+				if hover == "" {
+					return "no hover information", nil
+				}
+				return hover, nil
+			case "rename_preview":
+				if payload.NewName == "" {
+					return "", fmt.Errorf("new_name is required")
+				}
+				document["newName"] = payload.NewName
+				err = client.call(ctx, "textDocument/rename", document, &edit)
+				if err != nil {
+					return "", err
+				}
+				return view.renameDiff(edit)
+			}
+
+			return "", fmt.Errorf("invalid action %q", payload.Action)
+		},
+	}
+}
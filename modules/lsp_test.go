@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func fakeLanguageFrame(writer io.Writer, message map[string]any) {
+	var body []byte
+
+	body, _ = json.Marshal(message)
+	fmt.Fprintf(writer, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
+
+func fakeLanguageServer(reader io.Reader, writer io.WriteCloser, uri string) {
+	var buffered *bufio.Reader
+	var body []byte
+	var message lspMessage
+	var result any
+	var at func(line, start, end int) map[string]any
+
+	var err error
+
+	defer writer.Close()
+
+	at = func(line, start, end int) map[string]any {
+		return map[string]any{"start": map[string]int{"line": line, "character": start}, "end": map[string]int{"line": line, "character": end}}
+	}
+
+	buffered = bufio.NewReader(reader)
+	for {
+		body, err = lspReadFrame(buffered)
+		if err != nil {
+			return
+		}
+		message = lspMessage{}
+		json.Unmarshal(body, &message)
+
+		switch message.Method {
+		case "exit":
+			return
+		case "textDocument/didOpen", "textDocument/didChange":
+			fakeLanguageFrame(writer, map[string]any{"jsonrpc": "2.0", "id": "cfg", "method": "workspace/configuration",
+				"params": map[string]any{"items": []any{map[string]any{}}}})
+			fakeLanguageFrame(writer, map[string]any{"jsonrpc": "2.0", "method": "textDocument/publishDiagnostics",
+				"params": map[string]any{"uri": uri, "diagnostics": []any{
+					map[string]any{"range": at(4, 4, 5), "severity": 1, "source": "compiler", "message": "x declared and not used"},
+				}}})
+			continue
+		}
+		if message.Id == nil || message.Method == "" {
+			continue
+		}
+
+		switch message.Method {
+		case "initialize":
+			result = map[string]any{"capabilities": map[string]any{}}
+		case "textDocument/definition":
+			result = []any{map[string]any{"targetUri": uri, "targetRange": at(2, 0, 30), "targetSelectionRange": at(2, 5, 11)}}
+		case "textDocument/references":
+			result = []any{map[string]any{"uri": uri, "range": at(2, 5, 11)}, map[string]any{"uri": uri, "range": at(4, 8, 14)},
+				map[string]any{"uri": "file:///usr/lib/go/src/fmt/print.go", "range": at(9, 0, 1)}}
+		case "textDocument/hover":
+			result = map[string]any{"contents": map[string]any{"kind": "markdown", "value": "func Answer() int"}}
+		case "workspace/symbol":
+			result = []any{map[string]any{"name": "Answer", "kind": 12, "containerName": "main",
+				"location": map[string]any{"uri": uri, "range": at(2, 5, 11)}}}
+		case "textDocument/rename":
+			result = map[string]any{"changes": map[string]any{uri: []any{
+				map[string]any{"range": at(2, 5, 11), "newText": "Reply"},
+				map[string]any{"range": at(4, 8, 14), "newText": "Reply"},
+			}}}
+		default:
+			result = nil
+		}
+		fakeLanguageFrame(writer, map[string]any{"jsonrpc": "2.0", "id": message.Id, "result": result})
+	}
+}
+
+func lspTestSetup(t *testing.T) (string, string) {
+	var root string
+	var path string
+	var server LSPServer
+	var clientRead, serverRead *io.PipeReader
+	var clientWrite, serverWrite *io.PipeWriter
+
+	var err error
+
+	root, err = toolRoot(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path = filepath.Join(root, "main.go")
+	err = os.WriteFile(path, []byte("package main\n\nfunc Answer() int { return 42 }\n\nvar x = Answer()\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server = LSPServer{Name: "fake", Command: "fake-ls", Extensions: []string{".go"}, LanguageId: "go"}
+	lspConfMu.Lock()
+	lspConf = LSPConfig{Servers: []LSPServer{server}}
+	lspConfMu.Unlock()
+
+	serverRead, clientWrite = io.Pipe()
+	clientRead, serverWrite = io.Pipe()
+	go fakeLanguageServer(serverRead, serverWrite, lspURI(path))
+
+	lspClientsMu.Lock()
+	lspClients[server.Name+"\x00"+root] = lspConnect(server, root, clientRead, clientWrite)
+	lspClientsMu.Unlock()
+
+	t.Cleanup(func() {
+		LSPClose()
+		lspConfMu.Lock()
+		lspConf = LSPConfig{}
+		lspConfMu.Unlock()
+	})
+
+	return root, path
+}
+
+func TestLSPAnswersNavigationQueries(t *testing.T) {
+	var root string
+	var result string
+
+	var err error
+
+	root, _ = lspTestSetup(t)
+
+	result, err = LSP(root).Execute(context.Background(), `{"action":"definition","path":"main.go","line":5,"symbol":"Answer"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != "main.go:3:6: func Answer() int { return 42 }" {
+		t.Fatalf("definition = %q", result)
+	}
+
+	result, err = LSP(root).Execute(context.Background(), `{"action":"references","path":"main.go","line":3,"column":6}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "main.go:5:9: var x = Answer()") || !strings.Contains(result, "/usr/lib/go/src/fmt/print.go:10") ||
+		strings.Contains(result, "Fprintf") {
+		t.Fatalf("references = %q", result)
+	}
+
+	result, err = LSP(root).Execute(context.Background(), `{"action":"hover","path":"main.go","line":3,"symbol":"Answer"}`)
+	if err != nil || result != "func Answer() int" {
+		t.Fatalf("hover = %q, %v", result, err)
+	}
+
+	result, err = LSP(root).Execute(context.Background(), `{"action":"symbols","query":"Ans"}`)
+	if err != nil || result != "function Answer in main\n  main.go:3:6: func Answer() int { return 42 }" {
+		t.Fatalf("symbols = %q, %v", result, err)
+	}
+
+	result, err = LSP(root).Execute(context.Background(), `{"action":"diagnostics","path":"main.go"}`)
+	if err != nil || !strings.Contains(result, "main.go:5:5:") || !strings.Contains(result, "error: x declared and not used (compiler)") {
+		t.Fatalf("diagnostics = %q, %v", result, err)
+	}
+}
+
+func TestLSPRenamePreviewWritesNothing(t *testing.T) {
+	var root string
+	var path string
+	var result string
+	var buf []byte
+
+	var err error
+
+	root, path = lspTestSetup(t)
+
+	result, err = LSP(root).Execute(context.Background(), `{"action":"rename_preview","path":"main.go","line":3,"symbol":"Answer","new_name":"Reply"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result, "-func Answer() int { return 42 }") || !strings.Contains(result, "+func Reply() int { return 42 }") ||
+		!strings.Contains(result, "+var x = Reply()") || !strings.Contains(result, "nothing was written") {
+		t.Fatalf("rename preview = %q", result)
+	}
+
+	buf, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(buf), "Reply") {
+		t.Fatal("rename preview changed the file")
+	}
+}
+
+func TestLSPRejectsUnconfiguredFiles(t *testing.T) {
+	var root string
+
+	var err error
+
+	root, _ = lspTestSetup(t)
+	err = os.WriteFile(filepath.Join(root, "main.py"), []byte("print(1)\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = LSP(root).Execute(context.Background(), `{"action":"hover","path":"main.py","line":1,"column":1}`)
+	if err == nil || !strings.Contains(err.Error(), "handles .py files") {
+		t.Fatalf("unconfigured extension error = %v", err)
+	}
+	_, err = LSP(root).Execute(context.Background(), `{"action":"hover","path":"../main.go","line":1,"column":1}`)
+	if err == nil {
+		t.Fatal("lsp read a path outside the root")
+	}
+	_, err = LSP(root).Execute(context.Background(), `{"action":"hover","path":"main.go","line":3,"symbol":"Missing"}`)
+	if err == nil || !strings.Contains(err.Error(), "does not appear on line 3") {
+		t.Fatalf("missing symbol error = %v", err)
+	}
+}
@@ -0,0 +1,608 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/devproje/mininaru/util"
+)
+
+type lspMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	Id      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  json.RawMessage  `json:"result,omitempty"`
+	Error   *lspError        `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspLocation struct {
+	URI   string   `json:"uri"`
+	Range lspRange `json:"range"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspDocument struct {
+	version int
+	text    string
+}
+
+type lspClient struct {
+	server    LSPServer
+	root      string
+	command   *exec.Cmd
+	writer    io.WriteCloser
+	writeMu   sync.Mutex
+	mu        sync.Mutex
+	next      int
+	pending   map[int]chan lspMessage
+	published map[string]int
+	diagnosed chan struct{}
+	diagnoses map[string][]lspDiagnostic
+	docMu     sync.Mutex
+	documents map[string]*lspDocument
+	done      chan struct{}
+	err       error
+}
+
+const lspStartTimeout = 30 * time.Second
+const lspRequestTimeout = 30 * time.Second
+const lspStopTimeout = 2 * time.Second
+const lspDiagnosticsWait = 5 * time.Second
+
+var lspClients map[string]*lspClient = make(map[string]*lspClient)
+
+var lspClientsMu sync.Mutex
+
+func (e *lspError) Error() string {
+	return e.Message
+}
+
+func lspURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+func lspPath(uri string) string {
+	var parsed *url.URL
+
+	var err error
+
+	parsed, err = url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return uri
+	}
+
+	return filepath.FromSlash(parsed.Path)
+}
+
+func lspReadFrame(reader *bufio.Reader) ([]byte, error) {
+	var headers textproto.MIMEHeader
+	var length int
+	var body []byte
+
+	var err error
+
+	headers, err = textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err = strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("language server sent a frame without a valid Content-Length")
+	}
+
+	body = make([]byte, length)
+	_, err = io.ReadFull(reader, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+func lspConnect(server LSPServer, root string, reader io.Reader, writer io.WriteCloser) *lspClient {
+	var client *lspClient
+
+	client = &lspClient{
+		server:    server,
+		root:      root,
+		writer:    writer,
+		pending:   make(map[int]chan lspMessage),
+		published: make(map[string]int),
+		diagnosed: make(chan struct{}),
+		diagnoses: make(map[string][]lspDiagnostic),
+		documents: make(map[string]*lspDocument),
+		done:      make(chan struct{}),
+	}
+	go client.read(bufio.NewReader(reader))
+
+	return client
+}
+
+func (c *lspClient) read(reader *bufio.Reader) {
+	var body []byte
+	var message lspMessage
+	var id int
+	var reply chan lspMessage
+
+	var err error
+
+	for {
+		body, err = lspReadFrame(reader)
+		if err != nil {
+			c.fail(err)
+			return
+		}
+
+		message = lspMessage{}
+		err = json.Unmarshal(body, &message)
+		if err != nil {
+			util.Log.Warn("language server sent invalid json", "server", c.server.Name, "error", err)
+			continue
+		}
+
+		if message.Method != "" && message.Id != nil {
+			go c.answer(message)
+			continue
+		}
+		if message.Method == "textDocument/publishDiagnostics" {
+			c.publish(message.Params)
+			continue
+		}
+		if message.Method != "" || message.Id == nil {
+			continue
+		}
+
+		err = json.Unmarshal(*message.Id, &id)
+		if err != nil {
+			continue
+		}
+		c.mu.Lock()
+		reply = c.pending[id]
+		delete(c.pending, id)
+		c.mu.Unlock()
+		if reply != nil {
+			reply <- message
+		}
+	}
+}
+
+func (c *lspClient) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed() {
+		return
+	}
+	if err == io.EOF {
+		err = fmt.Errorf("language server %s exited", c.server.Name)
+	}
+	c.err = err
+	close(c.done)
+}
+
+func (c *lspClient) closed() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *lspClient) send(message lspMessage) error {
+	var body []byte
+
+	var err error
+
+	message.JSONRPC = "2.0"
+	body, err = json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_, err = fmt.Fprintf(c.writer, "Content-Length: %d\r\n\r\n%s", len(body), body)
+
+	return err
+}
+
+func lspParams(params any) (json.RawMessage, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(params)
+}
+
+func (c *lspClient) notify(method string, params any) error {
+	var encoded json.RawMessage
+
+	var err error
+
+	encoded, err = lspParams(params)
+	if err != nil {
+		return err
+	}
+
+	return c.send(lspMessage{Method: method, Params: encoded})
+}
+
+func (c *lspClient) call(ctx context.Context, method string, params, result any) error {
+	var encoded json.RawMessage
+	var id int
+	var raw json.RawMessage
+	var reply chan lspMessage
+	var message lspMessage
+
+	var err error
+
+	encoded, err = lspParams(params)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.next++
+	id = c.next
+	reply = make(chan lspMessage, 1)
+	c.pending[id] = reply
+	c.mu.Unlock()
+
+	raw = json.RawMessage(strconv.Itoa(id))
+	err = c.send(lspMessage{Id: &raw, Method: method, Params: encoded})
+	if err != nil {
+		return err
+	}
+
+	select {
+	case message = <-reply:
+	case <-c.done:
+		return c.err
+	case <-ctx.Done():
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		c.notify("$/cancelRequest", map[string]any{"id": id})
+		return ctx.Err()
+	}
+
+	if message.Error != nil {
+		return fmt.Errorf("%s %s: %w", c.server.Name, method, message.Error)
+	}
+	if result == nil || len(message.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(message.Result, result)
+}
+
+func (c *lspClient) answer(message lspMessage) {
+	var params struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	var result any
+	var encoded []byte
+
+	switch message.Method {
+	case "workspace/configuration":
+		json.Unmarshal(message.Params, &params)
+		result = make([]any, len(params.Items))
+	case "workspace/workspaceFolders":
+		result = []map[string]string{{"uri": lspURI(c.root), "name": filepath.Base(c.root)}}
+	case "client/registerCapability", "client/unregisterCapability", "window/workDoneProgress/create", "window/showMessageRequest":
+		result = nil
+	default:
+		c.send(lspMessage{Id: message.Id, Error: &lspError{Code: -32601, Message: "method not supported: " + message.Method}})
+		return
+	}
+
+	encoded, _ = json.Marshal(result)
+	c.send(lspMessage{Id: message.Id, Result: encoded})
+}
+
+func (c *lspClient) publish(raw json.RawMessage) {
+	var params struct {
+		URI         string          `json:"uri"`
+		Diagnostics []lspDiagnostic `json:"diagnostics"`
+	}
+
+	var err error
+
+	err = json.Unmarshal(raw, &params)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.diagnoses[params.URI] = params.Diagnostics
+	c.published[params.URI]++
+	close(c.diagnosed)
+	c.diagnosed = make(chan struct{})
+	c.mu.Unlock()
+}
+
+func (c *lspClient) initialize(ctx context.Context) error {
+	var params map[string]any
+
+	var err error
+
+	params = map[string]any{
+		"processId": os.Getpid(),
+		"rootUri":   lspURI(c.root),
+		"rootPath":  c.root,
+		"workspaceFolders": []map[string]string{
+			{"uri": lspURI(c.root), "name": filepath.Base(c.root)},
+		},
+		"capabilities": map[string]any{
+			"general": map[string]any{"positionEncodings": []string{"utf-16"}},
+			"textDocument": map[string]any{
+				"synchronization":    map[string]any{"didSave": false},
+				"hover":              map[string]any{"contentFormat": []string{"markdown", "plaintext"}},
+				"definition":         map[string]any{"linkSupport": true},
+				"references":         map[string]any{},
+				"rename":             map[string]any{"prepareSupport": false},
+				"publishDiagnostics": map[string]any{"relatedInformation": false},
+			},
+			"workspace": map[string]any{
+				"symbol":           map[string]any{},
+				"workspaceFolders": true,
+				"configuration":    true,
+				"workspaceEdit":    map[string]any{"documentChanges": true},
+			},
+		},
+	}
+	if len(c.server.InitializationOptions) > 0 {
+		params["initializationOptions"] = c.server.InitializationOptions
+	}
+
+	err = c.call(ctx, "initialize", params, nil)
+	if err != nil {
+		return err
+	}
+
+	return c.notify("initialized", map[string]any{})
+}
+
+func (c *lspClient) sync(path string) (bool, error) {
+	var buf []byte
+	var uri string
+	var document *lspDocument
+
+	var err error
+
+	buf, err = readTextFile(path)
+	if err != nil {
+		return false, err
+	}
+	uri = lspURI(path)
+
+	c.docMu.Lock()
+	defer c.docMu.Unlock()
+
+	document = c.documents[uri]
+	if document == nil {
+		c.documents[uri] = &lspDocument{version: 1, text: string(buf)}
+		return true, c.notify("textDocument/didOpen", map[string]any{
+			"textDocument": map[string]any{"uri": uri, "languageId": c.server.LanguageId, "version": 1, "text": string(buf)},
+		})
+	}
+	if document.text == string(buf) {
+		return false, nil
+	}
+
+	document.version++
+	document.text = string(buf)
+
+	return true, c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": uri, "version": document.version},
+		"contentChanges": []map[string]string{{"text": document.text}},
+	})
+}
+
+func (c *lspClient) diagnostics(ctx context.Context, path string) ([]lspDiagnostic, error) {
+	var uri string
+	var seen int
+	var changed bool
+	var timer *time.Timer
+	var wake chan struct{}
+	var current []lspDiagnostic
+	var count int
+
+	var err error
+
+	uri = lspURI(path)
+	c.mu.Lock()
+	seen = c.published[uri]
+	c.mu.Unlock()
+
+	changed, err = c.sync(path)
+	if err != nil {
+		return nil, err
+	}
+
+	timer = time.NewTimer(lspDiagnosticsWait)
+	defer timer.Stop()
+
+	for {
+		c.mu.Lock()
+		current = c.diagnoses[uri]
+		count = c.published[uri]
+		wake = c.diagnosed
+		c.mu.Unlock()
+
+		if count > seen || (!changed && count > 0) {
+			return current, nil
+		}
+
+		select {
+		case <-wake:
+		case <-timer.C:
+			return current, nil
+		case <-c.done:
+			return nil, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (c *lspClient) close() {
+	var ctx context.Context
+	var cancel context.CancelFunc
+
+	if !c.closed() {
+		ctx, cancel = context.WithTimeout(context.Background(), lspStopTimeout)
+		c.call(ctx, "shutdown", nil, nil)
+		cancel()
+		c.notify("exit", nil)
+	}
+	c.writer.Close()
+
+	if c.command == nil {
+		return
+	}
+
+	select {
+	case <-c.done:
+	case <-time.After(lspStopTimeout):
+		bashTerminate(c.command)
+	}
+	c.command.Wait()
+}
+
+func lspStart(ctx context.Context, server LSPServer, root string) (*lspClient, error) {
+	var command *exec.Cmd
+	var stdin io.WriteCloser
+	var stdout io.ReadCloser
+	var key string
+	var value string
+	var client *lspClient
+	var startCtx context.Context
+	var cancel context.CancelFunc
+
+	var err error
+
+	command = exec.Command(server.Command, server.Args...)
+	command.Dir = root
+	command.Env = os.Environ()
+	for key, value = range server.Env {
+		command.Env = append(command.Env, key+"="+value)
+	}
+	bashIsolate(command)
+
+	stdin, err = command.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err = command.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	err = command.Start()
+	if err != nil {
+		return nil, fmt.Errorf("start language server %s: %w", server.Name, err)
+	}
+
+	client = lspConnect(server, root, stdout, stdin)
+	client.command = command
+
+	startCtx, cancel = context.WithTimeout(ctx, lspStartTimeout)
+	defer cancel()
+
+	err = client.initialize(startCtx)
+	if err != nil {
+		client.close()
+		return nil, fmt.Errorf("initialize language server %s: %w", server.Name, err)
+	}
+
+	return client, nil
+}
+
+func lspClientFor(ctx context.Context, server LSPServer, root string) (*lspClient, error) {
+	var key string
+	var current *lspClient
+
+	var err error
+
+	key = server.Name + "\x00" + root
+
+	lspClientsMu.Lock()
+	defer lspClientsMu.Unlock()
+
+	current = lspClients[key]
+	if current != nil && !current.closed() {
+		return current, nil
+	}
+	if current != nil {
+		delete(lspClients, key)
+		go current.close()
+	}
+
+	current, err = lspStart(ctx, server, root)
+	if err != nil {
+		return nil, err
+	}
+	lspClients[key] = current
+
+	return current, nil
+}
+
+func LSPClose() {
+	var stale []*lspClient
+	var current *lspClient
+	var group sync.WaitGroup
+
+	lspClientsMu.Lock()
+	for _, current = range lspClients {
+		stale = append(stale, current)
+	}
+	lspClients = make(map[string]*lspClient)
+	lspClientsMu.Unlock()
+
+	for _, current = range stale {
+		group.Add(1)
+		go func(client *lspClient) {
+			defer group.Done()
+			client.close()
+		}(current)
+	}
+	group.Wait()
+}
@@ -0,0 +1,181 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/devproje/mininaru/util"
+)
+
+type LSPServer struct {
+	Name                  string            `json:"name"`
+	Command               string            `json:"command"`
+	Args                  []string          `json:"args,omitempty"`
+	Env                   map[string]string `json:"env,omitempty"`
+	Extensions            []string          `json:"extensions"`
+	LanguageId            string            `json:"language_id"`
+	InitializationOptions json.RawMessage   `json:"initialization_options,omitempty"`
+	Enabled               *bool             `json:"enabled,omitempty"`
+}
+
+type LSPConfig struct {
+	Servers []LSPServer `json:"servers"`
+}
+
+const LSP_PATH = "lsp.json"
+
+var lspConf LSPConfig
+
+var lspConfMu sync.RWMutex
+
+func lspEnabled(entry *LSPServer) bool {
+	return entry.Enabled == nil || *entry.Enabled
+}
+
+func LSPValidate(entry *LSPServer) error {
+	var extension string
+
+	if !serverNamePattern.MatchString(entry.Name) {
+		return fmt.Errorf("invalid language server name %q", entry.Name)
+	}
+	if entry.Command == "" {
+		return fmt.Errorf("language server %q needs a command", entry.Name)
+	}
+	if entry.LanguageId == "" {
+		return fmt.Errorf("language server %q needs a language_id", entry.Name)
+	}
+	if len(entry.Extensions) == 0 {
+		return fmt.Errorf("language server %q needs at least one extension", entry.Name)
+	}
+	for _, extension = range entry.Extensions {
+		if !strings.HasPrefix(extension, ".") || len(extension) < 2 {
+			return fmt.Errorf("language server %q has extension %q, expected something like .go", entry.Name, extension)
+		}
+	}
+
+	return nil
+}
+
+func lspAccept(loaded LSPConfig) LSPConfig {
+	var seen map[string]bool
+	var index int
+	var accepted LSPConfig
+
+	var err error
+
+	seen = make(map[string]bool)
+
+	for index = range loaded.Servers {
+		err = LSPValidate(&loaded.Servers[index])
+		if err != nil {
+			util.Log.Warn("ignoring an invalid language server entry", "config", LSP_PATH, "error", err)
+			continue
+		}
+		if seen[loaded.Servers[index].Name] {
+			util.Log.Warn("ignoring a duplicate language server", "config", LSP_PATH, "server", loaded.Servers[index].Name)
+			continue
+		}
+
+		seen[loaded.Servers[index].Name] = true
+		accepted.Servers = append(accepted.Servers, loaded.Servers[index])
+	}
+
+	return accepted
+}
+
+func LSPServers() []LSPServer {
+	var servers []LSPServer
+	var index int
+
+	lspConfMu.RLock()
+	defer lspConfMu.RUnlock()
+
+	for index = range lspConf.Servers {
+		if lspEnabled(&lspConf.Servers[index]) {
+			servers = append(servers, lspConf.Servers[index])
+		}
+	}
+
+	return servers
+}
+
+func lspServerFor(path string) (LSPServer, error) {
+	var extension string
+	var server LSPServer
+	var candidate string
+	var servers []LSPServer
+
+	servers = LSPServers()
+	if len(servers) == 0 {
+		return LSPServer{}, fmt.Errorf("no language servers are configured in %s", LSP_PATH)
+	}
+
+	extension = strings.ToLower(filepath.Ext(path))
+	if extension == "" {
+		return LSPServer{}, fmt.Errorf("%s has no extension to pick a language server by", filepath.Base(path))
+	}
+
+	for _, server = range servers {
+		for _, candidate = range server.Extensions {
+			if strings.ToLower(candidate) == extension {
+				return server, nil
+			}
+		}
+	}
+
+	return LSPServer{}, fmt.Errorf("no language server in %s handles %s files", LSP_PATH, extension)
+}
+
+func LSPLoad() error {
+	var path string
+	var buf []byte
+	var loaded LSPConfig
+
+	var err error
+
+	lspConfMu.Lock()
+	lspConf = LSPConfig{}
+	lspConfMu.Unlock()
+
+	path = util.Path(LSP_PATH)
+	buf, err = os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+
+		buf, _ = json.MarshalIndent(LSPConfig{Servers: []LSPServer{}}, "", "    ")
+
+		err = util.WriteFileAtomic(path, buf, 0600)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = json.Unmarshal(buf, &loaded)
+	if err != nil {
+		return err
+	}
+
+	lspConfMu.Lock()
+	lspConf = lspAccept(loaded)
+	lspConfMu.Unlock()
+
+	return nil
+}
+
+func LSPReload() error {
+	var err error
+
+	err = LSPLoad()
+	LSPClose()
+
+	return err
+}
@@ -4,6 +4,7 @@
 package modules
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -143,15 +144,18 @@ func overridePermission(entry *MCPServer, tool string, derived Permission) Permi
 	return derived
 }
 
-func contentText(content []mcp.Content) string {
+func contentText(ctx context.Context, content []mcp.Content) string {
 	var cur mcp.Content
 	var text *mcp.TextContent
 	var ok bool
 	var parts []string
 	var image *mcp.ImageContent
+	var note string
 	var audio *mcp.AudioContent
 	var buf []byte
 
+	var err error
+
 	for _, cur = range content {
 		text, ok = cur.(*mcp.TextContent)
 		if ok {
@@ -161,7 +165,12 @@ func contentText(content []mcp.Content) string {
 
 		image, ok = cur.(*mcp.ImageContent)
 		if ok {
-			parts = append(parts, fmt.Sprintf("[image content omitted: %s, %d bytes]", image.MIMEType, len(image.Data)))
+			note, err = ToolImageAttach(ctx, image.Data)
+			if err != nil {
+				note = fmt.Sprintf("[image content omitted: %s, %d bytes, %v]", image.MIMEType, len(image.Data), err)
+			}
+
+			parts = append(parts, note)
 			continue
 		}
 
@@ -178,7 +187,7 @@ func contentText(content []mcp.Content) string {
 	return strings.Join(parts, "\n")
 }
 
-func resultText(result *mcp.CallToolResult) (string, error) {
+func resultText(ctx context.Context, result *mcp.CallToolResult) (string, error) {
 	var text string
 	var buf []byte
 
@@ -188,7 +197,7 @@ func resultText(result *mcp.CallToolResult) (string, error) {
 		return "", errors.New("tool call returned no result")
 	}
 
-	text = contentText(result.Content)
+	text = contentText(ctx, result.Content)
 
 	if text == "" && result.StructuredContent != nil {
 		buf, err = json.Marshal(result.StructuredContent)
@@ -4,6 +4,7 @@
 package modules
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"testing"
@@ -102,36 +103,64 @@ func TestOverridePermission(t *testing.T) {
 	}
 }
 
+func TestResultTextAttachesImagesWhenTheTurnTakesThem(t *testing.T) {
+	var images ToolImages
+	var png []byte
+	var text string
+
+	var err error
+
+	png = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	images = ToolImages{Left: 1 << 20}
+	text, err = resultText(ToolImagesContext(context.Background(), &images), &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: "chart"}, &mcp.ImageContent{MIMEType: "image/png", Data: png}},
+	})
+	if err != nil || !strings.Contains(text, "image 1 attached") {
+		t.Fatalf("resultText(image) = %q, %v", text, err)
+	}
+	if len(images.Images) != 1 || images.Images[0].MediaType != "image/png" || images.Left != 1<<20-len(png) {
+		t.Fatalf("collected %d images with %d bytes left", len(images.Images), images.Left)
+	}
+
+	images = ToolImages{Left: 4}
+	text, _ = resultText(ToolImagesContext(context.Background(), &images), &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.ImageContent{MIMEType: "image/png", Data: png}},
+	})
+	if len(images.Images) != 0 || !strings.Contains(text, "left for images this turn") {
+		t.Fatalf("an image over the turn cap was attached: %q", text)
+	}
+}
+
 func TestResultText(t *testing.T) {
 	var text string
 
 	var err error
 
-	text, err = resultText(&mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "hello"}}})
+	text, err = resultText(context.Background(), &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "hello"}}})
 	if err != nil || text != "hello" {
 		t.Fatalf("resultText(text) = %q, %v", text, err)
 	}
 
-	text, err = resultText(&mcp.CallToolResult{StructuredContent: map[string]any{"ok": true}})
+	text, err = resultText(context.Background(), &mcp.CallToolResult{StructuredContent: map[string]any{"ok": true}})
 	if err != nil || text != `{"ok":true}` {
 		t.Fatalf("resultText(structured) = %q, %v", text, err)
 	}
 
-	text, err = resultText(&mcp.CallToolResult{
+	text, err = resultText(context.Background(), &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.ImageContent{MIMEType: "image/png", Data: []byte("1234")}},
 	})
 	if err != nil || !strings.Contains(text, "image content omitted") || strings.Contains(text, "1234") {
 		t.Fatalf("resultText(image) = %q, %v", text, err)
 	}
 
-	_, err = resultText(&mcp.CallToolResult{
+	_, err = resultText(context.Background(), &mcp.CallToolResult{
 		IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "boom"}},
 	})
 	if err == nil || err.Error() != "boom" {
 		t.Fatalf("resultText(error) = %v", err)
 	}
 
-	_, err = resultText(&mcp.CallToolResult{IsError: true})
+	_, err = resultText(context.Background(), &mcp.CallToolResult{IsError: true})
 	if err == nil || err.Error() != "tool call failed" {
 		t.Fatalf("resultText(empty error) = %v", err)
 	}
@@ -115,7 +115,7 @@ func sessionCall(ctx context.Context, session *mcp.ClientSession, name, argument
 		return "", err
 	}
 
-	return resultText(result)
+	return resultText(ctx, result)
 }
 
 func sessionExecute(session *mcp.ClientSession, name string) func(context.Context, string) (string, error) {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/devproje/mininaru/util"
@@ -27,6 +28,9 @@ type MCPServer struct {
 	Daemon         *bool             `json:"daemon,omitempty"`
 	Permission     string            `json:"permission,omitempty"`
 	ToolPermission map[string]string `json:"tool_permission,omitempty"`
+
+	envRefs    map[string]string
+	headerRefs map[string]string
 }
 
 type MCPConfig struct {
@@ -107,14 +111,93 @@ func mcpAccept(loaded MCPConfig) MCPConfig {
 	return accepted
 }
 
+func mcpSecretsLoad(owner string, values map[string]string) map[string]string {
+	var refs map[string]string
+	var key string
+	var ref string
+
+	for key = range values {
+		values[key], ref = util.SecretLoad(owner+" "+key, values[key])
+		if ref == "" {
+			continue
+		}
+
+		if refs == nil {
+			refs = make(map[string]string)
+		}
+		refs[key] = ref
+	}
+
+	return refs
+}
+
+func mcpSecretsStored(values, refs map[string]string) map[string]string {
+	var stored map[string]string
+	var key string
+
+	if len(refs) == 0 {
+		return values
+	}
+
+	stored = make(map[string]string, len(values))
+	for key = range values {
+		stored[key] = util.SecretStored(values[key], refs[key])
+	}
+
+	return stored
+}
+
+func mcpSecretFields(owner string, refs map[string]string) []util.SecretField {
+	var keys []string
+	var key string
+	var fields []util.SecretField
+
+	for key = range refs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key = range keys {
+		fields = append(fields, util.SecretField{Owner: owner + " " + key, Ref: refs[key]})
+	}
+
+	return fields
+}
+
+func MCPSecrets() []util.SecretField {
+	var fields []util.SecretField
+	var entry MCPServer
+
+	for _, entry = range MCP.Servers {
+		fields = append(fields, mcpSecretFields("mcp "+entry.Name+" env", entry.envRefs)...)
+		fields = append(fields, mcpSecretFields("mcp "+entry.Name+" header", entry.headerRefs)...)
+	}
+
+	return fields
+}
+
+func MCPServerStored(entry MCPServer) MCPServer {
+	entry.Env = mcpSecretsStored(entry.Env, entry.envRefs)
+	entry.Headers = mcpSecretsStored(entry.Headers, entry.headerRefs)
+
+	return entry
+}
+
 func MCPSave() error {
+	var stored MCPConfig
+	var entry MCPServer
 	var path string
 	var buf []byte
 
 	var err error
 
+	stored = MCPConfig{Servers: []MCPServer{}}
+	for _, entry = range MCP.Servers {
+		stored.Servers = append(stored.Servers, MCPServerStored(entry))
+	}
+
 	path = util.Path(MCP_PATH)
-	buf, err = json.MarshalIndent(MCP, "", "    ")
+	buf, err = json.MarshalIndent(stored, "", "    ")
 	if err != nil {
 		return err
 	}
@@ -126,6 +209,7 @@ func MCPLoad() error {
 	var path string
 	var buf []byte
 	var loaded MCPConfig
+	var index int
 
 	var err error
 
@@ -152,6 +236,14 @@ func MCPLoad() error {
 	}
 
 	MCP = mcpAccept(loaded)
+	for index = range MCP.Servers {
+		if !serverEnabled(&MCP.Servers[index]) {
+			continue
+		}
+
+		MCP.Servers[index].envRefs = mcpSecretsLoad("mcp "+MCP.Servers[index].Name+" env", MCP.Servers[index].Env)
+		MCP.Servers[index].headerRefs = mcpSecretsLoad("mcp "+MCP.Servers[index].Name+" header", MCP.Servers[index].Headers)
+	}
 
 	return nil
 }
@@ -5,6 +5,7 @@ package modules
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/devproje/mininaru/util"
@@ -119,3 +120,41 @@ func TestMCPSaveRoundTrip(t *testing.T) {
 		t.Fatal("explicit disable did not survive a round trip")
 	}
 }
+
+func TestMCPSecretReferencesStayReferences(t *testing.T) {
+	var buf []byte
+
+	var err error
+
+	util.RootDir = t.TempDir()
+	t.Setenv("NARU_TEST_GH", "ghp_resolved")
+	err = os.WriteFile(util.Path(MCP_PATH), []byte(`{"servers":[{"name":"github","transport":"http","url":"https://example.com/mcp",
+"headers":{"Authorization":"env:NARU_TEST_GH","X-Team":"core"}}]}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = MCPLoad()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if MCP.Servers[0].Headers["Authorization"] != "ghp_resolved" {
+		t.Fatalf("header = %q, want it resolved", MCP.Servers[0].Headers["Authorization"])
+	}
+
+	err = MCPSave()
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err = os.ReadFile(util.Path(MCP_PATH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(buf), "ghp_resolved") || !strings.Contains(string(buf), "env:NARU_TEST_GH") {
+		t.Fatal("the resolved header was written back to mcp.json")
+	}
+
+	if len(MCPSecrets()) != 1 || MCPSecrets()[0].Ref != "env:NARU_TEST_GH" {
+		t.Fatalf("secrets = %+v", MCPSecrets())
+	}
+}
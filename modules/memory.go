@@ -14,7 +14,7 @@ import (
 	"github.com/google/uuid"
 )
 
-type memoryEntry struct {
+type MemoryEntry struct {
 	Id      string `json:"id"`
 	Content string `json:"content"`
 }
@@ -23,10 +23,10 @@ const MemoryToolName = "memory"
 
 const memoryMaxChars = 4096
 
-func memoryEntries() ([]memoryEntry, error) {
-	var entries []memoryEntry
+func MemoryEntries() ([]MemoryEntry, error) {
+	var entries []MemoryEntry
 	var rows *sql.Rows
-	var entry memoryEntry
+	var entry MemoryEntry
 
 	var err error
 
@@ -65,13 +65,13 @@ func memoryUsage(excludeId string) (int, error) {
 }
 
 func MemorySnapshot() string {
-	var entries []memoryEntry
-	var entry memoryEntry
+	var entries []MemoryEntry
+	var entry MemoryEntry
 	var lines []string
 
 	var err error
 
-	entries, err = memoryEntries()
+	entries, err = MemoryEntries()
 	if err != nil || len(entries) == 0 {
 		return ""
 	}
@@ -84,12 +84,12 @@ func MemorySnapshot() string {
 }
 
 func memoryResult() (string, error) {
-	var entries []memoryEntry
+	var entries []MemoryEntry
 	var buf []byte
 
 	var err error
 
-	entries, err = memoryEntries()
+	entries, err = MemoryEntries()
 	if err != nil {
 		return "", err
 	}
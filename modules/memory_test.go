@@ -15,7 +15,7 @@ import (
 func TestMemoryCRUDAndSnapshot(t *testing.T) {
 	var def Def
 	var result string
-	var entries []memoryEntry
+	var entries []MemoryEntry
 	var snapshot string
 
 	var err error
@@ -30,7 +30,7 @@ func TestMemoryCRUDAndSnapshot(t *testing.T) {
 	if err != nil || !strings.Contains(result, "User likes Go") {
 		t.Fatalf("add result=%q err=%v", result, err)
 	}
-	entries, err = memoryEntries()
+	entries, err = MemoryEntries()
 	if err != nil || len(entries) != 1 {
 		t.Fatalf("entries=%#v err=%v", entries, err)
 	}
@@ -0,0 +1,580 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/devproje/mininaru/util"
+)
+
+type patchHunk struct {
+	header   string
+	oldStart int
+	lines    []string
+}
+
+type patchFile struct {
+	oldPath string
+	newPath string
+	hunks   []patchHunk
+}
+
+type patchTarget struct {
+	path    string
+	target  string
+	before  string
+	after   string
+	mode    os.FileMode
+	created bool
+	deleted bool
+}
+
+const patchDevNull = "/dev/null"
+
+const patchMaxFuzz = 2
+
+var patchHunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+\d+(?:,\d+)? @@`)
+
+func patchPath(field string) string {
+	var path string
+
+	path = strings.TrimSpace(field)
+	path, _, _ = strings.Cut(path, "\t")
+	if path == patchDevNull {
+		return path
+	}
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		path = path[2:]
+	}
+
+	return path
+}
+
+func patchTrimHunk(hunk *patchHunk) {
+	for len(hunk.lines) > 0 && hunk.lines[len(hunk.lines)-1] == "" {
+		hunk.lines = hunk.lines[:len(hunk.lines)-1]
+	}
+}
+
+func parsePatch(patch string) ([]patchFile, error) {
+	var lines []string
+	var files []patchFile
+	var file *patchFile
+	var hunk *patchHunk
+	var match []string
+	var index int
+	var line string
+
+	lines = strings.Split(strings.ReplaceAll(patch, "\r\n", "\n"), "\n")
+	for index = 0; index < len(lines); index++ {
+		line = lines[index]
+
+		if strings.HasPrefix(line, "--- ") && index+1 < len(lines) && strings.HasPrefix(lines[index+1], "+++ ") {
+			if hunk != nil {
+				patchTrimHunk(hunk)
+			}
+			files = append(files, patchFile{oldPath: patchPath(line[4:]), newPath: patchPath(lines[index+1][4:])})
+			file = &files[len(files)-1]
+			hunk = nil
+			index++
+			continue
+		}
+		if strings.HasPrefix(line, "diff ") {
+			if hunk != nil {
+				patchTrimHunk(hunk)
+			}
+			file = nil
+			hunk = nil
+			continue
+		}
+		if strings.HasPrefix(line, "@@") {
+			if file == nil {
+				return nil, fmt.Errorf("line %d: hunk %q has no --- and +++ file header before it", index+1, line)
+			}
+			match = patchHunkHeader.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("line %d: malformed hunk header %q; use @@ -start,count +start,count @@", index+1, line)
+			}
+			if hunk != nil {
+				patchTrimHunk(hunk)
+			}
+			file.hunks = append(file.hunks, patchHunk{header: line})
+			hunk = &file.hunks[len(file.hunks)-1]
+			hunk.oldStart, _ = strconv.Atoi(match[1])
+			continue
+		}
+		if hunk == nil {
+			continue
+		}
+		if strings.HasPrefix(line, "\\") {
+			continue
+		}
+		if line == "" || line[0] == ' ' || line[0] == '-' || line[0] == '+' {
+			hunk.lines = append(hunk.lines, line)
+			continue
+		}
+
+		return nil, fmt.Errorf("line %d: %q in %s is not a context, removed, or added line", index+1, line, hunk.header)
+	}
+	if hunk != nil {
+		patchTrimHunk(hunk)
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("patch has no --- and +++ file headers")
+	}
+	for index = range files {
+		if len(files[index].hunks) == 0 {
+			return nil, fmt.Errorf("%s has no hunks", files[index].newPath)
+		}
+	}
+
+	return files, nil
+}
+
+func patchName(file patchFile) string {
+	if file.newPath == patchDevNull {
+		return file.oldPath
+	}
+
+	return file.newPath
+}
+
+func PatchPaths(patch string) []string {
+	var files []patchFile
+	var file patchFile
+	var paths []string
+
+	var err error
+
+	files, err = parsePatch(patch)
+	if err != nil {
+		return nil
+	}
+	for _, file = range files {
+		paths = append(paths, patchName(file))
+	}
+
+	return paths
+}
+
+func patchSplit(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func patchBody(line string) string {
+	if line == "" {
+		return ""
+	}
+
+	return line[1:]
+}
+
+func patchSides(hunk patchHunk) ([]string, []string) {
+	var old []string
+	var changed []string
+	var line string
+
+	for _, line = range hunk.lines {
+		if line == "" || line[0] == ' ' {
+			old = append(old, patchBody(line))
+			changed = append(changed, patchBody(line))
+		} else if line[0] == '-' {
+			old = append(old, patchBody(line))
+		} else {
+			changed = append(changed, patchBody(line))
+		}
+	}
+
+	return old, changed
+}
+
+func patchLineEqual(a, b string, level int) bool {
+	if level == 0 {
+		return a == b
+	}
+	if level == 1 {
+		return strings.TrimRight(a, " \t\r") == strings.TrimRight(b, " \t\r")
+	}
+
+	return strings.Join(strings.Fields(a), " ") == strings.Join(strings.Fields(b), " ")
+}
+
+func patchMatchAt(lines, old []string, at, level int) bool {
+	var index int
+
+	if at < 0 || at+len(old) > len(lines) {
+		return false
+	}
+	for index = range old {
+		if !patchLineEqual(lines[at+index], old[index], level) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func patchFind(lines, old []string, from, expected, level int) int {
+	var distance int
+
+	for distance = 0; expected-distance >= from || expected+distance+len(old) <= len(lines); distance++ {
+		if expected-distance >= from && patchMatchAt(lines, old, expected-distance, level) {
+			return expected - distance
+		}
+		if distance > 0 && patchMatchAt(lines, old, expected+distance, level) {
+			return expected + distance
+		}
+	}
+
+	return -1
+}
+
+func patchContext(hunk patchHunk) (int, int) {
+	var leading int
+	var trailing int
+
+	for leading < len(hunk.lines) && (hunk.lines[leading] == "" || hunk.lines[leading][0] == ' ') {
+		leading++
+	}
+	for trailing < len(hunk.lines)-leading &&
+		(hunk.lines[len(hunk.lines)-1-trailing] == "" || hunk.lines[len(hunk.lines)-1-trailing][0] == ' ') {
+		trailing++
+	}
+
+	return leading, trailing
+}
+
+func patchApplyHunk(lines []string, hunk patchHunk, from, offset int) ([]string, int, int, error) {
+	var leading int
+	var trailing int
+	var fuzz int
+	var level int
+	var trimmed patchHunk
+	var cut int
+	var old []string
+	var changed []string
+	var base int
+	var at int
+	var expected int
+	var replaced []string
+	var line string
+	var oldIndex int
+	var result []string
+
+	leading, trailing = patchContext(hunk)
+	for fuzz = 0; fuzz <= patchMaxFuzz; fuzz++ {
+		if fuzz > 0 && fuzz > leading && fuzz > trailing {
+			break
+		}
+		cut = min(fuzz, leading)
+		trimmed = patchHunk{header: hunk.header, oldStart: hunk.oldStart + cut,
+			lines: hunk.lines[cut : len(hunk.lines)-min(fuzz, trailing)]}
+		old, changed = patchSides(trimmed)
+		if fuzz > 0 && len(old) == 0 {
+			break
+		}
+		base = trimmed.oldStart - 1
+		if len(old) == 0 {
+			base = trimmed.oldStart
+		}
+		expected = max(from, min(base+offset, len(lines)))
+
+		for level = 0; level <= 2; level++ {
+			at = expected
+			if len(old) > 0 {
+				at = patchFind(lines, old, from, expected, level)
+			}
+			if at < 0 {
+				continue
+			}
+
+			oldIndex = at
+			replaced = nil
+			for _, line = range trimmed.lines {
+				if line == "" || line[0] == ' ' {
+					replaced = append(replaced, lines[oldIndex])
+					oldIndex++
+				} else if line[0] == '-' {
+					oldIndex++
+				} else {
+					replaced = append(replaced, patchBody(line))
+				}
+			}
+
+			result = append(append(append([]string{}, lines[:at]...), replaced...), lines[at+len(old):]...)
+			return result, at + len(changed), at - base + len(changed) - len(old), nil
+		}
+	}
+
+	old, _ = patchSides(hunk)
+	if len(old) > 3 {
+		old = append(old[:3], "…")
+	}
+
+	return nil, 0, 0, fmt.Errorf("%s did not match near line %d; expected:\n  | %s",
+		hunk.header, hunk.oldStart, strings.Join(old, "\n  | "))
+}
+
+func patchApply(before string, hunks []patchHunk) (string, []string) {
+	var lines []string
+	var hunk patchHunk
+	var from int
+	var offset int
+	var rejected []string
+	var index int
+	var next []string
+	var end int
+	var shifted int
+	var after string
+
+	var err error
+
+	lines = patchSplit(before)
+	for index, hunk = range hunks {
+		next, end, shifted, err = patchApplyHunk(lines, hunk, from, offset)
+		if err != nil {
+			rejected = append(rejected, fmt.Sprintf("hunk %d %s", index+1, err.Error()))
+			continue
+		}
+		lines, from, offset = next, end, shifted
+	}
+	if len(rejected) > 0 {
+		return "", rejected
+	}
+
+	after = strings.Join(lines, "\n")
+	if len(lines) > 0 && (before == "" || strings.HasSuffix(before, "\n")) {
+		after += "\n"
+	}
+
+	return after, nil
+}
+
+func patchPrepare(root string, file patchFile) (*patchTarget, []string) {
+	var prepared patchTarget
+	var source string
+	var buf []byte
+	var info os.FileInfo
+	var rejected []string
+
+	var err error
+
+	if file.oldPath == patchDevNull && file.newPath == patchDevNull {
+		return nil, []string{"both sides are /dev/null"}
+	}
+	if file.oldPath != patchDevNull && file.newPath != patchDevNull && file.oldPath != file.newPath {
+		return nil, []string{fmt.Sprintf("renaming %s to %s is not supported; patch the file in place", file.oldPath, file.newPath)}
+	}
+
+	prepared.path = file.newPath
+	if file.oldPath == patchDevNull {
+		prepared.created = true
+		prepared.mode = 0644
+		prepared.target, err = writePath(root, file.newPath)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		_, err = os.Lstat(prepared.target)
+		if err == nil {
+			return nil, []string{"the file already exists; patch it against its current contents instead of /dev/null"}
+		}
+		if !os.IsNotExist(err) {
+			return nil, []string{err.Error()}
+		}
+	} else {
+		if file.newPath == patchDevNull {
+			prepared.deleted = true
+			prepared.path = file.oldPath
+		}
+		source, err = readPath(root, prepared.path)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		buf, err = readTextFile(source)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		err = requireFileRevision(source, buf)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		prepared.target, err = writePath(root, prepared.path)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		info, err = os.Stat(prepared.target)
+		if err != nil {
+			return nil, []string{err.Error()}
+		}
+		prepared.before = string(buf)
+		prepared.mode = info.Mode().Perm()
+	}
+
+	prepared.after, rejected = patchApply(prepared.before, file.hunks)
+	if len(rejected) > 0 {
+		return nil, rejected
+	}
+	if !utf8.ValidString(prepared.after) || strings.IndexByte(prepared.after, 0) >= 0 {
+		return nil, []string{"the patched content is not UTF-8 text"}
+	}
+	if prepared.deleted && prepared.after != "" {
+		return nil, []string{"deleting the file needs hunks that remove every line"}
+	}
+	if !prepared.created && !prepared.deleted && prepared.after == prepared.before {
+		return nil, []string{"the hunks change nothing"}
+	}
+
+	return &prepared, nil
+}
+
+func patchRollback(done []*patchTarget) {
+	var prepared *patchTarget
+	var index int
+
+	for index = len(done) - 1; index >= 0; index-- {
+		prepared = done[index]
+		if prepared.created {
+			os.Remove(prepared.target)
+			delete(fileRevisions, prepared.target)
+			continue
+		}
+		if util.WriteFileAtomic(prepared.target, []byte(prepared.before), prepared.mode) == nil {
+			fileRevisions[prepared.target] = fileRevision([]byte(prepared.before))
+		}
+	}
+}
+
+func patchCommit(prepared []*patchTarget) error {
+	var current *patchTarget
+	var done []*patchTarget
+
+	var err error
+
+	for _, current = range prepared {
+		if current.deleted {
+			err = os.Remove(current.target)
+		} else {
+			err = util.WriteFileAtomic(current.target, []byte(current.after), current.mode)
+		}
+		if err != nil {
+			patchRollback(done)
+			return fmt.Errorf("writing %s failed, earlier files were restored: %w", current.path, err)
+		}
+
+		if current.deleted {
+			delete(fileRevisions, current.target)
+		} else {
+			fileRevisions[current.target] = fileRevision([]byte(current.after))
+		}
+		done = append(done, current)
+	}
+
+	return nil
+}
+
+func FilePatch(root string) Def {
+	return Def{
+		Name: "file_patch",
+		Description: "Apply a unified diff to one or more UTF-8 text files relative to the process startup directory. " +
+			"Use --- a/path and +++ b/path headers with @@ hunks, and /dev/null to create or delete a file. " +
+			"Every existing file must be read with file_read first and must not have changed since. " +
+			"Hunks are placed by their context, tolerating shifted line numbers and whitespace differences. " +
+			"Nothing is written unless every hunk applies. Returns the combined unified diff.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"patch": map[string]any{"type": "string"},
+			},
+			"required":             []string{"patch"},
+			"additionalProperties": false,
+		},
+		Permission: PermissionDangerous,
+		Execute: func(ctx context.Context, arguments string) (string, error) {
+			var payload struct {
+				Patch string `json:"patch"`
+			}
+			var files []patchFile
+			var file patchFile
+			var seen map[string]bool
+			var current *patchTarget
+			var rejected []string
+			var prepared []*patchTarget
+			var report []string
+			var reason string
+			var diff strings.Builder
+			var result string
+
+			var err error
+
+			if err = ctx.Err(); err != nil {
+				return "", err
+			}
+			err = json.Unmarshal([]byte(arguments), &payload)
+			if err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			if strings.TrimSpace(payload.Patch) == "" {
+				return "", fmt.Errorf("patch is required")
+			}
+			files, err = parsePatch(payload.Patch)
+			if err != nil {
+				return "", fmt.Errorf("invalid patch: %w", err)
+			}
+
+			fileRevisionMu.Lock()
+			defer fileRevisionMu.Unlock()
+
+			seen = make(map[string]bool)
+			for _, file = range files {
+				current, rejected = patchPrepare(root, file)
+				if current != nil && seen[current.target] {
+					current, rejected = nil, []string{"the file appears more than once; put all of its hunks under one header"}
+				}
+				if len(rejected) > 0 {
+					for _, reason = range rejected {
+						report = append(report, patchName(file)+": "+reason)
+					}
+					continue
+				}
+				seen[current.target] = true
+				prepared = append(prepared, current)
+			}
+			if len(report) > 0 {
+				return "", fmt.Errorf("patch rejected, no files were changed:\n%s", strings.Join(report, "\n"))
+			}
+
+			err = patchCommit(prepared)
+			if err != nil {
+				return "", err
+			}
+
+			for _, current = range prepared {
+				checkpointRecord(ctx, current.target, !current.created, []byte(current.before), current.mode,
+					[]byte(current.after), !current.deleted)
+			}
+			for _, current = range prepared {
+				diff.WriteString(fileDiff(current.path, current.before, current.after))
+			}
+			result = diff.String()
+			if len(result) > maxFileDiffChars {
+				return result[:maxFileDiffChars] + "\n[diff truncated]", nil
+			}
+
+			return result, nil
+		},
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func patchArguments(t *testing.T, patch string) string {
+	var encoded []byte
+
+	var err error
+
+	t.Helper()
+
+	encoded, err = json.Marshal(map[string]string{"patch": patch})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(encoded)
+}
+
+func patchReadFile(t *testing.T, path string) string {
+	var buf []byte
+
+	var err error
+
+	t.Helper()
+
+	buf, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(buf)
+}
+
+func TestFilePatchAppliesEveryFileWithFuzzyContext(t *testing.T) {
+	var root string
+	var patch string
+	var result string
+
+	var err error
+
+	root = t.TempDir()
+	err = os.WriteFile(filepath.Join(root, "a.go"), []byte("package a\n\n// added later\n\nfunc A() int {\n\treturn 1\n}\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "b.txt"), []byte("one\ntwo  \nthree\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBeforeModify(t, root, "a.go")
+	readBeforeModify(t, root, "b.txt")
+
+	patch = "diff --git a/a.go b/a.go\n" +
+		"--- a/a.go\n+++ b/a.go\n" +
+		"@@ -2,4 +2,4 @@\n \n func A() int {\n-\treturn 1\n+\treturn 2\n }\n" +
+		"--- a/b.txt\n+++ b/b.txt\n" +
+		"@@ -1,3 +1,3 @@\n one\n two\n-three\n+THREE\n" +
+		"--- /dev/null\n+++ b/c.txt\n" +
+		"@@ -0,0 +1,2 @@\n+new\n+file\n"
+	result, err = FilePatch(root).Execute(context.Background(), patchArguments(t, patch))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if patchReadFile(t, filepath.Join(root, "a.go")) != "package a\n\n// added later\n\nfunc A() int {\n\treturn 2\n}\n" {
+		t.Fatalf("a.go = %q", patchReadFile(t, filepath.Join(root, "a.go")))
+	}
+	if patchReadFile(t, filepath.Join(root, "b.txt")) != "one\ntwo  \nTHREE\n" {
+		t.Fatalf("b.txt = %q", patchReadFile(t, filepath.Join(root, "b.txt")))
+	}
+	if patchReadFile(t, filepath.Join(root, "c.txt")) != "new\nfile\n" {
+		t.Fatalf("c.txt = %q", patchReadFile(t, filepath.Join(root, "c.txt")))
+	}
+	if !strings.Contains(result, "+++ b/a.go") || !strings.Contains(result, "+THREE") || !strings.Contains(result, "+++ b/c.txt") {
+		t.Fatalf("combined diff = %q", result)
+	}
+
+	_, err = FilePatch(root).Execute(context.Background(), patchArguments(t,
+		"--- a/b.txt\n+++ b/b.txt\n@@ -3 +3 @@\n-THREE\n+3\n"))
+	if err != nil {
+		t.Fatalf("second patch after the first one: %v", err)
+	}
+}
+
+func TestFilePatchRejectsEverythingWhenOneHunkFails(t *testing.T) {
+	var root string
+	var patch string
+
+	var err error
+
+	root = t.TempDir()
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("alpha\nbeta\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(root, "b.txt"), []byte("gamma\ndelta\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBeforeModify(t, root, "a.txt")
+	readBeforeModify(t, root, "b.txt")
+
+	patch = "--- a/a.txt\n+++ b/a.txt\n@@ -1,2 +1,2 @@\n alpha\n-beta\n+BETA\n" +
+		"--- a/b.txt\n+++ b/b.txt\n@@ -1,2 +1,2 @@\n gamma\n-epsilon\n+EPSILON\n"
+	_, err = FilePatch(root).Execute(context.Background(), patchArguments(t, patch))
+	if err == nil {
+		t.Fatal("a patch with a stale hunk was applied")
+	}
+	if !strings.Contains(err.Error(), "b.txt: hunk 1 @@ -1,2 +1,2 @@ did not match near line 1") ||
+		!strings.Contains(err.Error(), "| epsilon") || strings.Contains(err.Error(), "a.txt") {
+		t.Fatalf("rejection report = %q", err.Error())
+	}
+	if patchReadFile(t, filepath.Join(root, "a.txt")) != "alpha\nbeta\n" {
+		t.Fatal("a.txt was written although another file was rejected")
+	}
+}
+
+func TestFilePatchKeepsTheRevisionGuard(t *testing.T) {
+	var root string
+	var patch string
+
+	var err error
+
+	root = t.TempDir()
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("alpha\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch = "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-alpha\n+ALPHA\n"
+
+	_, err = FilePatch(root).Execute(context.Background(), patchArguments(t, patch))
+	if err == nil || !strings.Contains(err.Error(), "with file_read before modifying") {
+		t.Fatalf("unread patch error = %v", err)
+	}
+
+	readBeforeModify(t, root, "a.txt")
+	err = os.WriteFile(filepath.Join(root, "a.txt"), []byte("alpha\nchanged elsewhere\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = FilePatch(root).Execute(context.Background(), patchArguments(t, patch))
+	if err == nil || !strings.Contains(err.Error(), "changed since file_read") {
+		t.Fatalf("stale patch error = %v", err)
+	}
+
+	readBeforeModify(t, root, "a.txt")
+	_, err = FilePatch(root).Execute(context.Background(), patchArguments(t,
+		"--- a/a.txt\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-alpha\n-changed elsewhere\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = os.Stat(filepath.Join(root, "a.txt"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("deleted file still exists: %v", err)
+	}
+
+	_, err = FilePatch(root).Execute(context.Background(), patchArguments(t,
+		"--- /dev/null\n+++ b/../escape.txt\n@@ -0,0 +1 @@\n+bad\n"))
+	if err == nil {
+		t.Fatal("file_patch created a file outside the root")
+	}
+}
@@ -48,11 +48,13 @@ const maxCatalogChars = 4096
 
 var skills []Skill
 
+var skillProblems []string
+
 var skillMu sync.RWMutex
 
 var skillNamePattern *regexp.Regexp = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
 
-func splitFrontmatter(text string) (string, string, error) {
+func SplitFrontmatter(text string) (string, string, error) {
 	var lines []string
 	var index int
 
@@ -127,7 +129,7 @@ func skillParse(dir, scope string) (*Skill, error) {
 		buf = append(buf[:maxSkillBody:maxSkillBody], []byte("\n[truncated]")...)
 	}
 
-	front, body, err = splitFrontmatter(string(buf))
+	front, body, err = SplitFrontmatter(string(buf))
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +154,7 @@ func skillParse(dir, scope string) (*Skill, error) {
 	return &current, nil
 }
 
-func skillScan(root, scope string, seen map[string]bool, accepted []Skill) []Skill {
+func skillScan(root, scope string, seen map[string]bool, accepted []Skill, problems []string) ([]Skill, []string) {
 	var entries []os.DirEntry
 	var entry os.DirEntry
 	var bundle string
@@ -164,7 +166,10 @@ func skillScan(root, scope string, seen map[string]bool, accepted []Skill) []Ski
 	entries, err = os.ReadDir(root)
 	if err != nil {
 		util.Log.Warn("cannot read a skill root", "root", root, "error", err)
-		return accepted
+		if !os.IsNotExist(err) {
+			problems = append(problems, fmt.Sprintf("%s: %v", root, err))
+		}
+		return accepted, problems
 	}
 
 	sort.Slice(entries, func(a, b int) bool { return entries[a].Name() < entries[b].Name() })
@@ -192,24 +197,27 @@ func skillScan(root, scope string, seen map[string]bool, accepted []Skill) []Ski
 		current, err = skillParse(bundle, scope)
 		if err != nil {
 			util.Log.Warn("ignoring an unparsable skill", "skill", entry.Name(), "root", root, "error", err)
+			problems = append(problems, fmt.Sprintf("%s: %v", bundle, err))
 			continue
 		}
 
 		if seen[current.Name] {
 			util.Log.Warn("ignoring a duplicate skill", "skill", current.Name, "bundle", bundle)
+			problems = append(problems, fmt.Sprintf("%s: duplicate skill %s", bundle, current.Name))
 			continue
 		}
 
 		if len(accepted) >= maxSkills {
 			util.Log.Warn("ignoring skills past the limit", "limit", maxSkills, "root", root)
-			return accepted
+			problems = append(problems, fmt.Sprintf("%s: more than %d skills, the rest are ignored", root, maxSkills))
+			return accepted, problems
 		}
 
 		seen[current.Name] = true
 		accepted = append(accepted, *current)
 	}
 
-	return accepted
+	return accepted, problems
 }
 
 func skillRoots(project, user string) [][2]string {
@@ -276,6 +284,13 @@ func SkillFind(name string) *Skill {
 	return nil
 }
 
+func SkillProblems() []string {
+	skillMu.RLock()
+	defer skillMu.RUnlock()
+
+	return skillProblems
+}
+
 func SkillNames() []string {
 	var current Skill
 	var names []string
@@ -311,17 +326,19 @@ func SkillInitAt(project, user string) error {
 	var seen map[string]bool
 	var root [2]string
 	var accepted []Skill
+	var problems []string
 
 	seen = make(map[string]bool)
 
 	for _, root = range skillRoots(project, user) {
-		accepted = skillScan(root[0], root[1], seen, accepted)
+		accepted, problems = skillScan(root[0], root[1], seen, accepted, problems)
 	}
 
 	skillMu.Lock()
 	defer skillMu.Unlock()
 
 	skills = accepted
+	skillProblems = problems
 
 	return nil
 }
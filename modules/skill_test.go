@@ -107,6 +107,9 @@ func TestSkillSkipsBrokenBundles(t *testing.T) {
 	if len(SkillAll()) != 1 || SkillAll()[0].Name != "good" {
 		t.Fatalf("broken bundles were not skipped: %#v", SkillNames())
 	}
+	if len(SkillProblems()) != 4 {
+		t.Fatalf("problems = %q, want one per broken bundle", SkillProblems())
+	}
 }
 
 func TestSkillProjectBeatsUser(t *testing.T) {
@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/devproje/mininaru/util"
+)
+
+const maxSkillFiles = 64
+
+const MaxSkillBundleBytes = 4 << 20
+
+func skillFilePath(rel string) error {
+	var part string
+
+	if rel == "" || strings.Contains(rel, "\\") || path.IsAbs(rel) || path.Clean(rel) != rel {
+		return fmt.Errorf("invalid skill file path %q", rel)
+	}
+
+	for _, part = range strings.Split(rel, "/") {
+		if util.SafeSegment(part) != nil {
+			return fmt.Errorf("invalid skill file path %q", rel)
+		}
+	}
+
+	return nil
+}
+
+func SkillFiles(name string) (map[string][]byte, error) {
+	var current *Skill
+	var files map[string][]byte
+	var total int
+
+	var err error
+
+	current = SkillFind(name)
+	if current == nil {
+		return nil, fmt.Errorf("skill %q not found", name)
+	}
+
+	files = make(map[string][]byte)
+	err = filepath.WalkDir(current.Path, func(file string, entry fs.DirEntry, walkErr error) error {
+		var rel string
+		var buf []byte
+
+		var err error
+
+		if walkErr != nil {
+			return walkErr
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err = filepath.Rel(current.Path, file)
+		if err != nil {
+			return err
+		}
+
+		if len(files) == maxSkillFiles {
+			return fmt.Errorf("skill %q has more than %d files", name, maxSkillFiles)
+		}
+
+		buf, err = os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		total += len(buf)
+		if total > MaxSkillBundleBytes {
+			return fmt.Errorf("skill %q is larger than %d bytes", name, MaxSkillBundleBytes)
+		}
+
+		files[filepath.ToSlash(rel)] = buf
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func SkillInstall(name, scope string, files map[string][]byte, overwrite bool) (string, error) {
+	var root string
+	var bundle string
+	var rel string
+	var buf []byte
+	var target string
+	var total int
+	var ok bool
+
+	var err error
+
+	err = skillCreateName(name)
+	if err != nil {
+		return "", err
+	}
+
+	_, ok = files[SKILL_FILE]
+	if !ok {
+		return "", fmt.Errorf("skill %q has no %s", name, SKILL_FILE)
+	}
+	if len(files) > maxSkillFiles {
+		return "", fmt.Errorf("skill %q has more than %d files", name, maxSkillFiles)
+	}
+
+	for rel, buf = range files {
+		err = skillFilePath(rel)
+		if err != nil {
+			return "", err
+		}
+
+		total += len(buf)
+	}
+	if total > MaxSkillBundleBytes {
+		return "", fmt.Errorf("skill %q is larger than %d bytes", name, MaxSkillBundleBytes)
+	}
+
+	root, scope, err = skillCreateRoot(strings.TrimSpace(scope))
+	if err != nil {
+		return "", err
+	}
+
+	err = skillCreateConflict(name, scope, overwrite)
+	if err != nil {
+		return "", err
+	}
+
+	bundle = filepath.Join(root, name)
+	err = os.RemoveAll(bundle)
+	if err != nil {
+		return "", err
+	}
+
+	for rel, buf = range files {
+		target, err = util.SafeJoin(bundle, filepath.FromSlash(rel))
+		if err != nil {
+			return "", err
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0700)
+		if err != nil {
+			return "", err
+		}
+
+		err = util.WriteFileAtomic(target, buf, 0600)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = SkillInit()
+	if err != nil {
+		return "", err
+	}
+
+	if SkillFind(name) == nil {
+		return "", fmt.Errorf("skill %q was written to %s but did not load back", name, bundle)
+	}
+
+	return bundle, nil
+}
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devproje/mininaru/util"
+)
+
+func TestSkillFilesInstallElsewhere(t *testing.T) {
+	var files map[string][]byte
+	var target string
+	var buf []byte
+
+	var err error
+
+	skillCreateEnv(t)
+
+	_, err = SkillCreateResult("pr-review", "Review a pull request.", "read the tests first", "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(filepath.Join(util.Path(SKILL_DIR), "pr-review", "scripts"), 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(util.Path(SKILL_DIR), "pr-review", "scripts", "diff.sh"), []byte("git diff"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err = SkillFiles("pr-review")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(files["scripts/diff.sh"]) != "git diff" || files[SKILL_FILE] == nil {
+		t.Fatalf("files = %v", files)
+	}
+
+	skillCreateEnv(t)
+
+	target, err = SkillInstall("pr-review", "", files, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err = os.ReadFile(filepath.Join(target, "scripts", "diff.sh"))
+	if err != nil || string(buf) != "git diff" {
+		t.Fatalf("installed script = %q, %v", buf, err)
+	}
+	if SkillFind("pr-review") == nil {
+		t.Fatal("the installed skill did not load")
+	}
+
+	_, err = SkillInstall("pr-review", "", files, false)
+	if err == nil {
+		t.Fatal("an existing skill was overwritten without asking")
+	}
+}
+
+func TestSkillInstallRejectsEscapingPaths(t *testing.T) {
+	var files map[string][]byte
+	var bad string
+
+	var err error
+
+	skillCreateEnv(t)
+
+	for _, bad = range []string{"../evil", "/etc/passwd", "a/../../b", "scripts//x"} {
+		files = map[string][]byte{SKILL_FILE: []byte("---\ndescription: x\n---\nbody"), bad: []byte("x")}
+		_, err = SkillInstall("sneaky", "", files, false)
+		if err == nil {
+			t.Fatalf("path %q was accepted", bad)
+		}
+	}
+}
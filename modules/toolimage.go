@@ -0,0 +1,70 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+)
+
+type ToolImage struct {
+	MediaType string
+	Data      []byte
+}
+
+type ToolImages struct {
+	Images []ToolImage
+	Left   int
+}
+
+type toolImagesKey struct{}
+
+func ToolImagesContext(ctx context.Context, images *ToolImages) context.Context {
+	return context.WithValue(ctx, toolImagesKey{}, images)
+}
+
+func ToolImagesFrom(ctx context.Context) *ToolImages {
+	var images *ToolImages
+
+	images, _ = ctx.Value(toolImagesKey{}).(*ToolImages)
+
+	return images
+}
+
+func ToolImageType(data []byte) string {
+	var detected string
+
+	detected, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+	switch detected {
+	case "image/png", "image/jpeg", "image/gif", "image/webp":
+		return detected
+	}
+
+	return ""
+}
+
+func ToolImageAttach(ctx context.Context, data []byte) (string, error) {
+	var images *ToolImages
+	var mediaType string
+
+	images = ToolImagesFrom(ctx)
+	if images == nil {
+		return "", fmt.Errorf("this conversation cannot take images")
+	}
+
+	mediaType = ToolImageType(data)
+	if mediaType == "" {
+		return "", fmt.Errorf("not a PNG, JPEG, GIF, or WebP image")
+	}
+	if len(data) > images.Left {
+		return "", fmt.Errorf("%d bytes is more than the %d left for images this turn", len(data), images.Left)
+	}
+
+	images.Left -= len(data)
+	images.Images = append(images.Images, ToolImage{MediaType: mediaType, Data: data})
+
+	return fmt.Sprintf("[image %d attached: %s, %d bytes]", len(images.Images), mediaType, len(data)), nil
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package modules
+
+import (
+	"context"
+)
+
+type ToolProgressFunc func(chunk string)
+
+type toolProgressKey struct{}
+
+type toolCallKey struct{}
+
+type toolProgressWriter struct {
+	output   []byte
+	limit    int
+	progress ToolProgressFunc
+}
+
+func ToolProgressContext(ctx context.Context, progress ToolProgressFunc) context.Context {
+	return context.WithValue(ctx, toolProgressKey{}, progress)
+}
+
+func toolProgressFrom(ctx context.Context) ToolProgressFunc {
+	var progress ToolProgressFunc
+
+	progress, _ = ctx.Value(toolProgressKey{}).(ToolProgressFunc)
+
+	return progress
+}
+
+func ToolCallContext(ctx context.Context, callId string) context.Context {
+	return context.WithValue(ctx, toolCallKey{}, callId)
+}
+
+func ToolCallFrom(ctx context.Context) string {
+	var callId string
+
+	callId, _ = ctx.Value(toolCallKey{}).(string)
+
+	return callId
+}
+
+func (w *toolProgressWriter) Write(buf []byte) (int, error) {
+	var kept []byte
+
+	kept = buf[:min(len(buf), max(0, w.limit-len(w.output)))]
+	w.output = append(w.output, kept...)
+	if w.progress != nil && len(kept) > 0 {
+		w.progress(string(kept))
+	}
+
+	return len(buf), nil
+}
@@ -73,7 +73,8 @@ func DefaultToolsAt(root string) ([]Def, error) {
 	if err != nil {
 		return nil, err
 	}
-	rooted = []Def{FileRead(resolved), FileWrite(resolved), FileEdit(resolved), Glob(resolved), Grep(resolved), BashExec(resolved)}
+	rooted = []Def{FileRead(resolved), FileWrite(resolved), FileEdit(resolved), FilePatch(resolved), Glob(resolved), Grep(resolved),
+		LSP(resolved), Git(resolved), GitWrite(resolved), BashExec(resolved), BashStart(resolved)}
 	replacement = make(map[string]Def)
 	for index = range rooted {
 		replacement[rooted[index].Name] = rooted[index]
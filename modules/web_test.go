@@ -8,8 +8,11 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
+
+	"github.com/devproje/mininaru/util"
 )
 
 func webTestConfig(t *testing.T, provider, endpoint, key string) {
@@ -172,3 +175,31 @@ func TestWebConfigFallsBackOnBadProvider(t *testing.T) {
 		t.Fatalf("a valid searxng config was rejected: %#v", accepted)
 	}
 }
+
+func TestWebCheckReportsWhatLoadHides(t *testing.T) {
+	var err error
+
+	err = util.InitFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WebCheck()
+	if err != nil {
+		t.Fatalf("a missing web.json failed the check: %v", err)
+	}
+
+	err = os.WriteFile(util.Path(WEB_PATH), []byte(`{"search":{"provider":"brave"}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WebLoad()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = WebCheck()
+	if err == nil || !strings.Contains(err.Error(), "api key") {
+		t.Fatalf("err = %v, want the missing brave key", err)
+	}
+}
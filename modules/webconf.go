@@ -17,6 +17,8 @@ type SearchConfig struct {
 	Provider string `json:"provider"`
 	Endpoint string `json:"endpoint,omitempty"`
 	APIKey   string `json:"api_key,omitempty"`
+
+	apiKeyRef string
 }
 
 type WebConfig struct {
@@ -38,6 +40,28 @@ var webMu sync.RWMutex
 
 var defaultWeb WebConfig = WebConfig{Search: SearchConfig{Provider: ProviderDuckDuckGo}}
 
+func (c *SearchConfig) SetAPIKey(value string) {
+	c.APIKey, c.apiKeyRef = util.SecretLoad("web search", value)
+}
+
+func (c *SearchConfig) APIKeyRef() string {
+	return c.apiKeyRef
+}
+
+func WebSecrets() []util.SecretField {
+	var cfg SearchConfig
+	var field util.SecretField
+	var ok bool
+
+	cfg = WebSearchConfig()
+	field, ok = util.SecretOf("web search api_key", cfg.APIKey, cfg.apiKeyRef)
+	if !ok {
+		return nil
+	}
+
+	return []util.SecretField{field}
+}
+
 func WebSearchConfig() SearchConfig {
 	webMu.RLock()
 	defer webMu.RUnlock()
@@ -92,15 +116,39 @@ func webAccept(loaded WebConfig) WebConfig {
 	return loaded
 }
 
+func webParse(buf []byte) (WebConfig, error) {
+	var loaded WebConfig
+
+	var err error
+
+	loaded = defaultWeb
+
+	err = json.Unmarshal(buf, &loaded)
+	if err != nil {
+		return WebConfig{}, err
+	}
+
+	loaded.Search.Provider = strings.ToLower(strings.TrimSpace(loaded.Search.Provider))
+	if loaded.Search.Provider == "" {
+		loaded.Search.Provider = defaultWeb.Search.Provider
+	}
+
+	return loaded, nil
+}
+
 func WebSave() error {
+	var stored WebConfig
 	var path string
 	var buf []byte
 
 	var err error
 
 	webMu.RLock()
-	buf, err = json.MarshalIndent(web, "", "    ")
+	stored = web
 	webMu.RUnlock()
+
+	stored.Search.APIKey = util.SecretStored(stored.Search.APIKey, stored.Search.apiKeyRef)
+	buf, err = json.MarshalIndent(stored, "", "    ")
 	if err != nil {
 		return err
 	}
@@ -114,6 +162,7 @@ func WebLoad() error {
 	var path string
 	var buf []byte
 	var loaded WebConfig
+	var ref string
 
 	var err error
 
@@ -136,19 +185,15 @@ func WebLoad() error {
 		}
 	}
 
-	loaded = defaultWeb
-
-	err = json.Unmarshal(buf, &loaded)
+	loaded, err = webParse(buf)
 	if err != nil {
 		return err
 	}
 
-	loaded.Search.Provider = strings.ToLower(strings.TrimSpace(loaded.Search.Provider))
-	if loaded.Search.Provider == "" {
-		loaded.Search.Provider = defaultWeb.Search.Provider
-	}
-
+	loaded.Search.SetAPIKey(loaded.Search.APIKey)
+	ref = loaded.Search.apiKeyRef
 	loaded = webAccept(loaded)
+	loaded.Search.apiKeyRef = ref
 
 	webMu.Lock()
 	web = loaded
@@ -157,6 +202,33 @@ func WebLoad() error {
 	return nil
 }
 
+func WebCheck() error {
+	var buf []byte
+	var loaded WebConfig
+
+	var err error
+
+	buf, err = os.ReadFile(util.Path(WEB_PATH))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	loaded, err = webParse(buf)
+	if err != nil {
+		return err
+	}
+
+	loaded.Search.APIKey, err = util.SecretResolve(loaded.Search.APIKey)
+	if err != nil {
+		return err
+	}
+
+	return WebValidate(&loaded.Search)
+}
+
 func WebReload() error {
 	return WebLoad()
 }
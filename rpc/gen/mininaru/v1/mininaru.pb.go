@@ -71,6 +71,55 @@ func (PairingState) EnumDescriptor() ([]byte, []int) {
 	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{0}
 }
 
+type MessageRating int32
+
+const (
+	MessageRating_MESSAGE_RATING_UNSPECIFIED MessageRating = 0
+	MessageRating_MESSAGE_RATING_GOOD        MessageRating = 1
+	MessageRating_MESSAGE_RATING_BAD         MessageRating = 2
+)
+
+var (
+	MessageRating_name = map[int32]string{
+		0: "MESSAGE_RATING_UNSPECIFIED",
+		1: "MESSAGE_RATING_GOOD",
+		2: "MESSAGE_RATING_BAD",
+	}
+	MessageRating_value = map[string]int32{
+		"MESSAGE_RATING_UNSPECIFIED": 0,
+		"MESSAGE_RATING_GOOD":        1,
+		"MESSAGE_RATING_BAD":         2,
+	}
+)
+
+func (x MessageRating) Enum() *MessageRating {
+	var p *MessageRating
+
+	p = new(MessageRating)
+	*p = x
+	return p
+}
+
+func (x MessageRating) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MessageRating) Descriptor() protoreflect.EnumDescriptor {
+	return file_mininaru_v1_mininaru_proto_enumTypes[1].Descriptor()
+}
+
+func (MessageRating) Type() protoreflect.EnumType {
+	return &file_mininaru_v1_mininaru_proto_enumTypes[1]
+}
+
+func (x MessageRating) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+func (MessageRating) EnumDescriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{1}
+}
+
 type ApprovalChoice int32
 
 const (
@@ -108,11 +157,11 @@ func (x ApprovalChoice) String() string {
 }
 
 func (ApprovalChoice) Descriptor() protoreflect.EnumDescriptor {
-	return file_mininaru_v1_mininaru_proto_enumTypes[1].Descriptor()
+	return file_mininaru_v1_mininaru_proto_enumTypes[2].Descriptor()
 }
 
 func (ApprovalChoice) Type() protoreflect.EnumType {
-	return &file_mininaru_v1_mininaru_proto_enumTypes[1]
+	return &file_mininaru_v1_mininaru_proto_enumTypes[2]
 }
 
 func (x ApprovalChoice) Number() protoreflect.EnumNumber {
@@ -120,7 +169,7 @@ func (x ApprovalChoice) Number() protoreflect.EnumNumber {
 }
 
 func (ApprovalChoice) EnumDescriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{1}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{2}
 }
 
 type Empty struct {
@@ -530,6 +579,11 @@ type Session struct {
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	TurnMode      string                 `protobuf:"bytes,4,opt,name=turn_mode,json=turnMode,proto3" json:"turn_mode,omitempty"`
+	AgentIds      []string               `protobuf:"bytes,5,rep,name=agent_ids,json=agentIds,proto3" json:"agent_ids,omitempty"`
+	Tags          []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	Pinned        bool                   `protobuf:"varint,7,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	Archived      bool                   `protobuf:"varint,8,opt,name=archived,proto3" json:"archived,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -596,6 +650,41 @@ func (x *Session) GetName() string {
 	return ""
 }
 
+func (x *Session) GetTurnMode() string {
+	if x != nil {
+		return x.TurnMode
+	}
+	return ""
+}
+
+func (x *Session) GetAgentIds() []string {
+	if x != nil {
+		return x.AgentIds
+	}
+	return nil
+}
+
+func (x *Session) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Session) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *Session) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
 type Message struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -605,6 +694,7 @@ type Message struct {
 	Reasoning     string                 `protobuf:"bytes,5,opt,name=reasoning,proto3" json:"reasoning,omitempty"`
 	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
 	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	AgentId       string                 `protobuf:"bytes,8,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -699,6 +789,13 @@ func (x *Message) GetError() string {
 	return ""
 }
 
+func (x *Message) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
 type ToolCall struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -905,6 +1002,93 @@ func (x *UsageLine) GetCacheWriteTokens() int64 {
 	return 0
 }
 
+type AgentUsage struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	AgentId          string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Agent            string                 `protobuf:"bytes,2,opt,name=agent,proto3" json:"agent,omitempty"`
+	PromptTokens     int64                  `protobuf:"varint,3,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	CompletionTokens int64                  `protobuf:"varint,4,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
+	TotalTokens      int64                  `protobuf:"varint,5,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *AgentUsage) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = AgentUsage{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[10]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AgentUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AgentUsage) ProtoMessage() {}
+
+func (x *AgentUsage) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[10]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*AgentUsage) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AgentUsage) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *AgentUsage) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *AgentUsage) GetPromptTokens() int64 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *AgentUsage) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+func (x *AgentUsage) GetTotalTokens() int64 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
 type Usage struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	SessionId        string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
@@ -914,6 +1098,7 @@ type Usage struct {
 	TotalTokens      int64                  `protobuf:"varint,5,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
 	CachedTokens     int64                  `protobuf:"varint,6,opt,name=cached_tokens,json=cachedTokens,proto3" json:"cached_tokens,omitempty"`
 	CacheWriteTokens int64                  `protobuf:"varint,7,opt,name=cache_write_tokens,json=cacheWriteTokens,proto3" json:"cache_write_tokens,omitempty"`
+	Agents           []*AgentUsage          `protobuf:"bytes,8,rep,name=agents,proto3" json:"agents,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -926,7 +1111,7 @@ func (x *Usage) Reset() {
 	)
 
 	*x = Usage{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[10]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[11]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -944,7 +1129,7 @@ func (x *Usage) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[10]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[11]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -956,7 +1141,7 @@ func (x *Usage) ProtoReflect() protoreflect.Message {
 }
 
 func (*Usage) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{10}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *Usage) GetSessionId() string {
@@ -1008,6 +1193,13 @@ func (x *Usage) GetCacheWriteTokens() int64 {
 	return 0
 }
 
+func (x *Usage) GetAgents() []*AgentUsage {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
 type ListAgentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
@@ -1022,7 +1214,7 @@ func (x *ListAgentsRequest) Reset() {
 	)
 
 	*x = ListAgentsRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[11]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[12]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1040,7 +1232,7 @@ func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[11]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[12]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1052,7 +1244,7 @@ func (x *ListAgentsRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*ListAgentsRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{11}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{12}
 }
 
 type ListAgentsResponse struct {
@@ -1071,7 +1263,7 @@ func (x *ListAgentsResponse) Reset() {
 	)
 
 	*x = ListAgentsResponse{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[12]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[13]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1089,7 +1281,7 @@ func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[12]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[13]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1101,7 +1293,7 @@ func (x *ListAgentsResponse) ProtoReflect() protoreflect.Message {
 }
 
 func (*ListAgentsResponse) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{12}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *ListAgentsResponse) GetAgents() []*Agent {
@@ -1136,7 +1328,7 @@ func (x *Skill) Reset() {
 	)
 
 	*x = Skill{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[13]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[14]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1154,7 +1346,7 @@ func (x *Skill) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[13]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[14]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1166,7 +1358,7 @@ func (x *Skill) ProtoReflect() protoreflect.Message {
 }
 
 func (*Skill) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{13}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *Skill) GetName() string {
@@ -1211,7 +1403,7 @@ func (x *ListSkillsRequest) Reset() {
 	)
 
 	*x = ListSkillsRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[14]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[15]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1229,7 +1421,7 @@ func (x *ListSkillsRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[14]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[15]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1241,7 +1433,7 @@ func (x *ListSkillsRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*ListSkillsRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{14}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{15}
 }
 
 type ListSkillsResponse struct {
@@ -1259,7 +1451,7 @@ func (x *ListSkillsResponse) Reset() {
 	)
 
 	*x = ListSkillsResponse{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[15]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[16]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1277,7 +1469,7 @@ func (x *ListSkillsResponse) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[15]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[16]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1289,7 +1481,7 @@ func (x *ListSkillsResponse) ProtoReflect() protoreflect.Message {
 }
 
 func (*ListSkillsResponse) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{15}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *ListSkillsResponse) GetSkills() []*Skill {
@@ -1314,7 +1506,7 @@ func (x *GetSkillRequest) Reset() {
 	)
 
 	*x = GetSkillRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[16]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[17]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1332,7 +1524,7 @@ func (x *GetSkillRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[16]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[17]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1344,7 +1536,7 @@ func (x *GetSkillRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*GetSkillRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{16}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetSkillRequest) GetName() string {
@@ -1357,6 +1549,8 @@ func (x *GetSkillRequest) GetName() string {
 type ListSessionsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Agent         string                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
+	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	Archived      bool                   `protobuf:"varint,3,opt,name=archived,proto3" json:"archived,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1369,7 +1563,7 @@ func (x *ListSessionsRequest) Reset() {
 	)
 
 	*x = ListSessionsRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[17]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[18]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1387,7 +1581,7 @@ func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[17]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[18]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1399,7 +1593,7 @@ func (x *ListSessionsRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*ListSessionsRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{17}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ListSessionsRequest) GetAgent() string {
@@ -1409,6 +1603,20 @@ func (x *ListSessionsRequest) GetAgent() string {
 	return ""
 }
 
+func (x *ListSessionsRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListSessionsRequest) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
 type ListSessionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sessions      []*Session             `protobuf:"bytes,1,rep,name=sessions,proto3" json:"sessions,omitempty"`
@@ -1424,7 +1632,7 @@ func (x *ListSessionsResponse) Reset() {
 	)
 
 	*x = ListSessionsResponse{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[18]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[19]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1442,7 +1650,7 @@ func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[18]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[19]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1454,7 +1662,7 @@ func (x *ListSessionsResponse) ProtoReflect() protoreflect.Message {
 }
 
 func (*ListSessionsResponse) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{18}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ListSessionsResponse) GetSessions() []*Session {
@@ -1468,6 +1676,8 @@ type CreateSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Agent         string                 `protobuf:"bytes,1,opt,name=agent,proto3" json:"agent,omitempty"`
 	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Agents        []string               `protobuf:"bytes,3,rep,name=agents,proto3" json:"agents,omitempty"`
+	TurnMode      string                 `protobuf:"bytes,4,opt,name=turn_mode,json=turnMode,proto3" json:"turn_mode,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1480,7 +1690,7 @@ func (x *CreateSessionRequest) Reset() {
 	)
 
 	*x = CreateSessionRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[19]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[20]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1498,7 +1708,7 @@ func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[19]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[20]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1510,7 +1720,7 @@ func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{19}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *CreateSessionRequest) GetAgent() string {
@@ -1527,6 +1737,20 @@ func (x *CreateSessionRequest) GetName() string {
 	return ""
 }
 
+func (x *CreateSessionRequest) GetAgents() []string {
+	if x != nil {
+		return x.Agents
+	}
+	return nil
+}
+
+func (x *CreateSessionRequest) GetTurnMode() string {
+	if x != nil {
+		return x.TurnMode
+	}
+	return ""
+}
+
 type GetSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
@@ -1542,7 +1766,7 @@ func (x *GetSessionRequest) Reset() {
 	)
 
 	*x = GetSessionRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[20]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[21]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1560,7 +1784,7 @@ func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[20]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[21]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1572,7 +1796,7 @@ func (x *GetSessionRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*GetSessionRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{20}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetSessionRequest) GetSessionId() string {
@@ -1603,7 +1827,7 @@ func (x *SessionDetail) Reset() {
 	)
 
 	*x = SessionDetail{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[21]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[22]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1621,7 +1845,7 @@ func (x *SessionDetail) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[21]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[22]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1633,7 +1857,7 @@ func (x *SessionDetail) ProtoReflect() protoreflect.Message {
 }
 
 func (*SessionDetail) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{21}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *SessionDetail) GetSession() *Session {
@@ -1701,7 +1925,7 @@ func (x *RenameSessionRequest) Reset() {
 	)
 
 	*x = RenameSessionRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[22]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[23]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1719,7 +1943,7 @@ func (x *RenameSessionRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[22]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[23]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1731,7 +1955,7 @@ func (x *RenameSessionRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*RenameSessionRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{22}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *RenameSessionRequest) GetSessionId() string {
@@ -1748,40 +1972,44 @@ func (x *RenameSessionRequest) GetName() string {
 	return ""
 }
 
-type DeleteSessionRequest struct {
+type UpdateSessionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	AddTags       []string               `protobuf:"bytes,2,rep,name=add_tags,json=addTags,proto3" json:"add_tags,omitempty"`
+	RemoveTags    []string               `protobuf:"bytes,3,rep,name=remove_tags,json=removeTags,proto3" json:"remove_tags,omitempty"`
+	Pinned        *bool                  `protobuf:"varint,4,opt,name=pinned,proto3,oneof" json:"pinned,omitempty"`
+	Archived      *bool                  `protobuf:"varint,5,opt,name=archived,proto3,oneof" json:"archived,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteSessionRequest) Reset() {
+func (x *UpdateSessionRequest) Reset() {
 	var (
 		mi *protoimpl.
 			MessageInfo
 		ms messageState
 	)
 
-	*x = DeleteSessionRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[23]
+	*x = UpdateSessionRequest{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[24]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteSessionRequest) String() string {
+func (x *UpdateSessionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteSessionRequest) ProtoMessage() {}
+func (*UpdateSessionRequest) ProtoMessage() {}
 
-func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
+func (x *UpdateSessionRequest) ProtoReflect() protoreflect.Message {
 	var (
 		mi *protoimpl.
 			MessageInfo
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[23]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[24]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1792,11 +2020,291 @@ func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{23}
+func (*UpdateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *DeleteSessionRequest) GetSessionId() string {
+func (x *UpdateSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *UpdateSessionRequest) GetAddTags() []string {
+	if x != nil {
+		return x.AddTags
+	}
+	return nil
+}
+
+func (x *UpdateSessionRequest) GetRemoveTags() []string {
+	if x != nil {
+		return x.RemoveTags
+	}
+	return nil
+}
+
+func (x *UpdateSessionRequest) GetPinned() bool {
+	if x != nil && x.Pinned != nil {
+		return *x.Pinned
+	}
+	return false
+}
+
+func (x *UpdateSessionRequest) GetArchived() bool {
+	if x != nil && x.Archived != nil {
+		return *x.Archived
+	}
+	return false
+}
+
+type RateMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Rating        MessageRating          `protobuf:"varint,2,opt,name=rating,proto3,enum=mininaru.v1.MessageRating" json:"rating,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateMessageRequest) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = RateMessageRequest{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[25]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateMessageRequest) ProtoMessage() {}
+
+func (x *RateMessageRequest) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[25]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*RateMessageRequest) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RateMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *RateMessageRequest) GetRating() MessageRating {
+	if x != nil {
+		return x.Rating
+	}
+	return MessageRating_MESSAGE_RATING_UNSPECIFIED
+}
+
+func (x *RateMessageRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type UndoTurnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndoTurnRequest) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = UndoTurnRequest{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[26]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndoTurnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndoTurnRequest) ProtoMessage() {}
+
+func (x *UndoTurnRequest) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[26]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*UndoTurnRequest) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UndoTurnRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *UndoTurnRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type UndoTurnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Restored      []string               `protobuf:"bytes,1,rep,name=restored,proto3" json:"restored,omitempty"`
+	TurnId        string                 `protobuf:"bytes,2,opt,name=turn_id,json=turnId,proto3" json:"turn_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndoTurnResponse) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = UndoTurnResponse{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[27]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndoTurnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndoTurnResponse) ProtoMessage() {}
+
+func (x *UndoTurnResponse) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[27]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*UndoTurnResponse) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *UndoTurnResponse) GetRestored() []string {
+	if x != nil {
+		return x.Restored
+	}
+	return nil
+}
+
+func (x *UndoTurnResponse) GetTurnId() string {
+	if x != nil {
+		return x.TurnId
+	}
+	return ""
+}
+
+type DeleteSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSessionRequest) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = DeleteSessionRequest{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[28]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSessionRequest) ProtoMessage() {}
+
+func (x *DeleteSessionRequest) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[28]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*DeleteSessionRequest) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *DeleteSessionRequest) GetSessionId() string {
 	if x != nil {
 		return x.SessionId
 	}
@@ -1818,7 +2326,7 @@ func (x *GetUsageRequest) Reset() {
 	)
 
 	*x = GetUsageRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[24]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[29]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1836,7 +2344,7 @@ func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[24]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[29]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1848,7 +2356,7 @@ func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*GetUsageRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{24}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *GetUsageRequest) GetSessionId() string {
@@ -1873,7 +2381,7 @@ func (x *CompactSessionRequest) Reset() {
 	)
 
 	*x = CompactSessionRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[25]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[30]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1891,7 +2399,7 @@ func (x *CompactSessionRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[25]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[30]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1903,50 +2411,185 @@ func (x *CompactSessionRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*CompactSessionRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{25}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CompactSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type CompactSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Compacted     bool                   `protobuf:"varint,1,opt,name=compacted,proto3" json:"compacted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompactSessionResponse) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = CompactSessionResponse{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[31]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompactSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompactSessionResponse) ProtoMessage() {}
+
+func (x *CompactSessionResponse) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[31]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*CompactSessionResponse) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CompactSessionResponse) GetCompacted() bool {
+	if x != nil {
+		return x.Compacted
+	}
+	return false
+}
+
+type HandoffSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Agent         string                 `protobuf:"bytes,2,opt,name=agent,proto3" json:"agent,omitempty"`
+	Copy          bool                   `protobuf:"varint,3,opt,name=copy,proto3" json:"copy,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HandoffSessionRequest) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = HandoffSessionRequest{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[32]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HandoffSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HandoffSessionRequest) ProtoMessage() {}
+
+func (x *HandoffSessionRequest) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[32]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*HandoffSessionRequest) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *HandoffSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *HandoffSessionRequest) GetAgent() string {
+	if x != nil {
+		return x.Agent
+	}
+	return ""
+}
+
+func (x *HandoffSessionRequest) GetCopy() bool {
+	if x != nil {
+		return x.Copy
+	}
+	return false
 }
 
-func (x *CompactSessionRequest) GetSessionId() string {
+func (x *HandoffSessionRequest) GetName() string {
 	if x != nil {
-		return x.SessionId
+		return x.Name
 	}
 	return ""
 }
 
-type CompactSessionResponse struct {
+type HandoffSessionResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Compacted     bool                   `protobuf:"varint,1,opt,name=compacted,proto3" json:"compacted,omitempty"`
+	Session       *Session               `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	Agent         *Agent                 `protobuf:"bytes,2,opt,name=agent,proto3" json:"agent,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CompactSessionResponse) Reset() {
+func (x *HandoffSessionResponse) Reset() {
 	var (
 		mi *protoimpl.
 			MessageInfo
 		ms messageState
 	)
 
-	*x = CompactSessionResponse{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[26]
+	*x = HandoffSessionResponse{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[33]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CompactSessionResponse) String() string {
+func (x *HandoffSessionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CompactSessionResponse) ProtoMessage() {}
+func (*HandoffSessionResponse) ProtoMessage() {}
 
-func (x *CompactSessionResponse) ProtoReflect() protoreflect.Message {
+func (x *HandoffSessionResponse) ProtoReflect() protoreflect.Message {
 	var (
 		mi *protoimpl.
 			MessageInfo
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[26]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[33]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1957,15 +2600,22 @@ func (x *CompactSessionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (*CompactSessionResponse) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{26}
+func (*HandoffSessionResponse) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *CompactSessionResponse) GetCompacted() bool {
+func (x *HandoffSessionResponse) GetSession() *Session {
 	if x != nil {
-		return x.Compacted
+		return x.Session
 	}
-	return false
+	return nil
+}
+
+func (x *HandoffSessionResponse) GetAgent() *Agent {
+	if x != nil {
+		return x.Agent
+	}
+	return nil
 }
 
 type ChatStart struct {
@@ -1974,6 +2624,7 @@ type ChatStart struct {
 	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
 	Thinking      string                 `protobuf:"bytes,3,opt,name=thinking,proto3" json:"thinking,omitempty"`
 	Tools         []*ToolDefinition      `protobuf:"bytes,4,rep,name=tools,proto3" json:"tools,omitempty"`
+	Attachments   []*Attachment          `protobuf:"bytes,5,rep,name=attachments,proto3" json:"attachments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -1986,7 +2637,7 @@ func (x *ChatStart) Reset() {
 	)
 
 	*x = ChatStart{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[27]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[34]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2004,7 +2655,7 @@ func (x *ChatStart) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[27]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[34]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2016,7 +2667,7 @@ func (x *ChatStart) ProtoReflect() protoreflect.Message {
 }
 
 func (*ChatStart) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{27}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *ChatStart) GetSessionId() string {
@@ -2047,6 +2698,84 @@ func (x *ChatStart) GetTools() []*ToolDefinition {
 	return nil
 }
 
+func (x *ChatStart) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	MediaType     string                 `protobuf:"bytes,2,opt,name=media_type,json=mediaType,proto3" json:"media_type,omitempty"`
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = Attachment{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[35]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[35]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *Attachment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Attachment) GetMediaType() string {
+	if x != nil {
+		return x.MediaType
+	}
+	return ""
+}
+
+func (x *Attachment) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
 type ToolDefinition struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -2065,7 +2794,7 @@ func (x *ToolDefinition) Reset() {
 	)
 
 	*x = ToolDefinition{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[28]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[36]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2083,7 +2812,7 @@ func (x *ToolDefinition) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[28]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[36]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2095,7 +2824,7 @@ func (x *ToolDefinition) ProtoReflect() protoreflect.Message {
 }
 
 func (*ToolDefinition) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{28}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *ToolDefinition) GetName() string {
@@ -2143,7 +2872,7 @@ func (x *ToolResult) Reset() {
 	)
 
 	*x = ToolResult{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[29]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[37]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2161,7 +2890,7 @@ func (x *ToolResult) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[29]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[37]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2173,7 +2902,7 @@ func (x *ToolResult) ProtoReflect() protoreflect.Message {
 }
 
 func (*ToolResult) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{29}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *ToolResult) GetRequestId() string {
@@ -2213,7 +2942,7 @@ func (x *ApprovalDecision) Reset() {
 	)
 
 	*x = ApprovalDecision{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[30]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[38]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2231,7 +2960,7 @@ func (x *ApprovalDecision) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[30]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[38]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2243,7 +2972,7 @@ func (x *ApprovalDecision) ProtoReflect() protoreflect.Message {
 }
 
 func (*ApprovalDecision) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{30}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *ApprovalDecision) GetRequestId() string {
@@ -2275,7 +3004,7 @@ func (x *ChatClientEvent) Reset() {
 	)
 
 	*x = ChatClientEvent{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[31]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[39]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2293,7 +3022,7 @@ func (x *ChatClientEvent) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[31]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[39]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2305,7 +3034,7 @@ func (x *ChatClientEvent) ProtoReflect() protoreflect.Message {
 }
 
 func (*ChatClientEvent) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{31}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *ChatClientEvent) GetEvent() isChatClientEvent_Event {
@@ -2414,7 +3143,7 @@ func (x *ChatStarted) Reset() {
 	)
 
 	*x = ChatStarted{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[32]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[40]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2432,7 +3161,7 @@ func (x *ChatStarted) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[32]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[40]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2444,7 +3173,7 @@ func (x *ChatStarted) ProtoReflect() protoreflect.Message {
 }
 
 func (*ChatStarted) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{32}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *ChatStarted) GetTurnId() string {
@@ -2469,7 +3198,7 @@ func (x *TextDelta) Reset() {
 	)
 
 	*x = TextDelta{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[33]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[41]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2487,7 +3216,7 @@ func (x *TextDelta) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[33]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[41]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2499,7 +3228,7 @@ func (x *TextDelta) ProtoReflect() protoreflect.Message {
 }
 
 func (*TextDelta) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{33}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *TextDelta) GetText() string {
@@ -2518,6 +3247,7 @@ type ToolEvent struct {
 	Result        string                 `protobuf:"bytes,5,opt,name=result,proto3" json:"result,omitempty"`
 	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
 	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	Output        string                 `protobuf:"bytes,8,opt,name=output,proto3" json:"output,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2530,7 +3260,7 @@ func (x *ToolEvent) Reset() {
 	)
 
 	*x = ToolEvent{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[34]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[42]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2548,7 +3278,7 @@ func (x *ToolEvent) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[34]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[42]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2560,7 +3290,7 @@ func (x *ToolEvent) ProtoReflect() protoreflect.Message {
 }
 
 func (*ToolEvent) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{34}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *ToolEvent) GetPhase() string {
@@ -2612,6 +3342,13 @@ func (x *ToolEvent) GetError() string {
 	return ""
 }
 
+func (x *ToolEvent) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
 type ApprovalRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
@@ -2629,7 +3366,7 @@ func (x *ApprovalRequest) Reset() {
 	)
 
 	*x = ApprovalRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[35]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[43]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2647,7 +3384,7 @@ func (x *ApprovalRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[35]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[43]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2659,7 +3396,7 @@ func (x *ApprovalRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*ApprovalRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{35}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *ApprovalRequest) GetRequestId() string {
@@ -2688,6 +3425,9 @@ type ToolRequest struct {
 	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
 	ToolName      string                 `protobuf:"bytes,2,opt,name=tool_name,json=toolName,proto3" json:"tool_name,omitempty"`
 	Arguments     string                 `protobuf:"bytes,3,opt,name=arguments,proto3" json:"arguments,omitempty"`
+	CallId        string                 `protobuf:"bytes,4,opt,name=call_id,json=callId,proto3" json:"call_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,5,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Sandbox       string                 `protobuf:"bytes,6,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2700,7 +3440,7 @@ func (x *ToolRequest) Reset() {
 	)
 
 	*x = ToolRequest{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[36]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[44]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2718,7 +3458,7 @@ func (x *ToolRequest) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[36]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[44]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2730,7 +3470,7 @@ func (x *ToolRequest) ProtoReflect() protoreflect.Message {
 }
 
 func (*ToolRequest) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{36}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *ToolRequest) GetRequestId() string {
@@ -2754,10 +3494,95 @@ func (x *ToolRequest) GetArguments() string {
 	return ""
 }
 
+func (x *ToolRequest) GetCallId() string {
+	if x != nil {
+		return x.CallId
+	}
+	return ""
+}
+
+func (x *ToolRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ToolRequest) GetSandbox() string {
+	if x != nil {
+		return x.Sandbox
+	}
+	return ""
+}
+
+type Speaker struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AgentId       string                 `protobuf:"bytes,1,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Speaker) Reset() {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	*x = Speaker{}
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[45]
+	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Speaker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Speaker) ProtoMessage() {}
+
+func (x *Speaker) ProtoReflect() protoreflect.Message {
+	var (
+		mi *protoimpl.
+			MessageInfo
+		ms messageState
+	)
+
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[45]
+	if x != nil {
+		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (*Speaker) Descriptor() ([]byte, []int) {
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *Speaker) GetAgentId() string {
+	if x != nil {
+		return x.AgentId
+	}
+	return ""
+}
+
+func (x *Speaker) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
 type ChatCompleted struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       *Message               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	Usage         *Usage                 `protobuf:"bytes,2,opt,name=usage,proto3" json:"usage,omitempty"`
+	Messages      []*Message             `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -2770,7 +3595,7 @@ func (x *ChatCompleted) Reset() {
 	)
 
 	*x = ChatCompleted{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[37]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[46]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2788,7 +3613,7 @@ func (x *ChatCompleted) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[37]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[46]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2800,7 +3625,7 @@ func (x *ChatCompleted) ProtoReflect() protoreflect.Message {
 }
 
 func (*ChatCompleted) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{37}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *ChatCompleted) GetMessage() *Message {
@@ -2817,6 +3642,13 @@ func (x *ChatCompleted) GetUsage() *Usage {
 	return nil
 }
 
+func (x *ChatCompleted) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
 type ChatFailed struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
@@ -2833,7 +3665,7 @@ func (x *ChatFailed) Reset() {
 	)
 
 	*x = ChatFailed{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[38]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[47]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2851,7 +3683,7 @@ func (x *ChatFailed) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[38]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[47]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2863,7 +3695,7 @@ func (x *ChatFailed) ProtoReflect() protoreflect.Message {
 }
 
 func (*ChatFailed) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{38}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *ChatFailed) GetCode() string {
@@ -2895,7 +3727,7 @@ func (x *ChatServerEvent) Reset() {
 	)
 
 	*x = ChatServerEvent{}
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[39]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[48]
 	ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2913,7 +3745,7 @@ func (x *ChatServerEvent) ProtoReflect() protoreflect.Message {
 		ms messageState
 	)
 
-	mi = &file_mininaru_v1_mininaru_proto_msgTypes[39]
+	mi = &file_mininaru_v1_mininaru_proto_msgTypes[48]
 	if x != nil {
 		ms = protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2925,7 +3757,7 @@ func (x *ChatServerEvent) ProtoReflect() protoreflect.Message {
 }
 
 func (*ChatServerEvent) Descriptor() ([]byte, []int) {
-	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{39}
+	return file_mininaru_v1_mininaru_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *ChatServerEvent) GetEvent() isChatServerEvent_Event {
@@ -3047,6 +3879,20 @@ func (x *ChatServerEvent) GetToolRequest() *ToolRequest {
 	return nil
 }
 
+func (x *ChatServerEvent) GetSpeaker() *Speaker {
+	var (
+		xValue *ChatServerEvent_Speaker
+		ok     bool
+	)
+
+	if x != nil {
+		if xValue, ok = x.Event.(*ChatServerEvent_Speaker); ok {
+			return xValue.Speaker
+		}
+	}
+	return nil
+}
+
 type isChatServerEvent_Event interface {
 	isChatServerEvent_Event()
 }
@@ -3083,6 +3929,10 @@ type ChatServerEvent_ToolRequest struct {
 	ToolRequest *ToolRequest `protobuf:"bytes,8,opt,name=tool_request,json=toolRequest,proto3,oneof"`
 }
 
+type ChatServerEvent_Speaker struct {
+	Speaker *Speaker `protobuf:"bytes,9,opt,name=speaker,proto3,oneof"`
+}
+
 func (*ChatServerEvent_Started) isChatServerEvent_Event() {}
 
 func (*ChatServerEvent_Content) isChatServerEvent_Event() {}
@@ -3099,6 +3949,8 @@ func (*ChatServerEvent_Failed) isChatServerEvent_Event() {}
 
 func (*ChatServerEvent_ToolRequest) isChatServerEvent_Event() {}
 
+func (*ChatServerEvent_Speaker) isChatServerEvent_Event() {}
+
 var File_mininaru_v1_mininaru_proto protoreflect.FileDescriptor
 
 const file_mininaru_v1_mininaru_proto_rawDesc = "" +
@@ -3128,11 +3980,16 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05model\x18\x03 \x01(\tR\x05model\x12\x1a\n" +
-	"\bprovider\x18\x04 \x01(\tR\bprovider\"H\n" +
+	"\bprovider\x18\x04 \x01(\tR\bprovider\"\xca\x01\n" +
 	"\aSession\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
 	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x12\n" +
-	"\x04name\x18\x03 \x01(\tR\x04name\"\xb2\x01\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1b\n" +
+	"\tturn_mode\x18\x04 \x01(\tR\bturnMode\x12\x1b\n" +
+	"\tagent_ids\x18\x05 \x03(\tR\bagentIds\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x16\n" +
+	"\x06pinned\x18\a \x01(\bR\x06pinned\x12\x1a\n" +
+	"\barchived\x18\b \x01(\bR\barchived\"\xcd\x01\n" +
 	"\aMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1d\n" +
 	"\n" +
@@ -3141,7 +3998,8 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\acontent\x18\x04 \x01(\tR\acontent\x12\x1c\n" +
 	"\treasoning\x18\x05 \x01(\tR\treasoning\x12\x16\n" +
 	"\x06status\x18\x06 \x01(\tR\x06status\x12\x14\n" +
-	"\x05error\x18\a \x01(\tR\x05error\"\xca\x01\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x19\n" +
+	"\bagent_id\x18\b \x01(\tR\aagentId\"\xca\x01\n" +
 	"\bToolCall\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\acall_id\x18\x02 \x01(\tR\x06callId\x12\x1d\n" +
@@ -3158,7 +4016,14 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\x11completion_tokens\x18\x03 \x01(\x03R\x10completionTokens\x12!\n" +
 	"\ftotal_tokens\x18\x04 \x01(\x03R\vtotalTokens\x12#\n" +
 	"\rcached_tokens\x18\x05 \x01(\x03R\fcachedTokens\x12,\n" +
-	"\x12cache_write_tokens\x18\x06 \x01(\x03R\x10cacheWriteTokens\"\x9c\x02\n" +
+	"\x12cache_write_tokens\x18\x06 \x01(\x03R\x10cacheWriteTokens\"\xb2\x01\n" +
+	"\n" +
+	"AgentUsage\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x14\n" +
+	"\x05agent\x18\x02 \x01(\tR\x05agent\x12#\n" +
+	"\rprompt_tokens\x18\x03 \x01(\x03R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\x04 \x01(\x03R\x10completionTokens\x12!\n" +
+	"\ftotal_tokens\x18\x05 \x01(\x03R\vtotalTokens\"\xcd\x02\n" +
 	"\x05Usage\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12,\n" +
@@ -3167,7 +4032,8 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\x11completion_tokens\x18\x04 \x01(\x03R\x10completionTokens\x12!\n" +
 	"\ftotal_tokens\x18\x05 \x01(\x03R\vtotalTokens\x12#\n" +
 	"\rcached_tokens\x18\x06 \x01(\x03R\fcachedTokens\x12,\n" +
-	"\x12cache_write_tokens\x18\a \x01(\x03R\x10cacheWriteTokens\"\x13\n" +
+	"\x12cache_write_tokens\x18\a \x01(\x03R\x10cacheWriteTokens\x12/\n" +
+	"\x06agents\x18\b \x03(\v2\x17.mininaru.v1.AgentUsageR\x06agents\"\x13\n" +
 	"\x11ListAgentsRequest\"j\n" +
 	"\x12ListAgentsResponse\x12*\n" +
 	"\x06agents\x18\x01 \x03(\v2\x12.mininaru.v1.AgentR\x06agents\x12(\n" +
@@ -3181,14 +4047,18 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\x12ListSkillsResponse\x12*\n" +
 	"\x06skills\x18\x01 \x03(\v2\x12.mininaru.v1.SkillR\x06skills\"%\n" +
 	"\x0fGetSkillRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"+\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"Y\n" +
 	"\x13ListSessionsRequest\x12\x14\n" +
-	"\x05agent\x18\x01 \x01(\tR\x05agent\"H\n" +
+	"\x05agent\x18\x01 \x01(\tR\x05agent\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\x12\x1a\n" +
+	"\barchived\x18\x03 \x01(\bR\barchived\"H\n" +
 	"\x14ListSessionsResponse\x120\n" +
-	"\bsessions\x18\x01 \x03(\v2\x14.mininaru.v1.SessionR\bsessions\"@\n" +
+	"\bsessions\x18\x01 \x03(\v2\x14.mininaru.v1.SessionR\bsessions\"u\n" +
 	"\x14CreateSessionRequest\x12\x14\n" +
 	"\x05agent\x18\x01 \x01(\tR\x05agent\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"2\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06agents\x18\x03 \x03(\tR\x06agents\x12\x1b\n" +
+	"\tturn_mode\x18\x04 \x01(\tR\bturnMode\"2\n" +
 	"\x11GetSessionRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\"\xc4\x02\n" +
@@ -3204,7 +4074,30 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\x14RenameSessionRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"5\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\xc7\x01\n" +
+	"\x14UpdateSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x19\n" +
+	"\badd_tags\x18\x02 \x03(\tR\aaddTags\x12\x1f\n" +
+	"\vremove_tags\x18\x03 \x03(\tR\n" +
+	"removeTags\x12\x1b\n" +
+	"\x06pinned\x18\x04 \x01(\bH\x00R\x06pinned\x88\x01\x01\x12\x1f\n" +
+	"\barchived\x18\x05 \x01(\bH\x01R\barchived\x88\x01\x01B\t\n" +
+	"\a_pinnedB\v\n" +
+	"\t_archived\"{\n" +
+	"\x12RateMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x122\n" +
+	"\x06rating\x18\x02 \x01(\x0e2\x1a.mininaru.v1.MessageRatingR\x06rating\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"O\n" +
+	"\x0fUndoTurnRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"G\n" +
+	"\x10UndoTurnResponse\x12\x1a\n" +
+	"\brestored\x18\x01 \x03(\tR\brestored\x12\x17\n" +
+	"\aturn_id\x18\x02 \x01(\tR\x06turnId\"5\n" +
 	"\x14DeleteSessionRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\"0\n" +
@@ -3215,13 +4108,29 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\"6\n" +
 	"\x16CompactSessionResponse\x12\x1c\n" +
-	"\tcompacted\x18\x01 \x01(\bR\tcompacted\"\x93\x01\n" +
+	"\tcompacted\x18\x01 \x01(\bR\tcompacted\"t\n" +
+	"\x15HandoffSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x14\n" +
+	"\x05agent\x18\x02 \x01(\tR\x05agent\x12\x12\n" +
+	"\x04copy\x18\x03 \x01(\bR\x04copy\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\"r\n" +
+	"\x16HandoffSessionResponse\x12.\n" +
+	"\asession\x18\x01 \x01(\v2\x14.mininaru.v1.SessionR\asession\x12(\n" +
+	"\x05agent\x18\x02 \x01(\v2\x12.mininaru.v1.AgentR\x05agent\"\xce\x01\n" +
 	"\tChatStart\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1a\n" +
 	"\bthinking\x18\x03 \x01(\tR\bthinking\x121\n" +
-	"\x05tools\x18\x04 \x03(\v2\x1b.mininaru.v1.ToolDefinitionR\x05tools\"\x8f\x01\n" +
+	"\x05tools\x18\x04 \x03(\v2\x1b.mininaru.v1.ToolDefinitionR\x05tools\x129\n" +
+	"\vattachments\x18\x05 \x03(\v2\x17.mininaru.v1.AttachmentR\vattachments\"Y\n" +
+	"\n" +
+	"Attachment\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"media_type\x18\x02 \x01(\tR\tmediaType\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\"\x8f\x01\n" +
 	"\x0eToolDefinition\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12'\n" +
@@ -3249,7 +4158,7 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\vChatStarted\x12\x17\n" +
 	"\aturn_id\x18\x01 \x01(\tR\x06turnId\"\x1f\n" +
 	"\tTextDelta\x12\x12\n" +
-	"\x04text\x18\x01 \x01(\tR\x04text\"\xb2\x01\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\"\xca\x01\n" +
 	"\tToolEvent\x12\x14\n" +
 	"\x05phase\x18\x01 \x01(\tR\x05phase\x12\x17\n" +
 	"\acall_id\x18\x02 \x01(\tR\x06callId\x12\x12\n" +
@@ -3257,24 +4166,33 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\targuments\x18\x04 \x01(\tR\targuments\x12\x16\n" +
 	"\x06result\x18\x05 \x01(\tR\x06result\x12\x16\n" +
 	"\x06status\x18\x06 \x01(\tR\x06status\x12\x14\n" +
-	"\x05error\x18\a \x01(\tR\x05error\"k\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x16\n" +
+	"\x06output\x18\b \x01(\tR\x06output\"k\n" +
 	"\x0fApprovalRequest\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12\x1b\n" +
 	"\ttool_name\x18\x02 \x01(\tR\btoolName\x12\x1c\n" +
-	"\targuments\x18\x03 \x01(\tR\targuments\"g\n" +
+	"\targuments\x18\x03 \x01(\tR\targuments\"\xb9\x01\n" +
 	"\vToolRequest\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12\x1b\n" +
 	"\ttool_name\x18\x02 \x01(\tR\btoolName\x12\x1c\n" +
-	"\targuments\x18\x03 \x01(\tR\targuments\"i\n" +
+	"\targuments\x18\x03 \x01(\tR\targuments\x12\x17\n" +
+	"\acall_id\x18\x04 \x01(\tR\x06callId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x05 \x01(\tR\tmessageId\x12\x18\n" +
+	"\asandbox\x18\x06 \x01(\tR\asandbox\"8\n" +
+	"\aSpeaker\x12\x19\n" +
+	"\bagent_id\x18\x01 \x01(\tR\aagentId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\"\x9b\x01\n" +
 	"\rChatCompleted\x12.\n" +
 	"\amessage\x18\x01 \x01(\v2\x14.mininaru.v1.MessageR\amessage\x12(\n" +
-	"\x05usage\x18\x02 \x01(\v2\x12.mininaru.v1.UsageR\x05usage\":\n" +
+	"\x05usage\x18\x02 \x01(\v2\x12.mininaru.v1.UsageR\x05usage\x120\n" +
+	"\bmessages\x18\x03 \x03(\v2\x14.mininaru.v1.MessageR\bmessages\":\n" +
 	"\n" +
 	"ChatFailed\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\xd4\x03\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x86\x04\n" +
 	"\x0fChatServerEvent\x124\n" +
 	"\astarted\x18\x01 \x01(\v2\x18.mininaru.v1.ChatStartedH\x00R\astarted\x122\n" +
 	"\acontent\x18\x02 \x01(\v2\x16.mininaru.v1.TextDeltaH\x00R\acontent\x126\n" +
@@ -3283,14 +4201,19 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\bapproval\x18\x05 \x01(\v2\x1c.mininaru.v1.ApprovalRequestH\x00R\bapproval\x12:\n" +
 	"\tcompleted\x18\x06 \x01(\v2\x1a.mininaru.v1.ChatCompletedH\x00R\tcompleted\x121\n" +
 	"\x06failed\x18\a \x01(\v2\x17.mininaru.v1.ChatFailedH\x00R\x06failed\x12=\n" +
-	"\ftool_request\x18\b \x01(\v2\x18.mininaru.v1.ToolRequestH\x00R\vtoolRequestB\a\n" +
+	"\ftool_request\x18\b \x01(\v2\x18.mininaru.v1.ToolRequestH\x00R\vtoolRequest\x120\n" +
+	"\aspeaker\x18\t \x01(\v2\x14.mininaru.v1.SpeakerH\x00R\aspeakerB\a\n" +
 	"\x05event*\x99\x01\n" +
 	"\fPairingState\x12\x1d\n" +
 	"\x19PAIRING_STATE_UNSPECIFIED\x10\x00\x12\x19\n" +
 	"\x15PAIRING_STATE_WAITING\x10\x01\x12\x1a\n" +
 	"\x16PAIRING_STATE_APPROVED\x10\x02\x12\x18\n" +
 	"\x14PAIRING_STATE_DENIED\x10\x03\x12\x19\n" +
-	"\x15PAIRING_STATE_EXPIRED\x10\x04*\x82\x01\n" +
+	"\x15PAIRING_STATE_EXPIRED\x10\x04*`\n" +
+	"\rMessageRating\x12\x1e\n" +
+	"\x1aMESSAGE_RATING_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13MESSAGE_RATING_GOOD\x10\x01\x12\x16\n" +
+	"\x12MESSAGE_RATING_BAD\x10\x02*\x82\x01\n" +
 	"\x0eApprovalChoice\x12\x1f\n" +
 	"\x1bAPPROVAL_CHOICE_UNSPECIFIED\x10\x00\x12\x18\n" +
 	"\x14APPROVAL_CHOICE_DENY\x10\x01\x12\x18\n" +
@@ -3298,7 +4221,8 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\x17APPROVAL_CHOICE_SESSION\x10\x032\xa6\x01\n" +
 	"\x0ePairingService\x12L\n" +
 	"\x05Begin\x12 .mininaru.v1.BeginPairingRequest\x1a!.mininaru.v1.BeginPairingResponse\x12F\n" +
-	"\x05Watch\x12 .mininaru.v1.WatchPairingRequest\x1a\x19.mininaru.v1.PairingEvent0\x012\xc9\x06\n" +
+	"\x05Watch\x12 .mininaru.v1.WatchPairingRequest\x1a\x19.mininaru.v1.PairingEvent0\x012\x9f\n" +
+	"\n" +
 	"\x0fMininaruService\x12M\n" +
 	"\n" +
 	"ListAgents\x12\x1e.mininaru.v1.ListAgentsRequest\x1a\x1f.mininaru.v1.ListAgentsResponse\x12M\n" +
@@ -3309,11 +4233,17 @@ const file_mininaru_v1_mininaru_proto_rawDesc = "" +
 	"\rCreateSession\x12!.mininaru.v1.CreateSessionRequest\x1a\x14.mininaru.v1.Session\x12H\n" +
 	"\n" +
 	"GetSession\x12\x1e.mininaru.v1.GetSessionRequest\x1a\x1a.mininaru.v1.SessionDetail\x12H\n" +
-	"\rRenameSession\x12!.mininaru.v1.RenameSessionRequest\x1a\x14.mininaru.v1.Session\x12F\n" +
+	"\rRenameSession\x12!.mininaru.v1.RenameSessionRequest\x1a\x14.mininaru.v1.Session\x12H\n" +
+	"\rUpdateSession\x12!.mininaru.v1.UpdateSessionRequest\x1a\x14.mininaru.v1.Session\x12F\n" +
 	"\rDeleteSession\x12!.mininaru.v1.DeleteSessionRequest\x1a\x12.mininaru.v1.Empty\x12<\n" +
 	"\bGetUsage\x12\x1c.mininaru.v1.GetUsageRequest\x1a\x12.mininaru.v1.Usage\x12Y\n" +
-	"\x0eCompactSession\x12\".mininaru.v1.CompactSessionRequest\x1a#.mininaru.v1.CompactSessionResponse\x12F\n" +
-	"\x04Chat\x12\x1c.mininaru.v1.ChatClientEvent\x1a\x1c.mininaru.v1.ChatServerEvent(\x010\x01B=Z;github.com/devproje/mininaru/rpc/gen/mininaru/v1;mininaruv1b\x06proto3"
+	"\x0eCompactSession\x12\".mininaru.v1.CompactSessionRequest\x1a#.mininaru.v1.CompactSessionResponse\x12Y\n" +
+	"\x0eHandoffSession\x12\".mininaru.v1.HandoffSessionRequest\x1a#.mininaru.v1.HandoffSessionResponse\x12F\n" +
+	"\x04Chat\x12\x1c.mininaru.v1.ChatClientEvent\x1a\x1c.mininaru.v1.ChatServerEvent(\x010\x01\x12S\n" +
+	"\x11RegenerateMessage\x12\x1c.mininaru.v1.ChatClientEvent\x1a\x1c.mininaru.v1.ChatServerEvent(\x010\x01\x12M\n" +
+	"\vEditMessage\x12\x1c.mininaru.v1.ChatClientEvent\x1a\x1c.mininaru.v1.ChatServerEvent(\x010\x01\x12B\n" +
+	"\vRateMessage\x12\x1f.mininaru.v1.RateMessageRequest\x1a\x12.mininaru.v1.Empty\x12G\n" +
+	"\bUndoTurn\x12\x1c.mininaru.v1.UndoTurnRequest\x1a\x1d.mininaru.v1.UndoTurnResponseB=Z;github.com/devproje/mininaru/rpc/gen/mininaru/v1;mininaruv1b\x06proto3"
 
 var (
 	file_mininaru_v1_mininaru_proto_rawDescOnce sync.Once
@@ -3327,109 +4257,138 @@ func file_mininaru_v1_mininaru_proto_rawDescGZIP() []byte {
 	return file_mininaru_v1_mininaru_proto_rawDescData
 }
 
-var file_mininaru_v1_mininaru_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_mininaru_v1_mininaru_proto_msgTypes = make([]protoimpl.MessageInfo, 40)
+var file_mininaru_v1_mininaru_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_mininaru_v1_mininaru_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
 var file_mininaru_v1_mininaru_proto_goTypes = []any{
 	(PairingState)(0),              // 0: mininaru.v1.PairingState
-	(ApprovalChoice)(0),            // 1: mininaru.v1.ApprovalChoice
-	(*Empty)(nil),                  // 2: mininaru.v1.Empty
-	(*BeginPairingRequest)(nil),    // 3: mininaru.v1.BeginPairingRequest
-	(*BeginPairingResponse)(nil),   // 4: mininaru.v1.BeginPairingResponse
-	(*WatchPairingRequest)(nil),    // 5: mininaru.v1.WatchPairingRequest
-	(*PairingEvent)(nil),           // 6: mininaru.v1.PairingEvent
-	(*Agent)(nil),                  // 7: mininaru.v1.Agent
-	(*Session)(nil),                // 8: mininaru.v1.Session
-	(*Message)(nil),                // 9: mininaru.v1.Message
-	(*ToolCall)(nil),               // 10: mininaru.v1.ToolCall
-	(*UsageLine)(nil),              // 11: mininaru.v1.UsageLine
-	(*Usage)(nil),                  // 12: mininaru.v1.Usage
-	(*ListAgentsRequest)(nil),      // 13: mininaru.v1.ListAgentsRequest
-	(*ListAgentsResponse)(nil),     // 14: mininaru.v1.ListAgentsResponse
-	(*Skill)(nil),                  // 15: mininaru.v1.Skill
-	(*ListSkillsRequest)(nil),      // 16: mininaru.v1.ListSkillsRequest
-	(*ListSkillsResponse)(nil),     // 17: mininaru.v1.ListSkillsResponse
-	(*GetSkillRequest)(nil),        // 18: mininaru.v1.GetSkillRequest
-	(*ListSessionsRequest)(nil),    // 19: mininaru.v1.ListSessionsRequest
-	(*ListSessionsResponse)(nil),   // 20: mininaru.v1.ListSessionsResponse
-	(*CreateSessionRequest)(nil),   // 21: mininaru.v1.CreateSessionRequest
-	(*GetSessionRequest)(nil),      // 22: mininaru.v1.GetSessionRequest
-	(*SessionDetail)(nil),          // 23: mininaru.v1.SessionDetail
-	(*RenameSessionRequest)(nil),   // 24: mininaru.v1.RenameSessionRequest
-	(*DeleteSessionRequest)(nil),   // 25: mininaru.v1.DeleteSessionRequest
-	(*GetUsageRequest)(nil),        // 26: mininaru.v1.GetUsageRequest
-	(*CompactSessionRequest)(nil),  // 27: mininaru.v1.CompactSessionRequest
-	(*CompactSessionResponse)(nil), // 28: mininaru.v1.CompactSessionResponse
-	(*ChatStart)(nil),              // 29: mininaru.v1.ChatStart
-	(*ToolDefinition)(nil),         // 30: mininaru.v1.ToolDefinition
-	(*ToolResult)(nil),             // 31: mininaru.v1.ToolResult
-	(*ApprovalDecision)(nil),       // 32: mininaru.v1.ApprovalDecision
-	(*ChatClientEvent)(nil),        // 33: mininaru.v1.ChatClientEvent
-	(*ChatStarted)(nil),            // 34: mininaru.v1.ChatStarted
-	(*TextDelta)(nil),              // 35: mininaru.v1.TextDelta
-	(*ToolEvent)(nil),              // 36: mininaru.v1.ToolEvent
-	(*ApprovalRequest)(nil),        // 37: mininaru.v1.ApprovalRequest
-	(*ToolRequest)(nil),            // 38: mininaru.v1.ToolRequest
-	(*ChatCompleted)(nil),          // 39: mininaru.v1.ChatCompleted
-	(*ChatFailed)(nil),             // 40: mininaru.v1.ChatFailed
-	(*ChatServerEvent)(nil),        // 41: mininaru.v1.ChatServerEvent
+	(MessageRating)(0),             // 1: mininaru.v1.MessageRating
+	(ApprovalChoice)(0),            // 2: mininaru.v1.ApprovalChoice
+	(*Empty)(nil),                  // 3: mininaru.v1.Empty
+	(*BeginPairingRequest)(nil),    // 4: mininaru.v1.BeginPairingRequest
+	(*BeginPairingResponse)(nil),   // 5: mininaru.v1.BeginPairingResponse
+	(*WatchPairingRequest)(nil),    // 6: mininaru.v1.WatchPairingRequest
+	(*PairingEvent)(nil),           // 7: mininaru.v1.PairingEvent
+	(*Agent)(nil),                  // 8: mininaru.v1.Agent
+	(*Session)(nil),                // 9: mininaru.v1.Session
+	(*Message)(nil),                // 10: mininaru.v1.Message
+	(*ToolCall)(nil),               // 11: mininaru.v1.ToolCall
+	(*UsageLine)(nil),              // 12: mininaru.v1.UsageLine
+	(*AgentUsage)(nil),             // 13: mininaru.v1.AgentUsage
+	(*Usage)(nil),                  // 14: mininaru.v1.Usage
+	(*ListAgentsRequest)(nil),      // 15: mininaru.v1.ListAgentsRequest
+	(*ListAgentsResponse)(nil),     // 16: mininaru.v1.ListAgentsResponse
+	(*Skill)(nil),                  // 17: mininaru.v1.Skill
+	(*ListSkillsRequest)(nil),      // 18: mininaru.v1.ListSkillsRequest
+	(*ListSkillsResponse)(nil),     // 19: mininaru.v1.ListSkillsResponse
+	(*GetSkillRequest)(nil),        // 20: mininaru.v1.GetSkillRequest
+	(*ListSessionsRequest)(nil),    // 21: mininaru.v1.ListSessionsRequest
+	(*ListSessionsResponse)(nil),   // 22: mininaru.v1.ListSessionsResponse
+	(*CreateSessionRequest)(nil),   // 23: mininaru.v1.CreateSessionRequest
+	(*GetSessionRequest)(nil),      // 24: mininaru.v1.GetSessionRequest
+	(*SessionDetail)(nil),          // 25: mininaru.v1.SessionDetail
+	(*RenameSessionRequest)(nil),   // 26: mininaru.v1.RenameSessionRequest
+	(*UpdateSessionRequest)(nil),   // 27: mininaru.v1.UpdateSessionRequest
+	(*RateMessageRequest)(nil),     // 28: mininaru.v1.RateMessageRequest
+	(*UndoTurnRequest)(nil),        // 29: mininaru.v1.UndoTurnRequest
+	(*UndoTurnResponse)(nil),       // 30: mininaru.v1.UndoTurnResponse
+	(*DeleteSessionRequest)(nil),   // 31: mininaru.v1.DeleteSessionRequest
+	(*GetUsageRequest)(nil),        // 32: mininaru.v1.GetUsageRequest
+	(*CompactSessionRequest)(nil),  // 33: mininaru.v1.CompactSessionRequest
+	(*CompactSessionResponse)(nil), // 34: mininaru.v1.CompactSessionResponse
+	(*HandoffSessionRequest)(nil),  // 35: mininaru.v1.HandoffSessionRequest
+	(*HandoffSessionResponse)(nil), // 36: mininaru.v1.HandoffSessionResponse
+	(*ChatStart)(nil),              // 37: mininaru.v1.ChatStart
+	(*Attachment)(nil),             // 38: mininaru.v1.Attachment
+	(*ToolDefinition)(nil),         // 39: mininaru.v1.ToolDefinition
+	(*ToolResult)(nil),             // 40: mininaru.v1.ToolResult
+	(*ApprovalDecision)(nil),       // 41: mininaru.v1.ApprovalDecision
+	(*ChatClientEvent)(nil),        // 42: mininaru.v1.ChatClientEvent
+	(*ChatStarted)(nil),            // 43: mininaru.v1.ChatStarted
+	(*TextDelta)(nil),              // 44: mininaru.v1.TextDelta
+	(*ToolEvent)(nil),              // 45: mininaru.v1.ToolEvent
+	(*ApprovalRequest)(nil),        // 46: mininaru.v1.ApprovalRequest
+	(*ToolRequest)(nil),            // 47: mininaru.v1.ToolRequest
+	(*Speaker)(nil),                // 48: mininaru.v1.Speaker
+	(*ChatCompleted)(nil),          // 49: mininaru.v1.ChatCompleted
+	(*ChatFailed)(nil),             // 50: mininaru.v1.ChatFailed
+	(*ChatServerEvent)(nil),        // 51: mininaru.v1.ChatServerEvent
 }
 var file_mininaru_v1_mininaru_proto_depIdxs = []int32{
 	0,  // 0: mininaru.v1.PairingEvent.state:type_name -> mininaru.v1.PairingState
-	11, // 1: mininaru.v1.Usage.lines:type_name -> mininaru.v1.UsageLine
-	7,  // 2: mininaru.v1.ListAgentsResponse.agents:type_name -> mininaru.v1.Agent
-	15, // 3: mininaru.v1.ListSkillsResponse.skills:type_name -> mininaru.v1.Skill
-	8,  // 4: mininaru.v1.ListSessionsResponse.sessions:type_name -> mininaru.v1.Session
-	8,  // 5: mininaru.v1.SessionDetail.session:type_name -> mininaru.v1.Session
-	7,  // 6: mininaru.v1.SessionDetail.agent:type_name -> mininaru.v1.Agent
-	9,  // 7: mininaru.v1.SessionDetail.messages:type_name -> mininaru.v1.Message
-	10, // 8: mininaru.v1.SessionDetail.tool_calls:type_name -> mininaru.v1.ToolCall
-	30, // 9: mininaru.v1.ChatStart.tools:type_name -> mininaru.v1.ToolDefinition
-	1,  // 10: mininaru.v1.ApprovalDecision.choice:type_name -> mininaru.v1.ApprovalChoice
-	29, // 11: mininaru.v1.ChatClientEvent.start:type_name -> mininaru.v1.ChatStart
-	32, // 12: mininaru.v1.ChatClientEvent.approval:type_name -> mininaru.v1.ApprovalDecision
-	2,  // 13: mininaru.v1.ChatClientEvent.cancel:type_name -> mininaru.v1.Empty
-	31, // 14: mininaru.v1.ChatClientEvent.tool_result:type_name -> mininaru.v1.ToolResult
-	9,  // 15: mininaru.v1.ChatCompleted.message:type_name -> mininaru.v1.Message
-	12, // 16: mininaru.v1.ChatCompleted.usage:type_name -> mininaru.v1.Usage
-	34, // 17: mininaru.v1.ChatServerEvent.started:type_name -> mininaru.v1.ChatStarted
-	35, // 18: mininaru.v1.ChatServerEvent.content:type_name -> mininaru.v1.TextDelta
-	35, // 19: mininaru.v1.ChatServerEvent.reasoning:type_name -> mininaru.v1.TextDelta
-	36, // 20: mininaru.v1.ChatServerEvent.tool:type_name -> mininaru.v1.ToolEvent
-	37, // 21: mininaru.v1.ChatServerEvent.approval:type_name -> mininaru.v1.ApprovalRequest
-	39, // 22: mininaru.v1.ChatServerEvent.completed:type_name -> mininaru.v1.ChatCompleted
-	40, // 23: mininaru.v1.ChatServerEvent.failed:type_name -> mininaru.v1.ChatFailed
-	38, // 24: mininaru.v1.ChatServerEvent.tool_request:type_name -> mininaru.v1.ToolRequest
-	3,  // 25: mininaru.v1.PairingService.Begin:input_type -> mininaru.v1.BeginPairingRequest
-	5,  // 26: mininaru.v1.PairingService.Watch:input_type -> mininaru.v1.WatchPairingRequest
-	13, // 27: mininaru.v1.MininaruService.ListAgents:input_type -> mininaru.v1.ListAgentsRequest
-	16, // 28: mininaru.v1.MininaruService.ListSkills:input_type -> mininaru.v1.ListSkillsRequest
-	18, // 29: mininaru.v1.MininaruService.GetSkill:input_type -> mininaru.v1.GetSkillRequest
-	19, // 30: mininaru.v1.MininaruService.ListSessions:input_type -> mininaru.v1.ListSessionsRequest
-	21, // 31: mininaru.v1.MininaruService.CreateSession:input_type -> mininaru.v1.CreateSessionRequest
-	22, // 32: mininaru.v1.MininaruService.GetSession:input_type -> mininaru.v1.GetSessionRequest
-	24, // 33: mininaru.v1.MininaruService.RenameSession:input_type -> mininaru.v1.RenameSessionRequest
-	25, // 34: mininaru.v1.MininaruService.DeleteSession:input_type -> mininaru.v1.DeleteSessionRequest
-	26, // 35: mininaru.v1.MininaruService.GetUsage:input_type -> mininaru.v1.GetUsageRequest
-	27, // 36: mininaru.v1.MininaruService.CompactSession:input_type -> mininaru.v1.CompactSessionRequest
-	33, // 37: mininaru.v1.MininaruService.Chat:input_type -> mininaru.v1.ChatClientEvent
-	4,  // 38: mininaru.v1.PairingService.Begin:output_type -> mininaru.v1.BeginPairingResponse
-	6,  // 39: mininaru.v1.PairingService.Watch:output_type -> mininaru.v1.PairingEvent
-	14, // 40: mininaru.v1.MininaruService.ListAgents:output_type -> mininaru.v1.ListAgentsResponse
-	17, // 41: mininaru.v1.MininaruService.ListSkills:output_type -> mininaru.v1.ListSkillsResponse
-	15, // 42: mininaru.v1.MininaruService.GetSkill:output_type -> mininaru.v1.Skill
-	20, // 43: mininaru.v1.MininaruService.ListSessions:output_type -> mininaru.v1.ListSessionsResponse
-	8,  // 44: mininaru.v1.MininaruService.CreateSession:output_type -> mininaru.v1.Session
-	23, // 45: mininaru.v1.MininaruService.GetSession:output_type -> mininaru.v1.SessionDetail
-	8,  // 46: mininaru.v1.MininaruService.RenameSession:output_type -> mininaru.v1.Session
-	2,  // 47: mininaru.v1.MininaruService.DeleteSession:output_type -> mininaru.v1.Empty
-	12, // 48: mininaru.v1.MininaruService.GetUsage:output_type -> mininaru.v1.Usage
-	28, // 49: mininaru.v1.MininaruService.CompactSession:output_type -> mininaru.v1.CompactSessionResponse
-	41, // 50: mininaru.v1.MininaruService.Chat:output_type -> mininaru.v1.ChatServerEvent
-	38, // [38:51] is the sub-list for method output_type
-	25, // [25:38] is the sub-list for method input_type
-	25, // [25:25] is the sub-list for extension type_name
-	25, // [25:25] is the sub-list for extension extendee
-	0,  // [0:25] is the sub-list for field type_name
+	12, // 1: mininaru.v1.Usage.lines:type_name -> mininaru.v1.UsageLine
+	13, // 2: mininaru.v1.Usage.agents:type_name -> mininaru.v1.AgentUsage
+	8,  // 3: mininaru.v1.ListAgentsResponse.agents:type_name -> mininaru.v1.Agent
+	17, // 4: mininaru.v1.ListSkillsResponse.skills:type_name -> mininaru.v1.Skill
+	9,  // 5: mininaru.v1.ListSessionsResponse.sessions:type_name -> mininaru.v1.Session
+	9,  // 6: mininaru.v1.SessionDetail.session:type_name -> mininaru.v1.Session
+	8,  // 7: mininaru.v1.SessionDetail.agent:type_name -> mininaru.v1.Agent
+	10, // 8: mininaru.v1.SessionDetail.messages:type_name -> mininaru.v1.Message
+	11, // 9: mininaru.v1.SessionDetail.tool_calls:type_name -> mininaru.v1.ToolCall
+	1,  // 10: mininaru.v1.RateMessageRequest.rating:type_name -> mininaru.v1.MessageRating
+	9,  // 11: mininaru.v1.HandoffSessionResponse.session:type_name -> mininaru.v1.Session
+	8,  // 12: mininaru.v1.HandoffSessionResponse.agent:type_name -> mininaru.v1.Agent
+	39, // 13: mininaru.v1.ChatStart.tools:type_name -> mininaru.v1.ToolDefinition
+	38, // 14: mininaru.v1.ChatStart.attachments:type_name -> mininaru.v1.Attachment
+	2,  // 15: mininaru.v1.ApprovalDecision.choice:type_name -> mininaru.v1.ApprovalChoice
+	37, // 16: mininaru.v1.ChatClientEvent.start:type_name -> mininaru.v1.ChatStart
+	41, // 17: mininaru.v1.ChatClientEvent.approval:type_name -> mininaru.v1.ApprovalDecision
+	3,  // 18: mininaru.v1.ChatClientEvent.cancel:type_name -> mininaru.v1.Empty
+	40, // 19: mininaru.v1.ChatClientEvent.tool_result:type_name -> mininaru.v1.ToolResult
+	10, // 20: mininaru.v1.ChatCompleted.message:type_name -> mininaru.v1.Message
+	14, // 21: mininaru.v1.ChatCompleted.usage:type_name -> mininaru.v1.Usage
+	10, // 22: mininaru.v1.ChatCompleted.messages:type_name -> mininaru.v1.Message
+	43, // 23: mininaru.v1.ChatServerEvent.started:type_name -> mininaru.v1.ChatStarted
+	44, // 24: mininaru.v1.ChatServerEvent.content:type_name -> mininaru.v1.TextDelta
+	44, // 25: mininaru.v1.ChatServerEvent.reasoning:type_name -> mininaru.v1.TextDelta
+	45, // 26: mininaru.v1.ChatServerEvent.tool:type_name -> mininaru.v1.ToolEvent
+	46, // 27: mininaru.v1.ChatServerEvent.approval:type_name -> mininaru.v1.ApprovalRequest
+	49, // 28: mininaru.v1.ChatServerEvent.completed:type_name -> mininaru.v1.ChatCompleted
+	50, // 29: mininaru.v1.ChatServerEvent.failed:type_name -> mininaru.v1.ChatFailed
+	47, // 30: mininaru.v1.ChatServerEvent.tool_request:type_name -> mininaru.v1.ToolRequest
+	48, // 31: mininaru.v1.ChatServerEvent.speaker:type_name -> mininaru.v1.Speaker
+	4,  // 32: mininaru.v1.PairingService.Begin:input_type -> mininaru.v1.BeginPairingRequest
+	6,  // 33: mininaru.v1.PairingService.Watch:input_type -> mininaru.v1.WatchPairingRequest
+	15, // 34: mininaru.v1.MininaruService.ListAgents:input_type -> mininaru.v1.ListAgentsRequest
+	18, // 35: mininaru.v1.MininaruService.ListSkills:input_type -> mininaru.v1.ListSkillsRequest
+	20, // 36: mininaru.v1.MininaruService.GetSkill:input_type -> mininaru.v1.GetSkillRequest
+	21, // 37: mininaru.v1.MininaruService.ListSessions:input_type -> mininaru.v1.ListSessionsRequest
+	23, // 38: mininaru.v1.MininaruService.CreateSession:input_type -> mininaru.v1.CreateSessionRequest
+	24, // 39: mininaru.v1.MininaruService.GetSession:input_type -> mininaru.v1.GetSessionRequest
+	26, // 40: mininaru.v1.MininaruService.RenameSession:input_type -> mininaru.v1.RenameSessionRequest
+	27, // 41: mininaru.v1.MininaruService.UpdateSession:input_type -> mininaru.v1.UpdateSessionRequest
+	31, // 42: mininaru.v1.MininaruService.DeleteSession:input_type -> mininaru.v1.DeleteSessionRequest
+	32, // 43: mininaru.v1.MininaruService.GetUsage:input_type -> mininaru.v1.GetUsageRequest
+	33, // 44: mininaru.v1.MininaruService.CompactSession:input_type -> mininaru.v1.CompactSessionRequest
+	35, // 45: mininaru.v1.MininaruService.HandoffSession:input_type -> mininaru.v1.HandoffSessionRequest
+	42, // 46: mininaru.v1.MininaruService.Chat:input_type -> mininaru.v1.ChatClientEvent
+	42, // 47: mininaru.v1.MininaruService.RegenerateMessage:input_type -> mininaru.v1.ChatClientEvent
+	42, // 48: mininaru.v1.MininaruService.EditMessage:input_type -> mininaru.v1.ChatClientEvent
+	28, // 49: mininaru.v1.MininaruService.RateMessage:input_type -> mininaru.v1.RateMessageRequest
+	29, // 50: mininaru.v1.MininaruService.UndoTurn:input_type -> mininaru.v1.UndoTurnRequest
+	5,  // 51: mininaru.v1.PairingService.Begin:output_type -> mininaru.v1.BeginPairingResponse
+	7,  // 52: mininaru.v1.PairingService.Watch:output_type -> mininaru.v1.PairingEvent
+	16, // 53: mininaru.v1.MininaruService.ListAgents:output_type -> mininaru.v1.ListAgentsResponse
+	19, // 54: mininaru.v1.MininaruService.ListSkills:output_type -> mininaru.v1.ListSkillsResponse
+	17, // 55: mininaru.v1.MininaruService.GetSkill:output_type -> mininaru.v1.Skill
+	22, // 56: mininaru.v1.MininaruService.ListSessions:output_type -> mininaru.v1.ListSessionsResponse
+	9,  // 57: mininaru.v1.MininaruService.CreateSession:output_type -> mininaru.v1.Session
+	25, // 58: mininaru.v1.MininaruService.GetSession:output_type -> mininaru.v1.SessionDetail
+	9,  // 59: mininaru.v1.MininaruService.RenameSession:output_type -> mininaru.v1.Session
+	9,  // 60: mininaru.v1.MininaruService.UpdateSession:output_type -> mininaru.v1.Session
+	3,  // 61: mininaru.v1.MininaruService.DeleteSession:output_type -> mininaru.v1.Empty
+	14, // 62: mininaru.v1.MininaruService.GetUsage:output_type -> mininaru.v1.Usage
+	34, // 63: mininaru.v1.MininaruService.CompactSession:output_type -> mininaru.v1.CompactSessionResponse
+	36, // 64: mininaru.v1.MininaruService.HandoffSession:output_type -> mininaru.v1.HandoffSessionResponse
+	51, // 65: mininaru.v1.MininaruService.Chat:output_type -> mininaru.v1.ChatServerEvent
+	51, // 66: mininaru.v1.MininaruService.RegenerateMessage:output_type -> mininaru.v1.ChatServerEvent
+	51, // 67: mininaru.v1.MininaruService.EditMessage:output_type -> mininaru.v1.ChatServerEvent
+	3,  // 68: mininaru.v1.MininaruService.RateMessage:output_type -> mininaru.v1.Empty
+	30, // 69: mininaru.v1.MininaruService.UndoTurn:output_type -> mininaru.v1.UndoTurnResponse
+	51, // [51:70] is the sub-list for method output_type
+	32, // [32:51] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_mininaru_v1_mininaru_proto_init() }
@@ -3438,13 +4397,14 @@ func file_mininaru_v1_mininaru_proto_init() {
 	if File_mininaru_v1_mininaru_proto != nil {
 		return
 	}
-	file_mininaru_v1_mininaru_proto_msgTypes[31].OneofWrappers = []any{
+	file_mininaru_v1_mininaru_proto_msgTypes[24].OneofWrappers = []any{}
+	file_mininaru_v1_mininaru_proto_msgTypes[39].OneofWrappers = []any{
 		(*ChatClientEvent_Start)(nil),
 		(*ChatClientEvent_Approval)(nil),
 		(*ChatClientEvent_Cancel)(nil),
 		(*ChatClientEvent_ToolResult)(nil),
 	}
-	file_mininaru_v1_mininaru_proto_msgTypes[39].OneofWrappers = []any{
+	file_mininaru_v1_mininaru_proto_msgTypes[48].OneofWrappers = []any{
 		(*ChatServerEvent_Started)(nil),
 		(*ChatServerEvent_Content)(nil),
 		(*ChatServerEvent_Reasoning)(nil),
@@ -3453,6 +4413,7 @@ func file_mininaru_v1_mininaru_proto_init() {
 		(*ChatServerEvent_Completed)(nil),
 		(*ChatServerEvent_Failed)(nil),
 		(*ChatServerEvent_ToolRequest)(nil),
+		(*ChatServerEvent_Speaker)(nil),
 	}
 	type x struct{}
 
@@ -3460,8 +4421,8 @@ func file_mininaru_v1_mininaru_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mininaru_v1_mininaru_proto_rawDesc), len(file_mininaru_v1_mininaru_proto_rawDesc)),
-			NumEnums:      2,
-			NumMessages:   40,
+			NumEnums:      3,
+			NumMessages:   49,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
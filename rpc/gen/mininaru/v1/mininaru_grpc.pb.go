@@ -171,17 +171,23 @@ var PairingService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	MininaruService_ListAgents_FullMethodName     = "/mininaru.v1.MininaruService/ListAgents"
-	MininaruService_ListSkills_FullMethodName     = "/mininaru.v1.MininaruService/ListSkills"
-	MininaruService_GetSkill_FullMethodName       = "/mininaru.v1.MininaruService/GetSkill"
-	MininaruService_ListSessions_FullMethodName   = "/mininaru.v1.MininaruService/ListSessions"
-	MininaruService_CreateSession_FullMethodName  = "/mininaru.v1.MininaruService/CreateSession"
-	MininaruService_GetSession_FullMethodName     = "/mininaru.v1.MininaruService/GetSession"
-	MininaruService_RenameSession_FullMethodName  = "/mininaru.v1.MininaruService/RenameSession"
-	MininaruService_DeleteSession_FullMethodName  = "/mininaru.v1.MininaruService/DeleteSession"
-	MininaruService_GetUsage_FullMethodName       = "/mininaru.v1.MininaruService/GetUsage"
-	MininaruService_CompactSession_FullMethodName = "/mininaru.v1.MininaruService/CompactSession"
-	MininaruService_Chat_FullMethodName           = "/mininaru.v1.MininaruService/Chat"
+	MininaruService_ListAgents_FullMethodName        = "/mininaru.v1.MininaruService/ListAgents"
+	MininaruService_ListSkills_FullMethodName        = "/mininaru.v1.MininaruService/ListSkills"
+	MininaruService_GetSkill_FullMethodName          = "/mininaru.v1.MininaruService/GetSkill"
+	MininaruService_ListSessions_FullMethodName      = "/mininaru.v1.MininaruService/ListSessions"
+	MininaruService_CreateSession_FullMethodName     = "/mininaru.v1.MininaruService/CreateSession"
+	MininaruService_GetSession_FullMethodName        = "/mininaru.v1.MininaruService/GetSession"
+	MininaruService_RenameSession_FullMethodName     = "/mininaru.v1.MininaruService/RenameSession"
+	MininaruService_UpdateSession_FullMethodName     = "/mininaru.v1.MininaruService/UpdateSession"
+	MininaruService_DeleteSession_FullMethodName     = "/mininaru.v1.MininaruService/DeleteSession"
+	MininaruService_GetUsage_FullMethodName          = "/mininaru.v1.MininaruService/GetUsage"
+	MininaruService_CompactSession_FullMethodName    = "/mininaru.v1.MininaruService/CompactSession"
+	MininaruService_HandoffSession_FullMethodName    = "/mininaru.v1.MininaruService/HandoffSession"
+	MininaruService_Chat_FullMethodName              = "/mininaru.v1.MininaruService/Chat"
+	MininaruService_RegenerateMessage_FullMethodName = "/mininaru.v1.MininaruService/RegenerateMessage"
+	MininaruService_EditMessage_FullMethodName       = "/mininaru.v1.MininaruService/EditMessage"
+	MininaruService_RateMessage_FullMethodName       = "/mininaru.v1.MininaruService/RateMessage"
+	MininaruService_UndoTurn_FullMethodName          = "/mininaru.v1.MininaruService/UndoTurn"
 )
 
 type MininaruServiceClient interface {
@@ -192,10 +198,16 @@ type MininaruServiceClient interface {
 	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*Session, error)
 	GetSession(ctx context.Context, in *GetSessionRequest, opts ...grpc.CallOption) (*SessionDetail, error)
 	RenameSession(ctx context.Context, in *RenameSessionRequest, opts ...grpc.CallOption) (*Session, error)
+	UpdateSession(ctx context.Context, in *UpdateSessionRequest, opts ...grpc.CallOption) (*Session, error)
 	DeleteSession(ctx context.Context, in *DeleteSessionRequest, opts ...grpc.CallOption) (*Empty, error)
 	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*Usage, error)
 	CompactSession(ctx context.Context, in *CompactSessionRequest, opts ...grpc.CallOption) (*CompactSessionResponse, error)
+	HandoffSession(ctx context.Context, in *HandoffSessionRequest, opts ...grpc.CallOption) (*HandoffSessionResponse, error)
 	Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent], error)
+	RegenerateMessage(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent], error)
+	EditMessage(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent], error)
+	RateMessage(ctx context.Context, in *RateMessageRequest, opts ...grpc.CallOption) (*Empty, error)
+	UndoTurn(ctx context.Context, in *UndoTurnRequest, opts ...grpc.CallOption) (*UndoTurnResponse, error)
 }
 
 type mininaruServiceClient struct {
@@ -325,6 +337,23 @@ func (c *mininaruServiceClient) RenameSession(ctx context.Context, in *RenameSes
 	return out, nil
 }
 
+func (c *mininaruServiceClient) UpdateSession(ctx context.Context, in *UpdateSessionRequest, opts ...grpc.CallOption) (*Session, error) {
+	var (
+		cOpts []grpc.
+			CallOption
+		out *Session
+		err error
+	)
+
+	cOpts = append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out = new(Session)
+	err = c.cc.Invoke(ctx, MininaruService_UpdateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mininaruServiceClient) DeleteSession(ctx context.Context, in *DeleteSessionRequest, opts ...grpc.CallOption) (*Empty, error) {
 	var (
 		cOpts []grpc.
@@ -376,6 +405,23 @@ func (c *mininaruServiceClient) CompactSession(ctx context.Context, in *CompactS
 	return out, nil
 }
 
+func (c *mininaruServiceClient) HandoffSession(ctx context.Context, in *HandoffSessionRequest, opts ...grpc.CallOption) (*HandoffSessionResponse, error) {
+	var (
+		cOpts []grpc.
+			CallOption
+		out *HandoffSessionResponse
+		err error
+	)
+
+	cOpts = append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out = new(HandoffSessionResponse)
+	err = c.cc.Invoke(ctx, MininaruService_HandoffSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mininaruServiceClient) Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent], error) {
 	var (
 		cOpts []grpc.
@@ -400,6 +446,88 @@ func (c *mininaruServiceClient) Chat(ctx context.Context, opts ...grpc.CallOptio
 
 type MininaruService_ChatClient = grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent]
 
+func (c *mininaruServiceClient) RegenerateMessage(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent], error) {
+	var (
+		cOpts []grpc.
+			CallOption
+		stream grpc.
+			ClientStream
+		x *grpc.
+			GenericClientStream[ChatClientEvent,
+
+			ChatServerEvent]
+		err error
+	)
+
+	cOpts = append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err = c.cc.NewStream(ctx, &MininaruService_ServiceDesc.Streams[1], MininaruService_RegenerateMessage_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x = &grpc.GenericClientStream[ChatClientEvent, ChatServerEvent]{ClientStream: stream}
+	return x, nil
+}
+
+type MininaruService_RegenerateMessageClient = grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent]
+
+func (c *mininaruServiceClient) EditMessage(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent], error) {
+	var (
+		cOpts []grpc.
+			CallOption
+		stream grpc.
+			ClientStream
+		x *grpc.
+			GenericClientStream[ChatClientEvent,
+
+			ChatServerEvent]
+		err error
+	)
+
+	cOpts = append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err = c.cc.NewStream(ctx, &MininaruService_ServiceDesc.Streams[2], MininaruService_EditMessage_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x = &grpc.GenericClientStream[ChatClientEvent, ChatServerEvent]{ClientStream: stream}
+	return x, nil
+}
+
+type MininaruService_EditMessageClient = grpc.BidiStreamingClient[ChatClientEvent, ChatServerEvent]
+
+func (c *mininaruServiceClient) RateMessage(ctx context.Context, in *RateMessageRequest, opts ...grpc.CallOption) (*Empty, error) {
+	var (
+		cOpts []grpc.
+			CallOption
+		out *Empty
+		err error
+	)
+
+	cOpts = append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out = new(Empty)
+	err = c.cc.Invoke(ctx, MininaruService_RateMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mininaruServiceClient) UndoTurn(ctx context.Context, in *UndoTurnRequest, opts ...grpc.CallOption) (*UndoTurnResponse, error) {
+	var (
+		cOpts []grpc.
+			CallOption
+		out *UndoTurnResponse
+		err error
+	)
+
+	cOpts = append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out = new(UndoTurnResponse)
+	err = c.cc.Invoke(ctx, MininaruService_UndoTurn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 type MininaruServiceServer interface {
 	ListAgents(context.Context, *ListAgentsRequest) (*ListAgentsResponse, error)
 	ListSkills(context.Context, *ListSkillsRequest) (*ListSkillsResponse, error)
@@ -408,10 +536,16 @@ type MininaruServiceServer interface {
 	CreateSession(context.Context, *CreateSessionRequest) (*Session, error)
 	GetSession(context.Context, *GetSessionRequest) (*SessionDetail, error)
 	RenameSession(context.Context, *RenameSessionRequest) (*Session, error)
+	UpdateSession(context.Context, *UpdateSessionRequest) (*Session, error)
 	DeleteSession(context.Context, *DeleteSessionRequest) (*Empty, error)
 	GetUsage(context.Context, *GetUsageRequest) (*Usage, error)
 	CompactSession(context.Context, *CompactSessionRequest) (*CompactSessionResponse, error)
+	HandoffSession(context.Context, *HandoffSessionRequest) (*HandoffSessionResponse, error)
 	Chat(grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]) error
+	RegenerateMessage(grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]) error
+	EditMessage(grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]) error
+	RateMessage(context.Context, *RateMessageRequest) (*Empty, error)
+	UndoTurn(context.Context, *UndoTurnRequest) (*UndoTurnResponse, error)
 	mustEmbedUnimplementedMininaruServiceServer()
 }
 
@@ -438,6 +572,9 @@ func (UnimplementedMininaruServiceServer) GetSession(context.Context, *GetSessio
 func (UnimplementedMininaruServiceServer) RenameSession(context.Context, *RenameSessionRequest) (*Session, error) {
 	return nil, status.Error(codes.Unimplemented, "method RenameSession not implemented")
 }
+func (UnimplementedMininaruServiceServer) UpdateSession(context.Context, *UpdateSessionRequest) (*Session, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateSession not implemented")
+}
 func (UnimplementedMininaruServiceServer) DeleteSession(context.Context, *DeleteSessionRequest) (*Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method DeleteSession not implemented")
 }
@@ -447,9 +584,24 @@ func (UnimplementedMininaruServiceServer) GetUsage(context.Context, *GetUsageReq
 func (UnimplementedMininaruServiceServer) CompactSession(context.Context, *CompactSessionRequest) (*CompactSessionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CompactSession not implemented")
 }
+func (UnimplementedMininaruServiceServer) HandoffSession(context.Context, *HandoffSessionRequest) (*HandoffSessionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HandoffSession not implemented")
+}
 func (UnimplementedMininaruServiceServer) Chat(grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]) error {
 	return status.Error(codes.Unimplemented, "method Chat not implemented")
 }
+func (UnimplementedMininaruServiceServer) RegenerateMessage(grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]) error {
+	return status.Error(codes.Unimplemented, "method RegenerateMessage not implemented")
+}
+func (UnimplementedMininaruServiceServer) EditMessage(grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]) error {
+	return status.Error(codes.Unimplemented, "method EditMessage not implemented")
+}
+func (UnimplementedMininaruServiceServer) RateMessage(context.Context, *RateMessageRequest) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method RateMessage not implemented")
+}
+func (UnimplementedMininaruServiceServer) UndoTurn(context.Context, *UndoTurnRequest) (*UndoTurnResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UndoTurn not implemented")
+}
 func (UnimplementedMininaruServiceServer) mustEmbedUnimplementedMininaruServiceServer() {}
 func (UnimplementedMininaruServiceServer) testEmbeddedByValue()                         {}
 
@@ -651,6 +803,32 @@ func _MininaruService_RenameSession_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MininaruService_UpdateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	var (
+		in   *UpdateSessionRequest
+		info *grpc.
+			UnaryServerInfo
+		handler func(ctx context.Context, req interface{}) (interface{}, error)
+		err     error
+	)
+
+	in = new(UpdateSessionRequest)
+	if err = dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MininaruServiceServer).UpdateSession(ctx, in)
+	}
+	info = &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MininaruService_UpdateSession_FullMethodName,
+	}
+	handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MininaruServiceServer).UpdateSession(ctx, req.(*UpdateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MininaruService_DeleteSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	var (
 		in   *DeleteSessionRequest
@@ -729,12 +907,102 @@ func _MininaruService_CompactSession_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MininaruService_HandoffSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	var (
+		in   *HandoffSessionRequest
+		info *grpc.
+			UnaryServerInfo
+		handler func(ctx context.Context, req interface{}) (interface{}, error)
+		err     error
+	)
+
+	in = new(HandoffSessionRequest)
+	if err = dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MininaruServiceServer).HandoffSession(ctx, in)
+	}
+	info = &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MininaruService_HandoffSession_FullMethodName,
+	}
+	handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MininaruServiceServer).HandoffSession(ctx, req.(*HandoffSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MininaruService_Chat_Handler(srv interface{}, stream grpc.ServerStream) error {
 	return srv.(MininaruServiceServer).Chat(&grpc.GenericServerStream[ChatClientEvent, ChatServerEvent]{ServerStream: stream})
 }
 
 type MininaruService_ChatServer = grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]
 
+func _MininaruService_RegenerateMessage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MininaruServiceServer).RegenerateMessage(&grpc.GenericServerStream[ChatClientEvent, ChatServerEvent]{ServerStream: stream})
+}
+
+type MininaruService_RegenerateMessageServer = grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]
+
+func _MininaruService_EditMessage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(MininaruServiceServer).EditMessage(&grpc.GenericServerStream[ChatClientEvent, ChatServerEvent]{ServerStream: stream})
+}
+
+type MininaruService_EditMessageServer = grpc.BidiStreamingServer[ChatClientEvent, ChatServerEvent]
+
+func _MininaruService_RateMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	var (
+		in   *RateMessageRequest
+		info *grpc.
+			UnaryServerInfo
+		handler func(ctx context.Context, req interface{}) (interface{}, error)
+		err     error
+	)
+
+	in = new(RateMessageRequest)
+	if err = dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MininaruServiceServer).RateMessage(ctx, in)
+	}
+	info = &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MininaruService_RateMessage_FullMethodName,
+	}
+	handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MininaruServiceServer).RateMessage(ctx, req.(*RateMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MininaruService_UndoTurn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	var (
+		in   *UndoTurnRequest
+		info *grpc.
+			UnaryServerInfo
+		handler func(ctx context.Context, req interface{}) (interface{}, error)
+		err     error
+	)
+
+	in = new(UndoTurnRequest)
+	if err = dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MininaruServiceServer).UndoTurn(ctx, in)
+	}
+	info = &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MininaruService_UndoTurn_FullMethodName,
+	}
+	handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MininaruServiceServer).UndoTurn(ctx, req.(*UndoTurnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var MininaruService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "mininaru.v1.MininaruService",
 	HandlerType: (*MininaruServiceServer)(nil),
@@ -767,6 +1035,10 @@ var MininaruService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RenameSession",
 			Handler:    _MininaruService_RenameSession_Handler,
 		},
+		{
+			MethodName: "UpdateSession",
+			Handler:    _MininaruService_UpdateSession_Handler,
+		},
 		{
 			MethodName: "DeleteSession",
 			Handler:    _MininaruService_DeleteSession_Handler,
@@ -779,6 +1051,18 @@ var MininaruService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CompactSession",
 			Handler:    _MininaruService_CompactSession_Handler,
 		},
+		{
+			MethodName: "HandoffSession",
+			Handler:    _MininaruService_HandoffSession_Handler,
+		},
+		{
+			MethodName: "RateMessage",
+			Handler:    _MininaruService_RateMessage_Handler,
+		},
+		{
+			MethodName: "UndoTurn",
+			Handler:    _MininaruService_UndoTurn_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -787,6 +1071,18 @@ var MininaruService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "RegenerateMessage",
+			Handler:       _MininaruService_RegenerateMessage_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "EditMessage",
+			Handler:       _MininaruService_EditMessage_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "mininaru/v1/mininaru.proto",
 }
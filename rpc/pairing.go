@@ -134,6 +134,26 @@ func pairingInsert(name, fingerprint string, publicKey []byte, now time.Time) (*
 	return nil, fmt.Errorf("could not allocate pairing code")
 }
 
+func PairingPrune() (int64, error) {
+	var now time.Time
+	var result sql.Result
+
+	var err error
+
+	now = time.Now()
+	_, err = util.DB.Exec("UPDATE rpc_pairings SET status = ? WHERE status = ? AND expires_at <= ?;", pairingExpired, pairingWaiting, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	result, err = util.DB.Exec("DELETE FROM rpc_pairings WHERE status != ? AND expires_at <= ?;", pairingWaiting, now.Add(-pairingRetention).Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
 func PairingBegin(name string, encodedPublicKey []byte) (*PairingRequest, error) {
 	var fingerprint string
 	var now time.Time
@@ -155,11 +175,7 @@ func PairingBegin(name string, encodedPublicKey []byte) (*PairingRequest, error)
 	}
 
 	now = time.Now()
-	_, err = util.DB.Exec("UPDATE rpc_pairings SET status = ? WHERE status = ? AND expires_at <= ?;", pairingExpired, pairingWaiting, now.Unix())
-	if err != nil {
-		return nil, err
-	}
-	_, err = util.DB.Exec("DELETE FROM rpc_pairings WHERE status != ? AND expires_at <= ?;", pairingWaiting, now.Add(-pairingRetention).Unix())
+	_, err = PairingPrune()
 	if err != nil {
 		return nil, err
 	}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/devproje/mininaru/config"
 	"github.com/devproje/mininaru/core"
+	"github.com/devproje/mininaru/modules"
 	mininaruv1 "github.com/devproje/mininaru/rpc/gen/mininaru/v1"
 	"github.com/devproje/mininaru/util"
 	"google.golang.org/grpc"
@@ -185,6 +186,35 @@ func TestServerIdentityStableAndPrivate(t *testing.T) {
 	}
 }
 
+func TestPKIExpiryReadsTheServerCertificates(t *testing.T) {
+	var expiry []CertificateExpiry
+	var current CertificateExpiry
+
+	var err error
+
+	rpcTestSetup(t)
+
+	expiry, err = PKIExpiry()
+	if err != nil || len(expiry) != 0 {
+		t.Fatalf("expiry = %v, %v, want nothing before the pki exists", expiry, err)
+	}
+
+	_, err = LoadServerIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expiry, err = PKIExpiry()
+	if err != nil || len(expiry) != 2 {
+		t.Fatalf("expiry = %v, %v, want the ca and server certificates", expiry, err)
+	}
+	for _, current = range expiry {
+		if current.NotAfter.Before(time.Now().Add(300 * 24 * time.Hour)) {
+			t.Fatalf("%s expires %s, want about a year out", current.File, current.NotAfter)
+		}
+	}
+}
+
 func TestPairingApprovesAuthenticatesAndRevokes(t *testing.T) {
 	var privateKey ed25519.PrivateKey
 	var publicKey []byte
@@ -530,6 +560,197 @@ func TestChatStreamsAndPersistsServerSession(t *testing.T) {
 	}
 }
 
+func TestChatRejectsUnsupportedAttachments(t *testing.T) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var stream testChatStream
+
+	var err error
+
+	rpcTestSetup(t)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	stream = testChatStream{ctx: ctx, incoming: make(chan *mininaruv1.ChatClientEvent, 1)}
+	stream.incoming <- &mininaruv1.ChatClientEvent{Event: &mininaruv1.ChatClientEvent_Start{Start: &mininaruv1.ChatStart{
+		SessionId: "s", Content: "run this", Attachments: []*mininaruv1.Attachment{
+			{Name: "app.exe", MediaType: "application/octet-stream", Content: []byte{0x4d, 0x5a, 0x90, 0x00}}}}}}
+
+	err = (&mininaruService{slots: make(chan struct{}, 1)}).Chat(&stream)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("chat with a binary attachment = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+func TestEditMessageReplacesTheLastTurn(t *testing.T) {
+	var upstream *httptest.Server
+	var registry *core.Registry
+	var instance *core.Instance
+	var session *core.Session
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var stream testChatStream
+	var messages []*core.Message
+
+	var err error
+
+	rpcTestSetup(t)
+	config.Client = config.ClientConfig{Thinking: config.Thinking{Level: config.ThinkingOff}, Tools: config.Tools{Enabled: false}}
+
+	upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: {\"id\":\"chat\",\"object\":\"chat.completion.chunk\",\"created\":1,\"model\":\"model\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":\"better\"},\"finish_reason\":\"stop\"}]}\n\n")
+		io.WriteString(w, "data: [DONE]\n\n")
+	}))
+	defer upstream.Close()
+
+	registry = chatRegistry(t, upstream.URL)
+	instance, err = registry.Get("naru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err = instance.Session("remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = core.MessageSave(session.Id, "user", "hi", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = core.MessageSave(session.Id, "assistant", "meh", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	stream = testChatStream{ctx: ctx, incoming: make(chan *mininaruv1.ChatClientEvent, 1)}
+	stream.incoming <- &mininaruv1.ChatClientEvent{Event: &mininaruv1.ChatClientEvent_Start{Start: &mininaruv1.ChatStart{
+		SessionId: session.Id, Content: "hi again", Thinking: config.ThinkingOff}}}
+	err = (&mininaruService{registry: registry, slots: make(chan struct{}, 1)}).RegenerateMessage(&stream)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("regenerate with new content = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+
+	stream = testChatStream{ctx: ctx, incoming: make(chan *mininaruv1.ChatClientEvent, 1)}
+	stream.incoming <- &mininaruv1.ChatClientEvent{Event: &mininaruv1.ChatClientEvent_Start{Start: &mininaruv1.ChatStart{
+		SessionId: session.Id, Content: "hi again", Thinking: config.ThinkingOff}}}
+	err = (&mininaruService{registry: registry, slots: make(chan struct{}, 1)}).EditMessage(&stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err = core.MessageList(session.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 2 || messages[0].Content != "hi again" || messages[1].Content != "better" {
+		t.Fatalf("messages after edit = %#v", messages)
+	}
+}
+
+func TestRateMessageStoresAndClearsTheRating(t *testing.T) {
+	var session *core.Session
+	var answer *core.Message
+	var service *mininaruService
+	var rating *core.Rating
+
+	var err error
+
+	rpcTestSetup(t)
+
+	session, err = core.SessionCreate(&core.NaruAgent{Id: "naru"}, "rated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err = core.MessageSave(session.Id, "assistant", "answer", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service = &mininaruService{}
+	_, err = service.RateMessage(context.Background(), &mininaruv1.RateMessageRequest{
+		MessageId: answer.Id, Rating: mininaruv1.MessageRating_MESSAGE_RATING_BAD, Note: "off topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating == nil || rating.Rating != core.RatingBad || rating.Note != "off topic" {
+		t.Fatalf("stored rating = %+v, %v", rating, err)
+	}
+
+	_, err = service.RateMessage(context.Background(), &mininaruv1.RateMessageRequest{MessageId: answer.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rating, err = core.MessageRating(answer.Id)
+	if err != nil || rating != nil {
+		t.Fatalf("cleared rating = %+v, %v", rating, err)
+	}
+
+	_, err = service.RateMessage(context.Background(), &mininaruv1.RateMessageRequest{
+		MessageId: "missing", Rating: mininaruv1.MessageRating_MESSAGE_RATING_GOOD})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("rating a missing message = %v, want %v", status.Code(err), codes.InvalidArgument)
+	}
+}
+
+func TestUndoTurnRestoresFilesAndNamesTheTurn(t *testing.T) {
+	var session *core.Session
+	var question *core.Message
+	var answer *core.Message
+	var root string
+	var service *mininaruService
+	var response *mininaruv1.UndoTurnResponse
+
+	var err error
+
+	rpcTestSetup(t)
+
+	session, err = core.SessionCreate(&core.NaruAgent{Id: "naru"}, "undone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	question, err = core.MessageSave(session.Id, "user", "make a file", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err = core.MessageSave(session.Id, "assistant", "made it", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root = t.TempDir()
+	_, err = modules.FileWrite(root).Execute(modules.ToolTurnContext(modules.ToolSessionContext(context.Background(), session.Id),
+		question.Id), `{"path":"made.txt","content":"made\n"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service = &mininaruService{}
+	response, err = service.UndoTurn(context.Background(), &mininaruv1.UndoTurnRequest{SessionId: session.Id, MessageId: answer.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetTurnId() != question.Id || len(response.GetRestored()) != 1 {
+		t.Fatalf("undo response = %v", response)
+	}
+	_, err = os.Stat(filepath.Join(root, "made.txt"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("file after undo = %v", err)
+	}
+
+	response, err = service.UndoTurn(context.Background(), &mininaruv1.UndoTurnRequest{MessageId: answer.Id})
+	if err != nil || len(response.GetRestored()) != 0 {
+		t.Fatalf("second undo = %v, %v", response, err)
+	}
+
+	_, err = service.UndoTurn(context.Background(), &mininaruv1.UndoTurnRequest{SessionId: "other", MessageId: answer.Id})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("undo from another session = %v, want %v", status.Code(err), codes.NotFound)
+	}
+}
+
 func TestChatRunsAdvertisedToolsOnTheClient(t *testing.T) {
 	var calls atomic.Int32
 	var upstream *httptest.Server
@@ -567,6 +788,7 @@ func TestChatRunsAdvertisedToolsOnTheClient(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	instance.Agent.Sandbox = modules.SandboxOn
 	session, err = instance.Session("approval")
 	if err != nil {
 		t.Fatal(err)
@@ -585,14 +807,15 @@ func TestChatRunsAdvertisedToolsOnTheClient(t *testing.T) {
 	}
 	for _, event = range stream.outgoing {
 		if event.GetToolRequest() != nil && event.GetToolRequest().GetToolName() == "bash_exec" {
-			requested = true
+			requested = event.GetToolRequest().GetCallId() != "" && event.GetToolRequest().GetMessageId() != "" &&
+				event.GetToolRequest().GetSandbox() == modules.SandboxOn
 		}
 		if event.GetCompleted() != nil {
 			completed = event.GetCompleted()
 		}
 	}
 	if !requested {
-		t.Fatal("advertised tool produced no client execution request")
+		t.Fatal("advertised tool produced no client execution request carrying its call id and sandbox")
 	}
 	if completed == nil || completed.GetMessage().GetContent() != "denied safely" {
 		t.Fatalf("completed = %#v", completed)
@@ -677,3 +900,144 @@ func TestChatCancellationReachesTheModelTurn(t *testing.T) {
 		t.Fatalf("message status = %s, want %s", statusValue, core.MessageCancelled)
 	}
 }
+
+func TestChatDisconnectEndsTheSessionsBackgroundJobs(t *testing.T) {
+	var started chan struct{}
+	var release chan struct{}
+	var upstream *httptest.Server
+	var registry *core.Registry
+	var instance *core.Instance
+	var session *core.Session
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var stream testChatStream
+	var result chan error
+
+	var err error
+
+	rpcTestSetup(t)
+	config.Client = config.ClientConfig{Thinking: config.Thinking{Level: config.ThinkingOff}, Tools: config.Tools{Enabled: false}}
+	started = make(chan struct{})
+	release = make(chan struct{})
+	upstream = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer func() {
+		close(release)
+		upstream.Close()
+	}()
+
+	registry = chatRegistry(t, upstream.URL)
+	instance, err = registry.Get("naru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err = instance.Session("disconnect")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(modules.BashJobsClose)
+	_, err = modules.BashStart(t.TempDir()).Execute(modules.ToolSessionContext(context.Background(), session.Id), `{"command":"sleep 60"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = modules.BashStart(t.TempDir()).Execute(modules.ToolSessionContext(context.Background(), "another-session"), `{"command":"sleep 60"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	stream = testChatStream{ctx: ctx, incoming: make(chan *mininaruv1.ChatClientEvent, 1)}
+	stream.incoming <- &mininaruv1.ChatClientEvent{Event: &mininaruv1.ChatClientEvent_Start{Start: &mininaruv1.ChatStart{
+		SessionId: session.Id, Content: "wait", Thinking: config.ThinkingOff}}}
+	result = make(chan error, 1)
+	go func() {
+		result <- (&mininaruService{registry: registry, slots: make(chan struct{}, 1)}).Chat(&stream)
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("model request did not start")
+	}
+	cancel()
+
+	select {
+	case <-result:
+	case <-time.After(2 * time.Second):
+		t.Fatal("a disconnected chat did not stop")
+	}
+
+	if len(modules.BashJobs(session.Id)) != 0 {
+		t.Fatalf("jobs after the client went away = %+v", modules.BashJobs(session.Id))
+	}
+	if len(modules.BashJobs("another-session")) != 1 {
+		t.Fatal("a disconnect ended another session's job")
+	}
+}
+
+func TestHandoffSessionMovesOrCopiesTheConversation(t *testing.T) {
+	var registry *core.Registry
+	var instance *core.Instance
+	var session *core.Session
+	var service *mininaruService
+	var response *mininaruv1.HandoffSessionResponse
+	var found *core.Session
+
+	var err error
+
+	rpcTestSetup(t)
+
+	registry = chatRegistry(t, "http://127.0.0.1:1")
+	core.Agents = []*core.NaruAgent{core.AgentNew("worker", "", "", "worker-model", core.Providers[0])}
+	err = core.AgentSave()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = registry.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance, err = registry.Get("naru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err = instance.Session("remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service = &mininaruService{registry: registry, slots: make(chan struct{}, 1)}
+
+	response, err = service.HandoffSession(context.Background(),
+		&mininaruv1.HandoffSessionRequest{SessionId: session.Id, Agent: "worker", Copy: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetSession().GetId() == session.Id || response.GetAgent().GetName() != "worker" ||
+		response.GetSession().GetName() != "remote (worker)" {
+		t.Fatalf("copy response = %v", response)
+	}
+
+	response, err = service.HandoffSession(context.Background(),
+		&mininaruv1.HandoffSessionRequest{SessionId: session.Id, Agent: "worker"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err = core.SessionFind(session.Id)
+	if err != nil || found.AgentId != response.GetAgent().GetId() {
+		t.Fatalf("owner after handoff = %+v, %v", found, err)
+	}
+
+	_, err = service.HandoffSession(context.Background(),
+		&mininaruv1.HandoffSessionRequest{SessionId: session.Id, Agent: "worker"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("repeated handoff error = %v", err)
+	}
+}
@@ -22,6 +22,11 @@ import (
 	"github.com/devproje/mininaru/util"
 )
 
+type CertificateExpiry struct {
+	File     string
+	NotAfter time.Time
+}
+
 type ServerIdentity struct {
 	Certificate tls.Certificate
 	CAPool      *x509.CertPool
@@ -366,3 +371,40 @@ func IssueClientCertificate(publicKeyDER []byte, clientId string) ([]byte, strin
 
 	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate}), serial.String(), nil
 }
+
+func PKIExpiry() ([]CertificateExpiry, error) {
+	var paths []string
+	var path string
+	var buf []byte
+	var block *pem.Block
+	var certificate *x509.Certificate
+	var expiry []CertificateExpiry
+
+	var err error
+
+	paths, err = filepath.Glob(filepath.Join(util.Path(pkiDirectory), "*.crt"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path = range paths {
+		buf, err = os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		block, _ = pem.Decode(buf)
+		if block == nil {
+			return nil, fmt.Errorf("%s is not a pem certificate", path)
+		}
+
+		certificate, err = x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		expiry = append(expiry, CertificateExpiry{File: filepath.Join(pkiDirectory, filepath.Base(path)), NotAfter: certificate.NotAfter})
+	}
+
+	return expiry, nil
+}
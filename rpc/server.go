@@ -29,8 +29,8 @@ const (
 )
 
 const (
-	maxReceiveMessageBytes = 20 << 20
-	maxSendMessageBytes    = 20 << 20
+	maxReceiveMessageBytes = 24 << 20
+	maxSendMessageBytes    = 24 << 20
 	gracefulStopTimeout    = 5 * time.Second
 )
 
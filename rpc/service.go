@@ -27,12 +27,20 @@ type mininaruService struct {
 	slots    chan struct{}
 }
 
+type chatMode int
+
 const defaultSessionNameLayout = "2006-01-02 15:04"
 
 const maxConcurrentChats = 16
 
 const maxChatContentBytes = 1 << 20
 
+const (
+	chatSend chatMode = iota
+	chatRegenerate
+	chatEdit
+)
+
 func rpcAgent(agent *core.NaruAgent) *mininaruv1.Agent {
 	var provider *core.Provider
 	var providerName string
@@ -69,7 +77,31 @@ func rpcSession(session *core.Session) *mininaruv1.Session {
 		return nil
 	}
 
-	return &mininaruv1.Session{Id: session.Id, AgentId: session.AgentId, Name: session.Name}
+	return &mininaruv1.Session{Id: session.Id, AgentId: session.AgentId, Name: session.Name, TurnMode: session.TurnMode,
+		Tags: session.Tags, Pinned: session.Pinned, Archived: session.Archived}
+}
+
+func rpcSeatedSession(session *core.Session) (*mininaruv1.Session, error) {
+	var response *mininaruv1.Session
+	var seats []*core.NaruAgent
+	var seat *core.NaruAgent
+
+	var err error
+
+	response = rpcSession(session)
+	if session.TurnMode == "" {
+		return response, nil
+	}
+
+	seats, err = core.RoundtableSeats(session.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	for _, seat = range seats {
+		response.AgentIds = append(response.AgentIds, seat.Id)
+	}
+
+	return response, nil
 }
 
 func rpcMessage(message *core.Message) *mininaruv1.Message {
@@ -78,7 +110,8 @@ func rpcMessage(message *core.Message) *mininaruv1.Message {
 	}
 
 	return &mininaruv1.Message{Id: message.Id, SessionId: message.SessionId, Role: message.Role,
-		Content: message.Content, Reasoning: message.Reasoning, Status: message.Status, Error: message.Error}
+		Content: message.Content, Reasoning: message.Reasoning, Status: message.Status, Error: message.Error,
+		AgentId: message.AgentId}
 }
 
 func rpcToolCall(call *core.ToolCall) *mininaruv1.ToolCall {
@@ -93,6 +126,7 @@ func rpcToolCall(call *core.ToolCall) *mininaruv1.ToolCall {
 func rpcUsage(totals *core.UsageTotals) *mininaruv1.Usage {
 	var usage mininaruv1.Usage
 	var line core.UsageLine
+	var agent core.AgentUsage
 
 	if totals == nil {
 		return &usage
@@ -106,6 +140,10 @@ func rpcUsage(totals *core.UsageTotals) *mininaruv1.Usage {
 			CompletionTokens: line.CompletionTokens, TotalTokens: line.TotalTokens,
 			CachedTokens: line.CachedTokens, CacheWriteTokens: line.CacheWriteTokens})
 	}
+	for _, agent = range totals.Agents {
+		usage.Agents = append(usage.Agents, &mininaruv1.AgentUsage{AgentId: agent.AgentId, Agent: agent.Agent,
+			PromptTokens: agent.PromptTokens, CompletionTokens: agent.CompletionTokens, TotalTokens: agent.TotalTokens})
+	}
 
 	return &usage
 }
@@ -194,9 +232,9 @@ func (s *mininaruService) ListSessions(ctx context.Context, request *mininaruv1.
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	sessions, err = core.SessionList(instance.Agent.Id)
+	sessions, err = core.SessionList(instance.Agent.Id, core.SessionFilter{Tag: request.GetTag(), Archived: request.GetArchived()})
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	for _, session = range sessions {
@@ -206,13 +244,58 @@ func (s *mininaruService) ListSessions(ctx context.Context, request *mininaruv1.
 	return &response, nil
 }
 
+func registrySeats(registry *core.Registry, names []string) ([]*core.NaruAgent, error) {
+	var name string
+	var instance *core.Instance
+	var seats []*core.NaruAgent
+
+	var err error
+
+	for _, name = range names {
+		instance, err = registry.Get(name)
+		if err != nil {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		seats = append(seats, instance.Agent)
+	}
+
+	return seats, nil
+}
+
 func (s *mininaruService) CreateSession(ctx context.Context, request *mininaruv1.CreateSessionRequest) (*mininaruv1.Session, error) {
 	var instance *core.Instance
 	var name string
+	var seats []*core.NaruAgent
+	var mode string
 	var session *core.Session
 
 	var err error
 
+	name = request.GetName()
+	if name == "" {
+		name = time.Now().Format(defaultSessionNameLayout)
+	}
+
+	if len(request.GetAgents()) > 0 {
+		seats, err = registrySeats(s.registry, request.GetAgents())
+		if err != nil {
+			return nil, err
+		}
+
+		mode = request.GetTurnMode()
+		if mode == "" {
+			mode = core.TurnRoundRobin
+		}
+
+		session, err = core.RoundtableCreate(seats, mode, name)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		return rpcSeatedSession(session)
+	}
+
 	if request.GetAgent() == "" {
 		instance, err = s.registry.Default()
 	} else {
@@ -222,11 +305,6 @@ func (s *mininaruService) CreateSession(ctx context.Context, request *mininaruv1
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
-	name = request.GetName()
-	if name == "" {
-		name = time.Now().Format(defaultSessionNameLayout)
-	}
-
 	session, err = instance.Session(name)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
@@ -259,7 +337,10 @@ func (s *mininaruService) GetSession(ctx context.Context, request *mininaruv1.Ge
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	response.Session = rpcSession(session)
+	response.Session, err = rpcSeatedSession(session)
+	if err != nil {
+		return nil, err
+	}
 	response.Agent = rpcAgent(instance.Agent)
 	for _, message = range messages {
 		response.Messages = append(response.Messages, rpcMessage(message))
@@ -272,7 +353,7 @@ func (s *mininaruService) GetSession(ctx context.Context, request *mininaruv1.Ge
 		}
 	}
 
-	tokens, window, known, err = core.SessionContextTokens(session.Id)
+	tokens, window, known, err = core.SessionContext(instance.Agent, session.Id)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
@@ -306,6 +387,92 @@ func (s *mininaruService) RenameSession(ctx context.Context, request *mininaruv1
 	return rpcSession(session), nil
 }
 
+func (s *mininaruService) UpdateSession(ctx context.Context, request *mininaruv1.UpdateSessionRequest) (*mininaruv1.Session, error) {
+	var session *core.Session
+
+	var err error
+
+	session, _, err = sessionInstance(s.registry, request.GetSessionId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = core.SessionTag(session.Id, request.GetAddTags())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = core.SessionUntag(session.Id, request.GetRemoveTags())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if request.Pinned != nil {
+		err = core.SessionPin(session.Id, request.GetPinned())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	if request.Archived != nil {
+		err = core.SessionArchive(session.Id, request.GetArchived())
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	session, err = core.SessionFind(session.Id)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return rpcSession(session), nil
+}
+
+func (s *mininaruService) RateMessage(ctx context.Context, request *mininaruv1.RateMessageRequest) (*mininaruv1.Empty, error) {
+	var rating string
+
+	var err error
+
+	switch request.GetRating() {
+	case mininaruv1.MessageRating_MESSAGE_RATING_GOOD:
+		rating = core.RatingGood
+	case mininaruv1.MessageRating_MESSAGE_RATING_BAD:
+		rating = core.RatingBad
+	}
+
+	err = core.MessageRate(request.GetMessageId(), rating, request.GetNote())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &mininaruv1.Empty{}, nil
+}
+
+func (s *mininaruService) UndoTurn(ctx context.Context, request *mininaruv1.UndoTurnRequest) (*mininaruv1.UndoTurnResponse, error) {
+	var owner string
+	var turn string
+	var restored []string
+
+	var err error
+
+	if request.GetMessageId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "message id is required")
+	}
+
+	owner, turn, err = core.TurnMessage(request.GetSessionId(), request.GetMessageId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	restored, err = modules.CheckpointRestore(owner, turn)
+	if err != nil && !errors.Is(err, modules.ErrNoCheckpoint) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &mininaruv1.UndoTurnResponse{Restored: restored, TurnId: turn}, nil
+}
+
 func (s *mininaruService) DeleteSession(ctx context.Context, request *mininaruv1.DeleteSessionRequest) (*mininaruv1.Empty, error) {
 	var session *core.Session
 
@@ -363,6 +530,39 @@ func (s *mininaruService) CompactSession(ctx context.Context, request *mininaruv
 	return &mininaruv1.CompactSessionResponse{Compacted: compacted}, nil
 }
 
+func (s *mininaruService) HandoffSession(ctx context.Context, request *mininaruv1.HandoffSessionRequest) (*mininaruv1.HandoffSessionResponse, error) {
+	var session *core.Session
+	var target *core.Instance
+	var moved *core.Session
+
+	var err error
+
+	if request.GetAgent() == "" {
+		return nil, status.Error(codes.InvalidArgument, "agent is required")
+	}
+
+	session, err = core.SessionFind(request.GetSessionId())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	target, err = s.registry.Get(request.GetAgent())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if request.GetCopy() {
+		moved, err = core.SessionHandoffCopy(session, target.Agent, request.GetName())
+	} else {
+		moved, err = core.SessionHandoff(session, target.Agent)
+	}
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &mininaruv1.HandoffSessionResponse{Session: rpcSession(moved), Agent: rpcAgent(target.Agent)}, nil
+}
+
 func chatContentEvent(text string) *mininaruv1.ChatServerEvent {
 	return &mininaruv1.ChatServerEvent{Event: &mininaruv1.ChatServerEvent_Content{Content: &mininaruv1.TextDelta{Text: text}}}
 }
@@ -371,10 +571,14 @@ func chatReasoningEvent(text string) *mininaruv1.ChatServerEvent {
 	return &mininaruv1.ChatServerEvent{Event: &mininaruv1.ChatServerEvent_Reasoning{Reasoning: &mininaruv1.TextDelta{Text: text}}}
 }
 
+func chatSpeakerEvent(agent *core.NaruAgent) *mininaruv1.ChatServerEvent {
+	return &mininaruv1.ChatServerEvent{Event: &mininaruv1.ChatServerEvent_Speaker{Speaker: &mininaruv1.Speaker{AgentId: agent.Id, Name: agent.Name}}}
+}
+
 func chatToolEvent(event core.ToolEvent) *mininaruv1.ChatServerEvent {
 	return &mininaruv1.ChatServerEvent{Event: &mininaruv1.ChatServerEvent_Tool{Tool: &mininaruv1.ToolEvent{
 		Phase: event.Phase, CallId: event.CallId, Name: event.Name, Arguments: event.Arguments,
-		Result: event.Result, Status: event.Status, Error: event.Error}}}
+		Result: event.Result, Status: event.Status, Error: event.Error, Output: event.Output}}}
 }
 
 func receiveChat(stream mininaruv1.MininaruService_ChatServer, incoming chan<- *mininaruv1.ChatClientEvent, cancel context.CancelFunc) {
@@ -474,7 +678,8 @@ func remoteToolExecute(ctx context.Context, stream mininaruv1.MininaruService_Ch
 		requestId = uuid.NewString()
 		sendMu.Lock()
 		err = stream.Send(&mininaruv1.ChatServerEvent{Event: &mininaruv1.ChatServerEvent_ToolRequest{ToolRequest: &mininaruv1.ToolRequest{
-			RequestId: requestId, ToolName: name, Arguments: arguments}}})
+			RequestId: requestId, ToolName: name, Arguments: arguments, CallId: modules.ToolCallFrom(callCtx),
+			MessageId: modules.ToolTurnFrom(callCtx), Sandbox: modules.ToolSandboxFrom(callCtx)}}})
 		sendMu.Unlock()
 		if err != nil {
 			return "", err
@@ -538,7 +743,72 @@ func sendChatFailure(stream mininaruv1.MininaruService_ChatServer, err error) er
 		Code: code, Message: err.Error()}}})
 }
 
+func chatAttachments(incoming []*mininaruv1.Attachment) ([]*core.Attachment, error) {
+	var attachments []*core.Attachment
+	var cur *mininaruv1.Attachment
+	var attachment *core.Attachment
+
+	var err error
+
+	for _, cur = range incoming {
+		attachment, err = core.AttachmentNew(cur.GetName(), cur.GetMediaType(), cur.GetContent())
+		if err != nil {
+			return nil, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	err = core.AttachmentsCheck(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func chatStartCheck(start *mininaruv1.ChatStart, mode chatMode) error {
+	if start == nil {
+		return status.Error(codes.InvalidArgument, "first event must contain a chat start")
+	}
+	if len(start.GetContent()) > maxChatContentBytes {
+		return status.Error(codes.ResourceExhausted, "chat content exceeds 1 MiB")
+	}
+
+	switch mode {
+	case chatRegenerate:
+		if start.GetContent() != "" || len(start.GetAttachments()) > 0 {
+			return status.Error(codes.InvalidArgument, "regenerate resends the last message as is")
+		}
+	case chatEdit:
+		if start.GetContent() == "" {
+			return status.Error(codes.InvalidArgument, "edit requires the new message content")
+		}
+		if len(start.GetAttachments()) > 0 {
+			return status.Error(codes.InvalidArgument, "edit keeps the original attachments")
+		}
+	default:
+		if start.GetContent() == "" && len(start.GetAttachments()) == 0 {
+			return status.Error(codes.InvalidArgument, "first event must contain a non-empty chat start")
+		}
+	}
+
+	return nil
+}
+
 func (s *mininaruService) Chat(stream mininaruv1.MininaruService_ChatServer) error {
+	return s.serveChat(stream, chatSend)
+}
+
+func (s *mininaruService) RegenerateMessage(stream mininaruv1.MininaruService_RegenerateMessageServer) error {
+	return s.serveChat(stream, chatRegenerate)
+}
+
+func (s *mininaruService) EditMessage(stream mininaruv1.MininaruService_EditMessageServer) error {
+	return s.serveChat(stream, chatEdit)
+}
+
+func (s *mininaruService) serveChat(stream mininaruv1.MininaruService_ChatServer, mode chatMode) error {
 	var first *mininaruv1.ChatClientEvent
 	var start *mininaruv1.ChatStart
 	var session *core.Session
@@ -547,8 +817,12 @@ func (s *mininaruService) Chat(stream mininaruv1.MininaruService_ChatServer) err
 	var cancel context.CancelFunc
 	var incoming chan *mininaruv1.ChatClientEvent
 	var defs []modules.Def
+	var attachments []*core.Attachment
+	var forward func(*mininaruv1.ChatServerEvent)
+	var messages []*core.Message
 	var message *core.Message
 	var totals *core.UsageTotals
+	var completed *mininaruv1.ChatCompleted
 	var sendMu sync.Mutex
 	var streamErr error
 
@@ -569,17 +843,28 @@ func (s *mininaruService) Chat(stream mininaruv1.MininaruService_ChatServer) err
 		return err
 	}
 	start = first.GetStart()
-	if start == nil || start.GetContent() == "" {
-		return status.Error(codes.InvalidArgument, "first event must contain a non-empty chat start")
+	err = chatStartCheck(start, mode)
+	if err != nil {
+		return err
 	}
-	if len(start.GetContent()) > maxChatContentBytes {
-		return status.Error(codes.ResourceExhausted, "chat content exceeds 1 MiB")
+	attachments, err = chatAttachments(start.GetAttachments())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
 	}
 
 	session, instance, err = sessionInstance(s.registry, start.GetSessionId())
 	if err != nil {
 		return err
 	}
+	if mode != chatSend && session.TurnMode != "" {
+		return status.Error(codes.FailedPrecondition, "a roundtable turn cannot be regenerated or edited")
+	}
+	defer func() {
+		if stream.Context().Err() != nil {
+			modules.BashJobsCloseOwner(session.Id)
+			modules.BashShellsCloseOwner(session.Id)
+		}
+	}()
 
 	chatCtx, cancel = context.WithCancel(stream.Context())
 	defer cancel()
@@ -596,35 +881,43 @@ func (s *mininaruService) Chat(stream mininaruv1.MininaruService_ChatServer) err
 		return err
 	}
 
-	message, err = instance.ChatWithTools(chatCtx, session, start.GetContent(), defs, start.GetThinking(),
-		func(text string) {
-			sendMu.Lock()
-			if streamErr == nil {
-				streamErr = stream.Send(chatContentEvent(text))
-			}
-			sendMu.Unlock()
-			if streamErr != nil {
-				cancel()
-			}
-		}, func(text string) {
-			sendMu.Lock()
-			if streamErr == nil {
-				streamErr = stream.Send(chatReasoningEvent(text))
-			}
-			sendMu.Unlock()
-			if streamErr != nil {
-				cancel()
-			}
-		}, func(event core.ToolEvent) {
-			sendMu.Lock()
-			if streamErr == nil {
-				streamErr = stream.Send(chatToolEvent(event))
-			}
-			sendMu.Unlock()
-			if streamErr != nil {
-				cancel()
-			}
-		}, chatApprover(chatCtx, stream, incoming))
+	forward = func(event *mininaruv1.ChatServerEvent) {
+		sendMu.Lock()
+		if streamErr == nil {
+			streamErr = stream.Send(event)
+		}
+		sendMu.Unlock()
+		if streamErr != nil {
+			cancel()
+		}
+	}
+
+	switch {
+	case mode != chatSend:
+		message, err = instance.ChatRetry(chatCtx, session, start.GetContent(), defs, start.GetThinking(),
+			func(text string) { forward(chatContentEvent(text)) },
+			func(text string) { forward(chatReasoningEvent(text)) },
+			func(event core.ToolEvent) { forward(chatToolEvent(event)) },
+			chatApprover(chatCtx, stream, incoming))
+		messages = []*core.Message{message}
+	case session.TurnMode != "":
+		messages, err = s.registry.Roundtable(chatCtx, session, start.GetContent(), attachments, defs, start.GetThinking(),
+			func(agent *core.NaruAgent) { forward(chatSpeakerEvent(agent)) },
+			func(text string) { forward(chatContentEvent(text)) },
+			func(text string) { forward(chatReasoningEvent(text)) },
+			func(event core.ToolEvent) { forward(chatToolEvent(event)) },
+			chatApprover(chatCtx, stream, incoming))
+		if len(messages) > 0 {
+			message = messages[len(messages)-1]
+		}
+	default:
+		message, err = instance.ChatWithTools(chatCtx, session, start.GetContent(), attachments, defs, start.GetThinking(),
+			func(text string) { forward(chatContentEvent(text)) },
+			func(text string) { forward(chatReasoningEvent(text)) },
+			func(event core.ToolEvent) { forward(chatToolEvent(event)) },
+			chatApprover(chatCtx, stream, incoming))
+		messages = []*core.Message{message}
+	}
 	if streamErr != nil {
 		return streamErr
 	}
@@ -637,6 +930,10 @@ func (s *mininaruService) Chat(stream mininaruv1.MininaruService_ChatServer) err
 		return sendChatFailure(stream, fmt.Errorf("read usage: %w", err))
 	}
 
-	return stream.Send(&mininaruv1.ChatServerEvent{Event: &mininaruv1.ChatServerEvent_Completed{Completed: &mininaruv1.ChatCompleted{
-		Message: rpcMessage(message), Usage: rpcUsage(totals)}}})
+	completed = &mininaruv1.ChatCompleted{Message: rpcMessage(message), Usage: rpcUsage(totals)}
+	for _, message = range messages {
+		completed.Messages = append(completed.Messages, rpcMessage(message))
+	}
+
+	return stream.Send(&mininaruv1.ChatServerEvent{Event: &mininaruv1.ChatServerEvent_Completed{Completed: completed}})
 }
@@ -5,9 +5,11 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/devproje/mininaru/core"
 	"github.com/openai/openai-go"
 )
 
@@ -17,10 +19,18 @@ type RequestMessage struct {
 }
 
 type ChatRequest struct {
-	Model           string           `json:"model"`
-	Messages        []RequestMessage `json:"messages"`
-	Stream          bool             `json:"stream"`
-	ReasoningEffort string           `json:"reasoning_effort"`
+	Model               string           `json:"model"`
+	Messages            []RequestMessage `json:"messages"`
+	Stream              bool             `json:"stream"`
+	ReasoningEffort     string           `json:"reasoning_effort"`
+	Temperature         *float64         `json:"temperature"`
+	TopP                *float64         `json:"top_p"`
+	MaxTokens           *int64           `json:"max_tokens"`
+	MaxCompletionTokens *int64           `json:"max_completion_tokens"`
+	Stop                json.RawMessage  `json:"stop"`
+	Seed                *int64           `json:"seed"`
+	PresencePenalty     *float64         `json:"presence_penalty"`
+	FrequencyPenalty    *float64         `json:"frequency_penalty"`
 }
 
 type ResponseMessage struct {
@@ -129,6 +139,53 @@ func contentText(raw json.RawMessage) string {
 	return builder.String()
 }
 
+func requestStop(raw json.RawMessage) ([]string, error) {
+	var single string
+	var many []string
+
+	var err error
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	err = json.Unmarshal(raw, &single)
+	if err == nil {
+		return []string{single}, nil
+	}
+
+	err = json.Unmarshal(raw, &many)
+	if err != nil {
+		return nil, fmt.Errorf("stop must be a string or an array of strings")
+	}
+	if len(many) == 0 {
+		return nil, nil
+	}
+
+	return many, nil
+}
+
+func requestSampling(req ChatRequest) (core.Sampling, error) {
+	var sampling core.Sampling
+
+	var err error
+
+	sampling = core.Sampling{
+		Temperature: req.Temperature, TopP: req.TopP, MaxTokens: req.MaxTokens, Seed: req.Seed,
+		PresencePenalty: req.PresencePenalty, FrequencyPenalty: req.FrequencyPenalty,
+	}
+	if sampling.MaxTokens == nil {
+		sampling.MaxTokens = req.MaxCompletionTokens
+	}
+
+	sampling.Stop, err = requestStop(req.Stop)
+	if err != nil {
+		return core.Sampling{}, err
+	}
+
+	return sampling, nil
+}
+
 func requestMessages(messages []RequestMessage) []openai.ChatCompletionMessageParamUnion {
 	var message RequestMessage
 	var text string
@@ -32,7 +32,7 @@ func completionId() string {
 }
 
 func completeOnce(ctx context.Context, w http.ResponseWriter, target *core.Instance,
-	messages []openai.ChatCompletionMessageParamUnion, thinking string) {
+	messages []openai.ChatCompletionMessageParamUnion, thinking string, sampling core.Sampling) {
 	var logger *slog.Logger
 	var started time.Time
 	var result *core.Completion
@@ -44,7 +44,7 @@ func completeOnce(ctx context.Context, w http.ResponseWriter, target *core.Insta
 	logger = requestLogger(ctx)
 	started = time.Now()
 
-	result, err = target.Complete(ctx, messages, thinking, nil, nil)
+	result, err = target.Complete(ctx, messages, thinking, sampling, nil, nil)
 	if err != nil {
 		logger.Error("completion failed",
 			"agent", target.Agent.Name, "model", target.Agent.Model,
@@ -109,7 +109,7 @@ func sendChunk(w http.ResponseWriter, flusher http.Flusher, payload ChatResponse
 }
 
 func completeStream(ctx context.Context, w http.ResponseWriter, target *core.Instance,
-	messages []openai.ChatCompletionMessageParamUnion, thinking string) {
+	messages []openai.ChatCompletionMessageParamUnion, thinking string, sampling core.Sampling) {
 	var logger *slog.Logger
 	var started time.Time
 	var flusher http.Flusher
@@ -141,7 +141,7 @@ func completeStream(ctx context.Context, w http.ResponseWriter, target *core.Ins
 
 	sendChunk(w, flusher, chunkResponse(id, target.Agent.Name, created, Delta{Role: roleAssistant}, nil))
 
-	result, err = target.Complete(ctx, messages, thinking,
+	result, err = target.Complete(ctx, messages, thinking, sampling,
 		func(text string) {
 			sendChunk(w, flusher, chunkResponse(id, target.Agent.Name, created, Delta{Content: text}, nil))
 		},
@@ -176,6 +176,8 @@ func handleCompletions(w http.ResponseWriter, r *http.Request, reg *core.Registr
 	var target *core.Instance
 	var messages []openai.ChatCompletionMessageParamUnion
 	var thinking string
+	var requested core.Sampling
+	var sampling core.Sampling
 
 	var err error
 
@@ -214,6 +216,18 @@ func handleCompletions(w http.ResponseWriter, r *http.Request, reg *core.Registr
 
 	messages = requestMessages(req.Messages)
 
+	requested, err = requestSampling(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid_sampling", err.Error())
+		return
+	}
+
+	sampling, err = target.Agent.SamplingOverride(requested)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "invalid_sampling", err.Error())
+		return
+	}
+
 	thinking = strings.ToLower(req.ReasoningEffort)
 	if thinking == "" {
 		thinking = config.Client.Thinking.Level
@@ -224,9 +238,9 @@ func handleCompletions(w http.ResponseWriter, r *http.Request, reg *core.Registr
 		"messages", len(req.Messages), "stream", req.Stream, "reasoning_effort", thinking)
 
 	if req.Stream {
-		completeStream(r.Context(), w, target, messages, thinking)
+		completeStream(r.Context(), w, target, messages, thinking, sampling)
 		return
 	}
 
-	completeOnce(r.Context(), w, target, messages, thinking)
+	completeOnce(r.Context(), w, target, messages, thinking, sampling)
 }
@@ -353,3 +353,67 @@ func TestCompletionsReportUsageSummedAcrossToolRounds(t *testing.T) {
 		t.Fatalf("usage = %+v, want every round the server ran on the caller's behalf", payload.Usage)
 	}
 }
+
+func TestCompletionsOverrideSamplingWithinAgentBounds(t *testing.T) {
+	var reg *core.Registry
+	var captured []string
+	var temperature float64
+	var recorder *httptest.ResponseRecorder
+
+	reg = setupAgent(t, upstreamOnce(t, &captured, `{"role":"assistant","content":"ok"}`).URL)
+	temperature = 0.3
+	core.Global.Sampling = core.Sampling{Temperature: &temperature, Stop: []string{"###"}}
+	core.Global.SamplingBounds = core.SamplingBounds{Temperature: &core.Bound{Min: 0, Max: 1}, MaxTokens: 512}
+	reg = reloadRegistry(t, reg)
+
+	request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}]}`)
+	if len(captured) != 1 || !containsAll(captured[0], `"temperature":0.3`, `"stop":["###"]`) {
+		t.Fatalf("agent sampling missing from upstream request: %v", captured)
+	}
+
+	recorder = request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}],"temperature":0.9,"max_tokens":256}`)
+	if recorder.Code != http.StatusOK || len(captured) != 2 {
+		t.Fatalf("override = %d %s", recorder.Code, recorder.Body)
+	}
+	if !containsAll(captured[1], `"temperature":0.9`, `"max_tokens":256`, `"stop":["###"]`) {
+		t.Fatalf("override was not bounded by the agent: %s", captured[1])
+	}
+
+	recorder = request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}],"seed":7}`)
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "invalid_sampling") || len(captured) != 2 {
+		t.Fatalf("seed the agent does not open = %d %s, want 400 without an upstream call", recorder.Code, recorder.Body)
+	}
+
+	recorder = request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}],"stop":"END"}`)
+	if recorder.Code != http.StatusBadRequest || len(captured) != 2 {
+		t.Fatalf("stop the agent does not open = %d, want 400", recorder.Code)
+	}
+
+	recorder = request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}],"presence_penalty":0.5}`)
+	if recorder.Code != http.StatusBadRequest || !strings.Contains(recorder.Body.String(), "invalid_sampling") || len(captured) != 2 {
+		t.Fatalf("presence_penalty the agent does not open = %d %s, want 400 like seed and stop", recorder.Code, recorder.Body)
+	}
+
+	recorder = request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}],"stop":[]}`)
+	if recorder.Code != http.StatusOK || len(captured) != 3 || !strings.Contains(captured[2], `"stop":["###"]`) {
+		t.Fatalf("an empty stop list = %d %s, want it treated as absent", recorder.Code, recorder.Body)
+	}
+
+	recorder = request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}],"temperature":1.5}`)
+	if recorder.Code != http.StatusBadRequest || len(captured) != 3 {
+		t.Fatalf("out of bounds temperature = %d, want 400 without an upstream call", recorder.Code)
+	}
+
+	recorder = request(t, routes("k", reg), http.MethodPost, pathCompletions, "k",
+		`{"model":"naru","messages":[{"role":"user","content":"hi"}],"max_completion_tokens":4096}`)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("max tokens above the ceiling = %d, want 400", recorder.Code)
+	}
+}
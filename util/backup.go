@@ -0,0 +1,131 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package util
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"modernc.org/sqlite"
+)
+
+type sqliteBackup interface {
+	NewBackup(dstUri string) (*sqlite.Backup, error)
+	NewRestore(srcUri string) (*sqlite.Backup, error)
+}
+
+func databaseCopy(db *sql.DB, start func(sqliteBackup) (*sqlite.Backup, error)) error {
+	var conn *sql.Conn
+
+	var err error
+
+	conn, err = db.Conn(context.Background())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		var raw sqliteBackup
+		var backup *sqlite.Backup
+		var ok bool
+
+		var err error
+
+		raw, ok = driverConn.(sqliteBackup)
+		if !ok {
+			return fmt.Errorf("the database driver does not support online backup")
+		}
+
+		backup, err = start(raw)
+		if err != nil {
+			return err
+		}
+
+		_, err = backup.Step(-1)
+		if err != nil {
+			backup.Finish()
+			return err
+		}
+
+		return backup.Finish()
+	})
+}
+
+func DatabaseBackup(db *sql.DB, path string) error {
+	var temp string
+
+	var err error
+
+	_, err = os.Stat(path)
+	if err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if !os.IsNotExist(err) {
+		return err
+	}
+
+	temp = path + ".tmp"
+	os.Remove(temp)
+
+	err = databaseCopy(db, func(raw sqliteBackup) (*sqlite.Backup, error) {
+		return raw.NewBackup(temp)
+	})
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+
+	err = os.Chmod(temp, 0600)
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+
+	err = os.Rename(temp, path)
+	if err != nil {
+		os.Remove(temp)
+		return err
+	}
+
+	return nil
+}
+
+func DatabaseRestore(db *sql.DB, path string) error {
+	var err error
+
+	_, err = os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	_, err = DatabaseCheck(path)
+	if err != nil {
+		return fmt.Errorf("%s is not a usable backup: %w", path, err)
+	}
+
+	err = databaseCopy(db, func(raw sqliteBackup) (*sqlite.Backup, error) {
+		return raw.NewRestore(path)
+	})
+	if err != nil {
+		return err
+	}
+
+	return migrations(db)
+}
+
+func DatabaseVacuum(db *sql.DB) error {
+	var err error
+
+	_, err = db.Exec("VACUUM;")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec("PRAGMA wal_checkpoint(TRUNCATE);")
+
+	return err
+}
@@ -0,0 +1,86 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package util
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDatabaseBackupAndRestore(t *testing.T) {
+	var database *sql.DB
+	var path string
+	var info os.FileInfo
+	var name string
+
+	var err error
+
+	database = openTestDB(t)
+	path = filepath.Join(t.TempDir(), "backup.db")
+
+	_, err = database.Exec("INSERT INTO sessions (id, agent_id, name) VALUES ('s1', 'a1', 'before');")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = DatabaseBackup(database, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err = os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("backup mode = %o, want 600", info.Mode().Perm())
+	}
+	err = DatabaseBackup(database, path)
+	if err == nil {
+		t.Fatal("a backup overwrote an existing file")
+	}
+
+	_, err = database.Exec("UPDATE sessions SET name = 'after' WHERE id = 's1';")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = DatabaseRestore(database, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = database.QueryRow("SELECT name FROM sessions WHERE id = 's1';").Scan(&name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "before" {
+		t.Fatalf("name = %q after restore, want before", name)
+	}
+
+	err = DatabaseVacuum(database)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDatabaseRestoreRejectsAnUnusableFile(t *testing.T) {
+	var database *sql.DB
+	var path string
+
+	var err error
+
+	database = openTestDB(t)
+	path = filepath.Join(t.TempDir(), "garbage.db")
+
+	err = os.WriteFile(path, []byte("not a database"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = DatabaseRestore(database, path)
+	if err == nil {
+		t.Fatal("a garbage file was restored")
+	}
+}
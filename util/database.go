@@ -6,6 +6,7 @@ package util
 import (
 	"database/sql"
 	"embed"
+	"fmt"
 	"io/fs"
 	"path/filepath"
 	"slices"
@@ -43,54 +44,84 @@ func migration(tx *sql.Tx, version, buf string) error {
 	return nil
 }
 
-func migrations(db *sql.DB) error {
+func migrationVersions() ([]string, error) {
 	var migs []fs.DirEntry
-	var row *sql.Rows
-	var heap string
-	var applied []string
-	var tx *sql.Tx
 	var mig fs.DirEntry
-	var version, path string
-	var buf []byte
-	var rollbackErr error
+	var version string
+	var versions []string
 
 	var err error
 
 	migs, err = files.ReadDir("migrations")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	sort.Slice(migs, func(i, j int) bool {
-		return migs[i].Name() < migs[j].Name()
-	})
+	for _, mig = range migs {
+		if mig.IsDir() {
+			continue
+		}
 
-	_, err = db.Exec(migrationSchema)
-	if err != nil {
-		return err
+		version, _ = strings.CutSuffix(mig.Name(), ".sql")
+		versions = append(versions, version)
 	}
 
+	sort.Strings(versions)
+
+	return versions, nil
+}
+
+func migrationsApplied(db *sql.DB) ([]string, error) {
+	var row *sql.Rows
+	var heap string
+	var applied []string
+
+	var err error
+
 	row, err = db.Query("SELECT version FROM migrations;")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer row.Close()
 
 	for row.Next() {
 		err = row.Scan(&heap)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		applied = append(applied, heap)
 	}
 
 	err = row.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return applied, row.Close()
+}
+
+func migrations(db *sql.DB) error {
+	var versions []string
+	var applied []string
+	var tx *sql.Tx
+	var version string
+	var buf []byte
+	var rollbackErr error
+
+	var err error
+
+	versions, err = migrationVersions()
+	if err != nil {
+		return err
+	}
+
+	_, err = db.Exec(migrationSchema)
 	if err != nil {
 		return err
 	}
 
-	err = row.Close()
+	applied, err = migrationsApplied(db)
 	if err != nil {
 		return err
 	}
@@ -100,22 +131,16 @@ func migrations(db *sql.DB) error {
 		return err
 	}
 
-	for _, mig = range migs {
-		if mig.IsDir() {
+	for _, version = range versions {
+		if slices.Contains(applied, version) {
 			continue
 		}
 
-		version, _ = strings.CutSuffix(mig.Name(), ".sql")
-		path = filepath.Join("migrations", mig.Name())
-		buf, err = files.ReadFile(path)
+		buf, err = files.ReadFile(filepath.Join("migrations", version+".sql"))
 		if err != nil {
 			return err
 		}
 
-		if slices.Contains(applied, version) {
-			continue
-		}
-
 		err = migration(tx, version, string(buf))
 		if err != nil {
 			rollbackErr = tx.Rollback()
@@ -214,3 +239,73 @@ func InitDatabase(dbPath string) (*sql.DB, error) {
 
 	return database, nil
 }
+
+func DatabaseCheck(dbPath string) ([]string, error) {
+	var database *sql.DB
+	var rows *sql.Rows
+	var line string
+	var problems []string
+	var tables int
+	var versions []string
+	var applied []string
+	var version string
+	var pending []string
+
+	var err error
+
+	database, err = sql.Open("sqlite", "file:"+dbPath+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	defer database.Close()
+
+	rows, err = database.Query("PRAGMA integrity_check;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = rows.Scan(&line)
+		if err != nil {
+			return nil, err
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("integrity check failed: %s", strings.Join(problems[:min(len(problems), 3)], "; "))
+	}
+
+	versions, err = migrationVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.QueryRow("SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name = 'migrations';").Scan(&tables)
+	if err != nil {
+		return nil, err
+	}
+	if tables == 0 {
+		return versions, nil
+	}
+
+	applied, err = migrationsApplied(database)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version = range versions {
+		if !slices.Contains(applied, version) {
+			pending = append(pending, version)
+		}
+	}
+
+	return pending, nil
+}
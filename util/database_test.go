@@ -145,3 +145,33 @@ func TestDatabaseRejectsOrphanMessage(t *testing.T) {
 		t.Fatal("expected foreign key violation for a message without a session")
 	}
 }
+
+func TestDatabaseCheckReportsPendingMigrations(t *testing.T) {
+	var path string
+	var database *sql.DB
+	var pending []string
+
+	var err error
+
+	path = filepath.Join(t.TempDir(), "check.db")
+	database, err = InitDatabase(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err = DatabaseCheck(path)
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("pending = %v, %v, want a clean database", pending, err)
+	}
+
+	_, err = database.Exec("DELETE FROM migrations WHERE version = '0020_artifact_images';")
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.Close()
+
+	pending, err = DatabaseCheck(path)
+	if err != nil || len(pending) != 1 || pending[0] != "0020_artifact_images" {
+		t.Fatalf("pending = %v, %v, want 0020_artifact_images", pending, err)
+	}
+}
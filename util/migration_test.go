@@ -151,8 +151,8 @@ func TestToolCallsTableCreated(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if count != 9 {
-		t.Fatalf("tool_calls has %d columns, want 9", count)
+	if count != 10 {
+		t.Fatalf("tool_calls has %d columns, want 10", count)
 	}
 }
 
@@ -375,3 +375,72 @@ func TestCachedTokensColumnRepairedAfterMigrationAlreadyRecorded(t *testing.T) {
 		t.Fatalf("cached_tokens column count = %d, want 1", count)
 	}
 }
+
+func TestEmptySummaryBoundaryBecomesNull(t *testing.T) {
+	var db *sql.DB
+	var versions []string
+	var version string
+	var buf []byte
+	var tx *sql.Tx
+	var unset bool
+	var kept string
+
+	var err error
+
+	db, err = sql.Open("sqlite", filepath.Join(t.TempDir(), "old.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(migrationSchema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, err = migrationVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err = db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version = range versions {
+		if version >= "0025" {
+			break
+		}
+		buf, err = files.ReadFile("migrations/" + version + ".sql")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = migration(tx, version, string(buf))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec(`INSERT INTO sessions (id, agent_id, name) VALUES ('elided', 'agent', 'elided'), ('summarized', 'agent', 'summarized');
+		INSERT INTO session_summaries (session_id, content, through_message_id, elided_through_message_id)
+		VALUES ('elided', '', '', 'message-1'), ('summarized', 'so far', 'message-2', '');`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = migrations(db)
+	if err != nil {
+		t.Fatalf("upgrading an existing database failed: %v", err)
+	}
+
+	err = db.QueryRow("SELECT through_message_id IS NULL FROM session_summaries WHERE session_id = 'elided';").Scan(&unset)
+	if err != nil || !unset {
+		t.Fatalf("empty boundary unset = %t, %v, want NULL", unset, err)
+	}
+	err = db.QueryRow("SELECT through_message_id FROM session_summaries WHERE session_id = 'summarized';").Scan(&kept)
+	if err != nil || kept != "message-2" {
+		t.Fatalf("real boundary = %q, %v, want it kept", kept, err)
+	}
+}
@@ -0,0 +1,9 @@
+CREATE TABLE embeddings (
+	source      VARCHAR(16) NOT NULL,
+	source_id   TEXT NOT NULL,
+	model       TEXT NOT NULL,
+	digest      VARCHAR(64) NOT NULL,
+	vector      BLOB NOT NULL,
+	created_at  DATETIME DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (source, source_id, model)
+);
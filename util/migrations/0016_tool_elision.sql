@@ -0,0 +1 @@
+ALTER TABLE session_summaries ADD COLUMN elided_through_message_id VARCHAR(36) NOT NULL DEFAULT '';
@@ -0,0 +1,10 @@
+CREATE TABLE artifacts (
+	id            VARCHAR(36) PRIMARY KEY,
+	session_id    VARCHAR(36) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+	tool_call_id  VARCHAR(255) NOT NULL REFERENCES tool_calls(id) ON DELETE CASCADE,
+	name          VARCHAR(255) NOT NULL,
+	content       TEXT NOT NULL,
+	created_at    DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_artifacts_session_id ON artifacts(session_id);
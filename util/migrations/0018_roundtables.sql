@@ -0,0 +1,10 @@
+ALTER TABLE sessions ADD COLUMN turn_mode VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN agent_id VARCHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE token_usage ADD COLUMN agent_id VARCHAR(36) NOT NULL DEFAULT '';
+
+CREATE TABLE session_agents (
+	session_id  VARCHAR(36) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+	agent_id    VARCHAR(36) NOT NULL,
+	seat        INTEGER NOT NULL,
+	PRIMARY KEY (session_id, agent_id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE attachments (
+	id          VARCHAR(36) PRIMARY KEY,
+	message_id  VARCHAR(36) NOT NULL REFERENCES messages(id) ON DELETE CASCADE,
+	name        VARCHAR(255) NOT NULL,
+	media_type  VARCHAR(255) NOT NULL,
+	content     BLOB NOT NULL,
+	created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attachments_message_id ON attachments(message_id);
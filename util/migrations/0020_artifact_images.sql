@@ -0,0 +1,2 @@
+ALTER TABLE artifacts ADD COLUMN media_type VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE artifacts ADD COLUMN data BLOB;
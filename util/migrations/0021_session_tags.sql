@@ -0,0 +1,10 @@
+ALTER TABLE sessions ADD COLUMN pinned INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE sessions ADD COLUMN archived INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE session_tags (
+	session_id  VARCHAR(36) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+	tag         VARCHAR(32) NOT NULL,
+	PRIMARY KEY (session_id, tag)
+);
+
+CREATE INDEX idx_session_tags_tag ON session_tags(tag);
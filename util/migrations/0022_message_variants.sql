@@ -0,0 +1,4 @@
+ALTER TABLE messages ADD COLUMN variant_of VARCHAR(36) NOT NULL DEFAULT '';
+ALTER TABLE messages ADD COLUMN selected INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_messages_variant_of ON messages(variant_of);
@@ -0,0 +1,15 @@
+CREATE TABLE message_ratings (
+	message_id  VARCHAR(36) PRIMARY KEY REFERENCES messages(id) ON DELETE CASCADE,
+	rating      VARCHAR(8) NOT NULL,
+	note        TEXT NOT NULL DEFAULT '',
+	created_at  DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_message_ratings_rating ON message_ratings(rating);
+
+CREATE TABLE discord_answers (
+	discord_message_id  VARCHAR(32) PRIMARY KEY,
+	message_id          VARCHAR(36) NOT NULL REFERENCES messages(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_discord_answers_message_id ON discord_answers(message_id);
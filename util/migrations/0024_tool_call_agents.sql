@@ -0,0 +1 @@
+ALTER TABLE tool_calls ADD COLUMN agent_id VARCHAR(36) NOT NULL DEFAULT '';
@@ -0,0 +1,26 @@
+CREATE TABLE session_summaries_rebuilt (
+	session_id                 VARCHAR(36) PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+	content                    TEXT NOT NULL,
+	through_message_id         VARCHAR(36),
+	elided_through_message_id  VARCHAR(36) NOT NULL DEFAULT '',
+	created_at                 DATETIME DEFAULT CURRENT_TIMESTAMP,
+	updated_at                 DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO session_summaries_rebuilt (session_id, content, through_message_id, elided_through_message_id, created_at, updated_at)
+SELECT session_id, content, NULLIF(through_message_id, ''), elided_through_message_id, created_at, updated_at
+FROM session_summaries;
+
+DROP TABLE session_summaries;
+
+ALTER TABLE session_summaries_rebuilt RENAME TO session_summaries;
+
+CREATE TRIGGER update_session_summaries_updated_at
+AFTER UPDATE ON session_summaries
+FOR EACH ROW
+WHEN NEW.updated_at = OLD.updated_at
+BEGIN
+	UPDATE session_summaries
+	SET updated_at = CURRENT_TIMESTAMP
+	WHERE session_id = NEW.session_id;
+END;
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+type SecretField struct {
+	Owner string
+	Ref   string
+	Plain bool
+}
+
+const (
+	SecretEnv  = "env:"
+	SecretFile = "file:"
+	SecretCmd  = "cmd:"
+)
+
+const secretCmdTimeout = 10 * time.Second
+
+const maxSecretBytes = 65536
+
+func SecretIsRef(value string) bool {
+	return strings.HasPrefix(value, SecretEnv) || strings.HasPrefix(value, SecretFile) || strings.HasPrefix(value, SecretCmd)
+}
+
+func secretFile(path string) (string, error) {
+	var file *os.File
+	var buf []byte
+
+	var err error
+
+	file, err = os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	buf, err = io.ReadAll(io.LimitReader(file, maxSecretBytes+1))
+	if err != nil {
+		return "", err
+	}
+	if len(buf) > maxSecretBytes {
+		return "", fmt.Errorf("%s is larger than %d bytes", path, maxSecretBytes)
+	}
+
+	return strings.TrimRight(string(buf), "\r\n"), nil
+}
+
+func secretCmd(command string) (string, error) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var cmd *exec.Cmd
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	var err error
+
+	ctx, cancel = context.WithTimeout(context.Background(), secretCmdTimeout)
+	defer cancel()
+
+	cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("%q did not finish within %s", command, secretCmdTimeout)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%q failed: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() > maxSecretBytes {
+		return "", fmt.Errorf("%q printed more than %d bytes", command, maxSecretBytes)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+func SecretResolve(value string) (string, error) {
+	var name string
+	var resolved string
+	var ok bool
+
+	var err error
+
+	switch {
+	case strings.HasPrefix(value, SecretEnv):
+		name = strings.TrimPrefix(value, SecretEnv)
+		resolved, ok = os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+	case strings.HasPrefix(value, SecretFile):
+		resolved, err = secretFile(strings.TrimPrefix(value, SecretFile))
+	case strings.HasPrefix(value, SecretCmd):
+		resolved, err = secretCmd(strings.TrimPrefix(value, SecretCmd))
+	default:
+		return value, nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if resolved == "" {
+		return "", fmt.Errorf("%s resolved to an empty value", value)
+	}
+
+	return resolved, nil
+}
+
+func SecretLoad(owner, value string) (string, string) {
+	var resolved string
+
+	var err error
+
+	if !SecretIsRef(value) {
+		return value, ""
+	}
+
+	resolved, err = SecretResolve(value)
+	if err != nil {
+		Log.Warn("secret reference did not resolve, run `mininaru secrets check`", "owner", owner, "ref", value, "error", err)
+		return "", value
+	}
+
+	return resolved, value
+}
+
+func SecretStored(value, ref string) string {
+	if ref != "" {
+		return ref
+	}
+
+	return value
+}
+
+func SecretOf(owner, value, ref string) (SecretField, bool) {
+	if ref != "" {
+		return SecretField{Owner: owner, Ref: ref}, true
+	}
+	if value != "" {
+		return SecretField{Owner: owner, Plain: true}, true
+	}
+
+	return SecretField{}, false
+}
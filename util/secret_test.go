@@ -0,0 +1,73 @@
+// SPDX-FileCopyrightText: 2026 Wonhyeok Kim (Project_IO)
+// SPDX-License-Identifier: GPL-3.0-or-later
+
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecretResolveKinds(t *testing.T) {
+	var path string
+	var cases map[string]string
+	var ref string
+	var want string
+	var got string
+
+	var err error
+
+	t.Setenv("NARU_TEST_SECRET", "from-env")
+	path = filepath.Join(t.TempDir(), "key")
+	err = os.WriteFile(path, []byte("from-file\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases = map[string]string{
+		"sk-plain":              "sk-plain",
+		"env:NARU_TEST_SECRET":  "from-env",
+		"file:" + path:          "from-file",
+		"cmd:printf 'from-cmd'": "from-cmd",
+	}
+	for ref, want = range cases {
+		got, err = SecretResolve(ref)
+		if err != nil || got != want {
+			t.Fatalf("%s resolved to %q, %v, want %q", ref, got, err, want)
+		}
+	}
+}
+
+func TestSecretResolveFailures(t *testing.T) {
+	var ref string
+
+	var err error
+
+	for _, ref = range []string{"env:NARU_TEST_UNSET", "file:/does/not/exist", "cmd:exit 3", "cmd:true"} {
+		_, err = SecretResolve(ref)
+		if err == nil {
+			t.Fatalf("%s resolved", ref)
+		}
+	}
+}
+
+func TestSecretLoadKeepsTheReference(t *testing.T) {
+	var value string
+	var ref string
+
+	t.Setenv("NARU_TEST_SECRET", "sk-live")
+
+	value, ref = SecretLoad("provider test", "env:NARU_TEST_SECRET")
+	if value != "sk-live" || ref != "env:NARU_TEST_SECRET" {
+		t.Fatalf("loaded %q with ref %q", value, ref)
+	}
+	if SecretStored(value, ref) != "env:NARU_TEST_SECRET" {
+		t.Fatal("the resolved value would be written back")
+	}
+
+	value, ref = SecretLoad("provider test", "sk-plain")
+	if value != "sk-plain" || ref != "" || SecretStored(value, ref) != "sk-plain" {
+		t.Fatalf("plaintext loaded as %q with ref %q", value, ref)
+	}
+}